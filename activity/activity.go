@@ -18,8 +18,16 @@ type (
 	// RegisterOptions consists of options for registering an activity.
 	RegisterOptions = internal.RegisterActivityOptions
 
+	// DefaultOptions are the default timeouts and retry policy of an activity type, applied when a workflow schedules
+	// the activity without setting them. See RegisterOptions.DefaultOptions.
+	DefaultOptions = internal.ActivityDefaultOptions
+
 	// DynamicRegisterOptions consists of options for registering a dynamic activity.
 	DynamicRegisterOptions = internal.DynamicRegisterActivityOptions
+
+	// LogBuffer retains the most recent log lines of an activity so they can be attached to its heartbeats. See
+	// NewLogBuffer.
+	LogBuffer = internal.ActivityLogBuffer
 )
 
 // ErrResultPending is returned from activity's implementation to indicate the activity is not completed when the
@@ -44,6 +52,17 @@ func GetInfo(ctx context.Context) Info {
 	return internal.GetActivityInfo(ctx)
 }
 
+// NewLogBuffer creates a LogBuffer retaining the most recent maxLines lines, 20 if maxLines is not positive. Lines
+// added with LogBuffer.Printf are attached to the heartbeats recorded with LogBuffer.RecordHeartbeat, where they are
+// visible in the pending activity of the workflow and, when the activity times out, to the workflow through
+// workflow.ActivityLogs.
+//
+// The lines are best-effort: only the most recent maxLines lines are retained, each is truncated to 1024 bytes to
+// bound the size of heartbeats, and they are only as current as the last heartbeat that reached the server.
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return internal.NewActivityLogBuffer(maxLines)
+}
+
 // GetLogger returns a logger that can be used in the activity.
 func GetLogger(ctx context.Context) log.Logger {
 	return internal.GetActivityLogger(ctx)
@@ -83,6 +102,22 @@ func HasHeartbeatDetails(ctx context.Context) bool {
 	return internal.HasHeartbeatDetails(ctx)
 }
 
+// IsLastAttempt reports whether the current attempt is the last one allowed by the activity's retry policy, which
+// lets an activity persist partial results or do extra cleanup before giving up. It returns false when the retry
+// policy is unknown or has unlimited attempts, and cannot account for retries ending early because of non-retryable
+// errors, ScheduleToCloseTimeout or cancellation.
+func IsLastAttempt(ctx context.Context) bool {
+	return internal.IsLastActivityAttempt(ctx)
+}
+
+// GetIdempotencyToken returns a token that is the same for every attempt of the activity and differs between
+// activities, for external systems to deduplicate requests the activity makes. It is derived from the workflow ID,
+// run ID and activity ID, so it changes when a workflow reset or continue-as-new starts a new run. Use it together
+// with Info.Attempt to tell attempts apart.
+func GetIdempotencyToken(ctx context.Context) string {
+	return internal.GetActivityIdempotencyToken(ctx)
+}
+
 // GetHeartbeatDetails extracts heartbeat details from the last failed attempt. This is used in combination with the retry policy.
 // An activity could be scheduled with an optional retry policy on ActivityOptions. If the activity failed, then server
 // would attempt to dispatch another activity task to retry according to the retry policy. If there were heartbeat
@@ -218,12 +218,21 @@ type (
 	// ConnectionOptions are optional parameters that can be specified in ClientOptions
 	ConnectionOptions = internal.ConnectionOptions
 
+	// LongPollRetryOptions configure retries of long-poll calls. See Options.LongPollRetryOptions.
+	LongPollRetryOptions = internal.LongPollRetryOptions
+
 	// Credentials are optional credentials that can be specified in ClientOptions.
 	Credentials = internal.Credentials
 
 	// StartWorkflowOptions configuration parameters for starting a workflow execution.
 	StartWorkflowOptions = internal.StartWorkflowOptions
 
+	// CompletionCallback is a Nexus callback the server invokes when a workflow completes. See
+	// StartWorkflowOptions.CompletionCallbacks.
+	//
+	// NOTE: Experimental
+	CompletionCallback = internal.CompletionCallback
+
 	// WithStartWorkflowOperation defines how to start a workflow when using UpdateWithStartWorkflow.
 	// See [client.Client.NewWithStartWorkflowOperation] and [client.Client.UpdateWithStartWorkflow].
 	WithStartWorkflowOperation = internal.WithStartWorkflowOperation
@@ -336,6 +345,9 @@ type (
 	// ScheduleBackfillOptions configure the parameters for backfilling a schedule.
 	ScheduleBackfillOptions = internal.ScheduleBackfillOptions
 
+	// ScheduleBackfillResult describes the Actions taken by a backfill.
+	ScheduleBackfillResult = internal.ScheduleBackfillResult
+
 	// UpdateWorkflowOptions encapsulates the parameters for
 	// sending an update to a workflow execution.
 	UpdateWorkflowOptions = internal.UpdateWorkflowOptions
@@ -918,6 +930,42 @@ type (
 	// NOTE: Experimental
 	ListActivitiesResult = internal.ClientListActivitiesResult
 
+	// CountWorkflowOptions are options for Client.CountWorkflowWithOptions.
+	//
+	// NOTE: Experimental
+	CountWorkflowOptions = internal.CountWorkflowOptions
+
+	// CountWorkflowResult is the result of Client.CountWorkflowWithOptions.
+	//
+	// NOTE: Experimental
+	CountWorkflowResult = internal.CountWorkflowResult
+
+	// AddSearchAttributesOptions are options for Client.AddSearchAttributes.
+	AddSearchAttributesOptions = internal.AddSearchAttributesOptions
+
+	// ListSearchAttributesOptions are options for Client.ListSearchAttributes.
+	ListSearchAttributesOptions = internal.ListSearchAttributesOptions
+
+	// SearchAttributesSchema is the result of Client.ListSearchAttributes.
+	SearchAttributesSchema = internal.SearchAttributesSchema
+
+	// NamespaceDescription is the result of Client.DescribeNamespace.
+	NamespaceDescription = internal.NamespaceDescription
+
+	// NamespaceArchivalConfig is the archival configuration of a namespace.
+	NamespaceArchivalConfig = internal.NamespaceArchivalConfig
+
+	// ListStuckWorkflowsOptions are options for Client.ListStuckWorkflows.
+	//
+	// NOTE: Experimental
+	ListStuckWorkflowsOptions = internal.ListStuckWorkflowsOptions
+
+	// StuckWorkflow is a workflow whose workflow task keeps failing or timing out, as returned by
+	// Client.ListStuckWorkflows.
+	//
+	// NOTE: Experimental
+	StuckWorkflow = internal.StuckWorkflow
+
 	// CountActivitiesOptions contains input for CountActivities call.
 	//
 	// NOTE: Experimental
@@ -1232,6 +1280,27 @@ type (
 		//  - serviceerror.Unavailable
 		CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error)
 
+		// CountWorkflowWithOptions counts workflow executions matching a visibility query, like CountWorkflow, and
+		// returns the count along with the query and how long the count took, which is useful for monitoring.
+		// Optionally, a bounded number of matching workflows can be sampled to estimate how many had a failed update.
+		// See [CountWorkflowOptions] for the cost of sampling.
+		//
+		// NOTE: Experimental
+		CountWorkflowWithOptions(ctx context.Context, options CountWorkflowOptions) (*CountWorkflowResult, error)
+
+		// ListStuckWorkflows returns the running workflows matching a visibility query whose pending workflow task has
+		// been retried many times, which typically happens when a bad deployment makes workflow tasks fail or panic
+		// (see worker.Options.WorkflowPanicPolicy). This is useful for alerting on workflows blocked by a bad deploy.
+		// The last workflow task failure message is returned when the server recorded one in history: the server
+		// only records the first failure of consecutive failed attempts, so the message may be from an earlier
+		// attempt than the latest one.
+		//
+		// Each inspected workflow is described, and the history of each stuck workflow is fetched, so the cost grows
+		// with the number of workflows inspected. See [ListStuckWorkflowsOptions].
+		//
+		// NOTE: Experimental
+		ListStuckWorkflows(ctx context.Context, options ListStuckWorkflowsOptions) ([]StuckWorkflow, error)
+
 		// GetSearchAttributes returns valid search attributes keys and value types.
 		// The search attributes can be used in query of List/Scan/Count APIs. Adding new search attributes requires temporal server
 		// to update dynamic config ValidSearchAttributes.
@@ -1239,6 +1308,40 @@ type (
 		// NOTE: This API is not supported on Temporal Cloud.
 		GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error)
 
+		// AddSearchAttributes registers custom search attributes on a namespace through the operator service, so they
+		// can be provisioned as part of deploying an application. Search attributes that already exist with the same
+		// type are skipped, so it is safe to call on every deployment, and an error is returned if one already exists
+		// with a different type. Managing search attributes requires more permissions than using them, so this may fail
+		// with a wrapped serviceerror.PermissionDenied.
+		//
+		// Schema changes may take some time to propagate, so newly added search attributes might not be usable in
+		// workflows or visibility queries right away.
+		// The errors it can return:
+		//  - serviceerror.PermissionDenied
+		//  - serviceerror.InvalidArgument
+		//  - serviceerror.Internal
+		//  - serviceerror.Unavailable
+		AddSearchAttributes(ctx context.Context, options AddSearchAttributesOptions) error
+
+		// ListSearchAttributes returns the custom and system search attributes of a namespace through the operator
+		// service.
+		// The errors it can return:
+		//  - serviceerror.PermissionDenied
+		//  - serviceerror.NotFound
+		//  - serviceerror.Internal
+		//  - serviceerror.Unavailable
+		ListSearchAttributes(ctx context.Context, options ListSearchAttributesOptions) (*SearchAttributesSchema, error)
+
+		// DescribeNamespace returns the configuration of a namespace, including its retention, archival configuration
+		// and registered search attributes. The search attributes are listed through the operator service on a best
+		// effort basis and left nil if that fails. Defaults to the namespace of the client if namespace is empty.
+		// The errors it can return:
+		//  - serviceerror.NamespaceNotFound, if the namespace does not exist
+		//  - serviceerror.PermissionDenied
+		//  - serviceerror.Internal
+		//  - serviceerror.Unavailable
+		DescribeNamespace(ctx context.Context, namespace string) (*NamespaceDescription, error)
+
 		// QueryWorkflow queries a given workflow's last execution and returns the query result synchronously. Parameter workflowID
 		// and queryType are required, other parameters are optional. The workflowID and runID (optional) identify the
 		// target workflow execution that this query will be send to. If runID is not specified (empty string), server will
@@ -1315,6 +1418,25 @@ type (
 		// RequestId is used to deduplicate requests. It will be autogenerated if not set.
 		ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error)
 
+		// ForceNewWorkflowTask makes the server schedule a new workflow task for a running workflow, so that conditions
+		// it is blocked on, e.g. in workflow.Await, are evaluated again. This is done by sending an internal signal that
+		// the worker drops before it reaches workflow code, so it is recorded in history like any other signal.
+		//
+		// The signal is only dropped by workers running Go SDK v1.42.0 or later. Older workers, and workers of other
+		// SDKs, deliver it to the workflow as a signal named "__temporal_force_workflow_task", which is reported as
+		// unhandled unless the workflow drains every signal channel, so only call this for workflows whose workers have
+		// been upgraded.
+		//
+		// This is a recovery tool for workflows that are stuck waiting on a condition that nothing re-evaluates. A
+		// better design is to explicitly signal the workflow when the state it depends on changes.
+		// If the workflow is already closed, nothing is done and nil is returned.
+		// The errors it can return:
+		//  - serviceerror.NotFound
+		//  - serviceerror.InvalidArgument
+		//  - serviceerror.Internal
+		//  - serviceerror.Unavailable
+		ForceNewWorkflowTask(ctx context.Context, workflowID string, runID string) error
+
 		// UpdateWorkerBuildIdCompatibility
 		// Allows you to update the worker-build-id based version sets for a particular task queue. This is used in
 		// conjunction with workers who specify their build id and thus opt into the feature.
@@ -1329,7 +1451,12 @@ type (
 		GetWorkerBuildIdCompatibility(ctx context.Context, options *GetWorkerBuildIdCompatibilityOptions) (*WorkerBuildIDVersionSets, error)
 
 		// GetWorkerTaskReachability
-		// Returns which versions are is still in use by open or closed workflows
+		// Returns which versions are is still in use by open or closed workflows, i.e. whether each build ID is
+		// reachable by new workflows, by existing open workflows, only by closed workflows or not at all. This is
+		// used to decide when it is safe to decommission the workers of a build ID.
+		// If options.BuildIDs is empty, the current default build ID of each of options.TaskQueues is queried.
+		// Requires a Server supporting build-ID based versioning, i.e. 1.21 or later with the worker versioning APIs
+		// enabled.
 		//
 		// Deprecated: Use [DescribeTaskQueueEnhanced] with the versioning api.
 		GetWorkerTaskReachability(ctx context.Context, options *GetWorkerTaskReachabilityOptions) (*WorkerTaskReachability, error)
@@ -1391,6 +1518,14 @@ type (
 		// has reached the WaitForStage in the options. Note that this means
 		// that the call will not return successfully until the update has been
 		// delivered to a worker.
+		//
+		// The run ID of the workflow the update was issued against is
+		// available from the RunID method of the returned handle, and the
+		// run itself from the Get method of the start operation. If the
+		// workflow is running and the WorkflowIDConflictPolicy is FAIL, the
+		// returned error wraps a *serviceerror.WorkflowExecutionAlreadyStarted,
+		// which can be checked with errors.As, and Get of the start operation
+		// returns the same error.
 		UpdateWithStartWorkflow(ctx context.Context, options UpdateWithStartWorkflowOptions) (WorkflowUpdateHandle, error)
 
 		// GetWorkflowUpdateHandle creates a handle to the referenced update
@@ -1579,6 +1714,22 @@ func NewClientFromExistingWithContext(ctx context.Context, existingClient Client
 	return internal.NewClientFromExisting(ctx, existingClient, options)
 }
 
+// WithNamespace creates a new client bound to the given namespace using the
+// same connection, data converter, failure converter, context propagators,
+// interceptors and other options as the existing client. Unlike
+// NewClientFromExisting, this does not make any calls to the server. This is
+// useful to avoid a connection per namespace in applications that work with
+// multiple namespaces. The existing client must have been created from this
+// package and cannot be wrapped.
+//
+// The returned client does not own the connection: Close() on it does nothing,
+// and closing the existing client closes the connection of all clients created
+// from it with WithNamespace, which then can no longer be used. Workers created
+// with the returned client are independent and must be stopped separately.
+func WithNamespace(existingClient Client, namespace string) (Client, error) {
+	return internal.ClientWithNamespace(existingClient, namespace)
+}
+
 // NewNamespaceClient creates an instance of a namespace client, to manage
 // lifecycle of namespaces. This will not attempt to connect to the server
 // eagerly and therefore may not fail for an unreachable server until a call is
@@ -1618,6 +1769,21 @@ func NewValues(data *commonpb.Payloads) converter.EncodedValues {
 	return internal.NewValues(data)
 }
 
+// DecodeMemo decodes the value of key in a workflow memo, e.g. of a workflow listed with [Client.ListWorkflow], using
+// the data converter of c so that its codecs, such as encryption, apply. It returns false and no error if the memo has
+// no value for key. For example:
+//
+//	owner, ok, err := client.DecodeMemo[string](c, execution.GetMemo(), "owner")
+func DecodeMemo[T any](c Client, memo *commonpb.Memo, key string) (T, bool, error) {
+	return internal.DecodeMemo[T](c, memo, key)
+}
+
+// DecodeMemoValues decodes all values of a workflow memo into T, using the data converter of c like [DecodeMemo]. Use
+// interface{} as T for memos with values of different types.
+func DecodeMemoValues[T any](c Client, memo *commonpb.Memo) (map[string]T, error) {
+	return internal.DecodeMemoValues[T](c, memo)
+}
+
 // HistoryJSONOptions are options for HistoryFromJSON.
 type HistoryJSONOptions struct {
 	// LastEventID, if set, will only load history up to this ID (inclusive).
@@ -0,0 +1,191 @@
+// Package openmetrics provides a client.MetricsHandler that keeps the SDK's
+// metrics in memory and serves them over HTTP in the Prometheus text or
+// OpenMetrics exposition format, for users who want a metrics endpoint without
+// setting up tally or OpenTelemetry.
+//
+// The handler is set as client.Options.MetricsHandler (workers use the metrics
+// handler of their client) and served on any HTTP server:
+//
+//	handler := openmetrics.NewMetricsHandler(openmetrics.MetricsHandlerOptions{})
+//	c, err := client.Dial(client.Options{MetricsHandler: handler})
+//	...
+//	http.Handle("/metrics", handler)
+package openmetrics
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+// DefaultMaxSeriesPerMetric is the default for
+// MetricsHandlerOptions.MaxSeriesPerMetric.
+const DefaultMaxSeriesPerMetric = 1000
+
+// DefaultTimerBuckets are the default upper bounds, in seconds, of the
+// histogram buckets timers are recorded into.
+var DefaultTimerBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var _ client.MetricsHandler = (*MetricsHandler)(nil)
+var _ http.Handler = (*MetricsHandler)(nil)
+
+// MetricsHandlerOptions are options provided to NewMetricsHandler.
+type MetricsHandlerOptions struct {
+	// MaxSeriesPerMetric caps how many distinct tag sets are kept for each
+	// metric name, so high-cardinality tags cannot grow memory without bound.
+	// Values recorded for new tag sets beyond the cap are dropped and counted
+	// by MetricsHandler.DroppedSeries.
+	//
+	// Optional: defaults to DefaultMaxSeriesPerMetric. Negative means no cap.
+	MaxSeriesPerMetric int
+	// TimerBuckets are the ascending upper bounds, in seconds, of the histogram
+	// buckets timers are recorded into.
+	//
+	// Optional: defaults to DefaultTimerBuckets.
+	TimerBuckets []float64
+}
+
+// MetricsHandler is an implementation of client.MetricsHandler that
+// accumulates counters, gauges and timers in memory. It is also an
+// http.Handler serving the accumulated metrics. Counters are exposed with a
+// "_total" suffix and timers as histograms in seconds with a "_seconds"
+// suffix. It is safe for concurrent use.
+type MetricsHandler struct {
+	registry *registry
+	tags     map[string]string
+}
+
+// NewMetricsHandler returns a MetricsHandler with no tags and no recorded
+// metrics.
+func NewMetricsHandler(options MetricsHandlerOptions) *MetricsHandler {
+	if options.MaxSeriesPerMetric == 0 {
+		options.MaxSeriesPerMetric = DefaultMaxSeriesPerMetric
+	}
+	if len(options.TimerBuckets) == 0 {
+		options.TimerBuckets = DefaultTimerBuckets
+	}
+	return &MetricsHandler{
+		registry: &registry{
+			maxSeriesPerMetric: options.MaxSeriesPerMetric,
+			timerBuckets:       slices.Clone(options.TimerBuckets),
+			families:           map[string]*family{},
+		},
+	}
+}
+
+// WithTags returns a handler sharing this handler's metrics that adds the given
+// tags to the ones of this handler.
+func (m *MetricsHandler) WithTags(tags map[string]string) client.MetricsHandler {
+	newTags := maps.Clone(m.tags)
+	if newTags == nil {
+		newTags = make(map[string]string, len(tags))
+	}
+	maps.Copy(newTags, tags)
+	return &MetricsHandler{registry: m.registry, tags: newTags}
+}
+
+// Counter returns the counter of the given name with this handler's tags.
+func (m *MetricsHandler) Counter(name string) client.MetricsCounter {
+	s := m.registry.series(counterName(name), kindCounter, m.tags)
+	if s == nil {
+		return client.MetricsNopHandler.Counter(name)
+	}
+	return metrics.CounterFunc(func(d int64) {
+		m.registry.mu.Lock()
+		defer m.registry.mu.Unlock()
+		s.value += float64(d)
+	})
+}
+
+// Gauge returns the gauge of the given name with this handler's tags.
+func (m *MetricsHandler) Gauge(name string) client.MetricsGauge {
+	s := m.registry.series(sanitizeName(name), kindGauge, m.tags)
+	if s == nil {
+		return client.MetricsNopHandler.Gauge(name)
+	}
+	return metrics.GaugeFunc(func(f float64) {
+		m.registry.mu.Lock()
+		defer m.registry.mu.Unlock()
+		s.value = f
+	})
+}
+
+// Timer returns the timer of the given name with this handler's tags.
+func (m *MetricsHandler) Timer(name string) client.MetricsTimer {
+	s := m.registry.series(timerName(name), kindHistogram, m.tags)
+	if s == nil {
+		return client.MetricsNopHandler.Timer(name)
+	}
+	buckets := m.registry.timerBuckets
+	return metrics.TimerFunc(func(t time.Duration) {
+		seconds := t.Seconds()
+		m.registry.mu.Lock()
+		defer m.registry.mu.Unlock()
+		for i, upper := range buckets {
+			if seconds <= upper {
+				s.bucketCounts[i]++
+			}
+		}
+		s.value += seconds
+		s.count++
+	})
+}
+
+// DroppedSeries returns how many times a metric could not be recorded because
+// its name already had MetricsHandlerOptions.MaxSeriesPerMetric tag sets or
+// was used before as a different kind of metric.
+func (m *MetricsHandler) DroppedSeries() int64 {
+	m.registry.mu.Lock()
+	defer m.registry.mu.Unlock()
+	return m.registry.droppedSeries
+}
+
+// ServeHTTP writes all recorded metrics. The OpenMetrics format is used if the
+// request accepts "application/openmetrics-text" and the Prometheus text format
+// otherwise.
+func (m *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+	var b strings.Builder
+	m.registry.write(&b, openMetrics)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func counterName(name string) string {
+	name = sanitizeName(name)
+	if !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	return name
+}
+
+func timerName(name string) string {
+	name = sanitizeName(name)
+	if !strings.HasSuffix(name, "_seconds") {
+		name += "_seconds"
+	}
+	return name
+}
+
+// sanitizeName replaces characters that are not valid in metric and label
+// names with underscores.
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9' && i > 0) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
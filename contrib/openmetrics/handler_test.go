@@ -0,0 +1,100 @@
+package openmetrics_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/contrib/openmetrics"
+)
+
+func scrape(t *testing.T, handler http.Handler, accept string) (string, string) {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(t, err)
+	return rec.Result().Header.Get("Content-Type"), string(body)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	handler := openmetrics.NewMetricsHandler(openmetrics.MetricsHandlerOptions{
+		TimerBuckets: []float64{0.1, 1},
+	})
+	tagged := handler.WithTags(map[string]string{"namespace": "default"})
+	tagged.WithTags(map[string]string{"task_queue": `my "queue"`}).Counter("temporal_request").Inc(2)
+	tagged.Counter("temporal_request").Inc(1)
+	tagged.Counter("temporal_request").Inc(1)
+	handler.Gauge("temporal.num_pollers").Update(3)
+	handler.Gauge("temporal.num_pollers").Update(5)
+	handler.Timer("temporal_request_latency").Record(50 * time.Millisecond)
+	handler.Timer("temporal_request_latency").Record(2 * time.Second)
+
+	contentType, body := scrape(t, handler, "")
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", contentType)
+	require.Equal(t, `# TYPE temporal_num_pollers gauge
+temporal_num_pollers 5
+# TYPE temporal_request_latency_seconds histogram
+temporal_request_latency_seconds_bucket{le="0.1"} 1
+temporal_request_latency_seconds_bucket{le="1"} 1
+temporal_request_latency_seconds_bucket{le="+Inf"} 2
+temporal_request_latency_seconds_sum 2.05
+temporal_request_latency_seconds_count 2
+# TYPE temporal_request_total counter
+temporal_request_total{namespace="default"} 2
+temporal_request_total{namespace="default",task_queue="my \"queue\""} 2
+`, body)
+
+	contentType, body = scrape(t, handler, "application/openmetrics-text; version=1.0.0")
+	require.Equal(t, "application/openmetrics-text; version=1.0.0; charset=utf-8", contentType)
+	require.Contains(t, body, "# TYPE temporal_request counter\ntemporal_request_total{namespace=\"default\"} 2\n")
+	require.True(t, len(body) > 6 && body[len(body)-6:] == "# EOF\n")
+}
+
+func TestMetricsHandler_MaxSeriesPerMetric(t *testing.T) {
+	handler := openmetrics.NewMetricsHandler(openmetrics.MetricsHandlerOptions{MaxSeriesPerMetric: 2})
+	for i := 0; i < 5; i++ {
+		handler.WithTags(map[string]string{"id": fmt.Sprint(i)}).Counter("requests").Inc(1)
+	}
+	// Existing series keep being recorded
+	handler.WithTags(map[string]string{"id": "0"}).Counter("requests").Inc(1)
+	// A name used as another kind of metric is dropped
+	handler.Gauge("requests_total").Update(1)
+
+	_, body := scrape(t, handler, "")
+	require.Equal(t, `# TYPE requests_total counter
+requests_total{id="0"} 2
+requests_total{id="1"} 1
+`, body)
+	require.Equal(t, int64(4), handler.DroppedSeries())
+}
+
+func TestMetricsHandler_Concurrent(t *testing.T) {
+	handler := openmetrics.NewMetricsHandler(openmetrics.MetricsHandlerOptions{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				handler.WithTags(map[string]string{"worker": fmt.Sprint(j % 3)}).Counter("polls").Inc(1)
+				handler.Timer("latency").Record(time.Millisecond)
+				_, _ = scrape(t, handler, "")
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, body := scrape(t, handler, "")
+	require.Contains(t, body, "latency_seconds_count 1000\n")
+	require.Contains(t, body, `polls_total{worker="0"} 340`)
+}
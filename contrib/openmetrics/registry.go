@@ -0,0 +1,176 @@
+package openmetrics
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metricKind int
+
+const (
+	kindCounter metricKind = iota
+	kindGauge
+	kindHistogram
+)
+
+// registry holds all metrics of a MetricsHandler and the handlers derived from
+// it with WithTags.
+type registry struct {
+	maxSeriesPerMetric int
+	timerBuckets       []float64
+
+	mu            sync.Mutex
+	families      map[string]*family
+	droppedSeries int64
+}
+
+// family is all series of a metric name.
+type family struct {
+	kind   metricKind
+	series map[string]*series
+}
+
+// series is a metric name with one set of tags.
+type series struct {
+	// labels is the formatted label set, e.g. `a="1",b="2"`.
+	labels string
+	// value is the counter or gauge value, or the histogram sum.
+	value        float64
+	count        uint64
+	bucketCounts []uint64
+}
+
+// series returns the series of the metric name with the given tags, creating it
+// if needed. It returns nil if the series cannot be created.
+func (r *registry) series(name string, kind metricKind, tags map[string]string) *series {
+	labels := formatLabels(tags)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.families[name]
+	if f == nil {
+		f = &family{kind: kind, series: map[string]*series{}}
+		r.families[name] = f
+	} else if f.kind != kind {
+		r.droppedSeries++
+		return nil
+	}
+	s := f.series[labels]
+	if s == nil {
+		if r.maxSeriesPerMetric > 0 && len(f.series) >= r.maxSeriesPerMetric {
+			r.droppedSeries++
+			return nil
+		}
+		s = &series{labels: labels}
+		if kind == kindHistogram {
+			s.bucketCounts = make([]uint64, len(r.timerBuckets))
+		}
+		f.series[labels] = s
+	}
+	return s
+}
+
+// write writes all series sorted by name and labels, so the output is stable.
+func (r *registry) write(b *strings.Builder, openMetrics bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		f := r.families[name]
+		allSeries := make([]*series, 0, len(f.series))
+		for _, s := range f.series {
+			allSeries = append(allSeries, s)
+		}
+		slices.SortFunc(allSeries, func(a, b *series) int { return strings.Compare(a.labels, b.labels) })
+
+		switch f.kind {
+		case kindCounter:
+			// OpenMetrics names counter families without the suffix of their samples
+			familyName := name
+			if openMetrics {
+				familyName = strings.TrimSuffix(name, "_total")
+			}
+			b.WriteString("# TYPE " + familyName + " counter\n")
+			for _, s := range allSeries {
+				writeSample(b, name, s.labels, "", s.value)
+			}
+		case kindGauge:
+			b.WriteString("# TYPE " + name + " gauge\n")
+			for _, s := range allSeries {
+				writeSample(b, name, s.labels, "", s.value)
+			}
+		case kindHistogram:
+			b.WriteString("# TYPE " + name + " histogram\n")
+			for _, s := range allSeries {
+				for i, upper := range r.timerBuckets {
+					writeSample(b, name+"_bucket", s.labels, `le="`+formatFloat(upper)+`"`, float64(s.bucketCounts[i]))
+				}
+				writeSample(b, name+"_bucket", s.labels, `le="+Inf"`, float64(s.count))
+				writeSample(b, name+"_sum", s.labels, "", s.value)
+				writeSample(b, name+"_count", s.labels, "", float64(s.count))
+			}
+		}
+	}
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+}
+
+func writeSample(b *strings.Builder, name, labels, extraLabel string, value float64) {
+	b.WriteString(name)
+	if labels != "" || extraLabel != "" {
+		b.WriteByte('{')
+		b.WriteString(labels)
+		if labels != "" && extraLabel != "" {
+			b.WriteByte(',')
+		}
+		b.WriteString(extraLabel)
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// formatLabels formats tags as a label set sorted by name.
+func formatLabels(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sanitizeName(k))
+		b.WriteString(`="`)
+		b.WriteString(labelValueReplacer.Replace(tags[k]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -108,6 +108,35 @@ func (*zlibCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, err
 	return result, nil
 }
 
+// PayloadCountLimitCodecOptions are options for NewPayloadCountLimitCodec.
+type PayloadCountLimitCodecOptions struct {
+	// MaxPayloads is the maximum number of payloads that may be encoded
+	// together, e.g. the arguments of one activity call. Zero means unlimited.
+	MaxPayloads int
+}
+
+type payloadCountLimitCodec struct{ options PayloadCountLimitCodecOptions }
+
+// NewPayloadCountLimitCodec creates a PayloadCodec for use in
+// NewCodecDataConverter that fails encoding with ErrTooManyPayloads when more
+// than the configured number of payloads are encoded together. This guards
+// downstream systems that can't handle many payloads in one message. Decoding
+// is not limited so existing data can always be read.
+func NewPayloadCountLimitCodec(options PayloadCountLimitCodecOptions) PayloadCodec {
+	return &payloadCountLimitCodec{options}
+}
+
+func (c *payloadCountLimitCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	if c.options.MaxPayloads > 0 && len(payloads) > c.options.MaxPayloads {
+		return payloads, fmt.Errorf("%w: %d payloads exceeds limit of %d", ErrTooManyPayloads, len(payloads), c.options.MaxPayloads)
+	}
+	return payloads, nil
+}
+
+func (*payloadCountLimitCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	return payloads, nil
+}
+
 // CodecDataConverter is a DataConverter that wraps an underlying data
 // converter and supports chained encoding of just the payload without regard
 // for serialization to/from actual types.
@@ -355,3 +355,27 @@ func TestCodecDataConverter_ToPayload_EncodeError(t *testing.T) {
 	// Also assert that the original payload is returned on error.
 	require.True(proto.Equal(originalPayload, payload))
 }
+
+func TestPayloadCountLimitCodec(t *testing.T) {
+	require := require.New(t)
+
+	conv := NewCodecDataConverter(
+		GetDefaultDataConverter(),
+		NewPayloadCountLimitCodec(PayloadCountLimitCodecOptions{MaxPayloads: 2}),
+	)
+	payloads, err := conv.ToPayloads("a", "b")
+	require.NoError(err)
+	var a, b string
+	require.NoError(conv.FromPayloads(payloads, &a, &b))
+	require.Equal("a", a)
+	require.Equal("b", b)
+
+	_, err = conv.ToPayloads("a", "b", "c")
+	require.ErrorIs(err, ErrTooManyPayloads)
+	require.EqualError(err, "too many payloads: 3 payloads exceeds limit of 2")
+
+	// Unlimited by default
+	conv = NewCodecDataConverter(GetDefaultDataConverter(), NewPayloadCountLimitCodec(PayloadCountLimitCodecOptions{}))
+	_, err = conv.ToPayloads("a", "b", "c")
+	require.NoError(err)
+}
@@ -0,0 +1,201 @@
+package converter
+
+import (
+	"fmt"
+	"reflect"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// EnumInteger is the constraint for the underlying type of enums supported by NewEnumNames.
+type EnumInteger interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// EnumNames is the mapping between the values of an integer enum type and their names. Create it with NewEnumNames
+// and register it with NewEnumConverter.
+type EnumNames interface {
+	enumType() reflect.Type
+	name(value reflect.Value) (string, bool)
+	value(name string) (reflect.Value, bool)
+}
+
+type enumNames[T EnumInteger] struct {
+	names  map[T]string
+	values map[string]T
+}
+
+// NewEnumNames returns the mapping between the values of the enum type T and their names, which must be unique and
+// not empty. Values that are not in the map fail to encode.
+//
+// Names are what is stored in history, so they must not change once workflows passing the enum are running. Values
+// can be renumbered or reordered freely.
+func NewEnumNames[T EnumInteger](names map[T]string) EnumNames {
+	e := &enumNames[T]{names: make(map[T]string, len(names)), values: make(map[string]T, len(names))}
+	for value, name := range names {
+		if name == "" {
+			panic(fmt.Sprintf("empty name for value %d of enum %T", value, value))
+		}
+		if other, ok := e.values[name]; ok {
+			panic(fmt.Sprintf("duplicate name %q for values %d and %d of enum %T", name, other, value, value))
+		}
+		e.names[value] = name
+		e.values[name] = value
+	}
+	return e
+}
+
+func (e *enumNames[T]) enumType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (e *enumNames[T]) name(value reflect.Value) (string, bool) {
+	name, ok := e.names[value.Interface().(T)]
+	return name, ok
+}
+
+func (e *enumNames[T]) value(name string) (reflect.Value, bool) {
+	value, ok := e.values[name]
+	return reflect.ValueOf(value), ok
+}
+
+// EnumConverter is a DataConverter that wraps an underlying data converter and
+// encodes the values of registered integer enums as their names.
+type EnumConverter struct {
+	parent DataConverter
+	enums  map[reflect.Type]EnumNames
+}
+
+// NewEnumConverter wraps the given parent DataConverter so that values of the
+// enum types registered with enums are encoded as their names by the parent,
+// e.g. as JSON strings by the default data converter, instead of as numbers.
+// This keeps history readable and stops reordering enum constants from
+// changing the meaning of values already in history. For example:
+//
+//	dc := converter.NewEnumConverter(
+//		converter.GetDefaultDataConverter(),
+//		converter.NewEnumNames(map[Color]string{Red: "RED", Green: "GREEN"}),
+//	)
+//
+// Only values passed directly, e.g. as workflow or activity arguments and
+// results, are converted. Enums in struct fields, slices or maps are encoded
+// by the parent as usual; implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler on the enum type for those. Encoding a value
+// without a name and decoding an unknown name fail.
+func NewEnumConverter(parent DataConverter, enums ...EnumNames) DataConverter {
+	c := &EnumConverter{parent: parent, enums: make(map[reflect.Type]EnumNames, len(enums))}
+	for _, e := range enums {
+		if _, ok := c.enums[e.enumType()]; ok {
+			panic(fmt.Sprintf("enum %v is registered more than once", e.enumType()))
+		}
+		c.enums[e.enumType()] = e
+	}
+	return c
+}
+
+// ToPayload implements DataConverter.ToPayload encoding registered enums as
+// their names.
+func (c *EnumConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
+	value, err := c.toName(value)
+	if err != nil {
+		return nil, err
+	}
+	return c.parent.ToPayload(value)
+}
+
+// ToPayloads implements DataConverter.ToPayloads encoding registered enums as
+// their names.
+func (c *EnumConverter) ToPayloads(values ...interface{}) (*commonpb.Payloads, error) {
+	named := make([]interface{}, len(values))
+	for i, value := range values {
+		var err error
+		if named[i], err = c.toName(value); err != nil {
+			return nil, fmt.Errorf("values[%d]: %w", i, err)
+		}
+	}
+	return c.parent.ToPayloads(named...)
+}
+
+// FromPayload implements DataConverter.FromPayload decoding names into
+// registered enums.
+func (c *EnumConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
+	e := c.enumOf(valuePtr)
+	if e == nil {
+		return c.parent.FromPayload(payload, valuePtr)
+	}
+	var name string
+	if err := c.parent.FromPayload(payload, &name); err != nil {
+		return err
+	}
+	return setEnum(e, name, valuePtr)
+}
+
+// FromPayloads implements DataConverter.FromPayloads decoding names into
+// registered enums.
+func (c *EnumConverter) FromPayloads(payloads *commonpb.Payloads, valuePtrs ...interface{}) error {
+	ptrs := make([]interface{}, len(valuePtrs))
+	names := make([]string, len(valuePtrs))
+	for i, valuePtr := range valuePtrs {
+		ptrs[i] = valuePtr
+		if c.enumOf(valuePtr) != nil {
+			ptrs[i] = &names[i]
+		}
+	}
+	if err := c.parent.FromPayloads(payloads, ptrs...); err != nil {
+		return err
+	}
+	for i, valuePtr := range valuePtrs {
+		if i >= len(payloads.GetPayloads()) {
+			break
+		}
+		if e := c.enumOf(valuePtr); e != nil {
+			if err := setEnum(e, names[i], valuePtr); err != nil {
+				return fmt.Errorf("payload item %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ToString implements DataConverter.ToString using the parent ToString.
+func (c *EnumConverter) ToString(payload *commonpb.Payload) string {
+	return c.parent.ToString(payload)
+}
+
+// ToStrings implements DataConverter.ToStrings using the parent ToStrings.
+func (c *EnumConverter) ToStrings(payloads *commonpb.Payloads) []string {
+	return c.parent.ToStrings(payloads)
+}
+
+func (c *EnumConverter) toName(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(value)
+	e, ok := c.enums[v.Type()]
+	if !ok {
+		return value, nil
+	}
+	name, ok := e.name(v)
+	if !ok {
+		return nil, fmt.Errorf("%w: value %v of enum %T has no name", ErrUnableToEncode, value, value)
+	}
+	return name, nil
+}
+
+func (c *EnumConverter) enumOf(valuePtr interface{}) EnumNames {
+	t := reflect.TypeOf(valuePtr)
+	if t == nil || t.Kind() != reflect.Ptr || reflect.ValueOf(valuePtr).IsNil() {
+		return nil
+	}
+	return c.enums[t.Elem()]
+}
+
+func setEnum(e EnumNames, name string, valuePtr interface{}) error {
+	value, ok := e.value(name)
+	if !ok {
+		return fmt.Errorf("%w: unknown name %q of enum %v", ErrUnableToDecode, name, e.enumType())
+	}
+	reflect.ValueOf(valuePtr).Elem().Set(value)
+	return nil
+}
@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+type enumTestColor int
+
+const (
+	enumTestRed enumTestColor = iota
+	enumTestGreen
+	enumTestBlue
+)
+
+func newEnumTestConverter() DataConverter {
+	return NewEnumConverter(
+		GetDefaultDataConverter(),
+		NewEnumNames(map[enumTestColor]string{enumTestRed: "RED", enumTestGreen: "GREEN"}),
+	)
+}
+
+func TestEnumConverter_RoundTrip(t *testing.T) {
+	dc := newEnumTestConverter()
+
+	payload, err := dc.ToPayload(enumTestGreen)
+	require.NoError(t, err)
+	require.Equal(t, `"GREEN"`, string(payload.GetData()))
+	var color enumTestColor
+	require.NoError(t, dc.FromPayload(payload, &color))
+	require.Equal(t, enumTestGreen, color)
+
+	// The names stay stable when the values of the enum are renumbered
+	renumbered := NewEnumConverter(
+		GetDefaultDataConverter(),
+		NewEnumNames(map[enumTestColor]string{5: "RED", 7: "GREEN"}),
+	)
+	require.NoError(t, renumbered.FromPayload(payload, &color))
+	require.Equal(t, enumTestColor(7), color)
+
+	payloads, err := dc.ToPayloads("unrelated", enumTestRed, 42)
+	require.NoError(t, err)
+	require.Equal(t, []string{`"unrelated"`, `"RED"`, "42"}, dc.ToStrings(payloads))
+	var (
+		s string
+		n int
+	)
+	require.NoError(t, dc.FromPayloads(payloads, &s, &color, &n))
+	require.Equal(t, "unrelated", s)
+	require.Equal(t, enumTestRed, color)
+	require.Equal(t, 42, n)
+}
+
+func TestEnumConverter_Errors(t *testing.T) {
+	dc := newEnumTestConverter()
+
+	_, err := dc.ToPayload(enumTestBlue)
+	require.ErrorIs(t, err, ErrUnableToEncode)
+	_, err = dc.ToPayloads(enumTestBlue)
+	require.ErrorIs(t, err, ErrUnableToEncode)
+
+	payload, err := dc.ToPayload("PURPLE")
+	require.NoError(t, err)
+	var color enumTestColor
+	err = dc.FromPayload(payload, &color)
+	require.ErrorIs(t, err, ErrUnableToDecode)
+	require.ErrorContains(t, err, `unknown name "PURPLE"`)
+	err = dc.FromPayloads(&commonpb.Payloads{Payloads: []*commonpb.Payload{payload}}, &color)
+	require.ErrorIs(t, err, ErrUnableToDecode)
+
+	require.Panics(t, func() {
+		NewEnumNames(map[enumTestColor]string{enumTestRed: "RED", enumTestGreen: "RED"})
+	})
+	require.Panics(t, func() {
+		NewEnumNames(map[enumTestColor]string{enumTestRed: ""})
+	})
+	require.Panics(t, func() {
+		names := NewEnumNames(map[enumTestColor]string{enumTestRed: "RED"})
+		NewEnumConverter(GetDefaultDataConverter(), names, names)
+	})
+}
@@ -27,4 +27,8 @@ var (
 	ErrValuePtrMustConcreteType = errors.New("must be a concrete type, not interface")
 	// ErrTypeIsNotByteSlice is returned when value is not of *[]byte type.
 	ErrTypeIsNotByteSlice = errors.New("type is not *[]byte")
+	// ErrTooManyPayloads is returned when more payloads are encoded together than allowed.
+	ErrTooManyPayloads = errors.New("too many payloads")
+	// ErrTranscodingNotLossless is returned when a transcoded payload does not decode to the original value.
+	ErrTranscodingNotLossless = errors.New("transcoding is not lossless")
 )
@@ -0,0 +1,360 @@
+package converter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// MetadataRedactedFields is the payload metadata key holding the encrypted values of redacted fields.
+const MetadataRedactedFields = "redacted-fields"
+
+// FieldRedactingConverterOptions are options for NewFieldRedactingConverter. All fields are optional.
+type FieldRedactingConverterOptions struct {
+	// Key is an AES key of 16, 24 or 32 bytes used to encrypt the values of sensitive fields into the payload
+	// metadata so they can be restored on decode. If not set, the values are dropped.
+	Key []byte
+
+	// Placeholder replaces the value of sensitive string fields. Other fields are set to their zero value.
+	// Default: "[REDACTED]"
+	Placeholder string
+}
+
+// FieldRedactingConverter is a DataConverter that wraps an underlying data
+// converter and redacts struct fields tagged with `temporal:"sensitive"`
+// before encoding.
+type FieldRedactingConverter struct {
+	parent      DataConverter
+	placeholder string
+	aead        cipher.AEAD
+	keyErr      error
+}
+
+// NewFieldRedactingConverter wraps the given parent DataConverter so that
+// struct fields tagged with `temporal:"sensitive"` are redacted in payloads,
+// e.g. in workflow history, while the values stay usable in-process.
+//
+// On encode, sensitive fields are replaced with a placeholder for strings or
+// the zero value for other types. Fields of nested structs, pointers to
+// structs, and slices or arrays of them are redacted too. Fields inside maps
+// and interfaces are not. The original value is never modified, and values
+// referencing themselves fail to encode.
+//
+// If a key is set, the values of the sensitive fields are encrypted into the
+// payload metadata and restored on decode by a converter with the same key.
+// Without a key, redaction is lossy: decoded values contain the placeholders.
+// Unlike encrypting whole payloads with a PayloadCodec, the rest of the
+// payload stays readable, e.g. in the UI, at the cost of leaking the shape of
+// the data and which fields are sensitive.
+func NewFieldRedactingConverter(parent DataConverter, options FieldRedactingConverterOptions) DataConverter {
+	c := &FieldRedactingConverter{parent: parent, placeholder: options.Placeholder}
+	if c.placeholder == "" {
+		c.placeholder = "[REDACTED]"
+	}
+	if options.Key != nil {
+		block, err := aes.NewCipher(options.Key)
+		if err == nil {
+			c.aead, err = cipher.NewGCM(block)
+		}
+		if err != nil {
+			c.keyErr = fmt.Errorf("invalid redaction key: %w", err)
+		}
+	}
+	return c
+}
+
+// ToPayload implements DataConverter.ToPayload redacting sensitive fields of
+// the value before sending it to the parent ToPayload.
+func (c *FieldRedactingConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
+	if c.keyErr != nil {
+		return nil, c.keyErr
+	}
+	redacted, secrets, err := c.redact(value)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := c.parent.ToPayload(redacted)
+	if err != nil || payload == nil {
+		return payload, err
+	}
+	return payload, c.addSecrets(payload, secrets)
+}
+
+// ToPayloads implements DataConverter.ToPayloads redacting sensitive fields of
+// the values before sending them to the parent ToPayloads.
+func (c *FieldRedactingConverter) ToPayloads(values ...interface{}) (*commonpb.Payloads, error) {
+	if c.keyErr != nil {
+		return nil, c.keyErr
+	}
+	redacted := make([]interface{}, len(values))
+	secrets := make([]map[string]json.RawMessage, len(values))
+	for i, value := range values {
+		var err error
+		if redacted[i], secrets[i], err = c.redact(value); err != nil {
+			return nil, err
+		}
+	}
+	payloads, err := c.parent.ToPayloads(redacted...)
+	if err != nil || payloads == nil {
+		return payloads, err
+	}
+	for i, payload := range payloads.Payloads {
+		if i < len(secrets) {
+			if err := c.addSecrets(payload, secrets[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return payloads, nil
+}
+
+// FromPayload implements DataConverter.FromPayload restoring redacted fields
+// after the parent FromPayload if the payload holds their encrypted values.
+func (c *FieldRedactingConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
+	if err := c.parent.FromPayload(payload, valuePtr); err != nil {
+		return err
+	}
+	return c.restore(payload, valuePtr)
+}
+
+// FromPayloads implements DataConverter.FromPayloads restoring redacted fields
+// after the parent FromPayloads if the payloads hold their encrypted values.
+func (c *FieldRedactingConverter) FromPayloads(payloads *commonpb.Payloads, valuePtrs ...interface{}) error {
+	if err := c.parent.FromPayloads(payloads, valuePtrs...); err != nil {
+		return err
+	}
+	for i, payload := range payloads.GetPayloads() {
+		if i >= len(valuePtrs) {
+			break
+		}
+		if err := c.restore(payload, valuePtrs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToString implements DataConverter.ToString using the parent ToString, so
+// sensitive fields stay redacted.
+func (c *FieldRedactingConverter) ToString(payload *commonpb.Payload) string {
+	return c.parent.ToString(payload)
+}
+
+// ToStrings implements DataConverter.ToStrings using the parent ToStrings, so
+// sensitive fields stay redacted.
+func (c *FieldRedactingConverter) ToStrings(payloads *commonpb.Payloads) []string {
+	return c.parent.ToStrings(payloads)
+}
+
+func (c *FieldRedactingConverter) redact(value interface{}) (interface{}, map[string]json.RawMessage, error) {
+	if value == nil {
+		return nil, nil, nil
+	}
+	secrets := map[string]json.RawMessage{}
+	redacted, changed, err := c.redactValue(reflect.ValueOf(value), "", secrets, map[redactedRef]bool{})
+	if err != nil || !changed {
+		return value, nil, err
+	}
+	return redacted.Interface(), secrets, nil
+}
+
+// redactedRef identifies a pointer or slice visited by redactValue.
+type redactedRef struct {
+	t   reflect.Type
+	ptr uintptr
+}
+
+// redactValue returns a copy of v with sensitive fields redacted, or v itself if there is nothing to redact. visited
+// holds the pointers and slices on the path to v, to fail on self-referential values instead of recursing forever.
+func (c *FieldRedactingConverter) redactValue(
+	v reflect.Value,
+	path string,
+	secrets map[string]json.RawMessage,
+	visited map[redactedRef]bool,
+) (reflect.Value, bool, error) {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Slice) && !v.IsNil() {
+		ref := redactedRef{t: v.Type(), ptr: v.Pointer()}
+		if visited[ref] {
+			return v, false, fmt.Errorf("unable to redact field %v: value contains a cycle", path)
+		}
+		visited[ref] = true
+		defer delete(visited, ref)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, false, nil
+		}
+		elem, changed, err := c.redactValue(v.Elem(), path, secrets, visited)
+		if err != nil || !changed {
+			return v, false, err
+		}
+		ptr := reflect.New(elem.Type())
+		ptr.Elem().Set(elem)
+		return ptr, true, nil
+	case reflect.Struct:
+		var result reflect.Value
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinRedactedFieldPath(path, field.Name)
+			var newValue reflect.Value
+			if isSensitiveField(field) {
+				raw, err := json.Marshal(v.Field(i).Interface())
+				if err != nil {
+					return v, false, fmt.Errorf("unable to redact field %v: %w", fieldPath, err)
+				}
+				secrets[fieldPath] = raw
+				newValue = reflect.New(field.Type).Elem()
+				if field.Type.Kind() == reflect.String {
+					newValue.SetString(c.placeholder)
+				}
+			} else {
+				var changed bool
+				var err error
+				if newValue, changed, err = c.redactValue(v.Field(i), fieldPath, secrets, visited); err != nil {
+					return v, false, err
+				} else if !changed {
+					continue
+				}
+			}
+			if !result.IsValid() {
+				result = reflect.New(t).Elem()
+				result.Set(v)
+			}
+			result.Field(i).Set(newValue)
+		}
+		if !result.IsValid() {
+			return v, false, nil
+		}
+		return result, true, nil
+	case reflect.Slice, reflect.Array:
+		var result reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			elem, changed, err := c.redactValue(v.Index(i), joinRedactedFieldPath(path, strconv.Itoa(i)), secrets, visited)
+			if err != nil {
+				return v, false, err
+			} else if !changed {
+				continue
+			}
+			if !result.IsValid() {
+				if v.Kind() == reflect.Slice {
+					result = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+					reflect.Copy(result, v)
+				} else {
+					result = reflect.New(v.Type()).Elem()
+					result.Set(v)
+				}
+			}
+			result.Index(i).Set(elem)
+		}
+		if !result.IsValid() {
+			return v, false, nil
+		}
+		return result, true, nil
+	default:
+		return v, false, nil
+	}
+}
+
+func (c *FieldRedactingConverter) addSecrets(payload *commonpb.Payload, secrets map[string]json.RawMessage) error {
+	if c.aead == nil || len(secrets) == 0 {
+		return nil
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	if payload.Metadata == nil {
+		payload.Metadata = map[string][]byte{}
+	}
+	payload.Metadata[MetadataRedactedFields] = c.aead.Seal(nonce, nonce, plaintext, nil)
+	return nil
+}
+
+func (c *FieldRedactingConverter) restore(payload *commonpb.Payload, valuePtr interface{}) error {
+	ciphertext, ok := payload.GetMetadata()[MetadataRedactedFields]
+	if !ok || c.aead == nil {
+		return nil
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return fmt.Errorf("%w: redacted fields metadata is too short", ErrUnableToDecode)
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return fmt.Errorf("%w: unable to decrypt redacted fields: %v", ErrUnableToDecode, err)
+	}
+	var secrets map[string]json.RawMessage
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnableToDecode, err)
+	}
+	for path, raw := range secrets {
+		field, ok := findRedactedField(reflect.ValueOf(valuePtr), path)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("%w: unable to restore field %v: %v", ErrUnableToDecode, path, err)
+		}
+	}
+	return nil
+}
+
+// findRedactedField returns the settable value at the given path, if it exists in v.
+func findRedactedField(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(segment)
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i >= v.Len() {
+				return v, false
+			}
+			v = v.Index(i)
+		default:
+			return v, false
+		}
+		if !v.IsValid() {
+			return v, false
+		}
+	}
+	return v, v.CanSet()
+}
+
+func isSensitiveField(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("temporal"), ",") {
+		if option == "sensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+func joinRedactedFieldPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type redactingTestCard struct {
+	Number string `temporal:"sensitive"`
+	CVV    int    `json:"cvv" temporal:"sensitive"`
+	Brand  string
+}
+
+type redactingTestCustomer struct {
+	Name     string
+	SSN      string `temporal:"sensitive"`
+	Card     *redactingTestCard
+	Previous []redactingTestCard
+}
+
+func newRedactingTestCustomer() *redactingTestCustomer {
+	return &redactingTestCustomer{
+		Name: "Jane",
+		SSN:  "123-45-6789",
+		Card: &redactingTestCard{Number: "4111", CVV: 123, Brand: "visa"},
+		Previous: []redactingTestCard{
+			{Number: "5500", CVV: 456, Brand: "mastercard"},
+		},
+	}
+}
+
+func TestFieldRedactingConverter(t *testing.T) {
+	require := require.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	conv := NewFieldRedactingConverter(GetDefaultDataConverter(), FieldRedactingConverterOptions{Key: key})
+	customer := newRedactingTestCustomer()
+
+	payload, err := conv.ToPayload(customer)
+	require.NoError(err)
+	// Original is not modified
+	require.Equal(newRedactingTestCustomer(), customer)
+	require.NotContains(string(payload.Data), "123-45-6789")
+	require.NotContains(string(payload.Data), "4111")
+	require.NotContains(string(payload.Data), "5500")
+	require.Contains(string(payload.Data), "[REDACTED]")
+	require.Contains(string(payload.Data), "mastercard")
+
+	// Decoding without the converter gives the redacted value
+	var redacted redactingTestCustomer
+	require.NoError(GetDefaultDataConverter().FromPayload(payload, &redacted))
+	require.Equal("[REDACTED]", redacted.SSN)
+	require.Equal("[REDACTED]", redacted.Card.Number)
+	require.Zero(redacted.Card.CVV)
+	require.Equal("[REDACTED]", redacted.Previous[0].Number)
+
+	// Decoding with the key restores the value
+	var restored redactingTestCustomer
+	require.NoError(conv.FromPayload(payload, &restored))
+	require.Equal(*customer, restored)
+
+	// As do multiple payloads
+	payloads, err := conv.ToPayloads("plain", customer)
+	require.NoError(err)
+	var plain string
+	restored = redactingTestCustomer{}
+	require.NoError(conv.FromPayloads(payloads, &plain, &restored))
+	require.Equal("plain", plain)
+	require.Equal(*customer, restored)
+
+	// A different key can't restore
+	otherConv := NewFieldRedactingConverter(GetDefaultDataConverter(), FieldRedactingConverterOptions{Key: []byte("fedcba9876543210")})
+	require.ErrorIs(otherConv.FromPayload(payload, &restored), ErrUnableToDecode)
+}
+
+func TestFieldRedactingConverterWithoutKey(t *testing.T) {
+	require := require.New(t)
+	conv := NewFieldRedactingConverter(GetDefaultDataConverter(), FieldRedactingConverterOptions{Placeholder: "***"})
+
+	payload, err := conv.ToPayload(*newRedactingTestCustomer())
+	require.NoError(err)
+	require.NotContains(payload.Metadata, MetadataRedactedFields)
+
+	// Redaction without a key is lossy
+	var decoded redactingTestCustomer
+	require.NoError(conv.FromPayload(payload, &decoded))
+	require.Equal("***", decoded.SSN)
+	require.Equal("***", decoded.Card.Number)
+	require.Equal("Jane", decoded.Name)
+
+	// Values without sensitive fields are unaffected
+	payload, err = conv.ToPayload("value")
+	require.NoError(err)
+	var value string
+	require.NoError(conv.FromPayload(payload, &value))
+	require.Equal("value", value)
+}
+
+func TestFieldRedactingConverterInvalidKey(t *testing.T) {
+	conv := NewFieldRedactingConverter(GetDefaultDataConverter(), FieldRedactingConverterOptions{Key: []byte("short")})
+	_, err := conv.ToPayload(newRedactingTestCustomer())
+	require.ErrorContains(t, err, "invalid redaction key")
+}
+
+type redactingTestNode struct {
+	Secret string `temporal:"sensitive"`
+	Next   *redactingTestNode
+}
+
+func TestFieldRedactingConverterCycle(t *testing.T) {
+	conv := NewFieldRedactingConverter(GetDefaultDataConverter(), FieldRedactingConverterOptions{})
+	node := &redactingTestNode{Secret: "secret"}
+	node.Next = node
+	_, err := conv.ToPayload(node)
+	require.ErrorContains(t, err, "unable to redact field Next: value contains a cycle")
+
+	// Values shared without a cycle are redacted
+	shared := &redactingTestCard{Number: "4111"}
+	payload, err := conv.ToPayload([]*redactingTestCard{shared, shared})
+	require.NoError(t, err)
+	var cards []*redactingTestCard
+	require.NoError(t, conv.FromPayload(payload, &cards))
+	require.Equal(t, "[REDACTED]", cards[0].Number)
+	require.Equal(t, "[REDACTED]", cards[1].Number)
+	require.Equal(t, "4111", shared.Number)
+}
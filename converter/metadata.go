@@ -1,5 +1,9 @@
 package converter
 
+import (
+	commonpb "go.temporal.io/api/common/v1"
+)
+
 const (
 	// MetadataEncoding is "encoding"
 	MetadataEncoding = "encoding"
@@ -16,3 +20,22 @@ const (
 	// MetadataEncodingProto is "binary/protobuf"
 	MetadataEncodingProto = "binary/protobuf"
 )
+
+// PayloadMetadata returns a copy of the payload's metadata as strings, e.g. to inspect how a payload was encoded
+// without decoding it. Returns nil for a nil payload.
+func PayloadMetadata(p *commonpb.Payload) map[string]string {
+	if p == nil {
+		return nil
+	}
+	metadata := make(map[string]string, len(p.GetMetadata()))
+	for k, v := range p.GetMetadata() {
+		metadata[k] = string(v)
+	}
+	return metadata
+}
+
+// PayloadEncoding returns the value of the payload's "encoding" metadata, e.g. [MetadataEncodingJSON]. Returns an
+// empty string if the payload is nil or has no encoding.
+func PayloadEncoding(p *commonpb.Payload) string {
+	return string(p.GetMetadata()[MetadataEncoding])
+}
@@ -528,3 +528,18 @@ func TestJsonPayloadConverter_FromPayload_Errors(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "qwe", wt7.(map[string]interface{})["Name"])
 }
+
+func TestPayloadMetadata(t *testing.T) {
+	payload, err := NewJSONPayloadConverter().ToPayload(testStruct{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, MetadataEncodingJSON, PayloadEncoding(payload))
+	assert.Equal(t, map[string]string{MetadataEncoding: MetadataEncodingJSON}, PayloadMetadata(payload))
+
+	// The returned metadata is a copy
+	PayloadMetadata(payload)[MetadataEncoding] = "changed"
+	assert.Equal(t, MetadataEncodingJSON, PayloadEncoding(payload))
+
+	assert.Nil(t, PayloadMetadata(nil))
+	assert.Empty(t, PayloadEncoding(nil))
+	assert.Empty(t, PayloadEncoding(&commonpb.Payload{}))
+}
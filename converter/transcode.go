@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"fmt"
+	"reflect"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// TranscodeOptions are options for TranscodePayloadWithOptions and TranscodePayloadsWithOptions.
+type TranscodeOptions struct {
+	// NewValuePtr returns a pointer to a new value to decode a payload into. Converters that can only decode into
+	// concrete types, like the protobuf converters, need it.
+	//
+	// Optional: defaults to a pointer to an empty interface.
+	NewValuePtr func() interface{}
+	// Verify decodes each transcoded payload again with the converter it was encoded with and returns
+	// ErrTranscodingNotLossless if the value differs from the one decoded from the original payload.
+	Verify bool
+}
+
+// TranscodePayload decodes the payload with the from converter and encodes the decoded value with the to converter,
+// e.g. to migrate payloads of histories from one encoding to another. It returns an error with
+// ErrEncodingIsNotSupported if the payload was not encoded by from.
+//
+// Transcoding is only valid if the decoded value round-trips losslessly: a value decoded into an empty interface loses
+// the type it was encoded from, so e.g. JSON numbers become float64 and JSON objects become maps. Use
+// TranscodePayloadWithOptions to decode into a concrete type or to verify the transcoded payload.
+func TranscodePayload(payload *commonpb.Payload, from, to PayloadConverter) (*commonpb.Payload, error) {
+	return TranscodePayloadWithOptions(payload, from, to, TranscodeOptions{})
+}
+
+// TranscodePayloads transcodes each payload of the aggregate payloads like TranscodePayload.
+func TranscodePayloads(payloads *commonpb.Payloads, from, to PayloadConverter) (*commonpb.Payloads, error) {
+	return TranscodePayloadsWithOptions(payloads, from, to, TranscodeOptions{})
+}
+
+// TranscodePayloadWithOptions is TranscodePayload with options.
+func TranscodePayloadWithOptions(
+	payload *commonpb.Payload,
+	from, to PayloadConverter,
+	options TranscodeOptions,
+) (*commonpb.Payload, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	if enc := string(payload.GetMetadata()[MetadataEncoding]); enc != "" && enc != from.Encoding() {
+		return nil, fmt.Errorf("encoding %s instead of %s: %w", enc, from.Encoding(), ErrEncodingIsNotSupported)
+	}
+	valuePtr := newTranscodeValuePtr(options)
+	if err := from.FromPayload(payload, valuePtr); err != nil {
+		return nil, err
+	}
+	value := reflect.ValueOf(valuePtr).Elem().Interface()
+	transcoded, err := to.ToPayload(value)
+	if err != nil {
+		return nil, err
+	}
+	if transcoded == nil {
+		return nil, fmt.Errorf("value of type %T: %w", value, ErrUnableToEncode)
+	}
+	if options.Verify {
+		verifyPtr := newTranscodeValuePtr(options)
+		if err := to.FromPayload(transcoded, verifyPtr); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrTranscodingNotLossless, err)
+		}
+		if !transcodedValueEqual(value, reflect.ValueOf(verifyPtr).Elem().Interface()) {
+			return nil, fmt.Errorf("value of type %T: %w", value, ErrTranscodingNotLossless)
+		}
+	}
+	return transcoded, nil
+}
+
+// TranscodePayloadsWithOptions is TranscodePayloads with options.
+func TranscodePayloadsWithOptions(
+	payloads *commonpb.Payloads,
+	from, to PayloadConverter,
+	options TranscodeOptions,
+) (*commonpb.Payloads, error) {
+	if payloads == nil {
+		return nil, nil
+	}
+	result := &commonpb.Payloads{Payloads: make([]*commonpb.Payload, len(payloads.Payloads))}
+	for i, payload := range payloads.Payloads {
+		transcoded, err := TranscodePayloadWithOptions(payload, from, to, options)
+		if err != nil {
+			return nil, fmt.Errorf("payload item %d: %w", i, err)
+		}
+		result.Payloads[i] = transcoded
+	}
+	return result, nil
+}
+
+func newTranscodeValuePtr(options TranscodeOptions) interface{} {
+	if options.NewValuePtr != nil {
+		return options.NewValuePtr()
+	}
+	return new(interface{})
+}
+
+func transcodedValueEqual(expected, actual interface{}) bool {
+	if expectedMsg, ok := expected.(proto.Message); ok {
+		if actualMsg, ok := actual.(proto.Message); ok {
+			return proto.Equal(expectedMsg, actualMsg)
+		}
+	}
+	return reflect.DeepEqual(expected, actual)
+}
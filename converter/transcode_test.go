@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// stringPayloadConverter encodes any value as its fmt representation, losing its type.
+type stringPayloadConverter struct{}
+
+func (stringPayloadConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
+	return &commonpb.Payload{
+		Metadata: map[string][]byte{MetadataEncoding: []byte("text/plain")},
+		Data:     []byte(fmt.Sprint(value)),
+	}, nil
+}
+
+func (stringPayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
+	*valuePtr.(*interface{}) = string(payload.GetData())
+	return nil
+}
+
+func (stringPayloadConverter) ToString(payload *commonpb.Payload) string {
+	return string(payload.GetData())
+}
+
+func (stringPayloadConverter) Encoding() string { return "text/plain" }
+
+func TestTranscodePayload(t *testing.T) {
+	protoJSON := NewProtoJSONPayloadConverter()
+	protoBinary := NewProtoPayloadConverter()
+	execution := &commonpb.WorkflowExecution{WorkflowId: "workflow-id", RunId: "run-id"}
+	payload, err := protoJSON.ToPayload(execution)
+	require.NoError(t, err)
+
+	// Protobuf messages need a concrete type to decode into
+	options := TranscodeOptions{
+		NewValuePtr: func() interface{} { return new(*commonpb.WorkflowExecution) },
+		Verify:      true,
+	}
+	transcoded, err := TranscodePayloadWithOptions(payload, protoJSON, protoBinary, options)
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingProto, string(transcoded.Metadata[MetadataEncoding]))
+	var decoded commonpb.WorkflowExecution
+	require.NoError(t, protoBinary.FromPayload(transcoded, &decoded))
+	require.True(t, proto.Equal(execution, &decoded))
+
+	// The payload must be encoded by the from converter
+	_, err = TranscodePayloadWithOptions(payload, protoBinary, protoJSON, options)
+	require.ErrorIs(t, err, ErrEncodingIsNotSupported)
+}
+
+func TestTranscodePayloads(t *testing.T) {
+	jsonConverter := NewJSONPayloadConverter()
+	first, err := jsonConverter.ToPayload(map[string]interface{}{"Name": "John"})
+	require.NoError(t, err)
+	second, err := jsonConverter.ToPayload("Doe")
+	require.NoError(t, err)
+	payloads := &commonpb.Payloads{Payloads: []*commonpb.Payload{first, second}}
+
+	transcoded, err := TranscodePayloads(payloads, jsonConverter, stringPayloadConverter{})
+	require.NoError(t, err)
+	require.Len(t, transcoded.Payloads, 2)
+	require.Equal(t, "map[Name:John]", string(transcoded.Payloads[0].Data))
+	require.Equal(t, "Doe", string(transcoded.Payloads[1].Data))
+
+	// Verification catches values that do not round-trip
+	_, err = TranscodePayloadsWithOptions(payloads, jsonConverter, stringPayloadConverter{}, TranscodeOptions{Verify: true})
+	require.ErrorIs(t, err, ErrTranscodingNotLossless)
+	require.ErrorContains(t, err, "payload item 0")
+
+	// Converters that cannot encode the value fail
+	_, err = TranscodePayloads(payloads, jsonConverter, NewByteSlicePayloadConverter())
+	require.ErrorIs(t, err, ErrUnableToEncode)
+}
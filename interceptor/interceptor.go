@@ -42,6 +42,48 @@ type WorkerInterceptor = internal.WorkerInterceptor
 // handle future changes.
 type WorkerInterceptorBase = internal.WorkerInterceptorBase
 
+// WorkerLifecycleInterceptor is an interface for intercepting the start and
+// stop of a worker, e.g. to register it with service discovery or to flush
+// telemetry. It is created once per worker by
+// WorkerInterceptor.InterceptWorkerLifecycle.
+//
+// Interceptors are called in order: Start of the first interceptor is called
+// first and the worker starts polling when the last one calls Start on the
+// next interceptor, and likewise for Stop. Returning an error from Start aborts
+// startup and the error is returned from Worker.Start or Worker.Run.
+//
+// Stop is only called through Worker.Stop, which Worker.Run and fatal errors
+// also call. Panics in Start or Stop are not recovered. If the process panics,
+// Stop is not called unless Worker.Stop is deferred in the panicking
+// goroutine.
+//
+// All implementations must embed [WorkerLifecycleInterceptorBase] to safely
+// handle future changes.
+type WorkerLifecycleInterceptor = internal.WorkerLifecycleInterceptor
+
+// WorkerLifecycleInterceptorBase is a default implementation of
+// [WorkerLifecycleInterceptor] that forwards calls to the next interceptor.
+//
+// This must be embedded into all [WorkerLifecycleInterceptor] implementations
+// to safely handle future changes.
+type WorkerLifecycleInterceptorBase = internal.WorkerLifecycleInterceptorBase
+
+// WorkerLifecycleInfo describes the worker for [WorkerLifecycleInterceptor]
+// calls.
+type WorkerLifecycleInfo = internal.WorkerLifecycleInfo
+
+// WorkerInterceptorChain assembles worker interceptors in an explicit, named
+// order. Interceptors can be appended, prepended or inserted before or after
+// another interceptor by name, and Build returns the slice to use as
+// worker.Options.Interceptors, where earlier interceptors wrap later ones.
+// Build fails on duplicate names or positions relative to unknown names.
+type WorkerInterceptorChain = internal.WorkerInterceptorChain
+
+// NewWorkerInterceptorChain creates an empty [WorkerInterceptorChain].
+func NewWorkerInterceptorChain() *WorkerInterceptorChain {
+	return internal.NewWorkerInterceptorChain()
+}
+
 // ActivityInboundInterceptor is an interface for all activity calls originating
 // from the server. Implementers wanting to intercept outbound (i.e. from SDK)
 // activity calls, can change the outbound interceptor in Init before the next
@@ -3,6 +3,8 @@ package internal
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	commonpb "go.temporal.io/api/common/v1"
@@ -58,6 +60,10 @@ type (
 		// when scheduling the activity. If the value is nil, it means the server didn't send information about
 		// retry policy (e.g. due to old server version), but it may still be defined server-side.
 		RetryPolicy *RetryPolicy
+		// How long the latest attempt of the activity waited in the task queue before being started, computed from
+		// the scheduled and started times of that attempt. It is zero if either time is unknown or if clock skew
+		// makes the start appear to precede the schedule.
+		ScheduleToStartLatency time.Duration
 	}
 
 	// RegisterActivityOptions consists of options for registering an activity.
@@ -78,6 +84,50 @@ type (
 		// When registering a struct with activities, skip functions that are not valid activities. If false,
 		// registration panics.
 		SkipInvalidStructFunctions bool
+
+		// Optional: Additional activity type names this activity is registered under, e.g. to keep an old name
+		// resolving during a rename. Each alias is subject to the same already-registered check as the primary name.
+		// The activity keeps reporting its primary name as its type. Not supported when registering a struct.
+		Aliases []string
+
+		// Optional: Prefix prepended to the activity type name. Unlike Name, it applies the same way to functions and
+		// structs, so the same options can be used to register all activities of a group, e.g. with RegisterActivities.
+		// A struct method is registered as Prefix + Name + method name, and a function as Prefix + Name, or
+		// Prefix + function name if Name is empty. Aliases are registered as given.
+		Prefix string
+
+		// Optional: When registering a struct, the names of methods not to register as activities. An excluded method
+		// can still be registered individually with its own options, e.g. to give it a name that does not follow the
+		// struct's prefix.
+		ExcludeMethods []string
+
+		// Optional: Default timeouts and retry policy for the activity, applied when a workflow running on this
+		// worker schedules the activity without setting them. This keeps the SLAs of an activity next to its
+		// implementation. When registering a struct, they apply to each of its activities.
+		//
+		// Options set on the workflow context, including the ones a workflow sets for all of its activities with
+		// WithActivityOptions, always take precedence: a default only fills in an option left zero, and the server
+		// defaults apply to options still unset after that. As the workflow schedules the activity, the defaults
+		// are taken from the registration on the worker running the workflow, so they only apply if the activity is
+		// also registered there. Changing them does not affect activities already scheduled.
+		DefaultOptions *ActivityDefaultOptions
+	}
+
+	// ActivityDefaultOptions are the default options of an activity type set at registration, see
+	// RegisterActivityOptions.DefaultOptions. A zero value leaves the corresponding option unset.
+	//
+	// Exposed as: [go.temporal.io/sdk/activity.DefaultOptions]
+	ActivityDefaultOptions struct {
+		// ScheduleToCloseTimeout - Default of ActivityOptions.ScheduleToCloseTimeout.
+		ScheduleToCloseTimeout time.Duration
+		// ScheduleToStartTimeout - Default of ActivityOptions.ScheduleToStartTimeout.
+		ScheduleToStartTimeout time.Duration
+		// StartToCloseTimeout - Default of ActivityOptions.StartToCloseTimeout.
+		StartToCloseTimeout time.Duration
+		// HeartbeatTimeout - Default of ActivityOptions.HeartbeatTimeout.
+		HeartbeatTimeout time.Duration
+		// RetryPolicy - Default of ActivityOptions.RetryPolicy.
+		RetryPolicy *RetryPolicy
 	}
 
 	// ActivityOptions stores all activity-specific parameters that will be stored inside of a context.
@@ -123,6 +173,12 @@ type (
 		// WaitForCancellation - Whether to wait for canceled activity to be completed(
 		// activity can be failed, completed, cancel accepted)
 		//
+		// An activity accepts the cancellation by returning a CanceledError once its context is done. It can report
+		// how far it got by passing details to NewCanceledError, which the workflow then reads from the error of the
+		// activity future with GetCanceledErrorDetails, e.g. to decide what to compensate. This is only possible when
+		// waiting for cancellation: otherwise the future is resolved as soon as the cancellation is requested and the
+		// final details of the activity are dropped. Activities returning ctx.Err() report no details.
+		//
 		// Optional: default false
 		WaitForCancellation bool
 
@@ -171,6 +227,41 @@ type (
 		//
 		// WARNING: Task queue priority is currently experimental.
 		Priority Priority
+
+		// NonRetryableDecodeError makes the error returned from Future.Get when the result of the activity can't be
+		// decoded into the provided value, which wraps a [DecodeError], a non-retryable application error. A workflow
+		// that returns it then fails without being retried by its retry policy. By default it is retryable, so e.g. a
+		// workflow started with a retry policy can be retried after a fix to the types is deployed.
+		//
+		// Branching on the error, e.g. with errors.As, is deterministic as long as decoding is: changing the data
+		// converter or the result type while workflows are running can change which branch is taken on replay.
+		NonRetryableDecodeError bool
+
+		// SkipIfWorkflowCancelled makes the activity worker check, before running the activity, whether the workflow
+		// that scheduled it was canceled or requested cancellation of this activity, and if so respond to the task as
+		// canceled without running the activity. This avoids wasted work when an activity is picked up after its
+		// workflow was canceled.
+		//
+		// The check is a best-effort DescribeWorkflowExecution call made by the worker for each attempt, which costs
+		// a request and some latency, so it is meant for long-running activities. The activity still runs if the
+		// check fails, and a cancellation that happens after the check is only observed through heartbeating as
+		// usual.
+		//
+		// NOTE: Experimental
+		SkipIfWorkflowCancelled bool
+
+		// OnRetry is called from the workflow when it observes that the activity is on a new attempt, with the number
+		// of that attempt and the error the previous attempt failed with, e.g. to emit custom metrics for retries. It
+		// is never called for the first attempt and, like other side effects guarded by IsReplaying, not while
+		// replaying, so it is called at most once per attempt.
+		//
+		// The server records only the start of the last attempt of an activity in history, once the activity is closed,
+		// so the workflow observes a retried activity once, right before its result, with the attempt that produced
+		// the result. OnRetry must not block and must not call workflow functions that need a coroutine, like
+		// Future.Get or Sleep.
+		//
+		// Optional: default is not to be called.
+		OnRetry func(attempt int32, lastErr error) `json:"-"`
 	}
 
 	// LocalActivityOptions stores local activity specific parameters that will be stored inside of a context.
@@ -200,6 +291,18 @@ type (
 		//
 		// NOTE: Experimental
 		Summary string
+
+		// NonRetryableDecodeError makes the error returned from Future.Get when the result of the local activity can't
+		// be decoded a non-retryable application error. See ActivityOptions.NonRetryableDecodeError.
+		NonRetryableDecodeError bool
+
+		// OnRetry is called from the workflow before each retry of the local activity, with the number of the new
+		// attempt and the error the previous attempt failed with. It is called both for retries the worker runs
+		// in-process and for retries after a backoff timer, but never for the first attempt nor while replaying.
+		// See ActivityOptions.OnRetry.
+		//
+		// Optional: default is not to be called.
+		OnRetry func(attempt int32, lastErr error) `json:"-"`
 	}
 )
 
@@ -230,6 +333,44 @@ func IsActivity(ctx context.Context) bool {
 	return a != nil
 }
 
+// IsLastActivityAttempt reports whether the current attempt of the activity is the last one allowed by its retry
+// policy, i.e. the policy sets MaximumAttempts and ActivityInfo.Attempt has reached it.
+//
+// It returns false if the retry policy is unknown or has unlimited attempts, even though the attempt may still turn
+// out to be the last one: retries also stop on non-retryable errors, when ScheduleToCloseTimeout expires, or when the
+// workflow cancels the activity, none of which can be known in advance.
+//
+// Exposed as: [go.temporal.io/sdk/activity.IsLastAttempt]
+func IsLastActivityAttempt(ctx context.Context) bool {
+	info := GetActivityInfo(ctx)
+	if info.RetryPolicy == nil || info.RetryPolicy.MaximumAttempts <= 0 {
+		return false
+	}
+	return info.Attempt >= info.RetryPolicy.MaximumAttempts
+}
+
+// GetActivityIdempotencyToken returns a token identifying the logical activity, for external systems to deduplicate
+// requests made by its attempts. The token is the same for every attempt of the activity, so it must not be used to
+// tell attempts apart; combine it with ActivityInfo.Attempt for that.
+//
+// The token is made of the namespace, workflow ID, workflow run ID and activity ID for activities started by a workflow,
+// and of the namespace, activity ID and activity run ID for standalone activities. A workflow reset or continue-as-new
+// starts a new run and so gives the activities of the new run new tokens, even if their activity IDs are the same.
+//
+// Exposed as: [go.temporal.io/sdk/activity.GetIdempotencyToken]
+func GetActivityIdempotencyToken(ctx context.Context) string {
+	info := GetActivityInfo(ctx)
+	components := []string{info.Namespace, info.WorkflowExecution.ID, info.WorkflowExecution.RunID, info.ActivityID}
+	if info.WorkflowExecution.ID == "" {
+		components = []string{info.Namespace, info.ActivityID, info.ActivityRunID}
+	}
+	for i, c := range components {
+		// Escaped so that separators in IDs cannot make tokens of different activities equal
+		components[i] = url.PathEscape(c)
+	}
+	return strings.Join(components, "/")
+}
+
 // GetHeartbeatDetails extracts heartbeat details from the last failed attempt. This is used in combination with the retry policy.
 // An activity could be scheduled with an optional retry policy on ActivityOptions. If the activity failed, then server
 // would attempt to dispatch another activity task to retry according to the retry policy. If there were heartbeat
@@ -313,9 +454,40 @@ func WithActivityTask(
 	contextPropagators []ContextPropagator,
 	interceptors []WorkerInterceptor,
 	client *WorkflowClient,
+) (context.Context, error) {
+	return withActivityTask(ctx, task, task.ActivityType.GetName(), taskQueue, invoker, logger, metricsHandler,
+		dataConverter, workerStopChannel, contextPropagators, interceptors, client)
+}
+
+// withActivityTask is WithActivityTask reporting activityType as the type of the activity, e.g. the primary name of
+// an activity the task invokes by an alias.
+func withActivityTask(
+	ctx context.Context,
+	task *workflowservice.PollActivityTaskQueueResponse,
+	activityType string,
+	taskQueue string,
+	invoker ServiceInvoker,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+	dataConverter converter.DataConverter,
+	workerStopChannel <-chan struct{},
+	contextPropagators []ContextPropagator,
+	interceptors []WorkerInterceptor,
+	client *WorkflowClient,
 ) (context.Context, error) {
 	scheduled := task.GetScheduledTime().AsTime()
 	started := task.GetStartedTime().AsTime()
+	// Latency is measured from when the latest attempt was scheduled, which is the original schedule time only on the
+	// first attempt.
+	var attemptScheduled, attemptStarted time.Time
+	if ts := task.GetCurrentAttemptScheduledTime(); ts != nil {
+		attemptScheduled = ts.AsTime()
+	} else if ts := task.GetScheduledTime(); ts != nil {
+		attemptScheduled = ts.AsTime()
+	}
+	if task.GetStartedTime() != nil {
+		attemptStarted = started
+	}
 	scheduleToCloseTimeout := task.GetScheduleToCloseTimeout().AsDuration()
 	startToCloseTimeout := task.GetStartToCloseTimeout().AsDuration()
 	heartbeatTimeout := task.GetHeartbeatTimeout().AsDuration()
@@ -324,7 +496,7 @@ func WithActivityTask(
 	env := &activityEnvironment{
 		taskToken:              task.TaskToken,
 		serviceInvoker:         invoker,
-		activityType:           ActivityType{Name: task.ActivityType.GetName()},
+		activityType:           ActivityType{Name: activityType},
 		activityID:             task.ActivityId,
 		metricsHandler:         metricsHandler,
 		deadline:               deadline,
@@ -333,6 +505,7 @@ func WithActivityTask(
 		startToCloseTimeout:    startToCloseTimeout,
 		scheduledTime:          scheduled,
 		startedTime:            started,
+		scheduleToStartLatency: scheduleToStartLatency(attemptScheduled, attemptStarted),
 		taskQueue:              taskQueue,
 		dataConverter:          dataConverter,
 		attempt:                task.GetAttempt(),
@@ -350,7 +523,7 @@ func WithActivityTask(
 		env.logger = log.With(logger,
 			tagActivityID, task.ActivityId,
 			tagActivityRunID, task.ActivityRunId,
-			tagActivityType, task.ActivityType.GetName(),
+			tagActivityType, activityType,
 			tagAttempt, task.Attempt,
 		)
 	} else {
@@ -363,7 +536,7 @@ func WithActivityTask(
 		}
 		env.logger = log.With(logger,
 			tagActivityID, task.ActivityId,
-			tagActivityType, task.ActivityType.GetName(),
+			tagActivityType, activityType,
 			tagAttempt, task.Attempt,
 			tagWorkflowType, task.WorkflowType.GetName(),
 			tagWorkflowID, task.WorkflowExecution.GetWorkflowId(),
@@ -391,6 +564,9 @@ func WithLocalActivityTask(
 	workflowTypeLocal := task.params.WorkflowInfo.WorkflowType
 	workflowType := task.params.WorkflowInfo.WorkflowType.Name
 	activityType := task.params.ActivityType
+	if task.params.primaryActivityType != "" {
+		activityType = task.params.primaryActivityType
+	}
 	logger = log.With(logger,
 		tagActivityID, task.activityID,
 		tagActivityType, activityType,
@@ -429,6 +605,7 @@ func WithLocalActivityTask(
 		deadline:               deadline,
 		scheduledTime:          task.scheduledTime,
 		startedTime:            startedTime,
+		scheduleToStartLatency: scheduleToStartLatency(task.scheduledTime, startedTime),
 		dataConverter:          dataConverter,
 		attempt:                task.attempt,
 		retryPolicy:            task.retryPolicy,
@@ -465,6 +642,15 @@ func newActivityContext(
 	return ctx, nil
 }
 
+// scheduleToStartLatency returns how long an activity attempt waited before starting, or zero if it cannot be
+// determined or is negative due to clock skew.
+func scheduleToStartLatency(scheduled, started time.Time) time.Duration {
+	if scheduled.IsZero() || started.IsZero() || started.Before(scheduled) {
+		return 0
+	}
+	return started.Sub(scheduled)
+}
+
 func calculateActivityDeadline(scheduled time.Time, scheduleToCloseTimeout, startToCloseTimeout time.Duration) time.Time {
 	startToCloseDeadline := time.Now().Add(startToCloseTimeout)
 	if scheduleToCloseTimeout > 0 {
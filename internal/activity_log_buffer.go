@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultActivityLogBufferLines = 20
+	// maxActivityLogLineLength bounds each retained line, so a buffer can not grow a heartbeat beyond
+	// maxLines * maxActivityLogLineLength bytes of log lines.
+	maxActivityLogLineLength = 1024
+)
+
+type (
+	// ActivityLogBuffer retains the most recent log lines of an activity so they can be attached to its heartbeats,
+	// giving operators visibility into the progress of long activities without correlating external logs.
+	//
+	// Lines are best-effort: only the most recent lines are retained, lines longer than 1024 bytes are truncated,
+	// and they are only as current as the last heartbeat that reached the server. While the activity runs, the lines
+	// are part of the heartbeat details of the pending activity, e.g. as shown by describing the workflow. When the
+	// activity times out they are part of the last heartbeat details of the TimeoutError, from which ActivityLogs
+	// surfaces them through a workflow query.
+	//
+	// Exposed as: [go.temporal.io/sdk/activity.LogBuffer]
+	ActivityLogBuffer struct {
+		mu       sync.Mutex
+		lines    []string
+		next     int
+		maxLines int
+	}
+
+	// ActivityLogs collects the log lines activities attached to their heartbeats with an ActivityLogBuffer and
+	// serves them through a query, keyed by activity ID.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.ActivityLogs]
+	ActivityLogs struct {
+		lines map[string][]string
+	}
+
+	// activityLogHeartbeat is the heartbeat detail ActivityLogBuffer appends to the details of the activity.
+	activityLogHeartbeat struct {
+		ActivityLogLines []string `json:"activityLogLines"`
+	}
+)
+
+// NewActivityLogBuffer creates an ActivityLogBuffer retaining the most recent maxLines lines. Defaults to 20 lines
+// if maxLines is not positive.
+//
+// Exposed as: [go.temporal.io/sdk/activity.NewLogBuffer]
+func NewActivityLogBuffer(maxLines int) *ActivityLogBuffer {
+	if maxLines <= 0 {
+		maxLines = defaultActivityLogBufferLines
+	}
+	return &ActivityLogBuffer{maxLines: maxLines}
+}
+
+// Printf formats a line and adds it to the buffer, dropping the oldest line if the buffer is full.
+func (b *ActivityLogBuffer) Printf(format string, args ...interface{}) {
+	line := fmt.Sprintf(format, args...)
+	if len(line) > maxActivityLogLineLength {
+		line = strings.ToValidUTF8(line[:maxActivityLogLineLength], "")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.lines) < b.maxLines {
+		b.lines = append(b.lines, line)
+		return
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % b.maxLines
+}
+
+// Lines returns the retained lines, oldest first.
+func (b *ActivityLogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := make([]string, 0, len(b.lines))
+	lines = append(lines, b.lines[b.next:]...)
+	return append(lines, b.lines[:b.next]...)
+}
+
+// RecordHeartbeat records an activity heartbeat with the given details followed by the retained lines. The lines
+// come last so GetHeartbeatDetails of the next attempt decodes the details as usual.
+func (b *ActivityLogBuffer) RecordHeartbeat(ctx context.Context, details ...interface{}) {
+	details = append(details[:len(details):len(details)], activityLogHeartbeat{ActivityLogLines: b.Lines()})
+	RecordActivityHeartbeat(ctx, details...)
+}
+
+// NewActivityLogs creates an ActivityLogs and registers a query handler of the given type returning the collected
+// lines as a map of activity ID to lines.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewActivityLogs]
+func NewActivityLogs(ctx Context, queryType string) (*ActivityLogs, error) {
+	l := &ActivityLogs{lines: map[string][]string{}}
+	if err := SetQueryHandler(ctx, queryType, func() (map[string][]string, error) {
+		return maps.Clone(l.lines), nil
+	}); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Record collects the log lines in the last heartbeat details of the activity that failed with err, if err is an
+// activity timeout and the activity heartbeated with an ActivityLogBuffer. It reports whether lines were found.
+func (l *ActivityLogs) Record(err error) bool {
+	var activityErr *ActivityError
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &activityErr) || !errors.As(err, &timeoutErr) || !timeoutErr.HasLastHeartbeatDetails() {
+		return false
+	}
+	var heartbeat activityLogHeartbeat
+	switch details := timeoutErr.lastHeartbeatDetails.(type) {
+	case *EncodedValues:
+		payloads := details.values.GetPayloads()
+		if details.dataConverter.FromPayload(payloads[len(payloads)-1], &heartbeat) != nil {
+			return false
+		}
+	case ErrorDetailsValues:
+		if h, ok := details[len(details)-1].(activityLogHeartbeat); ok {
+			heartbeat = h
+		}
+	}
+	if heartbeat.ActivityLogLines == nil {
+		return false
+	}
+	l.lines[activityErr.ActivityID()] = heartbeat.ActivityLogLines
+	return true
+}
+
+// Lines returns the lines collected for the activity with the given ID.
+func (l *ActivityLogs) Lines(activityID string) []string {
+	return l.lines[activityID]
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -13,7 +14,10 @@ import (
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/internal/common/metrics"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 )
@@ -268,3 +272,111 @@ func (s *activityTestSuite) TestGetClient() {
 	client := GetClient(ctx)
 	s.NotNil(client)
 }
+
+func (s *activityTestSuite) TestScheduleToStartLatency() {
+	scheduled := time.Now()
+	task := &workflowservice.PollActivityTaskQueueResponse{
+		TaskToken:                   []byte("task-token"),
+		ActivityType:                &commonpb.ActivityType{Name: "test"},
+		ScheduledTime:               timestamppb.New(scheduled.Add(-time.Minute)),
+		CurrentAttemptScheduledTime: timestamppb.New(scheduled),
+		StartedTime:                 timestamppb.New(scheduled.Add(3 * time.Second)),
+		StartToCloseTimeout:         durationpb.New(time.Minute),
+	}
+	ctx, err := WithActivityTask(context.Background(), task, "tq", nil, getLogger(), metrics.NopHandler,
+		nil, nil, nil, nil, nil)
+	s.NoError(err)
+	s.Equal(3*time.Second, GetActivityInfo(ctx).ScheduleToStartLatency)
+
+	// Clock skew that puts the start before the schedule yields no latency
+	task.StartedTime = timestamppb.New(scheduled.Add(-time.Second))
+	ctx, err = WithActivityTask(context.Background(), task, "tq", nil, getLogger(), metrics.NopHandler,
+		nil, nil, nil, nil, nil)
+	s.NoError(err)
+	s.Zero(GetActivityInfo(ctx).ScheduleToStartLatency)
+
+	// Missing timestamps yield no latency
+	task.CurrentAttemptScheduledTime, task.ScheduledTime = nil, nil
+	task.StartedTime = timestamppb.New(scheduled)
+	ctx, err = WithActivityTask(context.Background(), task, "tq", nil, getLogger(), metrics.NopHandler,
+		nil, nil, nil, nil, nil)
+	s.NoError(err)
+	s.Zero(GetActivityInfo(ctx).ScheduleToStartLatency)
+}
+
+func (s *activityTestSuite) TestIsLastActivityAttempt() {
+	task := &workflowservice.PollActivityTaskQueueResponse{
+		TaskToken:           []byte("task-token"),
+		ActivityType:        &commonpb.ActivityType{Name: "test"},
+		StartToCloseTimeout: durationpb.New(time.Minute),
+		Attempt:             3,
+	}
+	isLastAttempt := func() bool {
+		ctx, err := WithActivityTask(context.Background(), task, "tq", nil, getLogger(), metrics.NopHandler,
+			nil, nil, nil, nil, nil)
+		s.NoError(err)
+		return IsLastActivityAttempt(ctx)
+	}
+
+	// Unknown retry policy
+	s.False(isLastAttempt())
+
+	// Unlimited attempts
+	task.RetryPolicy = &commonpb.RetryPolicy{MaximumAttempts: 0}
+	s.False(isLastAttempt())
+
+	task.RetryPolicy.MaximumAttempts = 5
+	s.False(isLastAttempt())
+
+	task.RetryPolicy.MaximumAttempts = 3
+	s.True(isLastAttempt())
+}
+
+func (s *activityTestSuite) TestGetActivityIdempotencyToken() {
+	task := &workflowservice.PollActivityTaskQueueResponse{
+		TaskToken:           []byte("task-token"),
+		WorkflowNamespace:   "ns",
+		WorkflowExecution:   &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"},
+		ActivityId:          "1",
+		ActivityType:        &commonpb.ActivityType{Name: "test"},
+		StartToCloseTimeout: durationpb.New(time.Minute),
+		Attempt:             1,
+	}
+	token := func() string {
+		ctx, err := WithActivityTask(context.Background(), task, "tq", nil, getLogger(), metrics.NopHandler,
+			nil, nil, nil, nil, nil)
+		s.NoError(err)
+		return GetActivityIdempotencyToken(ctx)
+	}
+
+	first := token()
+	// Stable across attempts
+	task.Attempt = 2
+	s.Equal(first, token())
+
+	// Separators in IDs are escaped
+	task.WorkflowExecution.WorkflowId = "wid/rid"
+	task.WorkflowExecution.RunId = ""
+	s.Equal("ns/wid%2Frid//1", token())
+
+	task.WorkflowExecution.WorkflowId = "wid"
+	task.WorkflowExecution.RunId = "rid"
+	task.ActivityId = "2"
+	s.NotEqual(first, token())
+}
+
+func TestActivityLogBuffer(t *testing.T) {
+	logs := NewActivityLogBuffer(0)
+	require.Empty(t, logs.Lines())
+	for i := 0; i < defaultActivityLogBufferLines+5; i++ {
+		logs.Printf("line %d", i)
+	}
+	lines := logs.Lines()
+	require.Len(t, lines, defaultActivityLogBufferLines)
+	require.Equal(t, "line 5", lines[0])
+	require.Equal(t, fmt.Sprintf("line %d", defaultActivityLogBufferLines+4), lines[len(lines)-1])
+
+	logs = NewActivityLogBuffer(1)
+	logs.Printf("%s", strings.Repeat("x", maxActivityLogLineLength+1))
+	require.Len(t, logs.Lines()[0], maxActivityLogLineLength)
+}
@@ -41,6 +41,10 @@ const (
 
 	// QueryTypeWorkflowMetadata is the query name for the workflow metadata.
 	QueryTypeWorkflowMetadata string = "__temporal_workflow_metadata"
+
+	// forceWorkflowTaskSignalName is the signal sent by Client.ForceNewWorkflowTask. It is dropped by workers since
+	// v1.42.0, older ones deliver it to workflow code like a user signal.
+	forceWorkflowTaskSignalName = "__temporal_force_workflow_task"
 )
 
 type (
@@ -298,11 +302,46 @@ type (
 		// [Visibility]: https://docs.temporal.io/visibility
 		CountWorkflow(ctx context.Context, request *workflowservice.CountWorkflowExecutionsRequest) (*workflowservice.CountWorkflowExecutionsResponse, error)
 
+		// CountWorkflowWithOptions counts workflow executions matching a visibility query, like CountWorkflow, and
+		// returns the count along with the query and how long the count took, which is useful for monitoring.
+		// Optionally, a bounded number of matching workflows can be sampled to estimate how many had a failed update.
+		// See [CountWorkflowOptions.SampleFailedUpdates] for the cost of sampling.
+		//
+		// NOTE: Experimental
+		CountWorkflowWithOptions(ctx context.Context, options CountWorkflowOptions) (*CountWorkflowResult, error)
+
+		// ListStuckWorkflows returns the running workflows matching a visibility query whose pending workflow task has
+		// been retried many times, which typically happens when a bad deployment makes workflow tasks fail or panic
+		// (see WorkerOptions.WorkflowPanicPolicy). The last workflow task failure message is returned when the
+		// server recorded one in history: the server only records the first failure of consecutive failed attempts,
+		// so the message may be from an earlier attempt than the latest one.
+		//
+		// Each inspected workflow is described, and the history of each stuck workflow is fetched, so the cost grows
+		// with the number of workflows inspected. See [ListStuckWorkflowsOptions.MaxWorkflows].
+		//
+		// NOTE: Experimental
+		ListStuckWorkflows(ctx context.Context, options ListStuckWorkflowsOptions) ([]StuckWorkflow, error)
+
 		// GetSearchAttributes returns valid search attributes keys and value types.
 		// The search attributes can be used in query of List/Scan/Count APIs. Adding new search attributes requires temporal server
 		// to update dynamic config ValidSearchAttributes.
 		GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error)
 
+		// AddSearchAttributes registers custom search attributes on a namespace through the operator service. Search
+		// attributes that already exist with the same type are skipped, so this can run on every deployment. It fails
+		// if one already exists with a different type. Schema changes may take some time to propagate before the
+		// search attributes can be used.
+		AddSearchAttributes(ctx context.Context, options AddSearchAttributesOptions) error
+
+		// ListSearchAttributes returns the custom and system search attributes of a namespace through the operator
+		// service.
+		ListSearchAttributes(ctx context.Context, options ListSearchAttributesOptions) (*SearchAttributesSchema, error)
+
+		// DescribeNamespace returns the configuration of a namespace, including its retention, archival configuration
+		// and registered search attributes. The search attributes are listed through the operator service on a best
+		// effort basis and left nil if that fails. Defaults to the namespace of the client if namespace is empty.
+		DescribeNamespace(ctx context.Context, namespace string) (*NamespaceDescription, error)
+
 		// QueryWorkflow queries a given workflow execution and returns the query result synchronously. Parameter workflowID
 		// and queryType are required, other parameters are optional. The workflowID and runID (optional) identify the
 		// target workflow execution that this query will be send to. If runID is not specified (empty string), server will
@@ -371,6 +410,11 @@ type (
 		// RequestId is used to deduplicate requests. It will be autogenerated if not set.
 		ResetWorkflowExecution(ctx context.Context, request *workflowservice.ResetWorkflowExecutionRequest) (*workflowservice.ResetWorkflowExecutionResponse, error)
 
+		// ForceNewWorkflowTask makes the server schedule a new workflow task for a running workflow so it re-evaluates
+		// its blocking conditions. This is a recovery tool for stuck workflows. Returns nil if the workflow is already
+		// closed. Requires workers running Go SDK v1.42.0 or later, older ones deliver it to the workflow as a signal.
+		ForceNewWorkflowTask(ctx context.Context, workflowID string, runID string) error
+
 		// UpdateWorkerBuildIdCompatibility allows you to update the worker-build-id based version sets for a particular
 		// task queue. This is used in conjunction with workers who specify their build id and thus opt into the
 		// feature.
@@ -385,7 +429,9 @@ type (
 		// See https://docs.temporal.io/worker-versioning for more information.
 		GetWorkerBuildIdCompatibility(ctx context.Context, options *GetWorkerBuildIdCompatibilityOptions) (*WorkerBuildIDVersionSets, error)
 
-		// GetWorkerTaskReachability returns which versions are is still in use by open or closed workflows.
+		// GetWorkerTaskReachability returns which versions are is still in use by open or closed workflows. If no build
+		// IDs are given, the default build IDs of the given task queues are queried. Requires a Server supporting
+		// build-ID based versioning, i.e. 1.21 or later with the worker versioning APIs enabled.
 		//
 		// Deprecated: Build-ID based versioning is deprecated. Use Worker Deployment based versioning instead.
 		// See https://docs.temporal.io/worker-versioning for more information.
@@ -440,6 +486,8 @@ type (
 		// workflow is running then, if the WorkflowIDConflictPolicy is
 		// USE_EXISTING, the update is issued against the specified workflow,
 		// and if the WorkflowIDConflictPolicy is FAIL, an error is returned.
+		// The error wraps a *serviceerror.WorkflowExecutionAlreadyStarted and
+		// is also returned by the Get method of the start operation.
 		UpdateWithStartWorkflow(ctx context.Context, options UpdateWithStartWorkflowOptions) (WorkflowUpdateHandle, error)
 
 		// GetWorkflowUpdateHandle creates a handle to the referenced update
@@ -575,6 +623,12 @@ type (
 
 		// Optional: HeadersProvider will be invoked on every outgoing gRPC request and gives user ability to
 		// set custom request headers. This can be used to set auth headers for example.
+		//
+		// It is called again for every retry of a request, so it also fits headers that change over the life of the
+		// client, such as rotating tokens or tenant routing headers required by a gateway. As it runs on every
+		// request it should return cached headers and only refresh them when needed. An error fails the request
+		// without sending it. An authorization header set here takes precedence over API key Credentials, which only
+		// add one to requests without it.
 		HeadersProvider HeadersProvider
 
 		// Optional parameter that is designed to be used *in tests*. It gets invoked last in
@@ -612,6 +666,40 @@ type (
 		//
 		// NOTE: Experimental
 		WorkerHeartbeatInterval time.Duration
+
+		// Optional: Retry options for long-poll calls, which are retried separately from other calls so they can be
+		// made more patient without slowing down retries of short calls. The long-poll calls are those waiting for the
+		// result of a workflow, update or standalone activity: fetching workflow history with isLongPoll set (which
+		// includes WorkflowRun.Get), starting or polling an update to wait for its outcome (including
+		// update-with-start), and polling for a standalone activity's result.
+		//
+		// default: the same retry behavior as other calls.
+		LongPollRetryOptions *LongPollRetryOptions
+	}
+
+	// LongPollRetryOptions configure retries of long-poll calls. See ClientOptions.LongPollRetryOptions.
+	//
+	// Exposed as: [go.temporal.io/sdk/client.LongPollRetryOptions]
+	LongPollRetryOptions struct {
+		// InitialInterval is the backoff interval for the first retry.
+		//
+		// default: 200ms
+		InitialInterval time.Duration
+
+		// BackoffCoefficient is the multiplier applied to the backoff interval after each retry. Must be at least 1.
+		//
+		// default: 2.0
+		BackoffCoefficient float64
+
+		// MaximumInterval is the maximum backoff interval between retries. Must not be less than InitialInterval.
+		//
+		// default: 1/10th of the remaining time until the context deadline, but at least InitialInterval.
+		MaximumInterval time.Duration
+
+		// ExpirationInterval is the maximum time spent retrying a single call.
+		//
+		// default: the remaining time until the context deadline, or 60s if there is no deadline.
+		ExpirationInterval time.Duration
 	}
 
 	// HeadersProvider returns a map of gRPC headers that should be used on every request.
@@ -789,6 +877,15 @@ type (
 		// Memo - Optional non-indexed info that will be shown in list workflow.
 		Memo map[string]interface{}
 
+		// Headers - Optional values to pass to the workflow without a context propagator. Each value is encoded with
+		// the data converter of the client and can be read in the workflow with workflow.GetHeader. They are
+		// propagated to child workflows and to the next run on continue-as-new, like the headers of context propagators.
+		//
+		// Headers are sent with the start request and copied to every child workflow and run, so they count towards the
+		// payload size limits of the server and should be kept to a few small values. They can't change during a run,
+		// so reading them is deterministic.
+		Headers map[string]interface{}
+
 		// SearchAttributes - Optional indexed info that can be used in query of List/Scan/Count workflow APIs. The key and value type must be registered on Temporal server side.
 		// Use GetSearchAttributes API to get valid key and corresponding value type.
 		// For supported operations on different server versions see [Visibility].
@@ -809,6 +906,10 @@ type (
 		TypedSearchAttributes SearchAttributes
 
 		// EnableEagerStart - request eager execution for this workflow, if a local worker is available.
+		// The first workflow task is then handed by the server directly to a worker of this client polling the
+		// workflow's task queue. If there is no such worker with a free slot, or the server does not support eager
+		// start, the workflow is dispatched normally. Started workflows are counted by the
+		// temporal_workflow_start metric with a start_type tag of "eager" or "normal".
 		// Cannot be set in WithStartWorkflowOperation.
 		//
 		// WARNING: Eager start does not respect worker versioning. An eagerly started workflow may run on
@@ -852,6 +953,31 @@ type (
 		// WARNING: Task queue priority is currently experimental.
 		Priority Priority
 
+		// DataConverter - Optional data converter used instead of the client's one for this call only, e.g. to encrypt
+		// the inputs of a workflow with a tenant-specific key. It encodes the workflow arguments, signal argument and
+		// memo, and the returned WorkflowRun uses it to decode the result in WorkflowRun.Get. Failures are still
+		// decoded with the client's failure converter. The workflow itself must be able to decode the inputs, e.g. via
+		// the worker's data converter.
+		//
+		// To decode the result of a run obtained otherwise, e.g. from Client.GetWorkflow, use
+		// WorkflowRunGetOptions.DataConverter.
+		//
+		// Optional: defaults to the client's data converter.
+		DataConverter converter.DataConverter
+
+		// CompletionCallbacks - Optional callbacks the server invokes when the workflow completes, e.g. to notify an
+		// external system waiting for the result in an asynchronous request-reply pattern built on Nexus. The
+		// callbacks are carried over to the runs started by continue-as-new, retries and cron, and are invoked once
+		// the last run of the chain closes.
+		//
+		// The URLs must be absolute http or https URLs, which is checked before starting the workflow. The server
+		// must support Nexus completion callbacks and allow the callback URLs (with the
+		// component.callbacks.allowedAddresses dynamic config), otherwise starting the workflow fails with the
+		// server's error. Not supported by Client.SignalWithStartWorkflow, which fails if they are set.
+		//
+		// NOTE: Experimental
+		CompletionCallbacks []CompletionCallback
+
 		// responseInfo - Optional pointer to store information of StartWorkflowExecution response.
 		// Only settable by the SDK - e.g. [temporalnexus.workflowRunOperation].
 		responseInfo *startWorkflowResponseInfo
@@ -872,6 +998,19 @@ type (
 		onConflictOptions *OnConflictOptions
 	}
 
+	// CompletionCallback is a Nexus callback the server invokes when a workflow completes. See
+	// StartWorkflowOptions.CompletionCallbacks.
+	//
+	// NOTE: Experimental
+	//
+	// Exposed as: [go.temporal.io/sdk/client.CompletionCallback]
+	CompletionCallback struct {
+		// URL the server sends the completion of the workflow to. Must be an absolute http or https URL.
+		URL string
+		// Header - Optional headers sent with the completion. Names must not be empty.
+		Header map[string]string
+	}
+
 	// startWorkflowResponseInfo can be passed to StartWorkflowOptions to receive additional information
 	// of StartWorkflowExecution response.
 	startWorkflowResponseInfo struct {
@@ -1060,6 +1199,62 @@ func NewClientFromExisting(ctx context.Context, existingClient Client, options C
 	return newClient(ctx, options, existingClient)
 }
 
+// ClientWithNamespace creates a new client bound to the given namespace that
+// shares the connection, converters, interceptors and other options of the
+// existing client.
+//
+// Exposed as: [go.temporal.io/sdk/client.WithNamespace]
+func ClientWithNamespace(existingClient Client, namespace string) (Client, error) {
+	existing, _ := existingClient.(*WorkflowClient)
+	if existing == nil {
+		return nil, fmt.Errorf("existing client must have been created directly from a client package call")
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace must not be empty")
+	}
+
+	client := &WorkflowClient{
+		workflowService:          existing.workflowService,
+		operatorService:          existing.operatorService,
+		conn:                     existing.conn,
+		namespace:                namespace,
+		registry:                 newRegistry(),
+		metricsHandler:           existing.metricsHandler.WithTags(metrics.RootTags(namespace)),
+		logger:                   existing.logger,
+		identity:                 existing.identity,
+		dataConverter:            existing.dataConverter,
+		failureConverter:         existing.failureConverter,
+		contextPropagators:       existing.contextPropagators,
+		workerPlugins:            existing.workerPlugins,
+		workerInterceptors:       existing.workerInterceptors,
+		clientPluginNames:        existing.clientPluginNames,
+		interceptors:             existing.interceptors,
+		excludeInternalFromRetry: existing.excludeInternalFromRetry,
+		eagerDispatcher: &eagerWorkflowDispatcher{
+			workersByTaskQueue: make(map[string]map[eagerWorker]struct{}),
+		},
+		getSystemInfoTimeout:    existing.getSystemInfoTimeout,
+		workerHeartbeatInterval: existing.workerHeartbeatInterval,
+		workerGroupingKey:       uuid.NewString(),
+		longPollRetryOptions:    existing.longPollRetryOptions,
+		unclosedClients:         existing.unclosedClients,
+		connOwnedByParent:       true,
+	}
+	existing.capabilitiesLock.RLock()
+	client.capabilities = existing.capabilities
+	existing.capabilitiesLock.RUnlock()
+
+	if client.workerHeartbeatInterval > 0 {
+		client.heartbeatManager = newHeartbeatManager(client, client.workerHeartbeatInterval, client.logger)
+	}
+
+	client.interceptor = &workflowClientInterceptor{client: client}
+	for i := len(client.interceptors) - 1; i >= 0; i-- {
+		client.interceptor = client.interceptors[i].InterceptClient(client.interceptor)
+	}
+	return client, nil
+}
+
 func newClient(ctx context.Context, options ClientOptions, existing Client) (Client, error) {
 	// Go over all plugins allowing them to configure the options
 	for _, plugin := range options.Plugins {
@@ -1092,6 +1287,12 @@ func newClient(ctx context.Context, options ClientOptions, existing Client) (Cli
 		return nil, fmt.Errorf("cannot set both TLS and TLSDisabled in ConnectionOptions")
 	}
 
+	if options.LongPollRetryOptions != nil {
+		if err := options.LongPollRetryOptions.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	if options.Credentials != nil {
 		if err := options.Credentials.applyToOptions(&options.ConnectionOptions); err != nil {
 			return nil, err
@@ -1237,6 +1438,7 @@ func NewServiceClient(workflowServiceClient workflowservice.WorkflowServiceClien
 
 	client := &WorkflowClient{
 		workflowService:          workflowServiceClient,
+		operatorService:          operatorservice.NewOperatorServiceClient(conn),
 		conn:                     conn,
 		namespace:                options.Namespace,
 		registry:                 newRegistry(),
@@ -1249,6 +1451,7 @@ func NewServiceClient(workflowServiceClient workflowservice.WorkflowServiceClien
 		workerPlugins:            workerPlugins,
 		workerInterceptors:       workerInterceptors,
 		clientPluginNames:        clientPluginNames,
+		interceptors:             options.Interceptors,
 		excludeInternalFromRetry: options.ConnectionOptions.excludeInternalFromRetry,
 		eagerDispatcher: &eagerWorkflowDispatcher{
 			workersByTaskQueue: make(map[string]map[eagerWorker]struct{}),
@@ -1256,6 +1459,7 @@ func NewServiceClient(workflowServiceClient workflowservice.WorkflowServiceClien
 		getSystemInfoTimeout:    options.ConnectionOptions.GetSystemInfoTimeout,
 		workerHeartbeatInterval: heartbeatInterval,
 		workerGroupingKey:       uuid.NewString(),
+		longPollRetryOptions:    options.LongPollRetryOptions,
 	}
 
 	if heartbeatInterval > 0 {
@@ -1359,6 +1563,56 @@ func NewValues(data *commonpb.Payloads) converter.EncodedValues {
 	return newEncodedValues(data, nil)
 }
 
+// DecodeMemo decodes the value of key in a workflow memo, e.g. of a workflow listed with Client.ListWorkflow, using the
+// data converter of c so that its codecs, such as encryption, apply. It returns false and no error if the memo has no
+// value for key, and an error if the value cannot be decoded into T.
+//
+// Exposed as: [go.temporal.io/sdk/client.DecodeMemo]
+func DecodeMemo[T any](c Client, memo *commonpb.Memo, key string) (T, bool, error) {
+	var value T
+	dc, err := clientDataConverter(c)
+	if err != nil {
+		return value, false, err
+	}
+	payload, ok := memo.GetFields()[key]
+	if !ok {
+		return value, false, nil
+	}
+	if err := dc.FromPayload(payload, &value); err != nil {
+		return value, true, fmt.Errorf("unable to decode memo %q: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// DecodeMemoValues decodes all values of a workflow memo into T, using the data converter of c like DecodeMemo. Use
+// interface{} as T for memos with values of different types. It returns an error naming the key of the first value
+// that cannot be decoded.
+//
+// Exposed as: [go.temporal.io/sdk/client.DecodeMemoValues]
+func DecodeMemoValues[T any](c Client, memo *commonpb.Memo) (map[string]T, error) {
+	dc, err := clientDataConverter(c)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]T, len(memo.GetFields()))
+	for key, payload := range memo.GetFields() {
+		var value T
+		if err := dc.FromPayload(payload, &value); err != nil {
+			return nil, fmt.Errorf("unable to decode memo %q: %w", key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func clientDataConverter(c Client) (converter.DataConverter, error) {
+	workflowClient, _ := c.(*WorkflowClient)
+	if workflowClient == nil {
+		return nil, fmt.Errorf("client must have been created directly from a client package call")
+	}
+	return workflowClient.dataConverter, nil
+}
+
 type apiKeyCredentials func(context.Context) (string, error)
 
 // Exposed as: [go.temporal.io/sdk/client.NewAPIKeyStaticCredentials]
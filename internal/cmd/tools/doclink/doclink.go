@@ -286,8 +286,16 @@ func checkFunction(funcDecl *ast.FuncDecl) string {
 
 // Check if a call expression is calling an internal function
 func isInternalFunctionCall(callExpr *ast.CallExpr) string {
+	fun := callExpr.Fun
+	// Look through explicit type arguments (e.g., "internal.SomeFunction[T]")
+	switch indexExpr := fun.(type) {
+	case *ast.IndexExpr:
+		fun = indexExpr.X
+	case *ast.IndexListExpr:
+		fun = indexExpr.X
+	}
 	// Check if the function being called is a SelectorExpr (e.g., "internal.SomeFunction")
-	if selExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
+	if selExpr, ok := fun.(*ast.SelectorExpr); ok {
 		if pkgIdent, ok := selExpr.X.(*ast.Ident); ok && pkgIdent.Name == "internal" {
 			return selExpr.Sel.Name
 		}
@@ -30,6 +30,9 @@ type Cache interface {
 	// Size returns the number of entries currently stored in the Cache
 	Size() int
 
+	// Values returns the elements currently stored in the Cache, from the most to the least recently used
+	Values() []interface{}
+
 	// Clear clears the cache.
 	Clear()
 }
@@ -148,6 +148,18 @@ func (c *lru) Size() int {
 	return len(c.byKey)
 }
 
+// Values returns the values in the lru, from the most to the least recently used
+func (c *lru) Values() []interface{} {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	values := make([]interface{}, 0, len(c.byKey))
+	for elt := c.byAccess.Front(); elt != nil; elt = elt.Next() {
+		values = append(values, elt.Value.(*cacheEntry).value)
+	}
+	return values
+}
+
 // Clear clears the cache.
 func (c *lru) Clear() {
 	c.mut.Lock()
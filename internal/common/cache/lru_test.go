@@ -172,3 +172,14 @@ func TestLRUMax(t *testing.T) {
 	assert.Equal(t, "Bar", cache.Get("B"))
 	assert.Equal(t, 1, cache.Size())
 }
+
+func TestValues(t *testing.T) {
+	cache := NewLRU(3)
+	assert.Empty(t, cache.Values())
+
+	cache.Put("A", "Foo")
+	cache.Put("B", "Bar")
+	cache.Put("C", "Baz")
+	assert.Equal(t, "Foo", cache.Get("A"))
+	assert.Equal(t, []interface{}{"Foo", "Baz", "Bar"}, cache.Values())
+}
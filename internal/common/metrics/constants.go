@@ -9,14 +9,19 @@ const (
 	WorkflowFailedCounter        = TemporalMetricsPrefix + "workflow_failed"
 	WorkflowContinueAsNewCounter = TemporalMetricsPrefix + "workflow_continue_as_new"
 	WorkflowEndToEndLatency      = TemporalMetricsPrefix + "workflow_endtoend_latency" // measure workflow execution from start to close
+	WorkflowStartCounter         = TemporalMetricsPrefix + "workflow_start"            // workflows started by the client, tagged by start type
 
-	WorkflowTaskReplayLatency           = TemporalMetricsPrefix + "workflow_task_replay_latency"
-	WorkflowTaskQueuePollEmptyCounter   = TemporalMetricsPrefix + "workflow_task_queue_poll_empty"
-	WorkflowTaskQueuePollSucceedCounter = TemporalMetricsPrefix + "workflow_task_queue_poll_succeed"
-	WorkflowTaskScheduleToStartLatency  = TemporalMetricsPrefix + "workflow_task_schedule_to_start_latency"
-	WorkflowTaskExecutionLatency        = TemporalMetricsPrefix + "workflow_task_execution_latency"
-	WorkflowTaskExecutionFailureCounter = TemporalMetricsPrefix + "workflow_task_execution_failed"
-	WorkflowTaskNoCompletionCounter     = TemporalMetricsPrefix + "workflow_task_no_completion"
+	WorkflowContinueAsNewHistoryLength = TemporalMetricsPrefix + "workflow_continue_as_new_history_length"
+
+	WorkflowTaskReplayLatency               = TemporalMetricsPrefix + "workflow_task_replay_latency"
+	WorkflowTaskSlowReplayCounter           = TemporalMetricsPrefix + "workflow_task_slow_replay"
+	WorkflowTaskQueuePollEmptyCounter       = TemporalMetricsPrefix + "workflow_task_queue_poll_empty"
+	WorkflowTaskQueuePollSucceedCounter     = TemporalMetricsPrefix + "workflow_task_queue_poll_succeed"
+	WorkflowTaskScheduleToStartLatency      = TemporalMetricsPrefix + "workflow_task_schedule_to_start_latency"
+	WorkflowTaskExecutionLatency            = TemporalMetricsPrefix + "workflow_task_execution_latency"
+	WorkflowTaskExecutionFailureCounter     = TemporalMetricsPrefix + "workflow_task_execution_failed"
+	WorkflowTaskNoCompletionCounter         = TemporalMetricsPrefix + "workflow_task_no_completion"
+	WorkflowTaskShadowReplayMismatchCounter = TemporalMetricsPrefix + "workflow_task_shadow_replay_mismatch"
 
 	ActivityPollNoTaskCounter             = TemporalMetricsPrefix + "activity_poll_no_task"
 	ActivityScheduleToStartLatency        = TemporalMetricsPrefix + "activity_schedule_to_start_latency"
@@ -81,6 +86,7 @@ const (
 	OperationTagName        = "operation"
 	CauseTagName            = "cause"
 	RequestFailureCode      = "status_code"
+	StartTypeTagName        = "start_type"
 )
 
 // Metric tag values
@@ -91,4 +97,6 @@ const (
 	PollerTypeWorkflowStickyTask = "workflow_sticky_task"
 	PollerTypeActivityTask       = "activity_task"
 	PollerTypeNexusTask          = "nexus_task"
+	StartTypeEager               = "eager"
+	StartTypeNormal              = "normal"
 )
@@ -95,6 +95,21 @@ func WorkflowTaskFailedTags(reason string) map[string]string {
 	}
 }
 
+// WorkflowStartTags returns a set of tags for a workflow started by the
+// client, where eager tells whether the first workflow task was dispatched
+// eagerly to a local worker.
+func WorkflowStartTags(workflowType, taskQueueName string, eager bool) map[string]string {
+	startType := StartTypeNormal
+	if eager {
+		startType = StartTypeEager
+	}
+	return map[string]string{
+		WorkflowTypeNameTagName: workflowType,
+		TaskQueueTagName:        taskQueueName,
+		StartTypeTagName:        startType,
+	}
+}
+
 // RequestFailureCodeTags returns a set of tags for a request failure.
 func RequestFailureCodeTags(statusCode codes.Code) map[string]string {
 	asStr := canonicalString(statusCode)
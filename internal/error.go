@@ -142,6 +142,18 @@ type (
 		cause                error
 	}
 
+	// DecodeError is the cause of the ApplicationError of type "DecodeError" returned from Future.Get in a workflow
+	// when the result of an activity, local activity or child workflow can't be decoded into the provided value.
+	//
+	// Exposed as: [go.temporal.io/sdk/temporal.DecodeError]
+	DecodeError struct {
+		// TypeName is the activity type, or workflow type for child workflows, whose result failed to decode.
+		TypeName string
+		// TargetType is the type of the value the result was decoded into.
+		TargetType reflect.Type
+		cause      error
+	}
+
 	// CanceledErrorOptions should be used to set all the desired attributes of a new CanceledError
 	//
 	// Exposed as: [go.temporal.io/sdk/temporal.CanceledErrorOptions]
@@ -373,6 +385,11 @@ var (
 	// Exposed as: [go.temporal.io/sdk/temporal.ErrScheduleAlreadyRunning]
 	ErrScheduleAlreadyRunning = errors.New("schedule with this ID is already registered")
 
+	// ErrSchedulePaused is returned by ScheduleHandle.AwaitNextRun if the Schedule is paused, so it will not fire.
+	//
+	// Exposed as: [go.temporal.io/sdk/temporal.ErrSchedulePaused]
+	ErrSchedulePaused = errors.New("schedule is paused")
+
 	// ErrSkipScheduleUpdate is used by a user if they want to skip updating a schedule.
 	//
 	// Exposed as: [go.temporal.io/sdk/temporal.ErrSkipScheduleUpdate]
@@ -382,6 +399,13 @@ var (
 	ErrMissingWorkflowID = errors.New("workflow ID is unset for Nexus operation")
 )
 
+// decodeErrorType is the type of the ApplicationError wrapping a DecodeError.
+const decodeErrorType = "DecodeError"
+
+// workflowInputValidationErrorType is the type of the ApplicationError wrapping an error of
+// RegisterWorkflowOptions.InputValidator.
+const workflowInputValidationErrorType = "InputValidationError"
+
 // ApplicationErrorCategory sets the category of the error. The category of the error
 // maps to logging/metrics behaviors.
 //
@@ -572,6 +596,38 @@ func IsCanceledError(err error) bool {
 	return errors.As(err, &canceledErr)
 }
 
+// GetActivityLastHeartbeatDetails extracts the last heartbeat details from the first TimeoutError in the chain of err
+// that has them. If there is none, it returns an error wrapping ErrNoData.
+//
+// Exposed as: [go.temporal.io/sdk/temporal.GetActivityLastHeartbeatDetails]
+func GetActivityLastHeartbeatDetails(err error, valuePtr interface{}) error {
+	for err != nil {
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			break
+		}
+		if timeoutErr.HasLastHeartbeatDetails() {
+			return timeoutErr.LastHeartbeatDetails(valuePtr)
+		}
+		err = timeoutErr.Unwrap()
+	}
+	return fmt.Errorf("no last heartbeat details found in error: %w", ErrNoData)
+}
+
+// GetCanceledErrorDetails extracts the details of the first CanceledError in the chain of err. If there is none or it
+// has no details, it returns an error wrapping ErrNoData.
+//
+// Exposed as: [go.temporal.io/sdk/temporal.GetCanceledErrorDetails]
+func GetCanceledErrorDetails(err error, valuePtr interface{}) error {
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) {
+		return fmt.Errorf("no canceled error found in error: %w", ErrNoData)
+	} else if !canceledErr.HasDetails() {
+		return fmt.Errorf("canceled error has no details: %w", ErrNoData)
+	}
+	return canceledErr.Details(valuePtr)
+}
+
 // NewContinueAsNewError creates ContinueAsNewError instance
 // If the workflow main function returns this error then the current execution is ended and
 // the new execution with same workflow ID is started automatically with options
@@ -610,6 +666,58 @@ func NewContinueAsNewErrorWithOptions(ctx Context, options ContinueAsNewErrorOpt
 	return err
 }
 
+// ContinueAsNewIfChanged returns a ContinueAsNewError for wfn with state followed by args as the arguments of the new
+// run if equals reports that state differs from baseline, or nil otherwise.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ContinueAsNewIfChanged]
+func ContinueAsNewIfChanged[T any](ctx Context, baseline, state T, equals func(a, b T) bool, wfn interface{}, args ...interface{}) error {
+	if equals(baseline, state) {
+		return nil
+	}
+	return NewContinueAsNewError(ctx, wfn, append([]interface{}{state}, args...)...)
+}
+
+// ContinueAsNewTyped returns a ContinueAsNewError for the workflow function wfn taking a single argument, with arg as
+// the argument of the new run. Unlike NewContinueAsNewError, the type of arg is checked against wfn at compile time.
+// It returns an error if wfn is not registered with the worker, unless the new run is on another task queue.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ContinueAsNewTyped]
+func ContinueAsNewTyped[T any](ctx Context, wfn func(Context, T) error, arg T) error {
+	if err := validateContinueAsNewWorkflowRegistered(ctx, wfn); err != nil {
+		return err
+	}
+	return NewContinueAsNewError(ctx, wfn, arg)
+}
+
+// ContinueAsNewTypedNoInput is ContinueAsNewTyped for workflow functions without arguments.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ContinueAsNewTypedNoInput]
+func ContinueAsNewTypedNoInput(ctx Context, wfn func(Context) error) error {
+	if err := validateContinueAsNewWorkflowRegistered(ctx, wfn); err != nil {
+		return err
+	}
+	return NewContinueAsNewError(ctx, wfn)
+}
+
+// validateContinueAsNewWorkflowRegistered returns an error if wfn is not registered with the worker. The workflows of
+// another task queue are registered with other workers, so they are not checked.
+func validateContinueAsNewWorkflowRegistered(ctx Context, wfn interface{}) error {
+	env := getWorkflowEnvironment(ctx)
+	if options := getWorkflowEnvOptions(ctx); options != nil && options.TaskQueueName != "" &&
+		options.TaskQueueName != env.WorkflowInfo().TaskQueueName {
+		return nil
+	}
+	registry := env.GetRegistry()
+	workflowType, err := getWorkflowFunctionName(registry, wfn)
+	if err != nil {
+		return err
+	}
+	if _, ok := registry.getWorkflowFn(workflowType); !ok {
+		return fmt.Errorf("unable to continue as new: workflow %v is not registered", workflowType)
+	}
+	return nil
+}
+
 func (wc *workflowEnvironmentInterceptor) NewContinueAsNewError(
 	ctx Context,
 	wfn interface{},
@@ -630,6 +738,7 @@ func (wc *workflowEnvironmentInterceptor) NewContinueAsNewError(
 	if err != nil {
 		return err
 	}
+	propagateStartWorkflowHeaders(ctx, header)
 
 	return &ContinueAsNewError{
 		WorkflowType:              workflowType,
@@ -710,6 +819,25 @@ func (e *ApplicationError) Category() ApplicationErrorCategory {
 	return e.category
 }
 
+// Error from error interface
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unable to decode result of %v into %v: %v", e.TypeName, e.TargetType, e.cause)
+}
+
+// Unwrap returns the data converter error.
+func (e *DecodeError) Unwrap() error {
+	return e.cause
+}
+
+// newDecodeApplicationError wraps a failure to decode the result of typeName into valuePtr in an ApplicationError.
+func newDecodeApplicationError(typeName string, valuePtr interface{}, err error, nonRetryable bool) error {
+	decodeErr := &DecodeError{TypeName: typeName, TargetType: reflect.TypeOf(valuePtr).Elem(), cause: err}
+	return NewApplicationErrorWithOptions(decodeErr.Error(), decodeErrorType, ApplicationErrorOptions{
+		NonRetryable: nonRetryable,
+		Cause:        decodeErr,
+	})
+}
+
 // Error from error interface
 func (e *TimeoutError) Error() string {
 	msg := fmt.Sprintf("%s (type: %s)", e.message(), e.timeoutType)
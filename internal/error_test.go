@@ -124,6 +124,44 @@ func Test_TimeoutError(t *testing.T) {
 	require.Equal(t, testErrorDetails1, data)
 }
 
+func Test_GetCanceledErrorDetails(t *testing.T) {
+	var data string
+	err := NewActivityError(8, 22, "alex", &commonpb.ActivityType{Name: "activityType"}, "32283", enumspb.RETRY_STATE_NON_RETRYABLE_FAILURE, NewCanceledError(testErrorDetails1))
+	require.NoError(t, GetCanceledErrorDetails(err, &data))
+	require.Equal(t, testErrorDetails1, data)
+
+	require.ErrorIs(t, GetCanceledErrorDetails(NewCanceledError(), &data), ErrNoData)
+	require.ErrorIs(t, GetCanceledErrorDetails(NewApplicationError("app err", "", false, nil, testErrorDetails1), &data), ErrNoData)
+}
+
+func Test_GetActivityLastHeartbeatDetails(t *testing.T) {
+	newActivityErr := func(cause error) error {
+		return NewActivityError(8, 22, "alex", &commonpb.ActivityType{Name: "activityType"}, "32283", enumspb.RETRY_STATE_TIMEOUT, cause)
+	}
+	var data string
+
+	err := newActivityErr(NewHeartbeatTimeoutError(testErrorDetails1))
+	require.NoError(t, GetActivityLastHeartbeatDetails(err, &data))
+	require.Equal(t, testErrorDetails1, data)
+
+	err = newActivityErr(NewTimeoutError("timeout", enumspb.TIMEOUT_TYPE_SCHEDULE_TO_CLOSE, NewHeartbeatTimeoutError("inner details")))
+	require.NoError(t, GetActivityLastHeartbeatDetails(err, &data))
+	require.Equal(t, "inner details", data)
+
+	var details testStruct
+	err = newActivityErr(NewTimeoutError("timeout", enumspb.TIMEOUT_TYPE_START_TO_CLOSE, nil, testErrorDetails3))
+	require.NoError(t, GetActivityLastHeartbeatDetails(err, &details))
+	require.Equal(t, testErrorDetails3, details)
+
+	err = newActivityErr(NewTimeoutError("timeout", enumspb.TIMEOUT_TYPE_SCHEDULE_TO_START, nil))
+	require.ErrorIs(t, GetActivityLastHeartbeatDetails(err, &data), ErrNoData)
+
+	err = newActivityErr(NewApplicationError("app err", "", false, nil, testErrorDetails1))
+	require.ErrorIs(t, GetActivityLastHeartbeatDetails(err, &data), ErrNoData)
+
+	require.ErrorIs(t, GetActivityLastHeartbeatDetails(nil, &data), ErrNoData)
+}
+
 func Test_TimeoutError_WithDetails(t *testing.T) {
 	testTimeoutErrorDetails(t, enumspb.TIMEOUT_TYPE_HEARTBEAT)
 	testTimeoutErrorDetails(t, enumspb.TIMEOUT_TYPE_SCHEDULE_TO_CLOSE)
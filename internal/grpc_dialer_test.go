@@ -114,6 +114,40 @@ func TestHeadersProvider_IncludedWithHeadersProvider(t *testing.T) {
 	require.Equal(t, 8, len(interceptors))
 }
 
+func TestHeadersProvider_TakesPrecedenceOverAPIKeyCredentials(t *testing.T) {
+	invokeAuthorization := func(opts *ClientOptions) []string {
+		interceptors := requiredInterceptors(opts, nil)
+		require.Equal(t, 9, len(interceptors))
+		var authorization []string
+		// Headers provider followed by credentials
+		require.NoError(t, interceptors[6](context.Background(), "method", "request", "reply", nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return interceptors[7](ctx, method, req, reply, cc,
+					func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+						md, _ := metadata.FromOutgoingContext(ctx)
+						authorization = md.Get("authorization")
+						return nil
+					}, opts...)
+			}))
+		return authorization
+	}
+
+	require.Equal(t, []string{"test-auth-token"}, invokeAuthorization(&ClientOptions{
+		HeadersProvider: authHeadersProvider{token: "test-auth-token"},
+		Credentials:     NewAPIKeyStaticCredentials("test-api-key"),
+	}))
+	require.Equal(t, []string{"Bearer test-api-key"}, invokeAuthorization(&ClientOptions{
+		HeadersProvider: tenantHeadersProvider{},
+		Credentials:     NewAPIKeyStaticCredentials("test-api-key"),
+	}))
+}
+
+type tenantHeadersProvider struct{}
+
+func (tenantHeadersProvider) GetHeaders(context.Context) (map[string]string, error) {
+	return map[string]string{"tenant": "test-tenant"}, nil
+}
+
 func TestMissingGetServerInfo(t *testing.T) {
 	// Make a gRPC server that has everything unimplemented
 	l, err := net.Listen("tcp", "127.0.0.1:0")
@@ -610,6 +644,7 @@ type testGRPCServer struct {
 	getSystemInfoResponse                workflowservice.GetSystemInfoResponse
 	getSystemInfoResponseError           error
 	lastSignalWorkflowExecutionContext   context.Context
+	lastSignalWorkflowExecutionRequest   *workflowservice.SignalWorkflowExecutionRequest
 	signalWorkflowExecutionResponse      workflowservice.SignalWorkflowExecutionResponse
 	signalWorkflowExecutionResponseError error
 }
@@ -670,10 +705,11 @@ func (t *testGRPCServer) GetSystemInfo(
 
 func (t *testGRPCServer) SignalWorkflowExecution(
 	ctx context.Context,
-	_ *workflowservice.SignalWorkflowExecutionRequest,
+	req *workflowservice.SignalWorkflowExecutionRequest,
 ) (*workflowservice.SignalWorkflowExecutionResponse, error) {
 	atomic.AddInt32(&t.sigWfCount, 1)
 	t.lastSignalWorkflowExecutionContext = ctx
+	t.lastSignalWorkflowExecutionRequest = req
 	return &t.signalWorkflowExecutionResponse, t.signalWorkflowExecutionResponseError
 }
 
@@ -37,9 +37,48 @@ type WorkerInterceptor interface {
 
 	InterceptNexusOperation(ctx context.Context, next NexusOperationInboundInterceptor) NexusOperationInboundInterceptor
 
+	// InterceptWorkerLifecycle is called once when a worker is created with the
+	// next interceptor in the chain.
+	InterceptWorkerLifecycle(next WorkerLifecycleInterceptor) WorkerLifecycleInterceptor
+
 	mustEmbedWorkerInterceptorBase()
 }
 
+// WorkerLifecycleInterceptor intercepts the start and stop of a worker. See
+// documentation in the interceptor package for more details.
+//
+// Exposed as: [go.temporal.io/sdk/interceptor.WorkerLifecycleInterceptor]
+type WorkerLifecycleInterceptor interface {
+	// Start intercepts Worker.Start and Worker.Run. The worker starts polling
+	// when the last interceptor in the chain calls Start. If an error is
+	// returned, the worker is not started, or is stopped again if it already
+	// was, and the error is returned from Worker.Start or Worker.Run.
+	Start(ctx context.Context, info WorkerLifecycleInfo) error
+
+	// Stop intercepts Worker.Stop, including stops due to a fatal error. The
+	// worker stops polling and waits for running tasks when the last
+	// interceptor in the chain calls Stop. It is called even if Start was not
+	// called or failed.
+	Stop(ctx context.Context, info WorkerLifecycleInfo)
+
+	// OnFatalError is called when the worker encounters a fatal error, before
+	// WorkerOptions.OnFatalError and before the worker is stopped.
+	OnFatalError(info WorkerLifecycleInfo, err error)
+
+	mustEmbedWorkerLifecycleInterceptorBase()
+}
+
+// WorkerLifecycleInfo describes the worker for WorkerLifecycleInterceptor
+// calls.
+//
+// Exposed as: [go.temporal.io/sdk/interceptor.WorkerLifecycleInfo]
+type WorkerLifecycleInfo struct {
+	Namespace         string
+	TaskQueue         string
+	Identity          string
+	WorkerInstanceKey string
+}
+
 // ActivityInboundInterceptor is an interface for all activity calls originating
 // from the server. See documentation in the interceptor package for more
 // details.
@@ -49,8 +49,42 @@ func (w *WorkerInterceptorBase) InterceptNexusOperation(ctx context.Context, nex
 	return &NexusOperationInboundInterceptorBase{Next: next}
 }
 
+// InterceptWorkerLifecycle implements WorkerInterceptor.InterceptWorkerLifecycle.
+func (*WorkerInterceptorBase) InterceptWorkerLifecycle(next WorkerLifecycleInterceptor) WorkerLifecycleInterceptor {
+	return &WorkerLifecycleInterceptorBase{Next: next}
+}
+
 func (*WorkerInterceptorBase) mustEmbedWorkerInterceptorBase() {}
 
+// WorkerLifecycleInterceptorBase is a default implementation of
+// WorkerLifecycleInterceptor meant for embedding. See documentation in the
+// interceptor package for more details.
+//
+// Exposed as: [go.temporal.io/sdk/interceptor.WorkerLifecycleInterceptorBase]
+type WorkerLifecycleInterceptorBase struct {
+	Next WorkerLifecycleInterceptor
+}
+
+// Exposed as: [go.temporal.io/sdk/interceptor.WorkerLifecycleInterceptor]
+var _ WorkerLifecycleInterceptor = &WorkerLifecycleInterceptorBase{}
+
+// Start implements WorkerLifecycleInterceptor.Start.
+func (w *WorkerLifecycleInterceptorBase) Start(ctx context.Context, info WorkerLifecycleInfo) error {
+	return w.Next.Start(ctx, info)
+}
+
+// Stop implements WorkerLifecycleInterceptor.Stop.
+func (w *WorkerLifecycleInterceptorBase) Stop(ctx context.Context, info WorkerLifecycleInfo) {
+	w.Next.Stop(ctx, info)
+}
+
+// OnFatalError implements WorkerLifecycleInterceptor.OnFatalError.
+func (w *WorkerLifecycleInterceptorBase) OnFatalError(info WorkerLifecycleInfo, err error) {
+	w.Next.OnFatalError(info, err)
+}
+
+func (*WorkerLifecycleInterceptorBase) mustEmbedWorkerLifecycleInterceptorBase() {}
+
 // ActivityInboundInterceptorBase is a default implementation of
 // ActivityInboundInterceptor meant for embedding. See documentation in the
 // interceptor package for more details.
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// WorkerInterceptorChain assembles worker interceptors in an explicit order by
+// name, so interceptors from different libraries can be positioned relative to
+// each other (e.g. a logging interceptor right after tracing) without
+// reordering a slice by hand. Interceptors earlier in the built chain wrap
+// later ones, the same as in WorkerOptions.Interceptors.
+//
+// Errors such as duplicate names or positioning relative to an unknown name
+// are reported by Build.
+//
+// Exposed as: [go.temporal.io/sdk/interceptor.WorkerInterceptorChain]
+type WorkerInterceptorChain struct {
+	names        []string
+	interceptors []WorkerInterceptor
+	err          error
+}
+
+// NewWorkerInterceptorChain creates an empty WorkerInterceptorChain.
+//
+// Exposed as: [go.temporal.io/sdk/interceptor.NewWorkerInterceptorChain]
+func NewWorkerInterceptorChain() *WorkerInterceptorChain {
+	return &WorkerInterceptorChain{}
+}
+
+// Append adds the interceptor to the end of the chain, making it the innermost
+// interceptor so far.
+func (c *WorkerInterceptorChain) Append(name string, interceptor WorkerInterceptor) *WorkerInterceptorChain {
+	return c.insert(len(c.names), name, interceptor)
+}
+
+// Prepend adds the interceptor to the start of the chain, making it the
+// outermost interceptor so far.
+func (c *WorkerInterceptorChain) Prepend(name string, interceptor WorkerInterceptor) *WorkerInterceptorChain {
+	return c.insert(0, name, interceptor)
+}
+
+// InsertBefore adds the interceptor immediately before the interceptor named
+// before, so it wraps that interceptor.
+func (c *WorkerInterceptorChain) InsertBefore(before, name string, interceptor WorkerInterceptor) *WorkerInterceptorChain {
+	i := slices.Index(c.names, before)
+	if i < 0 {
+		return c.fail(fmt.Errorf("cannot insert interceptor %q before unknown interceptor %q", name, before))
+	}
+	return c.insert(i, name, interceptor)
+}
+
+// InsertAfter adds the interceptor immediately after the interceptor named
+// after, so it is wrapped by that interceptor.
+func (c *WorkerInterceptorChain) InsertAfter(after, name string, interceptor WorkerInterceptor) *WorkerInterceptorChain {
+	i := slices.Index(c.names, after)
+	if i < 0 {
+		return c.fail(fmt.Errorf("cannot insert interceptor %q after unknown interceptor %q", name, after))
+	}
+	return c.insert(i+1, name, interceptor)
+}
+
+// Names returns the names of the interceptors in chain order.
+func (c *WorkerInterceptorChain) Names() []string {
+	return slices.Clone(c.names)
+}
+
+// Build returns the interceptors in chain order for use as
+// WorkerOptions.Interceptors, or the first error encountered while assembling
+// the chain.
+func (c *WorkerInterceptorChain) Build() ([]WorkerInterceptor, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return slices.Clone(c.interceptors), nil
+}
+
+func (c *WorkerInterceptorChain) insert(i int, name string, interceptor WorkerInterceptor) *WorkerInterceptorChain {
+	if c.err != nil {
+		return c
+	}
+	if name == "" {
+		return c.fail(fmt.Errorf("interceptor name must not be empty"))
+	} else if interceptor == nil {
+		return c.fail(fmt.Errorf("interceptor %q must not be nil", name))
+	} else if slices.Contains(c.names, name) {
+		return c.fail(fmt.Errorf("duplicate interceptor name %q", name))
+	}
+	c.names = slices.Insert(c.names, i, name)
+	c.interceptors = slices.Insert(c.interceptors, i, interceptor)
+	return c
+}
+
+func (c *WorkerInterceptorChain) fail(err error) *WorkerInterceptorChain {
+	if c.err == nil {
+		c.err = err
+	}
+	return c
+}
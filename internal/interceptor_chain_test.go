@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type namedTestWorkerInterceptor struct {
+	WorkerInterceptorBase
+	name string
+}
+
+func TestWorkerInterceptorChain(t *testing.T) {
+	tracing := &namedTestWorkerInterceptor{name: "tracing"}
+	metrics := &namedTestWorkerInterceptor{name: "metrics"}
+	logging := &namedTestWorkerInterceptor{name: "logging"}
+	auth := &namedTestWorkerInterceptor{name: "auth"}
+
+	chain := NewWorkerInterceptorChain().
+		Append("tracing", tracing).
+		Append("metrics", metrics).
+		InsertAfter("tracing", "logging", logging).
+		Prepend("auth", auth)
+	interceptors, err := chain.Build()
+	require.NoError(t, err)
+	require.Equal(t, []string{"auth", "tracing", "logging", "metrics"}, chain.Names())
+	require.Equal(t, []WorkerInterceptor{auth, tracing, logging, metrics}, interceptors)
+
+	interceptors, err = NewWorkerInterceptorChain().
+		Append("metrics", metrics).
+		InsertBefore("metrics", "tracing", tracing).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, []WorkerInterceptor{tracing, metrics}, interceptors)
+}
+
+func TestWorkerInterceptorChain_Errors(t *testing.T) {
+	i := &namedTestWorkerInterceptor{}
+
+	_, err := NewWorkerInterceptorChain().Append("a", i).Append("a", i).Build()
+	require.EqualError(t, err, `duplicate interceptor name "a"`)
+
+	_, err = NewWorkerInterceptorChain().InsertAfter("tracing", "a", i).Build()
+	require.EqualError(t, err, `cannot insert interceptor "a" after unknown interceptor "tracing"`)
+
+	_, err = NewWorkerInterceptorChain().InsertBefore("tracing", "a", i).Build()
+	require.EqualError(t, err, `cannot insert interceptor "a" before unknown interceptor "tracing"`)
+
+	_, err = NewWorkerInterceptorChain().Append("", i).Build()
+	require.Error(t, err)
+
+	_, err = NewWorkerInterceptorChain().Append("a", nil).Build()
+	require.Error(t, err)
+
+	// The first error is kept even if later calls are valid
+	_, err = NewWorkerInterceptorChain().Append("a", i).Append("a", i).Append("b", i).Build()
+	require.EqualError(t, err, `duplicate interceptor name "a"`)
+}
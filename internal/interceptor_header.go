@@ -3,10 +3,23 @@ package internal
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
 
 	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
 )
 
+// skipIfWorkflowCancelledHeaderKey is set in the header of activities scheduled with
+// ActivityOptions.SkipIfWorkflowCancelled, so the activity worker knows to check the workflow before running them. The
+// server only passes the header to the activity worker, which removes the key before context propagators and
+// interceptors see the header.
+const skipIfWorkflowCancelledHeaderKey = temporalPrefix + "skip_if_workflow_cancelled"
+
+// startWorkflowHeaderPrefix prefixes the keys of StartWorkflowOptions.Headers in the workflow header, so they can be
+// told apart from the fields set by context propagators and interceptors.
+const startWorkflowHeaderPrefix = temporalPrefix + "header_"
+
 type headerKey struct{}
 
 // Header provides Temporal header information from the context for reading or
@@ -101,6 +114,30 @@ func workflowContextWithHeaderPropagated(
 	return WithValue(ctx, headerKey{}, header.Fields), nil
 }
 
+// withSkipIfWorkflowCancelledHeader returns a copy of the header with skipIfWorkflowCancelledHeaderKey set.
+func withSkipIfWorkflowCancelledHeader(header *commonpb.Header) (*commonpb.Header, error) {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(true)
+	if err != nil {
+		return nil, err
+	}
+	fields := maps.Clone(header.GetFields())
+	if fields == nil {
+		fields = map[string]*commonpb.Payload{}
+	}
+	fields[skipIfWorkflowCancelledHeaderKey] = payload
+	return &commonpb.Header{Fields: fields}, nil
+}
+
+// withoutSkipIfWorkflowCancelledHeader returns header without the field set by withSkipIfWorkflowCancelledHeader.
+func withoutSkipIfWorkflowCancelledHeader(header *commonpb.Header) *commonpb.Header {
+	if _, ok := header.GetFields()[skipIfWorkflowCancelledHeaderKey]; !ok {
+		return header
+	}
+	fields := maps.Clone(header.GetFields())
+	delete(fields, skipIfWorkflowCancelledHeaderKey)
+	return &commonpb.Header{Fields: fields}
+}
+
 func workflowHeaderPropagated(ctx Context, ctxProps []ContextPropagator) (*commonpb.Header, error) {
 	header := &commonpb.Header{Fields: WorkflowHeader(ctx)}
 	if header.Fields == nil {
@@ -114,3 +151,25 @@ func workflowHeaderPropagated(ctx Context, ctxProps []ContextPropagator) (*commo
 	}
 	return header, nil
 }
+
+// setStartWorkflowHeaders encodes StartWorkflowOptions.Headers into header.
+func setStartWorkflowHeaders(header *commonpb.Header, headers map[string]interface{}, dc converter.DataConverter) error {
+	for key, value := range headers {
+		payload, err := dc.ToPayload(value)
+		if err != nil {
+			return fmt.Errorf("failed encoding header %q: %w", key, err)
+		}
+		header.Fields[startWorkflowHeaderPrefix+key] = payload
+	}
+	return nil
+}
+
+// propagateStartWorkflowHeaders copies the StartWorkflowOptions.Headers the current workflow was started with to the
+// header of a child workflow or continue-as-new, unless already set.
+func propagateStartWorkflowHeaders(ctx Context, header *commonpb.Header) {
+	for key, payload := range getWorkflowEnvironmentInterceptor(ctx).startHeader {
+		if _, ok := header.Fields[key]; !ok && strings.HasPrefix(key, startWorkflowHeaderPrefix) {
+			header.Fields[key] = payload
+		}
+	}
+}
@@ -38,27 +38,32 @@ type (
 
 	// ExecuteActivityOptions option for executing an activity
 	ExecuteActivityOptions struct {
-		ActivityID             string // Users can choose IDs but our framework makes it optional to decrease the crust.
-		TaskQueueName          string
-		ScheduleToCloseTimeout time.Duration
-		ScheduleToStartTimeout time.Duration
-		StartToCloseTimeout    time.Duration
-		HeartbeatTimeout       time.Duration
-		WaitForCancellation    bool
-		OriginalTaskQueueName  string
-		RetryPolicy            *commonpb.RetryPolicy
-		DisableEagerExecution  bool
-		VersioningIntent       VersioningIntent
-		Summary                string
-		Priority               *commonpb.Priority
+		ActivityID              string // Users can choose IDs but our framework makes it optional to decrease the crust.
+		TaskQueueName           string
+		ScheduleToCloseTimeout  time.Duration
+		ScheduleToStartTimeout  time.Duration
+		StartToCloseTimeout     time.Duration
+		HeartbeatTimeout        time.Duration
+		WaitForCancellation     bool
+		OriginalTaskQueueName   string
+		RetryPolicy             *commonpb.RetryPolicy
+		DisableEagerExecution   bool
+		VersioningIntent        VersioningIntent
+		Summary                 string
+		Priority                *commonpb.Priority
+		NonRetryableDecodeError bool
+		SkipIfWorkflowCancelled bool
+		OnRetry                 func(attempt int32, lastErr error)
 	}
 
 	// ExecuteLocalActivityOptions options for executing a local activity
 	ExecuteLocalActivityOptions struct {
-		ScheduleToCloseTimeout time.Duration
-		StartToCloseTimeout    time.Duration
-		RetryPolicy            *RetryPolicy
-		Summary                string
+		ScheduleToCloseTimeout  time.Duration
+		StartToCloseTimeout     time.Duration
+		RetryPolicy             *RetryPolicy
+		Summary                 string
+		NonRetryableDecodeError bool
+		OnRetry                 func(attempt int32, lastErr error)
 	}
 
 	// ExecuteActivityParams parameters for executing an activity
@@ -81,6 +86,8 @@ type (
 		Attempt       int32
 		ScheduledTime time.Time
 		Header        *commonpb.Header
+		// primaryActivityType is the type reported by the activity, the primary name if ActivityType is an alias.
+		primaryActivityType string
 	}
 
 	// AsyncActivityClient for requesting activity execution
@@ -118,6 +125,7 @@ type (
 		deadline               time.Time
 		scheduledTime          time.Time
 		startedTime            time.Time
+		scheduleToStartLatency time.Duration
 		taskQueue              string
 		dataConverter          converter.DataConverter
 		attempt                int32 // starts from 1.
@@ -292,6 +300,28 @@ func isActivityContext(inType reflect.Type) bool {
 	return inType != nil && inType.Implements(contextElem)
 }
 
+// applyActivityDefaultOptions returns a copy of options with the options left unset filled in from the defaults the
+// activity type was registered with.
+func applyActivityDefaultOptions(options *ExecuteActivityOptions, defaults *ActivityDefaultOptions) *ExecuteActivityOptions {
+	result := *options
+	if result.ScheduleToCloseTimeout == 0 {
+		result.ScheduleToCloseTimeout = defaults.ScheduleToCloseTimeout
+	}
+	if result.ScheduleToStartTimeout == 0 {
+		result.ScheduleToStartTimeout = defaults.ScheduleToStartTimeout
+	}
+	if result.StartToCloseTimeout == 0 {
+		result.StartToCloseTimeout = defaults.StartToCloseTimeout
+	}
+	if result.HeartbeatTimeout == 0 {
+		result.HeartbeatTimeout = defaults.HeartbeatTimeout
+	}
+	if result.RetryPolicy == nil {
+		result.RetryPolicy = convertToPBRetryPolicy(defaults.RetryPolicy)
+	}
+	return &result
+}
+
 func setActivityParametersIfNotExist(ctx Context) Context {
 	params := getActivityOptions(ctx)
 	var newParams ExecuteActivityOptions
@@ -368,6 +398,7 @@ func (a *activityEnvironmentInterceptor) GetInfo(ctx context.Context) ActivityIn
 		Deadline:               a.env.deadline,
 		ScheduledTime:          a.env.scheduledTime,
 		StartedTime:            a.env.startedTime,
+		ScheduleToStartLatency: a.env.scheduleToStartLatency,
 		TaskQueue:              a.env.taskQueue,
 		Namespace:              a.env.namespace,
 		Attempt:                a.env.attempt,
@@ -651,7 +651,7 @@ func (w *workflowClientInterceptor) PollActivityResult(
 
 	var resp *workflowservice.PollActivityExecutionResponse
 	for resp.GetOutcome() == nil {
-		grpcCtx, cancel := newGRPCContext(ctx, grpcLongPoll(true), grpcTimeout(pollActivityTimeout), defaultGrpcRetryParameters(ctx))
+		grpcCtx, cancel := newGRPCContext(ctx, grpcLongPoll(true), grpcTimeout(pollActivityTimeout), w.client.longPollGrpcRetryParameters(ctx))
 		var err error
 		resp, err = w.client.WorkflowService().PollActivityExecution(grpcCtx, request)
 		cancel()
@@ -466,6 +466,40 @@ func TestNotBlockingSelect(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestSelectClearDefault(t *testing.T) {
+	var history []string
+	d := createNewDispatcher(func(ctx Context) {
+		c := NewChannel(ctx)
+		s := NewSelector(ctx)
+		s.AddReceive(c, func(c ReceiveChannel, more bool) {
+			var v string
+			c.Receive(ctx, &v)
+			history = append(history, v)
+		})
+		require.False(t, s.HasDefault())
+		s.AddDefault(func() { history = append(history, "default1") })
+		require.True(t, s.HasDefault())
+		s.Select(ctx)
+		// The default branch can be replaced
+		s.AddDefault(func() { history = append(history, "default2") })
+		s.Select(ctx)
+
+		// Without the default branch Select blocks until the channel is ready
+		s.ClearDefault()
+		require.False(t, s.HasDefault())
+		Go(ctx, func(ctx Context) {
+			history = append(history, "send")
+			c.Send(ctx, "one")
+		})
+		s.Select(ctx)
+		history = append(history, "done")
+	})
+	defer d.Close()
+	requireNoExecuteErr(t, d.ExecuteUntilAllBlocked(defaultDeadlockDetectionTimeout))
+	require.True(t, d.IsDone(), strings.Join(history, "\n"))
+	require.EqualValues(t, []string{"default1", "default2", "send", "one", "done"}, history)
+}
+
 func TestBlockingSelect(t *testing.T) {
 	var history []string
 	d := createNewDispatcher(func(ctx Context) {
@@ -58,6 +58,7 @@ type (
 		waitForCancelRequest bool
 		handled              bool
 		activityType         ActivityType
+		onRetry              func(attempt int32, lastErr error)
 	}
 
 	scheduledNexusOperation struct {
@@ -179,6 +180,7 @@ type (
 		expireTime      time.Time
 		scheduledTime   time.Time // Time the activity was scheduled initially.
 		header          *commonpb.Header
+		lastErr         error // Error of the previous attempt, set when retrying in-process.
 	}
 
 	localActivityMarkerData struct {
@@ -759,6 +761,7 @@ func (wc *workflowEnvironmentImpl) ExecuteActivity(parameters ExecuteActivityPar
 		callback:             callback,
 		waitForCancelRequest: parameters.WaitForCancellation,
 		activityType:         parameters.ActivityType,
+		onRetry:              parameters.OnRetry,
 	})
 
 	wc.logger.Debug("ExecuteActivity",
@@ -1229,7 +1232,7 @@ func (weh *workflowExecutionEventHandlerImpl) ProcessEvent(
 			event.GetActivityTaskScheduledEventAttributes().GetActivityId(), event.GetEventId())
 
 	case enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED:
-		// No Operation
+		weh.handleActivityTaskStarted(event)
 
 	case enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED:
 		err = weh.handleActivityTaskCompleted(event)
@@ -1467,6 +1470,22 @@ func (weh *workflowExecutionEventHandlerImpl) handleWorkflowExecutionStarted(
 	return nil
 }
 
+func (weh *workflowExecutionEventHandlerImpl) handleActivityTaskStarted(event *historypb.HistoryEvent) {
+	attributes := event.GetActivityTaskStartedEventAttributes()
+	if weh.isReplay || attributes.GetAttempt() <= 1 {
+		return
+	}
+	activityID, ok := weh.commandsHelper.scheduledEventIDToActivityID[attributes.GetScheduledEventId()]
+	if !ok {
+		return
+	}
+	activity, ok := weh.commandsHelper.getCommand(makeCommandID(commandTypeActivity, activityID)).getData().(*scheduledActivity)
+	if !ok || activity.handled || activity.onRetry == nil {
+		return
+	}
+	activity.onRetry(attributes.GetAttempt(), weh.GetFailureConverter().FailureToError(attributes.GetLastFailure()))
+}
+
 func (weh *workflowExecutionEventHandlerImpl) handleActivityTaskCompleted(event *historypb.HistoryEvent) error {
 	activityID, scheduledEventID := weh.commandsHelper.getActivityAndScheduledEventIDs(event)
 	command := weh.commandsHelper.handleActivityTaskClosed(activityID, scheduledEventID)
@@ -1761,6 +1780,10 @@ func (weh *workflowExecutionEventHandlerImpl) ProcessLocalActivityResult(lar *lo
 func (weh *workflowExecutionEventHandlerImpl) handleWorkflowExecutionSignaled(
 	attributes *historypb.WorkflowExecutionSignaledEventAttributes,
 ) error {
+	if attributes.GetSignalName() == forceWorkflowTaskSignalName {
+		// Only sent to trigger a workflow task, see Client.ForceNewWorkflowTask
+		return nil
+	}
 	return weh.signalHandler(attributes.GetSignalName(), attributes.Input, attributes.Header)
 }
 
@@ -34,4 +34,14 @@ const (
 	tagPanicStack                   = "PanicStack"
 	tagUpdateID                     = "UpdateID"
 	tagUpdateName                   = "UpdateName"
+	tagCommandType                  = "CommandType"
+	tagCommandIndex                 = "CommandIndex"
+	tagScheduledEventID             = "ScheduledEventID"
+	tagStartToFireTimeout           = "StartToFireTimeout"
+	tagMarkerName                   = "MarkerName"
+	tagChildWorkflowType            = "ChildWorkflowType"
+	tagTargetWorkflowID             = "TargetWorkflowID"
+	tagSignalName                   = "SignalName"
+	tagNewWorkflowType              = "NewWorkflowType"
+	tagMessageID                    = "MessageID"
 )
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"errors"
+
+	commandpb "go.temporal.io/api/command/v1"
+	historypb "go.temporal.io/api/history/v1"
+
+	"go.temporal.io/sdk/log"
+)
+
+type (
+	// ReplayVariant is a version of the workflow code to replay a history with in BisectReplay.
+	//
+	// Exposed as: [go.temporal.io/sdk/testsuite.ReplayVariant]
+	ReplayVariant struct {
+		// Label identifies the code change of the variant, e.g. the output of git describe for the commit the
+		// workflows were built from.
+		Label string
+
+		// Replayer replays the history with the workflows of the variant, e.g. a worker.WorkflowReplayer the
+		// workflows of the variant are registered with.
+		Replayer interface {
+			ReplayWorkflowHistory(logger log.Logger, history *historypb.History) error
+		}
+	}
+
+	// ReplayBisectResult is the result of BisectReplay.
+	//
+	// Exposed as: [go.temporal.io/sdk/testsuite.ReplayBisectResult]
+	ReplayBisectResult struct {
+		// Index of the first variant that fails to replay the history, -1 if every variant replays it.
+		Index int
+
+		// Label of the first variant that fails to replay the history, empty if every variant replays it.
+		Label string
+
+		// Err is the error the variant failed to replay the history with.
+		Err error
+
+		// Event is the history event where the replay of the variant diverges from the history. It is nil if the
+		// variant produced a command the history has no event for, or if the replay did not fail on a mismatch
+		// between the commands and the history, e.g. because the workflow panicked.
+		Event *historypb.HistoryEvent
+
+		// Command is the command the variant produced in place of Event. It is nil if the variant produced no
+		// command for Event, or if the replay did not fail on a mismatch between the commands and the history.
+		Command *commandpb.Command
+	}
+)
+
+// BisectReplay finds the first of the variants that fails to replay the history, e.g. to find the code change that
+// broke the determinism of a workflow whose history fails to replay in production. Variants must be ordered from the
+// oldest to the newest change and a variant is assumed to fail if any older one fails, so only about log2(len(variants))
+// of them are replayed.
+//
+// Exposed as: [go.temporal.io/sdk/testsuite.BisectReplay]
+func BisectReplay(logger log.Logger, history *historypb.History, variants []ReplayVariant) (ReplayBisectResult, error) {
+	if len(variants) == 0 {
+		return ReplayBisectResult{}, errors.New("no variants to bisect")
+	}
+	errs := make(map[int]error, len(variants))
+	replay := func(i int) error {
+		if err, ok := errs[i]; ok {
+			return err
+		}
+		err := variants[i].Replayer.ReplayWorkflowHistory(logger, history)
+		errs[i] = err
+		return err
+	}
+
+	// Find the first failing variant, keeping lo the index of one that may fail and hi the index of one that fails
+	if replay(len(variants)-1) == nil {
+		return ReplayBisectResult{Index: -1}, nil
+	}
+	lo, hi := 0, len(variants)-1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if replay(mid) != nil {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	result := ReplayBisectResult{Index: hi, Label: variants[hi].Label, Err: errs[hi]}
+	var mismatch historyMismatchError
+	if errors.As(result.Err, &mismatch) {
+		result.Event = mismatch.event
+		result.Command = mismatch.command
+	}
+	return result, nil
+}
@@ -4,9 +4,11 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.temporal.io/sdk/internal/common/backoff"
+	"go.temporal.io/sdk/internal/common/retry"
 )
 
 const (
@@ -39,3 +41,63 @@ func createDynamicServiceRetryPolicy(ctx context.Context) backoff.RetryPolicy {
 	policy.SetExpirationInterval(timeout)
 	return policy
 }
+
+// Creates a retry policy for long-poll calls. Unset options fall back to createDynamicServiceRetryPolicy.
+func createLongPollRetryPolicy(ctx context.Context, options *LongPollRetryOptions) backoff.RetryPolicy {
+	if options == nil {
+		return createDynamicServiceRetryPolicy(ctx)
+	}
+	timeout := retryServiceOperationExpirationInterval
+	if ctx != nil {
+		now := time.Now()
+		if expiration, ok := ctx.Deadline(); ok && expiration.After(now) {
+			timeout = expiration.Sub(now)
+		}
+	}
+	if options.ExpirationInterval > 0 {
+		timeout = options.ExpirationInterval
+	}
+	initialInterval := retryServiceOperationInitialInterval
+	if options.InitialInterval > 0 {
+		initialInterval = options.InitialInterval
+	}
+	backoffCoefficient := float64(retryServiceOperationBackoff)
+	if options.BackoffCoefficient > 0 {
+		backoffCoefficient = options.BackoffCoefficient
+	}
+	maximumInterval := options.MaximumInterval
+	if maximumInterval == 0 {
+		maximumInterval = timeout / 10
+	}
+	if maximumInterval < initialInterval {
+		maximumInterval = initialInterval
+	}
+
+	policy := backoff.NewExponentialRetryPolicy(initialInterval)
+	policy.SetBackoffCoefficient(backoffCoefficient)
+	policy.SetMaximumInterval(maximumInterval)
+	policy.SetExpirationInterval(timeout)
+	return policy
+}
+
+// Returns the retry policy for long-poll calls made by this client.
+func (wc *WorkflowClient) longPollRetryPolicy(ctx context.Context) backoff.RetryPolicy {
+	return createLongPollRetryPolicy(ctx, wc.longPollRetryOptions)
+}
+
+func (wc *WorkflowClient) longPollGrpcRetryParameters(ctx context.Context) func(builder *grpcContextBuilder) {
+	return grpcContextValue(retry.ConfigKey, wc.longPollRetryPolicy(ctx).GrpcRetryConfig())
+}
+
+func (o *LongPollRetryOptions) validate() error {
+	if o.InitialInterval < 0 || o.MaximumInterval < 0 || o.ExpirationInterval < 0 {
+		return errors.New("LongPollRetryOptions intervals must not be negative")
+	}
+	if o.BackoffCoefficient != 0 && o.BackoffCoefficient < 1 {
+		return errors.New("LongPollRetryOptions.BackoffCoefficient must be at least 1")
+	}
+	if o.MaximumInterval != 0 && o.MaximumInterval < o.InitialInterval {
+		return errors.New("LongPollRetryOptions.MaximumInterval must not be less than InitialInterval")
+	}
+	return nil
+}
@@ -21,6 +21,13 @@ import (
 	"go.temporal.io/sdk/log"
 )
 
+var (
+	// How often TriggerAndWait and AwaitNextRun describe the schedule while waiting for an action.
+	scheduleTriggerAndWaitPollInterval = time.Second
+	// How many times TriggerAndWait describes the schedule before giving up on the triggered action.
+	scheduleTriggerAndWaitMaxPolls = 60
+)
+
 type (
 
 	// ScheduleClient is the client for starting a workflow execution.
@@ -242,6 +249,75 @@ func (scheduleHandle *scheduleHandleImpl) Backfill(ctx context.Context, options
 	return err
 }
 
+func (scheduleHandle *scheduleHandleImpl) BackfillAndWait(ctx context.Context, options ScheduleBackfillOptions) (*ScheduleBackfillResult, error) {
+	result := &ScheduleBackfillResult{}
+	matchingTimes := map[time.Time]bool{}
+	for _, backfill := range options.Backfill {
+		times, err := scheduleHandle.listMatchingTimes(ctx, backfill.Start, backfill.End)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range times {
+			result.MatchingTimes = append(result.MatchingTimes, t)
+			matchingTimes[t] = true
+		}
+	}
+
+	describeResponse, err := scheduleHandle.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	before := describeResponse.GetInfo()
+
+	if err := scheduleHandle.Backfill(ctx, options); err != nil {
+		return nil, err
+	}
+
+	for {
+		describeResponse, err = scheduleHandle.describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info := describeResponse.GetInfo()
+		actionCount := info.GetActionCount() - before.GetActionCount()
+		result.OverlapSkipped = info.GetOverlapSkipped() - before.GetOverlapSkipped()
+		result.BufferDropped = info.GetBufferDropped() - before.GetBufferDropped()
+		if actionCount+result.OverlapSkipped+result.BufferDropped >= int64(len(result.MatchingTimes)) {
+			for _, action := range convertFromPBScheduleActionResultList(info.GetRecentActions()) {
+				if matchingTimes[action.ScheduleTime] {
+					result.Actions = append(result.Actions, action)
+				}
+			}
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(scheduleTriggerAndWaitPollInterval):
+		}
+	}
+}
+
+func (scheduleHandle *scheduleHandleImpl) listMatchingTimes(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	request := &workflowservice.ListScheduleMatchingTimesRequest{
+		Namespace:  scheduleHandle.client.namespace,
+		ScheduleId: scheduleHandle.ID,
+		StartTime:  timestamppb.New(start),
+		EndTime:    timestamppb.New(end),
+	}
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	response, err := scheduleHandle.client.workflowService.ListScheduleMatchingTimes(grpcCtx, request)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, len(response.GetStartTime()))
+	for i, t := range response.GetStartTime() {
+		times[i] = t.AsTime()
+	}
+	return times, nil
+}
+
 func (scheduleHandle *scheduleHandleImpl) Update(ctx context.Context, options ScheduleUpdateOptions) error {
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
@@ -296,13 +372,7 @@ func (scheduleHandle *scheduleHandleImpl) Update(ctx context.Context, options Sc
 }
 
 func (scheduleHandle *scheduleHandleImpl) Describe(ctx context.Context) (*ScheduleDescription, error) {
-	request := &workflowservice.DescribeScheduleRequest{
-		Namespace:  scheduleHandle.client.namespace,
-		ScheduleId: scheduleHandle.ID,
-	}
-	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
-	defer cancel()
-	describeResponse, err := scheduleHandle.client.workflowService.DescribeSchedule(grpcCtx, request)
+	describeResponse, err := scheduleHandle.describe(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -311,12 +381,17 @@ func (scheduleHandle *scheduleHandleImpl) Describe(ctx context.Context) (*Schedu
 }
 
 func (scheduleHandle *scheduleHandleImpl) Trigger(ctx context.Context, options ScheduleTriggerOptions) error {
+	return scheduleHandle.trigger(ctx, options, nil)
+}
+
+func (scheduleHandle *scheduleHandleImpl) trigger(ctx context.Context, options ScheduleTriggerOptions, scheduledTime *timestamppb.Timestamp) error {
 	request := &workflowservice.PatchScheduleRequest{
 		Namespace:  scheduleHandle.client.namespace,
 		ScheduleId: scheduleHandle.ID,
 		Patch: &schedulepb.SchedulePatch{
 			TriggerImmediately: &schedulepb.TriggerImmediatelyRequest{
 				OverlapPolicy: options.Overlap,
+				ScheduledTime: scheduledTime,
 			},
 		},
 		Identity:  scheduleHandle.client.identity,
@@ -328,6 +403,106 @@ func (scheduleHandle *scheduleHandleImpl) Trigger(ctx context.Context, options S
 	return err
 }
 
+func (scheduleHandle *scheduleHandleImpl) ListRecentActions(ctx context.Context, n int) ([]ScheduleActionResult, error) {
+	describeResponse, err := scheduleHandle.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recentActions := convertFromPBScheduleActionResultList(describeResponse.GetInfo().GetRecentActions())
+	if n > 0 && len(recentActions) > n {
+		recentActions = recentActions[len(recentActions)-n:]
+	}
+	return recentActions, nil
+}
+
+func (scheduleHandle *scheduleHandleImpl) TriggerAndWait(ctx context.Context, options ScheduleTriggerOptions) (WorkflowRun, error) {
+	describeResponse, err := scheduleHandle.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := describeResponse.GetInfo()
+	actionCount := info.GetActionCount()
+	skippedCount := info.GetOverlapSkipped() + info.GetBufferDropped()
+
+	// The scheduled time identifies the triggered action among the ones the schedule takes concurrently.
+	scheduledTime := timestamppb.New(time.Now().UTC().Truncate(time.Millisecond))
+	if err := scheduleHandle.trigger(ctx, options, scheduledTime); err != nil {
+		return nil, err
+	}
+
+	for polls := 1; ; polls++ {
+		describeResponse, err = scheduleHandle.describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		info = describeResponse.GetInfo()
+		for _, action := range info.GetRecentActions() {
+			if !action.GetScheduleTime().AsTime().Equal(scheduledTime.AsTime()) {
+				continue
+			}
+			result := action.GetStartWorkflowResult()
+			if result == nil {
+				return nil, errors.New("schedule action did not start a workflow")
+			}
+			return scheduleHandle.client.GetWorkflow(ctx, result.GetWorkflowId(), result.GetRunId()), nil
+		}
+		if info.GetActionCount() == actionCount && info.GetOverlapSkipped()+info.GetBufferDropped() > skippedCount {
+			return nil, fmt.Errorf("schedule %q: triggered action was skipped by the overlap policy", scheduleHandle.ID)
+		}
+		if polls >= scheduleTriggerAndWaitMaxPolls {
+			return nil, fmt.Errorf("schedule %q: triggered action not reported after %d polls", scheduleHandle.ID, polls)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(scheduleTriggerAndWaitPollInterval):
+		}
+	}
+}
+
+func (scheduleHandle *scheduleHandleImpl) AwaitNextRun(ctx context.Context) (WorkflowRun, error) {
+	var actionCount int64
+	for first := true; ; first = false {
+		describeResponse, err := scheduleHandle.describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if describeResponse.GetSchedule().GetState().GetPaused() {
+			return nil, fmt.Errorf("schedule %q: %w", scheduleHandle.ID, ErrSchedulePaused)
+		}
+		info := describeResponse.GetInfo()
+		if first {
+			actionCount = info.GetActionCount()
+		} else if newActions := info.GetActionCount() - actionCount; newActions > 0 {
+			recentActions := info.GetRecentActions()
+			if len(recentActions) > 0 {
+				// The earliest of the new actions, or the earliest still known to the server
+				action := recentActions[max(0, int64(len(recentActions))-newActions)]
+				result := action.GetStartWorkflowResult()
+				if result == nil {
+					return nil, errors.New("schedule action did not start a workflow")
+				}
+				return scheduleHandle.client.GetWorkflow(ctx, result.GetWorkflowId(), result.GetRunId()), nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(scheduleTriggerAndWaitPollInterval):
+		}
+	}
+}
+
+func (scheduleHandle *scheduleHandleImpl) describe(ctx context.Context) (*workflowservice.DescribeScheduleResponse, error) {
+	request := &workflowservice.DescribeScheduleRequest{
+		Namespace:  scheduleHandle.client.namespace,
+		ScheduleId: scheduleHandle.ID,
+	}
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	return scheduleHandle.client.workflowService.DescribeSchedule(grpcCtx, request)
+}
+
 func (scheduleHandle *scheduleHandleImpl) Pause(ctx context.Context, options SchedulePauseOptions) error {
 	pauseNote := "Paused via Go SDK"
 	if options.Note != "" {
@@ -845,6 +1020,7 @@ func convertFromPBScheduleActionResultList(aa []*schedulepb.ScheduleActionResult
 			ScheduleTime:        a.GetScheduleTime().AsTime(),
 			ActualTime:          a.GetActualTime().AsTime(),
 			StartWorkflowResult: workflowExecution,
+			StartWorkflowStatus: a.GetStartWorkflowStatus(),
 		}
 	}
 	return recentActions
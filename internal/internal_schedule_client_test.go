@@ -4,14 +4,18 @@ import (
 	"context"
 	iconverter "go.temporal.io/sdk/internal/converter"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
 	schedulepb "go.temporal.io/api/schedule/v1"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -325,3 +329,225 @@ func (s *scheduleClientTestSuite) TestCreateScheduleWorkflowMemoUserAndDefaultCo
 		testFn()
 	})
 }
+
+func (s *scheduleClientTestSuite) TestListRecentActions() {
+	describeResp := &workflowservice.DescribeScheduleResponse{
+		Schedule: &schedulepb.Schedule{Action: &schedulepb.ScheduleAction{}},
+		Info: &schedulepb.ScheduleInfo{
+			RecentActions: []*schedulepb.ScheduleActionResult{
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-1"}},
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-2", RunId: "run-2"}},
+				{
+					StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-3", RunId: "run-3"},
+					StartWorkflowStatus: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+				},
+			},
+		},
+	}
+	s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(describeResp, nil).Times(2)
+
+	handle := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID)
+	actions, err := handle.ListRecentActions(context.Background(), 2)
+	s.NoError(err)
+	s.Len(actions, 2)
+	s.Equal("wf-2", actions[0].StartWorkflowResult.WorkflowID)
+	s.Equal("wf-3", actions[1].StartWorkflowResult.WorkflowID)
+	s.Equal(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, actions[1].StartWorkflowStatus)
+
+	actions, err = handle.ListRecentActions(context.Background(), 0)
+	s.NoError(err)
+	s.Len(actions, 3)
+}
+
+func (s *scheduleClientTestSuite) TestListRecentActionsNeverFired() {
+	describeResp := &workflowservice.DescribeScheduleResponse{
+		Schedule: &schedulepb.Schedule{Action: &schedulepb.ScheduleAction{}},
+		Info:     &schedulepb.ScheduleInfo{},
+	}
+	s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(describeResp, nil).Times(1)
+
+	actions, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).ListRecentActions(context.Background(), 5)
+	s.NoError(err)
+	s.Empty(actions)
+}
+
+func (s *scheduleClientTestSuite) TestTriggerAndWait() {
+	before := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{},
+	}
+	var scheduledTime *timestamppb.Timestamp
+	gomock.InOrder(
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(before, nil),
+		s.service.EXPECT().PatchSchedule(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *workflowservice.PatchScheduleRequest, _ ...interface{}) (*workflowservice.PatchScheduleResponse, error) {
+				s.Equal(enumspb.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL, req.GetPatch().GetTriggerImmediately().GetOverlapPolicy())
+				scheduledTime = req.GetPatch().GetTriggerImmediately().GetScheduledTime()
+				s.NotNil(scheduledTime)
+				return &workflowservice.PatchScheduleResponse{}, nil
+			}),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *workflowservice.DescribeScheduleRequest, ...interface{}) (*workflowservice.DescribeScheduleResponse, error) {
+				// A scheduled action taken concurrently is reported after the triggered one
+				return &workflowservice.DescribeScheduleResponse{
+					Info: &schedulepb.ScheduleInfo{
+						ActionCount: 2,
+						RecentActions: []*schedulepb.ScheduleActionResult{
+							{
+								ScheduleTime:        scheduledTime,
+								StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-1"},
+							},
+							{
+								ScheduleTime:        timestamppb.New(scheduledTime.AsTime().Add(time.Millisecond)),
+								StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-2", RunId: "run-2"},
+							},
+						},
+					},
+				}, nil
+			}),
+	)
+
+	run, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).TriggerAndWait(
+		context.Background(), ScheduleTriggerOptions{Overlap: enumspb.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL})
+	s.NoError(err)
+	s.Equal("wf-1", run.GetID())
+	s.Equal("run-1", run.GetRunID())
+}
+
+func (s *scheduleClientTestSuite) TestTriggerAndWaitSkipped() {
+	gomock.InOrder(
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeScheduleResponse{
+			Info: &schedulepb.ScheduleInfo{ActionCount: 1},
+		}, nil),
+		s.service.EXPECT().PatchSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.PatchScheduleResponse{}, nil),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeScheduleResponse{
+			Info: &schedulepb.ScheduleInfo{ActionCount: 1, OverlapSkipped: 1},
+		}, nil),
+	)
+
+	_, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).TriggerAndWait(
+		context.Background(), ScheduleTriggerOptions{Overlap: enumspb.SCHEDULE_OVERLAP_POLICY_SKIP})
+	s.ErrorContains(err, "skipped")
+}
+
+func (s *scheduleClientTestSuite) TestTriggerAndWaitNotReported() {
+	defer func(interval time.Duration, maxPolls int) {
+		scheduleTriggerAndWaitPollInterval = interval
+		scheduleTriggerAndWaitMaxPolls = maxPolls
+	}(scheduleTriggerAndWaitPollInterval, scheduleTriggerAndWaitMaxPolls)
+	scheduleTriggerAndWaitPollInterval = time.Millisecond
+	scheduleTriggerAndWaitMaxPolls = 3
+
+	describeResp := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{},
+	}
+	s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(describeResp, nil).Times(4)
+	s.service.EXPECT().PatchSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.PatchScheduleResponse{}, nil)
+
+	_, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).TriggerAndWait(
+		context.Background(), ScheduleTriggerOptions{})
+	s.ErrorContains(err, "not reported after 3 polls")
+}
+
+func (s *scheduleClientTestSuite) TestAwaitNextRun() {
+	before := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{
+			ActionCount: 1,
+			RecentActions: []*schedulepb.ScheduleActionResult{
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-0", RunId: "run-0"}},
+			},
+		},
+	}
+	after := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{
+			ActionCount: 3,
+			RecentActions: []*schedulepb.ScheduleActionResult{
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-0", RunId: "run-0"}},
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-1"}},
+				{StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-2", RunId: "run-2"}},
+			},
+		},
+	}
+	gomock.InOrder(
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(before, nil),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(after, nil),
+	)
+
+	// The schedule fired twice while waiting, the first run is returned
+	run, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).AwaitNextRun(context.Background())
+	s.NoError(err)
+	s.Equal("wf-1", run.GetID())
+	s.Equal("run-1", run.GetRunID())
+}
+
+func (s *scheduleClientTestSuite) TestAwaitNextRunPaused() {
+	s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(&workflowservice.DescribeScheduleResponse{
+		Schedule: &schedulepb.Schedule{State: &schedulepb.ScheduleState{Paused: true}},
+		Info:     &schedulepb.ScheduleInfo{},
+	}, nil)
+
+	_, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).AwaitNextRun(context.Background())
+	s.ErrorIs(err, ErrSchedulePaused)
+}
+
+func (s *scheduleClientTestSuite) TestBackfillAndWait() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)}
+	before := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{ActionCount: 5, OverlapSkipped: 1},
+	}
+	pending := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{ActionCount: 6, OverlapSkipped: 1},
+	}
+	after := &workflowservice.DescribeScheduleResponse{
+		Info: &schedulepb.ScheduleInfo{
+			ActionCount:    7,
+			OverlapSkipped: 2,
+			RecentActions: []*schedulepb.ScheduleActionResult{
+				{
+					ScheduleTime:        timestamppb.New(start.Add(-time.Hour)),
+					StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-0"},
+				},
+				{
+					ScheduleTime:        timestamppb.New(times[0]),
+					StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-1"},
+				},
+				{
+					ScheduleTime:        timestamppb.New(times[2]),
+					StartWorkflowResult: &commonpb.WorkflowExecution{WorkflowId: "wf-3"},
+				},
+			},
+		},
+	}
+	gomock.InOrder(
+		s.service.EXPECT().ListScheduleMatchingTimes(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *workflowservice.ListScheduleMatchingTimesRequest, _ ...interface{}) (*workflowservice.ListScheduleMatchingTimesResponse, error) {
+				s.Equal(scheduleID, req.GetScheduleId())
+				s.Equal(start, req.GetStartTime().AsTime())
+				return &workflowservice.ListScheduleMatchingTimesResponse{StartTime: []*timestamppb.Timestamp{
+					timestamppb.New(times[0]), timestamppb.New(times[1]), timestamppb.New(times[2]),
+				}}, nil
+			}),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(before, nil),
+		s.service.EXPECT().PatchSchedule(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *workflowservice.PatchScheduleRequest, _ ...interface{}) (*workflowservice.PatchScheduleResponse, error) {
+				s.Len(req.GetPatch().GetBackfillRequest(), 1)
+				return &workflowservice.PatchScheduleResponse{}, nil
+			}),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(pending, nil),
+		s.service.EXPECT().DescribeSchedule(gomock.Any(), gomock.Any(), gomock.Any()).Return(after, nil),
+	)
+
+	result, err := s.client.ScheduleClient().GetHandle(context.Background(), scheduleID).BackfillAndWait(
+		context.Background(), ScheduleBackfillOptions{Backfill: []ScheduleBackfill{{
+			Start:   start,
+			End:     start.Add(3 * time.Hour),
+			Overlap: enumspb.SCHEDULE_OVERLAP_POLICY_SKIP,
+		}}})
+	s.NoError(err)
+	s.Equal(times, result.MatchingTimes)
+	s.EqualValues(1, result.OverlapSkipped)
+	s.Zero(result.BufferDropped)
+	s.Len(result.Actions, 2)
+	s.Equal("wf-1", result.Actions[0].StartWorkflowResult.WorkflowID)
+	s.Equal("wf-3", result.Actions[1].StartWorkflowResult.WorkflowID)
+}
@@ -2,12 +2,15 @@ package internal
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"slices"
 	"time"
 
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/converter"
+	ilog "go.temporal.io/sdk/internal/log"
 	"go.temporal.io/sdk/log"
 )
 
@@ -70,6 +73,37 @@ type (
 	SearchAttributeKeyKeywordList struct {
 		baseSearchAttributeKey
 	}
+
+	// SearchAttributeSource describes where the current value of a search attribute was set.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SearchAttributeSource]
+	SearchAttributeSource int
+
+	// SearchAttributeInfo is the current value of a search attribute along with where it was set.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SearchAttributeInfo]
+	SearchAttributeInfo struct {
+		// Value is the current value of the attribute, of the type of its key (e.g. string for
+		// SearchAttributeKeyKeyword).
+		Value interface{}
+		// Source is where the current value was set.
+		Source SearchAttributeSource
+		// Version is the number of times this workflow run has upserted the attribute. Zero if the attribute was
+		// only set at start.
+		Version int
+	}
+)
+
+const (
+	// SearchAttributeSourceStart indicates the attribute was set when the workflow started and has not been upserted
+	// by this run.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SearchAttributeSourceStart]
+	SearchAttributeSourceStart SearchAttributeSource = iota
+	// SearchAttributeSourceUpsert indicates the attribute was last set by an upsert from this workflow run.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SearchAttributeSourceUpsert]
+	SearchAttributeSourceUpsert
 )
 
 // GetName of the search attribute.
@@ -374,6 +408,124 @@ func (sa SearchAttributes) Copy() SearchAttributeUpdate {
 	}
 }
 
+// SearchAttributesFromUntyped converts search attributes in the untyped form used by UpsertSearchAttributes and
+// StartWorkflowOptions.SearchAttributes to typed search attributes. Key types are inferred from the values:
+//   - string as keyword, since text and keyword attributes cannot be told apart
+//   - bool as bool
+//   - signed and unsigned integers as int64
+//   - float32 and float64 as float64
+//   - time.Time as time
+//   - []string, or []interface{} of strings, as keyword list
+//   - *commonpb.Payload, like the values of WorkflowInfo.SearchAttributes, from the type in its metadata
+//
+// A nil value, or a payload without data, unsets the attribute. Values of other types, integers that overflow int64
+// and payloads without a known type return an error naming the attribute.
+//
+// Exposed as: [go.temporal.io/sdk/temporal.SearchAttributesFromUntyped]
+func SearchAttributesFromUntyped(attributes map[string]interface{}) (SearchAttributes, error) {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	updates := make([]SearchAttributeUpdate, 0, len(attributes))
+	for _, name := range names {
+		update, err := untypedSearchAttributeUpdate(name, attributes[name])
+		if err != nil {
+			return SearchAttributes{}, err
+		}
+		updates = append(updates, update)
+	}
+	return NewSearchAttributes(updates...), nil
+}
+
+func untypedSearchAttributeUpdate(name string, value interface{}) (SearchAttributeUpdate, error) {
+	switch v := value.(type) {
+	case nil:
+		// The key type of an unset is not sent to the server, so any type works
+		return NewSearchAttributeKeyKeyword(name).ValueUnset(), nil
+	case string:
+		return NewSearchAttributeKeyKeyword(name).ValueSet(v), nil
+	case bool:
+		return NewSearchAttributeKeyBool(name).ValueSet(v), nil
+	case int:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case int8:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case int16:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case int32:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case int64:
+		return NewSearchAttributeKeyInt64(name).ValueSet(v), nil
+	case uint:
+		return untypedUintSearchAttributeUpdate(name, uint64(v))
+	case uint8:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case uint16:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case uint32:
+		return NewSearchAttributeKeyInt64(name).ValueSet(int64(v)), nil
+	case uint64:
+		return untypedUintSearchAttributeUpdate(name, v)
+	case float32:
+		return NewSearchAttributeKeyFloat64(name).ValueSet(float64(v)), nil
+	case float64:
+		return NewSearchAttributeKeyFloat64(name).ValueSet(v), nil
+	case time.Time:
+		return NewSearchAttributeKeyTime(name).ValueSet(v), nil
+	case []string:
+		return NewSearchAttributeKeyKeywordList(name).ValueSet(v), nil
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("search attribute %q is a list with unsupported element type %T, only strings are supported", name, elem)
+			}
+			values[i] = s
+		}
+		return NewSearchAttributeKeyKeywordList(name).ValueSet(values), nil
+	case *commonpb.Payload:
+		if v.GetData() == nil {
+			return NewSearchAttributeKeyKeyword(name).ValueUnset(), nil
+		}
+		converted := convertToTypedSearchAttributes(ilog.NewNopLogger(), map[string]*commonpb.Payload{name: v})
+		if converted.Size() == 0 {
+			return nil, fmt.Errorf("search attribute %q is a payload with unknown type %q", name, v.GetMetadata()["type"])
+		}
+		return converted.Copy(), nil
+	default:
+		return nil, fmt.Errorf("search attribute %q has unsupported value type %T", name, value)
+	}
+}
+
+func untypedUintSearchAttributeUpdate(name string, value uint64) (SearchAttributeUpdate, error) {
+	if value > math.MaxInt64 {
+		return nil, fmt.Errorf("search attribute %q value %d overflows int64", name, value)
+	}
+	return NewSearchAttributeKeyInt64(name).ValueSet(int64(value)), nil
+}
+
+// SearchAttributesToUntyped converts typed search attributes to the untyped form used by UpsertSearchAttributes and
+// StartWorkflowOptions.SearchAttributes. Unset attributes are included with a nil value, which removes them when
+// upserted.
+//
+// Exposed as: [go.temporal.io/sdk/temporal.SearchAttributesToUntyped]
+//
+//workflowcheck:ignore
+func SearchAttributesToUntyped(attributes SearchAttributes) map[string]interface{} {
+	untyped := make(map[string]interface{}, len(attributes.untypedValue))
+	for key, value := range attributes.untypedValue {
+		if values, ok := value.([]string); ok {
+			value = append([]string(nil), values...)
+		}
+		untyped[key.GetName()] = value
+	}
+	return untyped
+}
+
 func serializeUntypedSearchAttributes(input map[string]interface{}) (*commonpb.SearchAttributes, error) {
 	if input == nil {
 		return nil, nil
@@ -1,10 +1,13 @@
 package internal
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
 )
 
 func TestSearchAttributes(t *testing.T) {
@@ -153,3 +156,62 @@ func TestSearchAttributesDeepCopy(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, []string{"keyword1", "keyword2", "keyword3"}, keywordListSA)
 }
+
+func TestSearchAttributesFromUntyped(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	payload, err := converter.GetDefaultDataConverter().ToPayload(int64(7))
+	require.NoError(t, err)
+	payload.Metadata["type"] = []byte("Int")
+
+	sa, err := SearchAttributesFromUntyped(map[string]interface{}{
+		"keyword":      "value",
+		"bool":         true,
+		"int":          3,
+		"uint32":       uint32(4),
+		"float":        float32(1.5),
+		"time":         now,
+		"list":         []string{"a", "b"},
+		"anyList":      []interface{}{"c"},
+		"payload":      payload,
+		"unset":        nil,
+		"unsetPayload": &commonpb.Payload{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, NewSearchAttributes(
+		NewSearchAttributeKeyKeyword("keyword").ValueSet("value"),
+		NewSearchAttributeKeyBool("bool").ValueSet(true),
+		NewSearchAttributeKeyInt64("int").ValueSet(3),
+		NewSearchAttributeKeyInt64("uint32").ValueSet(4),
+		NewSearchAttributeKeyFloat64("float").ValueSet(1.5),
+		NewSearchAttributeKeyTime("time").ValueSet(now),
+		NewSearchAttributeKeyKeywordList("list").ValueSet([]string{"a", "b"}),
+		NewSearchAttributeKeyKeywordList("anyList").ValueSet([]string{"c"}),
+		NewSearchAttributeKeyInt64("payload").ValueSet(7),
+		NewSearchAttributeKeyKeyword("unset").ValueUnset(),
+		NewSearchAttributeKeyKeyword("unsetPayload").ValueUnset(),
+	), sa)
+
+	require.Equal(t, map[string]interface{}{
+		"keyword":      "value",
+		"bool":         true,
+		"int":          int64(3),
+		"uint32":       int64(4),
+		"float":        1.5,
+		"time":         now,
+		"list":         []string{"a", "b"},
+		"anyList":      []string{"c"},
+		"payload":      int64(7),
+		"unset":        nil,
+		"unsetPayload": nil,
+	}, SearchAttributesToUntyped(sa))
+
+	_, err = SearchAttributesFromUntyped(map[string]interface{}{"struct": struct{}{}})
+	require.EqualError(t, err, `search attribute "struct" has unsupported value type struct {}`)
+	_, err = SearchAttributesFromUntyped(map[string]interface{}{"list": []interface{}{1}})
+	require.EqualError(t, err, `search attribute "list" is a list with unsupported element type int, only strings are supported`)
+	_, err = SearchAttributesFromUntyped(map[string]interface{}{"big": uint64(math.MaxUint64)})
+	require.EqualError(t, err, `search attribute "big" value 18446744073709551615 overflows int64`)
+	_, err = SearchAttributesFromUntyped(map[string]interface{}{"payload": &commonpb.Payload{Data: []byte("1")}})
+	require.EqualError(t, err, `search attribute "payload" is a payload with unknown type ""`)
+}
@@ -0,0 +1,314 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/sdk/internal/common/cache"
+)
+
+var errPrewarmSkipped = errors.New("workflow is not pre-warmed")
+
+// prewarmStickyCache loads the state of the workflows selected by StickyCachePrewarm into the sticky cache until the
+// cache is full. It is best-effort, workflows that fail to load are skipped.
+func (ww *workflowWorker) prewarmStickyCache() {
+	ctx, cancel := ww.stopContext()
+	defer cancel()
+
+	params := ww.executionParameters
+	var loaded int
+	prewarm := func(execution *commonpb.WorkflowExecution) bool {
+		if ctx.Err() != nil || ww.taskHandler.cache.getWorkflowCache().Size() >= ww.taskHandler.cache.MaxWorkflowCacheSize() {
+			return false
+		}
+		if err := ww.prewarmWorkflow(ctx, execution); err == nil {
+			loaded++
+		} else if !errors.Is(err, errPrewarmSkipped) {
+			params.Logger.Debug("Failed to pre-warm sticky cache",
+				tagWorkflowID, execution.GetWorkflowId(),
+				tagRunID, execution.GetRunId(),
+				tagError, err)
+		}
+		return true
+	}
+
+	for _, workflowID := range params.StickyCachePrewarm.WorkflowIDs {
+		if !prewarm(&commonpb.WorkflowExecution{WorkflowId: workflowID}) {
+			break
+		}
+	}
+	if params.StickyCachePrewarm.Query != "" {
+		if err := ww.prewarmQueriedWorkflows(ctx, prewarm); err != nil && ctx.Err() == nil {
+			params.Logger.Warn("Failed to list workflows to pre-warm sticky cache", tagTaskQueue, params.TaskQueue, tagError, err)
+		}
+	}
+	params.Logger.Info("Pre-warmed sticky cache", tagTaskQueue, params.TaskQueue, "Workflows", loaded)
+}
+
+// stopContext returns a context that is canceled when the worker stops.
+func (ww *workflowWorker) stopContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-ww.stopC:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// prewarmQueriedWorkflows calls prewarm for the running workflows of the task queue matching the query until it returns
+// false.
+func (ww *workflowWorker) prewarmQueriedWorkflows(ctx context.Context, prewarm func(*commonpb.WorkflowExecution) bool) error {
+	params := ww.executionParameters
+	request := &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: params.Namespace,
+		Query: fmt.Sprintf("TaskQueue = '%s' AND ExecutionStatus = 'Running' AND (%s)",
+			params.TaskQueue, params.StickyCachePrewarm.Query),
+	}
+	for {
+		grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(params.MetricsHandler), defaultGrpcRetryParameters(ctx))
+		response, err := ww.workflowService.ListWorkflowExecutions(grpcCtx, request)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, execution := range response.GetExecutions() {
+			if !prewarm(execution.GetExecution()) {
+				return nil
+			}
+		}
+		if len(response.GetNextPageToken()) == 0 {
+			return nil
+		}
+		request.NextPageToken = response.GetNextPageToken()
+	}
+}
+
+// prewarmWorkflow replays the history of a running workflow of the task queue up to its last completed workflow task
+// and caches the resulting state, as if this worker had processed that task. The next workflow task, which has the
+// full history since it is not sticky, continues from the cached state.
+func (ww *workflowWorker) prewarmWorkflow(ctx context.Context, execution *commonpb.WorkflowExecution) error {
+	params := ww.executionParameters
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(params.MetricsHandler), defaultGrpcRetryParameters(ctx))
+	describe, err := ww.workflowService.DescribeWorkflowExecution(grpcCtx, &workflowservice.DescribeWorkflowExecutionRequest{
+		Namespace: params.Namespace,
+		Execution: execution,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+	info := describe.GetWorkflowExecutionInfo()
+	if info.GetStatus() != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING || info.GetTaskQueue() != params.TaskQueue {
+		return errPrewarmSkipped
+	}
+	execution = info.GetExecution()
+	if ww.taskHandler.cache.getWorkflowContext(execution.GetRunId()) != nil {
+		return errPrewarmSkipped
+	}
+
+	iterator := &historyIteratorImpl{
+		iteratorFunc: newGetHistoryPageFunc(ctx, ww.workflowService, params.Namespace, execution, 0,
+			params.MetricsHandler, params.TaskQueue),
+	}
+	var events []*historypb.HistoryEvent
+	for {
+		page, err := iterator.GetNextPage()
+		if err != nil {
+			return err
+		}
+		events = append(events, page.GetEvents()...)
+		if !iterator.HasNextPage() {
+			break
+		}
+	}
+	end, startedEventID := lastCompletedWorkflowTask(events)
+	if end == 0 {
+		return errPrewarmSkipped
+	}
+
+	task := &workflowservice.PollWorkflowTaskQueueResponse{
+		WorkflowExecution: execution,
+		WorkflowType:      info.GetType(),
+		History:           &historypb.History{Events: events[:end]},
+		Attempt:           1,
+		StartedEventId:    events[end-1].GetEventId(),
+		// All events are replayed, nothing is executed
+		PreviousStartedEventId: math.MaxInt64,
+	}
+	workflowContext, err := ww.taskHandler.GetOrCreateWorkflowContext(task, nil)
+	if err != nil {
+		return err
+	}
+	// Local activities are resolved by their markers in history, make sure none are started
+	workflowContext.laTunnel = nil
+	_, err = workflowContext.ProcessWorkflowTask(&workflowTask{task: task})
+	workflowContext.laTunnel = ww.taskHandler.laTunnel
+	if err == nil && (workflowContext.err != nil || workflowContext.isWorkflowCompleted ||
+		len(workflowContext.getEventHandler().pendingLaTasks) > 0) {
+		err = errors.New("workflow state after replay does not match a completed workflow task")
+	}
+	if err == nil {
+		workflowContext.previousStartedEventID = startedEventID
+		workflowContext.prewarmed = true
+	}
+	workflowContext.Unlock(err)
+	return err
+}
+
+// lastCompletedWorkflowTask returns the number of events up to the end of the last completed workflow task, including
+// the events of its commands, and the started event ID of that workflow task. It returns 0, 0 if no workflow task has
+// completed.
+func lastCompletedWorkflowTask(events []*historypb.HistoryEvent) (int, int64) {
+	for i := len(events) - 2; i >= 0; i-- {
+		if events[i].GetEventType() == enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED &&
+			events[i+1].GetEventType() == enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED {
+			end := i + 2
+			for end < len(events) && isCommandEvent(events[end].GetEventType()) {
+				end++
+			}
+			return end, events[i].GetEventId()
+		}
+	}
+	return 0, 0
+}
+
+// stickyCacheExportVersion is the version of the format of ExportStickyCache, bumped on incompatible changes.
+const stickyCacheExportVersion = 1
+
+type (
+	// stickyCacheExport is the format of ExportStickyCache.
+	stickyCacheExport struct {
+		Version   int                           `json:"version"`
+		Workflows []stickyCacheExportedWorkflow `json:"workflows"`
+	}
+
+	// stickyCacheExportedWorkflow identifies a workflow whose state was cached.
+	stickyCacheExportedWorkflow struct {
+		Namespace    string `json:"namespace"`
+		TaskQueue    string `json:"taskQueue"`
+		WorkflowType string `json:"workflowType"`
+		WorkflowID   string `json:"workflowId"`
+		RunID        string `json:"runId"`
+	}
+)
+
+// ExportStickyCache returns a blob identifying the workflows whose state is in the sticky cache of this process,
+// from the most to the least recently used, to load their state on the workers of a new process with
+// ImportStickyCache after a restart.
+//
+// Exposed as: [go.temporal.io/sdk/worker.ExportStickyCache]
+func ExportStickyCache() ([]byte, error) {
+	sharedWorkerCacheLock.Lock()
+	var workflowCache cache.Cache
+	if sharedWorkerCachePtr.workflowCache != nil {
+		workflowCache = *sharedWorkerCachePtr.workflowCache
+	}
+	sharedWorkerCacheLock.Unlock()
+	return exportStickyCache(workflowCache)
+}
+
+func exportStickyCache(workflowCache cache.Cache) ([]byte, error) {
+	export := stickyCacheExport{Version: stickyCacheExportVersion, Workflows: []stickyCacheExportedWorkflow{}}
+	if workflowCache != nil {
+		for _, value := range workflowCache.Values() {
+			wc := value.(*workflowExecutionContextImpl)
+			// Waits for the workflow task being processed, if any
+			wc.mutex.Lock()
+			current := !wc.IsDestroyed() && !wc.isWorkflowCompleted && wc.err == nil && wc.previousStartedEventID > 0
+			info := wc.workflowInfo
+			wc.mutex.Unlock()
+			if !current {
+				continue
+			}
+			export.Workflows = append(export.Workflows, stickyCacheExportedWorkflow{
+				Namespace:    info.Namespace,
+				TaskQueue:    info.TaskQueueName,
+				WorkflowType: info.WorkflowType.Name,
+				WorkflowID:   info.WorkflowExecution.ID,
+				RunID:        info.WorkflowExecution.RunID,
+			})
+		}
+	}
+	return json.Marshal(export)
+}
+
+// ImportStickyCache loads the state of the workflows of a blob returned by ExportStickyCache into the sticky cache,
+// using the started workers of this process with the namespace and task queue of each workflow. It returns the
+// number of workflows loaded.
+//
+// Exposed as: [go.temporal.io/sdk/worker.ImportStickyCache]
+func ImportStickyCache(blob []byte) (int, error) {
+	var export stickyCacheExport
+	if err := json.Unmarshal(blob, &export); err != nil {
+		return 0, fmt.Errorf("invalid sticky cache export: %w", err)
+	}
+	if export.Version != stickyCacheExportVersion {
+		return 0, fmt.Errorf("unsupported sticky cache export version %d", export.Version)
+	}
+
+	activeWorkersLock.Lock()
+	workflowWorkers := make([]*workflowWorker, 0, len(activeWorkers))
+	for aw := range activeWorkers {
+		if aw.workflowWorker != nil {
+			workflowWorkers = append(workflowWorkers, aw.workflowWorker)
+		}
+	}
+	activeWorkersLock.Unlock()
+
+	var loaded int
+	for _, ww := range workflowWorkers {
+		loaded += ww.importStickyCache(export.Workflows)
+	}
+	return loaded, nil
+}
+
+// importStickyCache loads the state of the exported workflows of the namespace and task queue of the worker until the
+// cache is full, and returns the number of workflows loaded. Workflows of types that are not registered anymore, that
+// are not running anymore or whose history does not replay with the current code are discarded.
+func (ww *workflowWorker) importStickyCache(workflows []stickyCacheExportedWorkflow) int {
+	if ww.taskHandler == nil || ww.workflowService == nil || ww.taskHandler.cache.MaxWorkflowCacheSize() <= 0 {
+		return 0
+	}
+	ctx, cancel := ww.stopContext()
+	defer cancel()
+
+	params := ww.executionParameters
+	var loaded int
+	for _, workflow := range workflows {
+		if workflow.Namespace != params.Namespace || workflow.TaskQueue != params.TaskQueue {
+			continue
+		}
+		if ctx.Err() != nil || ww.taskHandler.cache.getWorkflowCache().Size() >= ww.taskHandler.cache.MaxWorkflowCacheSize() {
+			break
+		}
+		if _, ok := ww.taskHandler.registry.getWorkflowFn(workflow.WorkflowType); !ok {
+			params.Logger.Debug("Discarding imported sticky cache workflow of unregistered type",
+				tagWorkflowType, workflow.WorkflowType,
+				tagWorkflowID, workflow.WorkflowID,
+				tagRunID, workflow.RunID)
+			continue
+		}
+		execution := &commonpb.WorkflowExecution{WorkflowId: workflow.WorkflowID, RunId: workflow.RunID}
+		if err := ww.prewarmWorkflow(ctx, execution); err == nil {
+			loaded++
+		} else if !errors.Is(err, errPrewarmSkipped) {
+			params.Logger.Debug("Discarding imported sticky cache workflow",
+				tagWorkflowID, workflow.WorkflowID,
+				tagRunID, workflow.RunID,
+				tagError, err)
+		}
+	}
+	params.Logger.Info("Imported sticky cache", tagTaskQueue, params.TaskQueue, "Workflows", loaded)
+	return loaded
+}
@@ -116,6 +116,8 @@ type (
 		currentWorkflowTask *workflowservice.PollWorkflowTaskQueueResponse
 		laTunnel            *localActivityTunnel
 		cached              bool
+		// prewarmed is set if the state was loaded by pre-warming the sticky cache instead of by a workflow task
+		prewarmed bool
 	}
 
 	// workflowTaskHandlerImpl is the implementation of WorkflowTaskHandler
@@ -130,6 +132,10 @@ type (
 		workerDeploymentVersion   WorkerDeploymentVersion
 		defaultVersioningBehavior VersioningBehavior
 		enableLoggingInReplay     bool
+		enableCommandLogging      bool
+		workerStopCh              <-chan struct{}
+		replayLastWorkflowTask    bool
+		fatalError                func() error
 		registry                  *registry
 		laTunnel                  *localActivityTunnel
 		workflowPanicPolicy       WorkflowPanicPolicy
@@ -138,6 +144,12 @@ type (
 		contextPropagators        []ContextPropagator
 		cache                     *WorkerCache
 		deadlockDetectionTimeout  time.Duration
+		forcedHeartbeatThreshold  float64
+		slowReplayThreshold       time.Duration
+		onSlowReplay              func(SlowReplayInfo)
+		onWorkflowPanic           func(info WorkflowInfo, recovered any, stack string)
+		reportContinueAsNew       bool
+		logContinueAsNew          bool
 		capabilities              *workflowservice.GetSystemInfoResponse_Capabilities
 	}
 
@@ -163,6 +175,7 @@ type (
 		versionStamp                     *commonpb.WorkerVersionStamp
 		deployment                       *deploymentpb.Deployment
 		workerDeploymentOptions          *deploymentpb.WorkerDeploymentOptions
+		onActivityPanic                  func(ctx context.Context, info ActivityInfo, recovered any, stack string)
 	}
 
 	// history wrapper method to help information about events.
@@ -188,6 +201,9 @@ type (
 
 	historyMismatchError struct {
 		message string
+		// event and command where replay diverged from history, if known
+		event   *historypb.HistoryEvent
+		command *commandpb.Command
 	}
 
 	unknownSdkFlagError struct {
@@ -206,6 +222,8 @@ type (
 		// Is null if there was no task completed event to read the build ID from (but may be
 		// empty string if there was, and it was empty)
 		buildID *string
+		// True if the worker that completed the task used versioning
+		versioned bool
 	}
 
 	finishedTask struct {
@@ -374,6 +392,7 @@ func (eh *history) nextTask() (*preparedTask, error) {
 	var markers []*historypb.HistoryEvent
 	var acceptedMsgs []*protocolpb.Message
 	var buildID *string
+	var versioned bool
 	if len(result) > 0 {
 		nextTaskEvents, err := eh.prepareTask()
 		if err != nil {
@@ -387,6 +406,7 @@ func (eh *history) nextTask() (*preparedTask, error) {
 		markers = nextTaskEvents.markers
 		acceptedMsgs = nextTaskEvents.acceptedMsgs
 		buildID = nextTaskEvents.buildID
+		versioned = nextTaskEvents.versioned
 	}
 	return &preparedTask{
 		events:         result,
@@ -398,6 +418,7 @@ func (eh *history) nextTask() (*preparedTask, error) {
 		sdkName:        sdkName,
 		sdkVersion:     sdkVersion,
 		buildID:        buildID,
+		versioned:      versioned,
 	}, nil
 }
 
@@ -501,10 +522,12 @@ OrderEvents:
 						bidStr = splitVersion[1]
 					}
 				}
+				//lint:ignore SA1019 ignore deprecated versioning APIs
+				workerVersion := event.GetWorkflowTaskCompletedEventAttributes().GetWorkerVersion()
+				// Deployment versions are only recorded for versioned workers
+				taskEvents.versioned = bidStr != "" || workerVersion.GetUseVersioning()
 				if bidStr == "" {
-					//lint:ignore SA1019 ignore deprecated versioning APIs
-					bidStr = event.GetWorkflowTaskCompletedEventAttributes().
-						GetWorkerVersion().GetBuildId()
+					bidStr = workerVersion.GetBuildId()
 				}
 				taskEvents.buildID = &bidStr
 			} else if isPreloadMarkerEvent(event) {
@@ -570,6 +593,10 @@ func newWorkflowTaskHandler(params workerExecutionParameters, ppMgr pressurePoin
 		workerDeploymentVersion:   params.DeploymentOptions.Version,
 		defaultVersioningBehavior: params.DeploymentOptions.DefaultVersioningBehavior,
 		enableLoggingInReplay:     params.EnableLoggingInReplay,
+		enableCommandLogging:      params.EnableWorkflowCommandLogging,
+		workerStopCh:              params.WorkerStopChannel,
+		replayLastWorkflowTask:    params.replayLastWorkflowTask,
+		fatalError:                params.WorkflowTaskFatalError,
 		registry:                  registry,
 		workflowPanicPolicy:       params.WorkflowPanicPolicy,
 		dataConverter:             params.DataConverter,
@@ -577,6 +604,12 @@ func newWorkflowTaskHandler(params workerExecutionParameters, ppMgr pressurePoin
 		contextPropagators:        params.ContextPropagators,
 		cache:                     params.cache,
 		deadlockDetectionTimeout:  params.DeadlockDetectionTimeout,
+		forcedHeartbeatThreshold:  params.WorkflowTaskForcedHeartbeatThreshold,
+		slowReplayThreshold:       params.SlowReplayThreshold,
+		onSlowReplay:              params.OnSlowReplay,
+		onWorkflowPanic:           params.OnWorkflowPanic,
+		reportContinueAsNew:       params.ReportContinueAsNew,
+		logContinueAsNew:          params.LogContinueAsNew,
 		capabilities:              params.capabilities,
 	}
 }
@@ -663,6 +696,7 @@ func (w *workflowExecutionContextImpl) clearState() {
 	w.err = nil
 	w.previousStartedEventID = 0
 	w.lastHandledEventID = 0
+	w.prewarmed = false
 	w.newCommands = nil
 	w.newMessages = nil
 
@@ -677,6 +711,10 @@ func (w *workflowExecutionContextImpl) clearState() {
 
 func (w *workflowExecutionContextImpl) createEventHandler() {
 	w.clearState()
+	deadlockDetectionTimeout := w.wth.deadlockDetectionTimeout
+	if w.wth.registry != nil {
+		deadlockDetectionTimeout = w.wth.registry.getWorkflowDeadlockDetectionTimeout(w.workflowInfo.WorkflowType, deadlockDetectionTimeout)
+	}
 	eventHandler := newWorkflowExecutionEventHandler(
 		w.workflowInfo,
 		w.completeWorkflow,
@@ -687,7 +725,7 @@ func (w *workflowExecutionContextImpl) createEventHandler() {
 		w.wth.dataConverter,
 		w.wth.failureConverter,
 		w.wth.contextPropagators,
-		w.wth.deadlockDetectionTimeout,
+		deadlockDetectionTimeout,
 		w.wth.capabilities,
 	)
 
@@ -736,6 +774,11 @@ func (wth *workflowTaskHandlerImpl) createWorkflowContext(task *workflowservice.
 		}
 	}
 
+	// Workflows started by an alias report their primary name
+	workflowTypeName := task.WorkflowType.GetName()
+	if wth.registry != nil {
+		workflowTypeName = wth.registry.getWorkflowPrimaryName(workflowTypeName)
+	}
 	workflowInfo := &WorkflowInfo{
 		WorkflowExecution: WorkflowExecution{
 			ID:    workflowID,
@@ -743,7 +786,7 @@ func (wth *workflowTaskHandlerImpl) createWorkflowContext(task *workflowservice.
 		},
 		OriginalRunID:            attributes.OriginalExecutionRunId,
 		FirstRunID:               attributes.FirstExecutionRunId,
-		WorkflowType:             WorkflowType{Name: task.WorkflowType.GetName()},
+		WorkflowType:             WorkflowType{Name: workflowTypeName},
 		TaskQueueName:            taskQueue.GetName(),
 		WorkflowExecutionTimeout: attributes.GetWorkflowExecutionTimeout().AsDuration(),
 		WorkflowRunTimeout:       attributes.GetWorkflowRunTimeout().AsDuration(),
@@ -760,7 +803,7 @@ func (wth *workflowTaskHandlerImpl) createWorkflowContext(task *workflowservice.
 		RootWorkflowExecution:    rootWorkflowExecution,
 		Memo:                     attributes.Memo,
 		SearchAttributes:         attributes.SearchAttributes,
-		RetryPolicy:              convertFromPBRetryPolicy(attributes.RetryPolicy),
+		RetryPolicy:              convertFromPBWorkflowRetryPolicy(attributes.RetryPolicy),
 		// Use the original execution run ID from the start event as the initial seed.
 		// Original execution run ID stays the same for the entire chain of workflow resets.
 		// This helps us keep child workflow IDs consistent up until a reset-point is encountered.
@@ -771,6 +814,14 @@ func (wth *workflowTaskHandlerImpl) createWorkflowContext(task *workflowservice.
 	return newWorkflowExecutionContext(workflowInfo, wth), nil
 }
 
+// convertFromPBWorkflowRetryPolicy is convertFromPBRetryPolicy, but also treats an empty policy as not set.
+func convertFromPBWorkflowRetryPolicy(retryPolicy *commonpb.RetryPolicy) *RetryPolicy {
+	if proto.Equal(retryPolicy, &commonpb.RetryPolicy{}) {
+		return nil
+	}
+	return convertFromPBRetryPolicy(retryPolicy)
+}
+
 func (wth *workflowTaskHandlerImpl) GetOrCreateWorkflowContext(
 	task *workflowservice.PollWorkflowTaskQueueResponse,
 	historyIterator HistoryIterator,
@@ -798,9 +849,15 @@ func (wth *workflowTaskHandlerImpl) GetOrCreateWorkflowContext(
 		if task.Query != nil && !isFullHistory && wth == workflowContext.wth && !workflowContext.IsDestroyed() {
 			// query task and we have a valid cached state
 			metricsHandler.Counter(metrics.StickyCacheHit).Inc(1)
+			stickyCacheCounters.hits.Add(1)
 		} else if len(history.Events) > 0 && history.Events[0].GetEventId() == workflowContext.previousStartedEventID+1 && wth == workflowContext.wth && !workflowContext.IsDestroyed() {
 			// non query task and we have a valid cached state
 			metricsHandler.Counter(metrics.StickyCacheHit).Inc(1)
+			stickyCacheCounters.hits.Add(1)
+		} else if workflowContext.isPrewarmedFor(task) && wth == workflowContext.wth && !workflowContext.IsDestroyed() {
+			// first task after the cached state was pre-warmed
+			metricsHandler.Counter(metrics.StickyCacheHit).Inc(1)
+			stickyCacheCounters.hits.Add(1)
 		} else {
 			// possible another task already destroyed this context.
 			if !workflowContext.IsDestroyed() {
@@ -831,6 +888,7 @@ func (wth *workflowTaskHandlerImpl) GetOrCreateWorkflowContext(
 			// we are getting partial history task, but cached state was already evicted.
 			// we need to reset history so we get events from beginning to replay/rebuild the state
 			metricsHandler.Counter(metrics.StickyCacheMiss).Inc(1)
+			stickyCacheCounters.misses.Add(1)
 			if _, err = resetHistory(task, historyIterator); err != nil {
 				return
 			}
@@ -939,6 +997,12 @@ func (wth *workflowTaskHandlerImpl) ProcessWorkflowTask(
 		}
 	}()
 
+	// Stop waiting on local activities when the worker stops because of a fatal error, so the task can be failed
+	var fatalStopCh <-chan struct{}
+	if wth.fatalError != nil {
+		fatalStopCh = wth.workerStopCh
+	}
+
 processWorkflowLoop:
 	for {
 		startTime := time.Now()
@@ -946,7 +1010,7 @@ processWorkflowLoop:
 		if err == nil && response == nil {
 		waitLocalActivityLoop:
 			for {
-				deadlineToTrigger := time.Duration(float32(ratioToForceCompleteWorkflowTaskComplete) * float32(workflowContext.workflowInfo.WorkflowTaskTimeout))
+				deadlineToTrigger := time.Duration(float32(wth.forcedHeartbeatThreshold) * float32(workflowContext.workflowInfo.WorkflowTaskTimeout))
 				delayDuration := time.Until(startTime.Add(deadlineToTrigger))
 
 			heartbeatLoop:
@@ -1008,8 +1072,18 @@ processWorkflowLoop:
 
 						if !wth.laTunnel.sendTask(laRetry) {
 							laRetry.attempt--
+						} else if onRetry := laRetry.params.OnRetry; onRetry != nil && !eventHandler.isReplay {
+							onRetry(laRetry.attempt, laRetry.lastErr)
 						}
 
+					case <-fatalStopCh:
+						if fatalErr := wth.fatalError(); fatalErr != nil {
+							errRet = newWorkerFatalErrorTaskFailure(fatalErr)
+							return
+						}
+						// Stopped for another reason, keep waiting as usual
+						fatalStopCh = nil
+
 					case lar := <-workflowTask.laResultCh:
 						// local activity result ready
 						response, err = workflowContext.ProcessLocalActivityResult(workflowTask, lar)
@@ -1030,6 +1104,12 @@ processWorkflowLoop:
 	return
 }
 
+// setCurrentTaskBuildIDOfWorker sets the build ID of the current task to the one of this worker.
+func (w *workflowExecutionContextImpl) setCurrentTaskBuildIDOfWorker() {
+	w.workflowInfo.currentTaskBuildID = w.wth.workerBuildID
+	w.workflowInfo.currentTaskVersioned = w.wth.useBuildIDForVersioning || (w.wth.workerDeploymentVersion != WorkerDeploymentVersion{})
+}
+
 func (w *workflowExecutionContextImpl) ProcessWorkflowTask(workflowTask *workflowTask) (*workflowTaskCompletion, error) {
 	task := workflowTask.task
 	historyIterator := workflowTask.historyIterator
@@ -1068,11 +1148,12 @@ func (w *workflowExecutionContextImpl) ProcessWorkflowTask(workflowTask *workflo
 	start := time.Now()
 	// This is set to nil once recorded
 	metricsTimer := metricsHandler.Timer(metrics.WorkflowTaskReplayLatency)
+	var replayedEvents int
 
 	eventHandler.ResetLAWFTAttemptCounts()
 	eventHandler.sdkFlags.markSDKFlagsSent()
 
-	w.workflowInfo.currentTaskBuildID = w.wth.workerBuildID
+	w.setCurrentTaskBuildIDOfWorker()
 ProcessEvents:
 	for {
 		nextTask, err := reorderedHistory.nextTask()
@@ -1091,7 +1172,8 @@ ProcessEvents:
 		isLastWFTForPartialWFE := len(reorderedEvents) > 0 &&
 			reorderedEvents[len(reorderedEvents)-1].EventType == enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED &&
 			len(reorderedHistory.next) == 0 &&
-			isInReplayer
+			isInReplayer &&
+			!w.wth.replayLastWorkflowTask
 		if isLastWFTForPartialWFE {
 			partialHistory = true
 			break ProcessEvents
@@ -1149,6 +1231,10 @@ ProcessEvents:
 		}
 		if isReplay && nextTaskBuildId != nil {
 			w.workflowInfo.currentTaskBuildID = *nextTaskBuildId
+			w.workflowInfo.currentTaskVersioned = nextTask.versioned
+		} else if !isReplay {
+			// The task is processed by this worker, even if earlier ones were replayed in the same call
+			w.setCurrentTaskBuildIDOfWorker()
 		}
 		// Reset the mutable side effect markers recorded
 		eventHandler.mutableSideEffectsRecorded = nil
@@ -1168,8 +1254,10 @@ ProcessEvents:
 
 		for i, event := range reorderedEvents {
 			isInReplay := reorderedHistory.IsReplayEvent(event)
-			if !isInReplay && metricsTimer != nil {
-				metricsTimer.Record(time.Since(start))
+			if isInReplay {
+				replayedEvents++
+			} else if metricsTimer != nil {
+				w.recordReplayLatency(metricsTimer, metricsHandler, task, replayedEvents, time.Since(start))
 				metricsTimer = nil
 			}
 
@@ -1251,7 +1339,7 @@ ProcessEvents:
 	}
 
 	if metricsTimer != nil {
-		metricsTimer.Record(time.Since(start))
+		w.recordReplayLatency(metricsTimer, metricsHandler, task, replayedEvents, time.Since(start))
 		metricsTimer = nil
 	}
 
@@ -1274,6 +1362,33 @@ ProcessEvents:
 	return w.applyWorkflowPanicPolicy(workflowTask, workflowError)
 }
 
+// recordReplayLatency records the replay latency of a workflow task and reports
+// it as slow if it replayed events and exceeded the worker's threshold.
+func (w *workflowExecutionContextImpl) recordReplayLatency(
+	timer metrics.Timer,
+	metricsHandler metrics.Handler,
+	task *workflowservice.PollWorkflowTaskQueueResponse,
+	replayedEvents int,
+	replayDuration time.Duration,
+) {
+	timer.Record(replayDuration)
+	if w.wth.slowReplayThreshold <= 0 || replayedEvents == 0 || replayDuration <= w.wth.slowReplayThreshold {
+		return
+	}
+	metricsHandler.Counter(metrics.WorkflowTaskSlowReplayCounter).Inc(1)
+	if w.wth.onSlowReplay != nil {
+		w.wth.onSlowReplay(SlowReplayInfo{
+			WorkflowType: task.GetWorkflowType().GetName(),
+			WorkflowExecution: WorkflowExecution{
+				ID:    task.GetWorkflowExecution().GetWorkflowId(),
+				RunID: task.GetWorkflowExecution().GetRunId(),
+			},
+			HistoryLength:  task.GetStartedEventId(),
+			ReplayDuration: replayDuration,
+		})
+	}
+}
+
 func (w *workflowExecutionContextImpl) ProcessLocalActivityResult(workflowTask *workflowTask, lar *localActivityResult) (*workflowTaskCompletion, error) {
 	if lar.err != nil && w.retryLocalActivity(lar) {
 		return nil, nil // nothing to do here as we are retrying...
@@ -1300,6 +1415,7 @@ func (w *workflowExecutionContextImpl) applyWorkflowPanicPolicy(workflowTask *wo
 				tagAttempt, task.Attempt,
 				tagError, workflowError,
 				tagStackTrace, panicErr.StackTrace())
+			w.wth.notifyWorkflowPanic(w.workflowInfo, panicErr.value, panicErr.StackTrace())
 		} else {
 			w.wth.logger.Error("Workflow panic",
 				tagWorkflowType, task.WorkflowType.GetName(),
@@ -1309,7 +1425,11 @@ func (w *workflowExecutionContextImpl) applyWorkflowPanicPolicy(workflowTask *wo
 				tagError, workflowError)
 		}
 
-		switch w.wth.workflowPanicPolicy {
+		policy := w.wth.workflowPanicPolicy
+		if typePolicy, ok := w.wth.registry.getWorkflowPanicPolicy(WorkflowType{Name: task.WorkflowType.GetName()}); ok {
+			policy = typePolicy
+		}
+		switch policy {
 		case FailWorkflow:
 			// complete workflow with custom error will fail the workflow
 			w.getEventHandler().Complete(nil, NewApplicationError(
@@ -1328,6 +1448,20 @@ func (w *workflowExecutionContextImpl) applyWorkflowPanicPolicy(workflowTask *wo
 	return w.CompleteWorkflowTask(workflowTask, true), nil
 }
 
+// notifyWorkflowPanic calls the user's OnWorkflowPanic callback, if any. A panic in the callback is logged and
+// swallowed so the workflow panic policy is still applied.
+func (wth *workflowTaskHandlerImpl) notifyWorkflowPanic(info *WorkflowInfo, p any, st string) {
+	if wth.onWorkflowPanic == nil {
+		return
+	}
+	defer func() {
+		if hp := recover(); hp != nil {
+			wth.logger.Error("OnWorkflowPanic callback panicked.", tagPanicError, fmt.Sprintf("%v", hp))
+		}
+	}()
+	wth.onWorkflowPanic(*info, p, st)
+}
+
 func (w *workflowExecutionContextImpl) retryLocalActivity(lar *localActivityResult) bool {
 	if lar.task.retryPolicy == nil || lar.err == nil || IsCanceledError(lar.err) {
 		return false
@@ -1336,6 +1470,7 @@ func (w *workflowExecutionContextImpl) retryLocalActivity(lar *localActivityResu
 	retryBackoff := getRetryBackoff(lar, time.Now())
 	if retryBackoff > 0 && retryBackoff <= w.workflowInfo.WorkflowTaskTimeout {
 		// we need a local retry
+		lar.task.lastErr = lar.err
 		time.AfterFunc(retryBackoff, func() {
 			// Send retry signal
 			select {
@@ -1488,7 +1623,18 @@ func (w *workflowExecutionContextImpl) SetPreviousStartedEventID(eventID int64)
 	w.previousStartedEventID = eventID
 }
 
+// isPrewarmedFor returns whether the state was pre-warmed and the task continues from it. The first task after
+// pre-warming has the full history, since it is not sticky, but the events that were already handled are skipped.
+func (w *workflowExecutionContextImpl) isPrewarmedFor(task *workflowservice.PollWorkflowTaskQueueResponse) bool {
+	return w.prewarmed && task.Query == nil && isFullHistory(task.History) &&
+		task.GetPreviousStartedEventId() == w.previousStartedEventID
+}
+
 func (w *workflowExecutionContextImpl) ResetIfStale(task *workflowservice.PollWorkflowTaskQueueResponse, historyIterator HistoryIterator) error {
+	if w.isPrewarmedFor(task) {
+		w.prewarmed = false
+		return nil
+	}
 	if len(task.History.Events) > 0 && task.History.Events[0].GetEventId() != w.previousStartedEventID+1 {
 		w.wth.logger.Debug("Cached state staled, new task has unexpected events",
 			tagWorkflowID, task.WorkflowExecution.GetWorkflowId(),
@@ -1593,16 +1739,22 @@ matchLoop:
 		}
 
 		if d == nil {
-			return historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: missing replay command for %s", util.HistoryEventToString(e))
+			err := historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: missing replay command for %s", util.HistoryEventToString(e))
+			err.event = e
+			return err
 		}
 
 		if e == nil {
-			return historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: extra replay command for %s", util.CommandToString(d))
+			err := historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: extra replay command for %s", util.CommandToString(d))
+			err.command = d
+			return err
 		}
 
 		if !isCommandMatchEvent(d, e, msgs) {
-			return historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: history event is %s, replay command is %s",
+			err := historyMismatchErrorf("[TMPRL1100] nondeterministic workflow: history event is %s, replay command is %s",
 				util.HistoryEventToString(e), util.CommandToString(d))
+			err.event, err.command = e, d
+			return err
 		}
 
 		di++
@@ -1919,6 +2071,9 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 		commands = append(commands, closeCommand)
 		forceNewWorkflowTask = false
 	}
+	if wth.enableCommandLogging {
+		logCommands(eventHandler.logger, task.GetStartedEventId(), commands)
+	}
 
 	var queryResults map[string]*querypb.WorkflowQueryResult
 	if len(task.Queries) != 0 {
@@ -2009,10 +2164,75 @@ func (wth *workflowTaskHandlerImpl) completeWorkflow(
 				elapsed := time.Since(workflowContext.workflowInfo.WorkflowStartTime)
 				metricsHandler.Timer(metrics.WorkflowEndToEndLatency).Record(elapsed)
 			}
+			if contErr != nil && wth.reportContinueAsNew {
+				wth.reportWorkflowContinueAsNew(metricsHandler, workflowContext.workflowInfo, contErr)
+			}
 		},
 	}
 }
 
+// reportWorkflowContinueAsNew reports a workflow task that continued the workflow as new, see
+// WorkerOptions.ReportContinueAsNew. It is only called for completed workflow tasks, which are never replayed.
+func (wth *workflowTaskHandlerImpl) reportWorkflowContinueAsNew(
+	metricsHandler metrics.Handler,
+	workflowInfo *WorkflowInfo,
+	contErr *ContinueAsNewError,
+) {
+	historyLength := workflowInfo.GetCurrentHistoryLength()
+	metricsHandler.Gauge(metrics.WorkflowContinueAsNewHistoryLength).Update(float64(historyLength))
+	if wth.logContinueAsNew {
+		wth.logger.Info("Workflow continued as new",
+			tagWorkflowType, workflowInfo.WorkflowType.Name,
+			tagWorkflowID, workflowInfo.WorkflowExecution.ID,
+			tagRunID, workflowInfo.WorkflowExecution.RunID,
+			"HistoryLength", historyLength,
+			"NewWorkflowType", contErr.WorkflowType.Name,
+			"NewTaskQueue", contErr.TaskQueueName,
+		)
+	}
+}
+
+// logCommands logs the commands of a workflow task with their key attributes.
+func logCommands(logger log.Logger, startedEventID int64, commands []*commandpb.Command) {
+	for i, command := range commands {
+		keyvals := []interface{}{
+			tagTaskStartedEventID, startedEventID,
+			tagCommandIndex, i,
+			tagCommandType, command.GetCommandType().String(),
+		}
+		switch command.GetCommandType() {
+		case enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+			attr := command.GetScheduleActivityTaskCommandAttributes()
+			keyvals = append(keyvals, tagActivityID, attr.GetActivityId(), tagActivityType, attr.GetActivityType().GetName())
+		case enumspb.COMMAND_TYPE_REQUEST_CANCEL_ACTIVITY_TASK:
+			keyvals = append(keyvals, tagScheduledEventID, command.GetRequestCancelActivityTaskCommandAttributes().GetScheduledEventId())
+		case enumspb.COMMAND_TYPE_START_TIMER:
+			attr := command.GetStartTimerCommandAttributes()
+			keyvals = append(keyvals, tagTimerID, attr.GetTimerId(), tagStartToFireTimeout, attr.GetStartToFireTimeout().AsDuration())
+		case enumspb.COMMAND_TYPE_CANCEL_TIMER:
+			keyvals = append(keyvals, tagTimerID, command.GetCancelTimerCommandAttributes().GetTimerId())
+		case enumspb.COMMAND_TYPE_RECORD_MARKER:
+			keyvals = append(keyvals, tagMarkerName, command.GetRecordMarkerCommandAttributes().GetMarkerName())
+		case enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+			attr := command.GetStartChildWorkflowExecutionCommandAttributes()
+			keyvals = append(keyvals, tagChildWorkflowID, attr.GetWorkflowId(), tagChildWorkflowType, attr.GetWorkflowType().GetName())
+		case enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION:
+			attr := command.GetSignalExternalWorkflowExecutionCommandAttributes()
+			keyvals = append(keyvals, tagTargetWorkflowID, attr.GetExecution().GetWorkflowId(), tagSignalName, attr.GetSignalName())
+		case enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION:
+			keyvals = append(keyvals, tagTargetWorkflowID, command.GetRequestCancelExternalWorkflowExecutionCommandAttributes().GetWorkflowId())
+		case enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:
+			keyvals = append(keyvals, tagNewWorkflowType, command.GetContinueAsNewWorkflowExecutionCommandAttributes().GetWorkflowType().GetName())
+		case enumspb.COMMAND_TYPE_SCHEDULE_NEXUS_OPERATION:
+			attr := command.GetScheduleNexusOperationCommandAttributes()
+			keyvals = append(keyvals, tagNexusEndpoint, attr.GetEndpoint(), tagNexusService, attr.GetService(), tagNexusOperation, attr.GetOperation())
+		case enumspb.COMMAND_TYPE_PROTOCOL_MESSAGE:
+			keyvals = append(keyvals, tagMessageID, command.GetProtocolMessageCommandAttributes().GetMessageId())
+		}
+		logger.Debug("Workflow command", keyvals...)
+	}
+}
+
 func (wth *workflowTaskHandlerImpl) executeAnyPressurePoints(event *historypb.HistoryEvent, isInReplay bool) error {
 	if wth.ppMgr != nil && !reflect.ValueOf(wth.ppMgr).IsNil() && !isInReplay {
 		switch event.GetEventType() {
@@ -2075,6 +2295,7 @@ func newActivityTaskHandlerWithCustomProvider(
 			params.UseBuildIDForVersioning,
 			params.DeploymentOptions.Version,
 		),
+		onActivityPanic: params.OnActivityPanic,
 	}
 }
 
@@ -2278,9 +2499,10 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 		ath.workerStopCh, ath.namespace, ath.client.excludeInternalFromRetry)
 
 	workflowType := t.WorkflowType.GetName()
-	activityType := t.ActivityType.GetName()
+	// Activities invoked by an alias report their primary name
+	activityType := ath.registry.getActivityPrimaryName(t.ActivityType.GetName())
 	metricsHandler := ath.metricsHandler.WithTags(metrics.ActivityTags(workflowType, activityType, ath.taskQueueName))
-	ctx, err := WithActivityTask(canCtx, t, taskQueue, invoker, ath.logger, metricsHandler,
+	ctx, err := withActivityTask(canCtx, t, activityType, taskQueue, invoker, ath.logger, metricsHandler,
 		ath.dataConverter, ath.workerStopCh, ath.contextPropagators, ath.registry.interceptors, ath.client)
 	if err != nil {
 		return nil, err
@@ -2303,6 +2525,17 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 			ath.dataConverter, ath.failureConverter, ath.namespace, false, ath.versionStamp, ath.deployment, ath.workerDeploymentOptions), nil
 	}
 
+	if ath.workflowCancelledBeforeStart(ctx, t) {
+		ath.logger.Info("Skipping activity of canceled workflow.",
+			tagWorkflowID, t.WorkflowExecution.GetWorkflowId(),
+			tagRunID, t.WorkflowExecution.GetRunId(),
+			tagActivityType, activityType,
+			tagAttempt, t.Attempt,
+		)
+		return convertActivityResultToRespondRequest(ath.identity, t.TaskToken, nil, NewCanceledError(),
+			ath.dataConverter, ath.failureConverter, ath.namespace, true, ath.versionStamp, ath.deployment, ath.workerDeploymentOptions), nil
+	}
+
 	// panic handler
 	defer func() {
 		if p := recover(); p != nil {
@@ -2316,6 +2549,7 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 				tagPanicError, fmt.Sprintf("%v", p),
 				tagPanicStack, st)
 			metricsHandler.Counter(metrics.ActivityTaskErrorCounter).Inc(1)
+			notifyActivityPanic(ctx, ath.logger, ath.onActivityPanic, p, st)
 			panicErr := newPanicError(p, st)
 			result = convertActivityResultToRespondRequest(ath.identity, t.TaskToken, nil, panicErr,
 				ath.dataConverter, ath.failureConverter, ath.namespace, false, ath.versionStamp, ath.deployment, ath.workerDeploymentOptions)
@@ -2323,7 +2557,7 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 	}()
 
 	// propagate context information into the activity context from the headers
-	ctx, err = contextWithHeaderPropagated(ctx, t.Header, ath.contextPropagators)
+	ctx, err = contextWithHeaderPropagated(ctx, withoutSkipIfWorkflowCancelledHeader(t.Header), ath.contextPropagators)
 	if err != nil {
 		return nil, err
 	}
@@ -2368,6 +2602,61 @@ func (ath *activityTaskHandlerImpl) Execute(taskQueue string, t *workflowservice
 		ath.dataConverter, ath.failureConverter, ath.namespace, isActivityCanceled, ath.versionStamp, ath.deployment, ath.workerDeploymentOptions), nil
 }
 
+// workflowCancelledBeforeStart reports whether an activity scheduled with ActivityOptions.SkipIfWorkflowCancelled
+// should not run because its workflow was canceled or requested cancellation of the activity. This is best effort:
+// failures to describe the workflow are logged and the activity runs.
+func (ath *activityTaskHandlerImpl) workflowCancelledBeforeStart(
+	ctx context.Context,
+	t *workflowservice.PollActivityTaskQueueResponse,
+) bool {
+	if t.GetHeader().GetFields()[skipIfWorkflowCancelledHeaderKey] == nil || t.GetWorkflowExecution().GetWorkflowId() == "" {
+		return false
+	}
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	resp, err := ath.client.workflowService.DescribeWorkflowExecution(grpcCtx, &workflowservice.DescribeWorkflowExecutionRequest{
+		Namespace: t.GetWorkflowNamespace(),
+		Execution: t.GetWorkflowExecution(),
+	})
+	if err != nil {
+		ath.logger.Warn("Failed to describe workflow to check for its cancellation, running activity.",
+			tagWorkflowID, t.WorkflowExecution.GetWorkflowId(),
+			tagRunID, t.WorkflowExecution.GetRunId(),
+			tagError, err,
+		)
+		return false
+	}
+	if resp.GetWorkflowExecutionInfo().GetStatus() == enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED {
+		return true
+	}
+	for _, pending := range resp.GetPendingActivities() {
+		if pending.GetActivityId() == t.GetActivityId() {
+			return pending.GetState() == enumspb.PENDING_ACTIVITY_STATE_CANCEL_REQUESTED
+		}
+	}
+	return false
+}
+
+// notifyActivityPanic calls the user's OnActivityPanic callback, if any. A panic in the callback is logged and
+// swallowed so the original activity panic is still reported as the activity failure.
+func notifyActivityPanic(
+	ctx context.Context,
+	logger log.Logger,
+	onActivityPanic func(ctx context.Context, info ActivityInfo, recovered any, stack string),
+	p any,
+	st string,
+) {
+	if onActivityPanic == nil {
+		return
+	}
+	defer func() {
+		if hp := recover(); hp != nil {
+			logger.Error("OnActivityPanic callback panicked.", tagPanicError, fmt.Sprintf("%v", hp))
+		}
+	}()
+	onActivityPanic(ctx, GetActivityInfo(ctx), p, st)
+}
+
 func (ath *activityTaskHandlerImpl) getActivity(name string) activity {
 	if ath.activityProvider != nil {
 		return ath.activityProvider(name)
@@ -2,9 +2,12 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -20,6 +23,7 @@ import (
 
 	commandpb "go.temporal.io/api/command/v1"
 	commonpb "go.temporal.io/api/common/v1"
+	deploymentpb "go.temporal.io/api/deployment/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	protocolpb "go.temporal.io/api/protocol/v1"
@@ -28,6 +32,7 @@ import (
 	"go.temporal.io/api/serviceerror"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
 	updatepb "go.temporal.io/api/update/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 
@@ -86,6 +91,10 @@ func registerWorkflows(r *registry) {
 		binaryChecksumWorkflowFunc,
 		RegisterWorkflowOptions{Name: "BinaryChecksumWorkflow"},
 	)
+	r.RegisterWorkflowWithOptions(
+		currentBuildIDWorkflowFunc,
+		RegisterWorkflowOptions{Name: "CurrentBuildIDWorkflow"},
+	)
 	r.RegisterWorkflowWithOptions(
 		helloUpdateWorkflowFunc,
 		RegisterWorkflowOptions{Name: "HelloUpdate_Workflow"},
@@ -603,6 +612,75 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_BinaryChecksum() {
 	t.Equal(getBinaryChecksum(), checksums[2])
 }
 
+func (t *TaskHandlersTestSuite) TestWorkflowTask_AliasReportsPrimaryName() {
+	t.registry.RegisterWorkflowWithOptions(
+		func(ctx Context) (string, error) { return GetWorkflowInfo(ctx).WorkflowType.Name, nil },
+		RegisterWorkflowOptions{Name: "PrimaryNameWorkflow", Aliases: []string{"AliasNameWorkflow"}, DisableAlreadyRegisteredCheck: true},
+	)
+	taskQueue := "tq1"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+	}
+	task := createWorkflowTask(testEvents, 0, "AliasNameWorkflow")
+	params := t.getTestWorkerExecutionParams()
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry)
+	wftask := workflowTask{task: task}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	t.Equal(1, len(response.Commands))
+	t.Equal(enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION, response.Commands[0].GetCommandType())
+	var workflowType string
+	t.NoError(converter.GetDefaultDataConverter().FromPayloads(
+		response.Commands[0].GetCompleteWorkflowExecutionCommandAttributes().GetResult(), &workflowType))
+	t.Equal("PrimaryNameWorkflow", workflowType)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_CurrentBuildID() {
+	taskQueue := "tq1"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId:  2,
+			DeploymentVersion: &deploymentpb.WorkerDeploymentVersion{DeploymentName: "deployment", BuildId: "1.0"},
+		}),
+		createTestEventTimerStarted(5, 5),
+		createTestEventTimerFired(6, 5),
+		createTestEventWorkflowTaskScheduled(7, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(8),
+		createTestEventWorkflowTaskCompleted(9, &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId: 7,
+			WorkerVersion:    &commonpb.WorkerVersionStamp{BuildId: "chck2"},
+		}),
+		createTestEventTimerStarted(10, 10),
+		createTestEventTimerFired(11, 10),
+		createTestEventWorkflowTaskScheduled(12, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(13),
+	}
+	task := createWorkflowTask(testEvents, 8, "CurrentBuildIDWorkflow")
+	params := t.getTestWorkerExecutionParams()
+	params.WorkerBuildID = "2.0"
+	params.UseBuildIDForVersioning = true
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry)
+	wftask := workflowTask{task: task}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	t.Equal(enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION, response.Commands[0].GetCommandType())
+	var buildIDs []string
+	t.NoError(converter.GetDefaultDataConverter().FromPayloads(response.Commands[0].GetCompleteWorkflowExecutionCommandAttributes().GetResult(), &buildIDs))
+	// The second task was completed by a worker not using versioning
+	t.Equal([]string{"1.0", "", "2.0"}, buildIDs)
+}
+
 func (t *TaskHandlersTestSuite) TestRespondsToWFTWithWorkerBinaryID() {
 	taskQueue := "tq1"
 	workerBuildID := "yaaaay"
@@ -702,6 +780,111 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_ActivityTaskScheduled() {
 	t.NotNil(response.Commands[0].GetCompleteWorkflowExecutionCommandAttributes())
 }
 
+func (t *TaskHandlersTestSuite) TestWorkflowTask_SlowReplay() {
+	taskQueue := "tq1"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{ScheduledEventId: 2}),
+		createTestEventActivityTaskScheduled(5, &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:   "0",
+			ActivityType: &commonpb.ActivityType{Name: "Greeter_Activity"},
+			TaskQueue:    &taskqueuepb.TaskQueue{Name: taskQueue},
+		}),
+		createTestEventActivityTaskStarted(6, &historypb.ActivityTaskStartedEventAttributes{}),
+		createTestEventActivityTaskCompleted(7, &historypb.ActivityTaskCompletedEventAttributes{ScheduledEventId: 5}),
+		createTestEventWorkflowTaskStarted(8),
+	}
+	metricsHandler := metrics.NewCapturingHandler()
+	var slowReplays []SlowReplayInfo
+	params := t.getTestWorkerExecutionParams()
+	params.MetricsHandler = metricsHandler
+	params.SlowReplayThreshold = time.Nanosecond
+	params.OnSlowReplay = func(info SlowReplayInfo) { slowReplays = append(slowReplays, info) }
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry)
+
+	// A workflow task without replay is never reported
+	wftask := workflowTask{task: createWorkflowTask(testEvents[0:3], 0, "HelloWorld_Workflow")}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	_, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+	t.Empty(slowReplays)
+
+	// Replaying the first workflow task exceeds the threshold
+	task := createWorkflowTask(testEvents, 3, "HelloWorld_Workflow")
+	task.StartedEventId = 8
+	wftask = workflowTask{task: task}
+	wfctx = t.mustWorkflowContextImpl(&wftask, taskHandler)
+	_, err = taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+	t.Len(slowReplays, 1)
+	t.Equal("HelloWorld_Workflow", slowReplays[0].WorkflowType)
+	t.Equal(int64(8), slowReplays[0].HistoryLength)
+	t.Positive(slowReplays[0].ReplayDuration)
+	var slowReplayCount int64
+	for _, counter := range metricsHandler.Counters() {
+		if counter.Name == metrics.WorkflowTaskSlowReplayCounter {
+			slowReplayCount += counter.Value()
+		}
+	}
+	t.Equal(int64(1), slowReplayCount)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_ReportContinueAsNew() {
+	continueAsNewWorkflowFunc := func(ctx Context, input []byte) error {
+		return NewContinueAsNewError(ctx, "ReportedContinueAsNewWorkflow", input)
+	}
+	registry := newRegistry()
+	registry.RegisterWorkflowWithOptions(continueAsNewWorkflowFunc, RegisterWorkflowOptions{Name: "ReportedContinueAsNewWorkflow"})
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: "taskQueue"}}),
+	}
+	processTask := func(report bool) ([]*metrics.CapturedGauge, []string) {
+		metricsHandler := metrics.NewCapturingHandler()
+		logger := ilog.NewMemoryLogger()
+		params := t.getTestWorkerExecutionParams()
+		params.MetricsHandler = metricsHandler
+		params.Logger = logger
+		params.ReportContinueAsNew = report
+		params.LogContinueAsNew = report
+		taskHandler := newWorkflowTaskHandler(params, nil, registry)
+		wftask := workflowTask{task: createWorkflowTask(testEvents, 3, "ReportedContinueAsNewWorkflow")}
+		wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+		completion, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+		wfctx.Unlock(err)
+		t.NoError(err)
+		completion.applyCompletionMetrics()
+		var gauges []*metrics.CapturedGauge
+		for _, gauge := range metricsHandler.Gauges() {
+			if gauge.Name == metrics.WorkflowContinueAsNewHistoryLength {
+				gauges = append(gauges, gauge)
+			}
+		}
+		var lines []string
+		for _, line := range logger.Lines() {
+			if strings.Contains(line, "Workflow continued as new") {
+				lines = append(lines, line)
+			}
+		}
+		return gauges, lines
+	}
+
+	// Continue-as-new is not reported by default
+	gauges, lines := processTask(false)
+	t.Empty(gauges)
+	t.Empty(lines)
+
+	gauges, lines = processTask(true)
+	t.Len(gauges, 1)
+	t.Equal("ReportedContinueAsNewWorkflow", gauges[0].Tags[metrics.WorkflowTypeNameTagName])
+	t.Equal(float64(3), gauges[0].Value())
+	t.Len(lines, 1)
+	t.Contains(lines[0], "HistoryLength 3")
+}
+
 func (t *TaskHandlersTestSuite) TestWorkflowTask_QueryWorkflow_Sticky() {
 	// Schedule an activity and see if we complete workflow.
 	taskQueue := "sticky-tq"
@@ -741,6 +924,7 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_QueryWorkflow_Sticky() {
 	t.NotNil(response.Commands[0].GetScheduleActivityTaskCommandAttributes())
 
 	// then check the current state using query task
+	statsBefore := GetStickyCacheStats()
 	task = createQueryTask([]*historypb.HistoryEvent{}, 6, "HelloWorld_Workflow", queryType)
 	task.WorkflowExecution = execution
 	wftask = workflowTask{task: task}
@@ -749,6 +933,11 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_QueryWorkflow_Sticky() {
 	wfctx.Unlock(err)
 	t.NoError(err)
 	t.verifyQueryResult(queryResp, "waiting-activity-result")
+
+	// The query used the cached workflow state
+	statsAfter := GetStickyCacheStats()
+	t.Equal(statsBefore.Hits+1, statsAfter.Hits)
+	t.Equal(statsBefore.Misses, statsAfter.Misses)
 }
 
 func (t *TaskHandlersTestSuite) TestWorkflowTask_QueryWorkflow_NonSticky() {
@@ -855,6 +1044,7 @@ func (t *TaskHandlersTestSuite) TestCacheEvictionWhenErrorOccurs() {
 	// newWorkflowTaskWorkerInternal will set the laTunnel in taskHandler, without it, ProcessWorkflowTask()
 	// will fail as it can't find laTunnel in newWorkerCache().
 	newWorkflowTaskWorkerInternal(taskHandler, taskHandler, t.client, params, make(chan struct{}), nil)
+	statsBefore := GetStickyCacheStats()
 	wftask := workflowTask{task: task}
 	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
 	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
@@ -866,6 +1056,10 @@ func (t *TaskHandlersTestSuite) TestCacheEvictionWhenErrorOccurs() {
 
 	// There should be nothing in the cache.
 	t.EqualValues(params.cache.getWorkflowCache().Size(), 0)
+	// The cache notifies removals asynchronously
+	t.Eventually(func() bool {
+		return GetStickyCacheStats().Removals == statsBefore.Removals+1
+	}, time.Second, 10*time.Millisecond)
 }
 
 func (t *TaskHandlersTestSuite) TestWithMissingHistoryEvents() {
@@ -1022,6 +1216,49 @@ func (t *TaskHandlersTestSuite) testSideEffectDeferHelper(cacheSize int) {
 	t.EqualValues(0, params.cache.getWorkflowCache().Size())
 }
 
+func (t *TaskHandlersTestSuite) TestConfigValue_RecordsMarkerOnChange() {
+	fetched := []int{1, 1, 2, 2}
+	var values []int
+	workflowFunc := func(ctx Context) error {
+		for range fetched {
+			value, err := NewConfigValue(ctx, "config", func(ctx Context) int {
+				next := fetched[0]
+				fetched = fetched[1:]
+				return next
+			})
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		return nil
+	}
+	t.registry.RegisterWorkflowWithOptions(workflowFunc, RegisterWorkflowOptions{Name: "ConfigValueWorkflow"})
+
+	taskQueue := "taskQueue"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+	}
+	taskHandler := newWorkflowTaskHandler(t.getTestWorkerExecutionParams(), nil, t.registry)
+	wftask := workflowTask{task: createWorkflowTask(testEvents, 0, "ConfigValueWorkflow")}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+
+	t.Equal([]int{1, 1, 2, 2}, values)
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	var markers int
+	for _, command := range response.Commands {
+		if command.GetCommandType() == enumspb.COMMAND_TYPE_RECORD_MARKER {
+			markers++
+		}
+	}
+	t.Equal(2, markers)
+}
+
 func (t *TaskHandlersTestSuite) TestWorkflowTask_NondeterministicDetection() {
 	taskQueue := "taskQueue"
 	testEvents := []*historypb.HistoryEvent{
@@ -1145,6 +1382,325 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_WorkflowPanics() {
 	t.True(ok)
 }
 
+func (t *TaskHandlersTestSuite) TestWorkflowTask_AwaitWithOptionsTimerSummary() {
+	t.registry.RegisterWorkflowWithOptions(
+		func(ctx Context) (bool, error) {
+			options := AwaitOptions{Timeout: time.Hour, TimerOptions: TimerOptions{Summary: "awaiting approval"}}
+			return AwaitWithOptions(ctx, options, func() bool { return false })
+		},
+		RegisterWorkflowOptions{Name: "AwaitWithOptionsWorkflow", DisableAlreadyRegisteredCheck: true},
+	)
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: testWorkflowTaskTaskqueue}}),
+	}
+	taskHandler := newWorkflowTaskHandler(t.getTestWorkerExecutionParams(), nil, t.registry)
+	wftask := workflowTask{task: createWorkflowTask(testEvents, 0, "AwaitWithOptionsWorkflow")}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+
+	// The summary is set on the timer the await starts
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	t.Len(response.Commands, 1)
+	t.Equal(enumspb.COMMAND_TYPE_START_TIMER, response.Commands[0].GetCommandType())
+	t.Equal(time.Hour, response.Commands[0].GetStartTimerCommandAttributes().GetStartToFireTimeout().AsDuration())
+	var summary string
+	t.NoError(converter.GetDefaultDataConverter().FromPayload(response.Commands[0].GetUserMetadata().GetSummary(), &summary))
+	t.Equal("awaiting approval", summary)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_WorkflowPanicCallsOnWorkflowPanic() {
+	taskQueue := "taskQueue"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+	}
+	params := t.getTestWorkerExecutionParams()
+	params.WorkflowPanicPolicy = BlockWorkflow
+	var calls int
+	var gotInfo WorkflowInfo
+	var gotRecovered any
+	var gotStack string
+	params.OnWorkflowPanic = func(info WorkflowInfo, recovered any, stack string) {
+		calls++
+		gotInfo, gotRecovered, gotStack = info, recovered, stack
+		// A panicking callback must not prevent the panic policy from being applied
+		panic("handler boom")
+	}
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry)
+	processTask := func(workflowType string) error {
+		wftask := workflowTask{task: createWorkflowTask(testEvents, 3, workflowType)}
+		wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+		_, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+		wfctx.Unlock(err)
+		return err
+	}
+
+	err := processTask("PanicWorkflow")
+	var panicErr *workflowPanicError
+	t.ErrorAs(err, &panicErr)
+	t.Equal(1, calls)
+	t.Equal("PanicWorkflow", gotInfo.WorkflowType.Name)
+	t.Equal("panicError", gotRecovered)
+	t.Contains(gotStack, "panicWorkflowFunc")
+
+	// A PanicError returned by the workflow is not a panic of the workflow code
+	t.NoError(processTask("ReturnPanicWorkflow"))
+	t.Equal(1, calls)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_PanicPolicyPerWorkflowType() {
+	failWorkflow := FailWorkflow
+	registry := newRegistry()
+	registry.RegisterWorkflowWithOptions(panicWorkflowFunc, RegisterWorkflowOptions{Name: "StrictPanicWorkflow"})
+	registry.RegisterWorkflowWithOptions(panicWorkflowFunc, RegisterWorkflowOptions{
+		Name:        "LenientPanicWorkflow",
+		PanicPolicy: &failWorkflow,
+	})
+	t.Panics(func() {
+		invalid := WorkflowPanicPolicy(42)
+		registry.RegisterWorkflowWithOptions(panicWorkflowFunc, RegisterWorkflowOptions{Name: "InvalidPanicWorkflow", PanicPolicy: &invalid})
+	})
+
+	params := t.getTestWorkerExecutionParams()
+	params.WorkflowPanicPolicy = BlockWorkflow
+	taskHandler := newWorkflowTaskHandler(params, nil, registry)
+	processTask := func(workflowType string) (*workflowTaskCompletion, error) {
+		testEvents := []*historypb.HistoryEvent{
+			createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: "taskQueue"}}),
+		}
+		wftask := workflowTask{task: createWorkflowTask(testEvents, 3, workflowType)}
+		wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+		request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+		wfctx.Unlock(err)
+		return request, err
+	}
+
+	// The worker policy blocks workflows registered without a policy
+	_, err := processTask("StrictPanicWorkflow")
+	var panicErr *workflowPanicError
+	t.ErrorAs(err, &panicErr)
+
+	// The policy of the workflow type takes precedence over the one of the worker
+	request, err := processTask("LenientPanicWorkflow")
+	t.NoError(err)
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	closeCommand := response.Commands[len(response.Commands)-1]
+	t.Equal(enumspb.COMMAND_TYPE_FAIL_WORKFLOW_EXECUTION, closeCommand.CommandType)
+	t.Contains(closeCommand.GetFailWorkflowExecutionCommandAttributes().GetFailure().GetMessage(), "FailWorkflow")
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_DeadlockDetectionTimeoutPerWorkflowType() {
+	slowWorkflowFunc := func(Context, []byte) error {
+		time.Sleep(300 * time.Millisecond)
+		return nil
+	}
+	registry := newRegistry()
+	registry.RegisterWorkflowWithOptions(slowWorkflowFunc, RegisterWorkflowOptions{Name: "SlowWorkflow"})
+	registry.RegisterWorkflowWithOptions(slowWorkflowFunc, RegisterWorkflowOptions{
+		Name:                     "HeavyComputationWorkflow",
+		DeadlockDetectionTimeout: 10 * time.Second,
+	})
+	t.Panics(func() {
+		registry.RegisterWorkflowWithOptions(slowWorkflowFunc, RegisterWorkflowOptions{
+			Name:                     "InvalidDeadlockTimeoutWorkflow",
+			DeadlockDetectionTimeout: -time.Second,
+		})
+	})
+
+	params := t.getTestWorkerExecutionParams()
+	params.DeadlockDetectionTimeout = 100 * time.Millisecond
+	params.WorkflowPanicPolicy = BlockWorkflow
+	taskHandler := newWorkflowTaskHandler(params, nil, registry)
+	processTask := func(workflowType string) error {
+		testEvents := []*historypb.HistoryEvent{
+			createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: "taskQueue"}}),
+		}
+		wftask := workflowTask{task: createWorkflowTask(testEvents, 3, workflowType)}
+		wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+		_, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+		wfctx.Unlock(err)
+		return err
+	}
+
+	// The worker timeout applies to workflows registered without a timeout
+	err := processTask("SlowWorkflow")
+	t.ErrorContains(err, "Potential deadlock detected")
+
+	// The timeout of the workflow type takes precedence over the one of the worker
+	t.NoError(processTask("HeavyComputationWorkflow"))
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_CommandLogging() {
+	taskQueue := "tq1"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{ScheduledEventId: 2}),
+		createTestEventTimerStarted(5, 5),
+		createTestEventTimerFired(6, 5),
+		createTestEventWorkflowTaskScheduled(7, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(8),
+	}
+	processTask := func(enabled bool) []string {
+		logger := ilog.NewMemoryLogger()
+		params := t.getTestWorkerExecutionParams()
+		params.Logger = logger
+		params.EnableWorkflowCommandLogging = enabled
+		taskHandler := newWorkflowTaskHandler(params, nil, t.registry)
+		task := createWorkflowTask(testEvents, 3, "BinaryChecksumWorkflow")
+		task.StartedEventId = 8
+		wftask := workflowTask{task: task}
+		wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+		request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+		wfctx.Unlock(err)
+		t.NoError(err)
+		response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+		t.Equal(1, len(response.Commands))
+		var lines []string
+		for _, line := range logger.Lines() {
+			if strings.Contains(line, "Workflow command") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	t.Empty(processTask(false))
+
+	// The command of the replayed timer is not logged, only the one of the new timer
+	lines := processTask(true)
+	t.Equal(1, len(lines))
+	t.Contains(lines[0], "DEBUG")
+	t.Contains(lines[0], "TaskStartedEventID 8")
+	t.Contains(lines[0], "CommandIndex 0")
+	t.Contains(lines[0], "CommandType StartTimer")
+	t.Contains(lines[0], "TimerID 10")
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_StickyCachePrewarm() {
+	taskQueue := "tq1"
+	execution := &commonpb.WorkflowExecution{WorkflowId: "prewarm-workflow-id", RunId: uuid.NewString()}
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{ScheduledEventId: 2, BinaryChecksum: "chck1"}),
+		createTestEventTimerStarted(5, 5),
+		createTestEventTimerFired(6, 5),
+		createTestEventWorkflowTaskScheduled(7, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(8),
+		createTestEventWorkflowTaskCompleted(9, &historypb.WorkflowTaskCompletedEventAttributes{ScheduledEventId: 7, BinaryChecksum: "chck2"}),
+		createTestEventTimerStarted(10, 10),
+		createTestEventTimerFired(11, 10),
+	}
+
+	mockCtrl := gomock.NewController(t.T())
+	mockService := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
+	mockService.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Execution: execution,
+			Type:      &commonpb.WorkflowType{Name: "BinaryChecksumWorkflow"},
+			Status:    enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			TaskQueue: taskQueue,
+		}}, nil)
+	mockService.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: testEvents}}, nil)
+
+	params := t.getTestWorkerExecutionParams()
+	params.TaskQueue = taskQueue
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry).(*workflowTaskHandlerImpl)
+	ww := &workflowWorker{executionParameters: params, workflowService: mockService, taskHandler: taskHandler}
+	t.NoError(ww.prewarmWorkflow(context.Background(), &commonpb.WorkflowExecution{WorkflowId: execution.WorkflowId}))
+	prewarmed := params.cache.getWorkflowContext(execution.RunId)
+	t.NotNil(prewarmed)
+	t.True(prewarmed.prewarmed)
+	// The timer fired event was not part of the last completed workflow task
+	t.Equal(int64(10), prewarmed.lastHandledEventID)
+
+	// The next task has the full history but continues from the pre-warmed state
+	task := createWorkflowTask(testEvents, 8, "BinaryChecksumWorkflow")
+	task.WorkflowExecution = execution
+	task.StartedEventId = 13
+	wftask := workflowTask{task: task}
+	wfctx := t.mustWorkflowContextImpl(&wftask, taskHandler)
+	t.Same(prewarmed, wfctx)
+	request, err := taskHandler.ProcessWorkflowTask(&wftask, wfctx, nil)
+	wfctx.Unlock(err)
+	t.NoError(err)
+	t.False(wfctx.prewarmed)
+	response := request.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	t.Equal(1, len(response.Commands))
+	t.Equal(enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION, response.Commands[0].GetCommandType())
+	var checksums []string
+	t.NoError(converter.GetDefaultDataConverter().FromPayloads(response.Commands[0].GetCompleteWorkflowExecutionCommandAttributes().GetResult(), &checksums))
+	t.Equal([]string{"chck1", "chck2", getBinaryChecksum()}, checksums)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_ExportImportStickyCache() {
+	taskQueue := "tq-export"
+	execution := &commonpb.WorkflowExecution{WorkflowId: "export-workflow-id", RunId: uuid.NewString()}
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{TaskQueue: &taskqueuepb.TaskQueue{Name: taskQueue}}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{ScheduledEventId: 2}),
+		createTestEventTimerStarted(5, 5),
+	}
+
+	mockCtrl := gomock.NewController(t.T())
+	mockService := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
+	// Loaded once to populate the cache and once on import
+	mockService.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&workflowservice.DescribeWorkflowExecutionResponse{WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+			Execution: execution,
+			Type:      &commonpb.WorkflowType{Name: "BinaryChecksumWorkflow"},
+			Status:    enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			TaskQueue: taskQueue,
+		}}, nil).Times(2)
+	mockService.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		&workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: testEvents}}, nil).Times(2)
+
+	params := t.getTestWorkerExecutionParams()
+	params.TaskQueue = taskQueue
+	taskHandler := newWorkflowTaskHandler(params, nil, t.registry).(*workflowTaskHandlerImpl)
+	ww := &workflowWorker{executionParameters: params, workflowService: mockService, taskHandler: taskHandler, stopC: make(chan struct{})}
+	t.NoError(ww.prewarmWorkflow(context.Background(), execution))
+
+	blob, err := exportStickyCache(params.cache.getWorkflowCache())
+	t.NoError(err)
+	var export stickyCacheExport
+	t.NoError(json.Unmarshal(blob, &export))
+	t.Equal(stickyCacheExportVersion, export.Version)
+	idx := slices.IndexFunc(export.Workflows, func(w stickyCacheExportedWorkflow) bool { return w.RunID == execution.RunId })
+	t.GreaterOrEqual(idx, 0)
+	exported := export.Workflows[idx]
+	t.Equal(stickyCacheExportedWorkflow{
+		Namespace:    testNamespace,
+		TaskQueue:    taskQueue,
+		WorkflowType: "BinaryChecksumWorkflow",
+		WorkflowID:   execution.WorkflowId,
+		RunID:        execution.RunId,
+	}, exported)
+
+	// A new worker loads the exported workflow, discarding the ones it cannot load
+	params.cache.removeWorkflowContext(execution.RunId)
+	unregistered, otherTaskQueue := exported, exported
+	unregistered.WorkflowType = "UnregisteredWorkflow"
+	otherTaskQueue.TaskQueue = "other-task-queue"
+	t.Equal(1, ww.importStickyCache([]stickyCacheExportedWorkflow{unregistered, otherTaskQueue, exported}))
+	imported := params.cache.getWorkflowContext(execution.RunId)
+	t.NotNil(imported)
+	t.True(imported.prewarmed)
+	params.cache.removeWorkflowContext(execution.RunId)
+
+	_, err = ImportStickyCache([]byte(`{"version":2}`))
+	t.ErrorContains(err, "unsupported sticky cache export version")
+	_, err = ImportStickyCache([]byte("not json"))
+	t.ErrorContains(err, "invalid sticky cache export")
+}
+
 func (t *TaskHandlersTestSuite) TestGetWorkflowInfo() {
 	parentID := "parentID"
 	parentRunID := "parentRun"
@@ -2219,6 +2775,197 @@ func (t *TaskHandlersTestSuite) TestActivityCancellationUsesIsCanceledError() {
 	t.Equal(wep.Namespace, canceledReq.Namespace)
 }
 
+func (t *TaskHandlersTestSuite) TestActivityPanicCallsOnActivityPanic() {
+	activityName := "activityPanicHandler"
+	t.registry.RegisterActivityWithOptions(
+		func(ctx context.Context) error { panic("boom") },
+		RegisterActivityOptions{Name: activityName, DisableAlreadyRegisteredCheck: true},
+	)
+
+	var gotInfo ActivityInfo
+	var gotRecovered any
+	var gotStack string
+	mockCtrl := gomock.NewController(t.T())
+	mockService := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
+	client := WorkflowClient{workflowService: mockService}
+	wep := t.getTestWorkerExecutionParams()
+	wep.OnActivityPanic = func(ctx context.Context, info ActivityInfo, recovered any, stack string) {
+		gotInfo, gotRecovered, gotStack = info, recovered, stack
+		// A panicking callback must not prevent the activity failure from being reported
+		panic("handler boom")
+	}
+	activityHandler := newActivityTaskHandler(&client, wep, t.registry)
+	now := time.Now()
+	pats := &workflowservice.PollActivityTaskQueueResponse{
+		Attempt:   1,
+		TaskToken: []byte("token"),
+		WorkflowExecution: &commonpb.WorkflowExecution{
+			WorkflowId: "wID",
+			RunId:      "rID",
+		},
+		ActivityType:           &commonpb.ActivityType{Name: activityName},
+		ActivityId:             "aID",
+		ScheduledTime:          timestamppb.New(now),
+		ScheduleToCloseTimeout: durationpb.New(time.Second),
+		StartedTime:            timestamppb.New(now),
+		StartToCloseTimeout:    durationpb.New(time.Second),
+		WorkflowType: &commonpb.WorkflowType{
+			Name: "wType",
+		},
+		WorkflowNamespace: wep.Namespace,
+	}
+
+	result, err := activityHandler.Execute(taskqueue, pats)
+	t.Require().NoError(err)
+	failedReq, ok := result.(*workflowservice.RespondActivityTaskFailedRequest)
+	t.Require().True(ok, "expected failed response")
+	t.Equal("boom", failedReq.Failure.GetMessage())
+	t.Equal("boom", gotRecovered)
+	t.Equal("aID", gotInfo.ActivityID)
+	t.Equal(activityName, gotInfo.ActivityType.Name)
+	t.Contains(gotStack, "panic")
+}
+
+func (t *TaskHandlersTestSuite) TestLocalActivityPanicCallsOnActivityPanic() {
+	var gotInfo ActivityInfo
+	var gotRecovered any
+	var gotStack string
+	taskHandler := localActivityTaskHandler{
+		backgroundContext: context.Background(),
+		metricsHandler:    metrics.NopHandler,
+		logger:            t.logger,
+		onActivityPanic: func(ctx context.Context, info ActivityInfo, recovered any, stack string) {
+			gotInfo, gotRecovered, gotStack = info, recovered, stack
+			panic("handler boom")
+		},
+	}
+	params := &ExecuteLocalActivityParams{
+		ExecuteLocalActivityOptions: ExecuteLocalActivityOptions{ScheduleToCloseTimeout: time.Second},
+		ActivityFn:                  func(ctx context.Context) error { panic("boom") },
+		ActivityType:                "localActivityPanicHandler",
+		WorkflowInfo: &WorkflowInfo{
+			WorkflowType:      WorkflowType{Name: "wType"},
+			WorkflowExecution: WorkflowExecution{ID: "wID", RunID: "rID"},
+		},
+	}
+	task := &localActivityTask{
+		activityID:    "laID",
+		params:        params,
+		callback:      func(lar *LocalActivityResultWrapper) {},
+		attempt:       1,
+		scheduledTime: time.Now(),
+	}
+
+	result := taskHandler.executeLocalActivityTask(task)
+	var panicErr *PanicError
+	t.Require().ErrorAs(result.err, &panicErr)
+	t.Equal("boom", panicErr.Error())
+	t.Equal("boom", gotRecovered)
+	t.Equal("laID", gotInfo.ActivityID)
+	t.Equal("localActivityPanicHandler", gotInfo.ActivityType.Name)
+	t.True(gotInfo.IsLocalActivity)
+	t.Contains(gotStack, "panic")
+}
+
+func (t *TaskHandlersTestSuite) TestActivitySkipIfWorkflowCancelled() {
+	activityName := "activitySkipIfWorkflowCancelled"
+	var ran int
+	t.registry.RegisterActivityWithOptions(
+		func(ctx context.Context) error {
+			ran++
+			t.Nil(ctx.Value(contextKey(skipIfWorkflowCancelledHeaderKey)))
+			return nil
+		},
+		RegisterActivityOptions{Name: activityName, DisableAlreadyRegisteredCheck: true},
+	)
+	header, err := withSkipIfWorkflowCancelledHeader(&commonpb.Header{})
+	t.Require().NoError(err)
+
+	tests := []struct {
+		name         string
+		describeResp *workflowservice.DescribeWorkflowExecutionResponse
+		describeErr  error
+		skipped      bool
+	}{
+		{
+			name: "workflow canceled",
+			describeResp: &workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED},
+			},
+			skipped: true,
+		},
+		{
+			name: "activity cancel requested",
+			describeResp: &workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING},
+				PendingActivities: []*workflowpb.PendingActivityInfo{
+					{ActivityId: "other", State: enumspb.PENDING_ACTIVITY_STATE_SCHEDULED},
+					{ActivityId: "aID", State: enumspb.PENDING_ACTIVITY_STATE_CANCEL_REQUESTED},
+				},
+			},
+			skipped: true,
+		},
+		{
+			name: "workflow running",
+			describeResp: &workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING},
+				PendingActivities: []*workflowpb.PendingActivityInfo{
+					{ActivityId: "aID", State: enumspb.PENDING_ACTIVITY_STATE_SCHEDULED},
+				},
+			},
+		},
+		{
+			name:        "describe fails",
+			describeErr: serviceerror.NewPermissionDenied("denied", ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func() {
+			ran = 0
+			mockCtrl := gomock.NewController(t.T())
+			mockService := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
+			mockService.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(tt.describeResp, tt.describeErr)
+			client := WorkflowClient{workflowService: mockService}
+			wep := t.getTestWorkerExecutionParams()
+			wep.DataConverter = converter.GetDefaultDataConverter()
+			// The SDK's header field is not visible to context propagators and interceptors
+			wep.ContextPropagators = []ContextPropagator{NewKeysPropagator([]string{skipIfWorkflowCancelledHeaderKey})}
+			activityHandler := newActivityTaskHandler(&client, wep, t.registry)
+			now := time.Now()
+			pats := &workflowservice.PollActivityTaskQueueResponse{
+				Attempt:   1,
+				TaskToken: []byte("token"),
+				WorkflowExecution: &commonpb.WorkflowExecution{
+					WorkflowId: "wID",
+					RunId:      "rID",
+				},
+				ActivityType:           &commonpb.ActivityType{Name: activityName},
+				ActivityId:             "aID",
+				ScheduledTime:          timestamppb.New(now),
+				ScheduleToCloseTimeout: durationpb.New(time.Second),
+				StartedTime:            timestamppb.New(now),
+				StartToCloseTimeout:    durationpb.New(time.Second),
+				WorkflowType: &commonpb.WorkflowType{
+					Name: "wType",
+				},
+				WorkflowNamespace: wep.Namespace,
+				Header:            header,
+			}
+
+			result, err := activityHandler.Execute(taskqueue, pats)
+			t.Require().NoError(err)
+			if tt.skipped {
+				t.IsType(&workflowservice.RespondActivityTaskCanceledRequest{}, result)
+				t.Equal(0, ran)
+			} else {
+				t.IsType(&workflowservice.RespondActivityTaskCompletedRequest{}, result)
+				t.Equal(1, ran)
+			}
+		})
+	}
+}
+
 func Test_NonDeterministicCheck(t *testing.T) {
 	unimplementedCommands := []int32{
 		int32(enumspb.COMMAND_TYPE_UNSPECIFIED),
@@ -2775,3 +3522,9 @@ func TestHistoryIteratorMaxEventID(t *testing.T) {
 	require.Error(t, err)
 
 }
+
+func TestConvertFromPBWorkflowRetryPolicy(t *testing.T) {
+	require.Nil(t, convertFromPBWorkflowRetryPolicy(nil))
+	require.Nil(t, convertFromPBWorkflowRetryPolicy(&commonpb.RetryPolicy{}))
+	require.Equal(t, &RetryPolicy{MaximumAttempts: 3}, convertFromPBWorkflowRetryPolicy(&commonpb.RetryPolicy{MaximumAttempts: 3}))
+}
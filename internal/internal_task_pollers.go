@@ -144,6 +144,10 @@ type (
 
 		numNormalPollerMetric *numPollerMetric
 		numStickyPollerMetric *numPollerMetric
+
+		fatalError func() error
+
+		shadowReplayer *workflowShadowReplayer
 	}
 
 	// activityTaskPoller implements polling/processing a workflow task
@@ -189,6 +193,7 @@ type (
 		interceptors       []WorkerInterceptor
 		client             *WorkflowClient
 		workerStopChannel  <-chan struct{}
+		onActivityPanic    func(ctx context.Context, info ActivityInfo, recovered any, stack string)
 	}
 
 	localActivityResult struct {
@@ -344,6 +349,8 @@ func newWorkflowTaskProcessor(
 		eagerActivityExecutor:        params.eagerActivityExecutor,
 		numNormalPollerMetric:        newNumPollerMetric(params.MetricsHandler, metrics.PollerTypeWorkflowTask),
 		numStickyPollerMetric:        newNumPollerMetric(params.MetricsHandler, metrics.PollerTypeWorkflowStickyTask),
+		fatalError:                   params.WorkflowTaskFatalError,
+		shadowReplayer:               params.shadowReplayer,
 	}
 }
 
@@ -386,6 +393,7 @@ func (wtp *workflowTaskProcessor) createPoller(mode workflowTaskPollerMode) task
 // ProcessTask processes a task which could be workflow task or local activity result
 func (wtp *workflowTaskProcessor) ProcessTask(task interface{}) error {
 	if wtp.stopping() {
+		wtp.failTaskOnFatalError(task)
 		return errStop
 	}
 
@@ -399,6 +407,36 @@ func (wtp *workflowTaskProcessor) ProcessTask(task interface{}) error {
 	}
 }
 
+// failTaskOnFatalError fails a workflow task that is dropped because the worker is stopping, if the worker stops
+// because of a fatal error and WorkerOptions.FailWorkflowTasksOnFatalError is set.
+func (wtp *workflowTaskProcessor) failTaskOnFatalError(task interface{}) {
+	if wtp.fatalError == nil {
+		return
+	}
+	fatalErr := wtp.fatalError()
+	if fatalErr == nil {
+		return
+	}
+	var pollResponse *workflowservice.PollWorkflowTaskQueueResponse
+	switch task := task.(type) {
+	case *workflowTask:
+		pollResponse = task.task
+	case *eagerWorkflowTask:
+		pollResponse = task.task
+	}
+	// Legacy query tasks have no workflow task to fail
+	if pollResponse == nil || pollResponse.Query != nil {
+		return
+	}
+	_, _ = wtp.RespondTaskCompletedWithMetrics(nil, newWorkerFatalErrorTaskFailure(fatalErr), pollResponse, time.Now())
+}
+
+// newWorkerFatalErrorTaskFailure returns the error a workflow task is failed with when the worker stops because of
+// the given fatal error.
+func newWorkerFatalErrorTaskFailure(err error) error {
+	return fmt.Errorf("worker stopped because of a fatal error: %w", err)
+}
+
 func (wtp *workflowTaskProcessor) processWorkflowTask(task *workflowTask) (retErr error) {
 	if task.task == nil {
 		// We didn't have task, poll might have timeout.
@@ -468,6 +506,9 @@ func (wtp *workflowTaskProcessor) processWorkflowTask(task *workflowTask) (retEr
 		if _, ok := taskErr.(workflowTaskHeartbeatError); ok {
 			return taskErr
 		}
+		if taskErr == nil && wtp.shadowReplayer != nil {
+			taskErr = wtp.shadowReplayer.check(task.task, taskCompletion)
+		}
 		response, err := wtp.RespondTaskCompletedWithMetrics(taskCompletion, taskErr, task.task, startTime)
 		if err != nil {
 			// If we get an error responding to the workflow task we need to evict the execution from the cache.
@@ -701,6 +742,7 @@ func newLocalActivityPoller(
 		interceptors:       interceptors,
 		client:             client,
 		workerStopChannel:  workerStopCh,
+		onActivityPanic:    params.OnActivityPanic,
 	}
 	return &localActivityTaskPoller{
 		basePoller:   basePoller{metricsHandler: params.MetricsHandler, stopC: params.WorkerStopChannel},
@@ -800,6 +842,7 @@ func (lath *localActivityTaskHandler) executeLocalActivityTask(task *localActivi
 					tagPanicError, fmt.Sprintf("%v", p),
 					tagPanicStack, st)
 				metricsHandler.Counter(metrics.LocalActivityErrorCounter).Inc(1)
+				notifyActivityPanic(ctx, lath.logger, lath.onActivityPanic, p, st)
 				err = newPanicError(p, st)
 			}
 			if err != nil && !isBenignApplicationError(err) {
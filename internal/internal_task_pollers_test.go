@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"github.com/google/uuid"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -12,14 +13,18 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	protocolpb "go.temporal.io/api/protocol/v1"
+	querypb "go.temporal.io/api/query/v1"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
 	"go.temporal.io/api/update/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.temporal.io/sdk/internal/common/metrics"
 )
 
 type countingTaskHandler struct {
@@ -409,3 +414,153 @@ func TestWFTPanicInTaskHandler(t *testing.T) {
 	// Workflow should not be in cache
 	require.Nil(t, cache.getWorkflowContext(runID))
 }
+
+func TestWFTFailedOnFatalStop(t *testing.T) {
+	stopCh := make(chan struct{})
+	close(stopCh)
+	fatalErr := errors.New("fatal error")
+	params := workerExecutionParameters{
+		cache:                  NewWorkerCache(),
+		WorkerStopChannel:      stopCh,
+		WorkflowTaskFatalError: func() error { return fatalErr },
+	}
+	ensureRequiredParams(&params)
+	var (
+		wfType    = commonpb.WorkflowType{Name: t.Name() + "-workflow-type"}
+		wfe       = commonpb.WorkflowExecution{RunId: t.Name() + "-run-id", WorkflowId: t.Name() + "-workflow-id"}
+		ctrl      = gomock.NewController(t)
+		client    = workflowservicemock.NewMockWorkflowServiceClient(ctrl)
+		pollResp0 = workflowservice.PollWorkflowTaskQueueResponse{
+			Attempt:           1,
+			WorkflowExecution: &wfe,
+			WorkflowType:      &wfType,
+			TaskToken:         []byte("token"),
+		}
+		queryResp = workflowservice.PollWorkflowTaskQueueResponse{
+			WorkflowExecution: &wfe,
+			WorkflowType:      &wfType,
+			TaskToken:         []byte("query-token"),
+			Query:             &querypb.WorkflowQuery{QueryType: "query"},
+		}
+	)
+
+	client.EXPECT().RespondWorkflowTaskFailed(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			req *workflowservice.RespondWorkflowTaskFailedRequest,
+			_ ...grpc.CallOption,
+		) (*workflowservice.RespondWorkflowTaskFailedResponse, error) {
+			require.Equal(t, []byte("token"), req.TaskToken)
+			require.Contains(t, req.Failure.GetMessage(), "fatal error")
+			return &workflowservice.RespondWorkflowTaskFailedResponse{}, nil
+		}).Times(1)
+
+	taskHandler := newWorkflowTaskHandler(params, nil, newRegistry())
+	poller := newWorkflowTaskProcessor(taskHandler, taskHandler, client, params, uuid.NewString())
+	require.ErrorIs(t, poller.ProcessTask(&workflowTask{task: &pollResp0}), errStop)
+	// Legacy query tasks are dropped without a response
+	require.ErrorIs(t, poller.ProcessTask(&workflowTask{task: &queryResp}), errStop)
+
+	// Without a fatal error the task is dropped as before
+	fatalErr = nil
+	require.ErrorIs(t, poller.ProcessTask(&workflowTask{task: &pollResp0}), errStop)
+}
+
+func TestWFTShadowReplay(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		deterministic  bool
+		failOnMismatch bool
+		expectFailure  bool
+		expectMismatch bool
+	}{
+		{name: "Deterministic", deterministic: true, failOnMismatch: true},
+		{name: "Mismatch", expectMismatch: true},
+		{name: "MismatchFailsTask", failOnMismatch: true, expectMismatch: true, expectFailure: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			metricsHandler := metrics.NewCapturingHandler()
+			params := workerExecutionParameters{
+				cache:                                  newWorkerCache(&sharedWorkerCache{}, &sync.Mutex{}, 10),
+				MetricsHandler:                         metricsHandler,
+				ShadowReplaySampleRate:                 1,
+				FailWorkflowTaskOnShadowReplayMismatch: tc.failOnMismatch,
+			}
+			ensureRequiredParams(&params)
+			wfType := commonpb.WorkflowType{Name: t.Name() + "-workflow-type"}
+			reg := newRegistry()
+			var runs int
+			reg.RegisterWorkflowWithOptions(func(ctx Context) error {
+				runs++
+				if runs == 1 || tc.deterministic {
+					return Sleep(ctx, time.Minute)
+				}
+				return nil
+			}, RegisterWorkflowOptions{
+				Name: wfType.Name,
+			})
+			taskQueue := taskqueuepb.TaskQueue{Name: t.Name() + "task-queue"}
+			history := historypb.History{Events: []*historypb.HistoryEvent{
+				createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{
+					TaskQueue: &taskQueue,
+				}),
+				createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{
+					TaskQueue:           &taskQueue,
+					StartToCloseTimeout: &durationpb.Duration{Seconds: 10},
+					Attempt:             1,
+				}),
+				createTestEventWorkflowTaskStarted(3),
+			}}
+			ctrl := gomock.NewController(t)
+			client := workflowservicemock.NewMockWorkflowServiceClient(ctrl)
+			if tc.expectFailure {
+				client.EXPECT().RespondWorkflowTaskFailed(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(
+						_ context.Context,
+						req *workflowservice.RespondWorkflowTaskFailedRequest,
+						_ ...grpc.CallOption,
+					) (*workflowservice.RespondWorkflowTaskFailedResponse, error) {
+						require.Equal(t, enumspb.WORKFLOW_TASK_FAILED_CAUSE_NON_DETERMINISTIC_ERROR, req.Cause)
+						return &workflowservice.RespondWorkflowTaskFailedResponse{}, nil
+					})
+			} else {
+				client.EXPECT().RespondWorkflowTaskCompleted(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(
+						_ context.Context,
+						req *workflowservice.RespondWorkflowTaskCompletedRequest,
+						_ ...grpc.CallOption,
+					) (*workflowservice.RespondWorkflowTaskCompletedResponse, error) {
+						require.Len(t, req.Commands, 1)
+						require.Equal(t, enumspb.COMMAND_TYPE_START_TIMER, req.Commands[0].CommandType)
+						return &workflowservice.RespondWorkflowTaskCompletedResponse{}, nil
+					})
+			}
+			params.shadowReplayer = newWorkflowShadowReplayer(params, reg, client)
+			taskHandler := newWorkflowTaskHandler(params, nil, reg)
+			poller := newWorkflowTaskProcessor(taskHandler, taskHandler, client, params, uuid.NewString())
+
+			pollResp := workflowservice.PollWorkflowTaskQueueResponse{
+				Attempt:           1,
+				TaskToken:         []byte("token"),
+				WorkflowExecution: &commonpb.WorkflowExecution{WorkflowId: t.Name() + "-workflow-id", RunId: t.Name() + "-run-id"},
+				WorkflowType:      &wfType,
+				History:           &history,
+				StartedEventId:    3,
+			}
+			require.NoError(t, poller.processWorkflowTask(&workflowTask{task: &pollResp}))
+			require.Equal(t, 2, runs)
+
+			var mismatches int64
+			for _, counter := range metricsHandler.Counters() {
+				if counter.Name == metrics.WorkflowTaskShadowReplayMismatchCounter {
+					mismatches += counter.Value()
+				}
+			}
+			if tc.expectMismatch {
+				require.EqualValues(t, 1, mismatches)
+			} else {
+				require.Zero(t, mismatches)
+			}
+		})
+	}
+}
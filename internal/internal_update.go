@@ -302,6 +302,7 @@ func defaultUpdateHandler(
 			}
 		}
 		callbacks.Accept()
+		eo.acceptedUpdateIDs[id] = struct{}{}
 		success, err := envInterceptor.inboundInterceptor.ExecuteUpdate(ctx, &input)
 		callbacks.Complete(success, err)
 	}
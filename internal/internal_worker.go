@@ -10,10 +10,12 @@ import (
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"io"
+	"maps"
 	"math"
 	"os"
 	"reflect"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,6 +26,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/nexus-rpc/sdk-go/nexus"
+	commandpb "go.temporal.io/api/command/v1"
 	commonpb "go.temporal.io/api/common/v1"
 	deploymentpb "go.temporal.io/api/deployment/v1"
 	enumspb "go.temporal.io/api/enums/v1"
@@ -87,6 +90,8 @@ type (
 		stopC               chan struct{}
 		localActivityStopC  chan struct{}
 		stickyUUID          string // Used for ShutdownWorker call
+		// taskHandler loads the workflows of executionParameters.StickyCachePrewarm on start, nil if not supported
+		taskHandler *workflowTaskHandlerImpl
 	}
 
 	// ActivityWorker wraps the code for hosting activity types.
@@ -139,6 +144,11 @@ type (
 		// a default option.
 		Identity string
 
+		// Identities of the pollers of each task type, Identity if empty.
+		WorkflowIdentity string
+		ActivityIdentity string
+		NexusIdentity    string
+
 		// The worker's build ID used for versioning, if one was set.
 		//
 		// Deprecated: use DeploymentOptions.Version for versioning instead.
@@ -159,6 +169,9 @@ type (
 		// Enable logging in replay mode
 		EnableLoggingInReplay bool
 
+		// Log the commands of each completed workflow task
+		EnableWorkflowCommandLogging bool
+
 		// Context to store user provided key/value pairs
 		BackgroundContext context.Context
 
@@ -167,6 +180,8 @@ type (
 
 		StickyScheduleToStartTimeout time.Duration
 
+		StickyCachePrewarm StickyCachePrewarmOptions
+
 		// WorkflowPanicPolicy is used for configuring how client's workflow task handler deals with workflow
 		// code panicking which includes non backwards compatible changes to the workflow code without appropriate
 		// versioning (see workflow.GetVersion).
@@ -187,6 +202,10 @@ type (
 		// the worker.
 		WorkerFatalErrorCallback func(error)
 
+		// WorkflowTaskFatalError returns the fatal error the worker is stopping with if in-flight workflow tasks
+		// should be failed because of it. Nil if WorkerOptions.FailWorkflowTasksOnFatalError is not set.
+		WorkflowTaskFatalError func() error
+
 		// SessionResourceID is a unique identifier of the resource the session will consume
 		SessionResourceID string
 
@@ -195,10 +214,34 @@ type (
 		// DeadlockDetectionTimeout specifies workflow task timeout.
 		DeadlockDetectionTimeout time.Duration
 
+		// WorkflowTaskForcedHeartbeatThreshold is the fraction of the workflow task timeout after which a workflow
+		// task waiting on local activities is heartbeated. Zero means the default.
+		WorkflowTaskForcedHeartbeatThreshold float64
+
+		// SlowReplayThreshold and OnSlowReplay report workflow tasks whose replay takes too long. Zero disables.
+		SlowReplayThreshold time.Duration
+		OnSlowReplay        func(SlowReplayInfo)
+
+		// ShadowReplaySampleRate and FailWorkflowTaskOnShadowReplayMismatch configure shadow replay of completed
+		// workflow tasks, see workflowShadowReplayer. Zero rate disables.
+		ShadowReplaySampleRate                 float64
+		FailWorkflowTaskOnShadowReplayMismatch bool
+		shadowReplayer                         *workflowShadowReplayer
+
+		// ReportContinueAsNew and LogContinueAsNew report the workflows that continue-as-new.
+		ReportContinueAsNew bool
+		LogContinueAsNew    bool
+
 		DefaultHeartbeatThrottleInterval time.Duration
 
 		MaxHeartbeatThrottleInterval time.Duration
 
+		// OnActivityPanic is called with the recovered value and stack of any panicking activity.
+		OnActivityPanic func(ctx context.Context, info ActivityInfo, recovered any, stack string)
+
+		// OnWorkflowPanic is called with the recovered value and stack of any panicking workflow.
+		OnWorkflowPanic func(info WorkflowInfo, recovered any, stack string)
+
 		// WorkflowTaskPollerBehavior defines the behavior of the workflow task poller.
 		WorkflowTaskPollerBehavior PollerBehavior
 
@@ -218,6 +261,10 @@ type (
 		pollTimeTracker *pollTimeTracker
 
 		workerInstanceKey string
+
+		// Run the last workflow task of a replayed history that ends with a started workflow task instead of
+		// skipping it, so its commands can be captured.
+		replayLastWorkflowTask bool
 	}
 
 	// HistoryJSONOptions are options for HistoryFromJSON.
@@ -264,6 +311,9 @@ func ensureRequiredParams(params *workerExecutionParameters) {
 	if params.FailureConverter == nil {
 		params.FailureConverter = GetDefaultFailureConverter()
 	}
+	if params.WorkflowTaskForcedHeartbeatThreshold == 0 {
+		params.WorkflowTaskForcedHeartbeatThreshold = ratioToForceCompleteWorkflowTaskComplete
+	}
 	if params.Tuner == nil {
 		// Err cannot happen since these slot numbers are guaranteed valid
 		params.Tuner, _ = NewFixedSizeTuner(
@@ -293,6 +343,14 @@ func (params *workerExecutionParameters) isInternalWorker() bool {
 	return params.Namespace == "temporal-system" || params.TaskQueue == "temporal-sys-per-ns-tq"
 }
 
+// withIdentity returns a copy of params with identity as Identity if it is set, e.g. for the pollers of one task type.
+func (params workerExecutionParameters) withIdentity(identity string) workerExecutionParameters {
+	if identity != "" {
+		params.Identity = identity
+	}
+	return params
+}
+
 func newWorkflowWorkerInternal(client *WorkflowClient, params workerExecutionParameters, ppMgr pressurePointMgr, overrides *workerOverrides, registry *registry) *workflowWorker {
 	workerStopChannel := make(chan struct{})
 	params.WorkerStopChannel = getReadOnlyChannel(workerStopChannel)
@@ -304,6 +362,9 @@ func newWorkflowWorkerInternal(client *WorkflowClient, params workerExecutionPar
 	} else {
 		taskHandler = newWorkflowTaskHandler(params, ppMgr, registry)
 	}
+	if params.ShadowReplaySampleRate > 0 {
+		params.shadowReplayer = newWorkflowShadowReplayer(params, registry, client.workflowService)
+	}
 	return newWorkflowTaskWorkerInternal(taskHandler, taskHandler, client, params, workerStopChannel, registry.interceptors)
 }
 
@@ -371,7 +432,8 @@ func newWorkflowTaskWorkerInternal(
 	laTunnel := newLocalActivityTunnel(getReadOnlyChannel(laStopChannel))
 
 	// 1) workflow handler will send local activity task to laTunnel
-	if handlerImpl, ok := taskHandler.(*workflowTaskHandlerImpl); ok {
+	handlerImpl, ok := taskHandler.(*workflowTaskHandlerImpl)
+	if ok {
 		handlerImpl.laTunnel = laTunnel
 	}
 
@@ -413,6 +475,7 @@ func newWorkflowTaskWorkerInternal(
 		stopC:               stopC,
 		localActivityStopC:  laStopChannel,
 		stickyUUID:          stickyUUID,
+		taskHandler:         handlerImpl,
 	}
 }
 
@@ -420,6 +483,10 @@ func newWorkflowTaskWorkerInternal(
 func (ww *workflowWorker) Start() error {
 	ww.localActivityWorker.Start()
 	ww.worker.Start()
+	if ww.taskHandler != nil && ww.workflowService != nil && ww.taskHandler.cache.MaxWorkflowCacheSize() > 0 &&
+		(len(ww.executionParameters.StickyCachePrewarm.WorkflowIDs) > 0 || ww.executionParameters.StickyCachePrewarm.Query != "") {
+		go ww.prewarmStickyCache()
+	}
 	return nil // TODO: propagate error
 }
 
@@ -577,8 +644,16 @@ type registry struct {
 	workflowFuncMap               map[string]interface{}
 	workflowAliasMap              map[string]string
 	workflowVersioningBehaviorMap map[string]VersioningBehavior
+	workflowCompletionHookMap     map[string]*WorkflowCompletionHook
+	workflowPanicPolicyMap        map[string]WorkflowPanicPolicy
+	workflowStaticConfigMap       map[string]map[string]string
+	workflowInputValidatorMap     map[string]func(args []interface{}) error
+	workflowDeadlockTimeoutMap    map[string]time.Duration
+	workflowPrimaryNameMap        map[string]string
 	activityFuncMap               map[string]activity
 	activityAliasMap              map[string]string
+	activityPrimaryNameMap        map[string]string
+	activityDefaultOptionsMap     map[string]*ActivityDefaultOptions
 	dynamicWorkflow               interface{}
 	dynamicWorkflowOptions        DynamicRegisterWorkflowOptions
 	dynamicActivity               activity
@@ -607,10 +682,28 @@ func (r *registry) RegisterWorkflowWithOptions(
 		if strings.HasPrefix(options.Name, temporalPrefix) {
 			panic(temporalPrefixError)
 		}
+		if options.OnCompletion != nil {
+			panic("OnCompletion is not supported when registering a WorkflowDefinitionFactory")
+		}
+		if options.StaticConfig != nil {
+			panic("StaticConfig is not supported when registering a WorkflowDefinitionFactory")
+		}
+		if options.InputValidator != nil {
+			panic("InputValidator is not supported when registering a WorkflowDefinitionFactory")
+		}
+		validateRegistrationAliases(options.Name, options.Aliases)
+		validateWorkflowPanicPolicy(options.PanicPolicy)
+		validateWorkflowDeadlockDetectionTimeout(options.DeadlockDetectionTimeout)
 		r.Lock()
 		defer r.Unlock()
+		if !options.DisableAlreadyRegisteredCheck {
+			r.checkWorkflowNamesNoLock(options.Aliases)
+		}
 		r.workflowFuncMap[options.Name] = factory
 		r.workflowVersioningBehaviorMap[options.Name] = options.VersioningBehavior
+		r.setWorkflowPanicPolicyNoLock(options.Name, options.PanicPolicy)
+		r.workflowDeadlockTimeoutMap[options.Name] = options.DeadlockDetectionTimeout
+		r.registerWorkflowAliasesNoLock(options.Name, factory, options)
 		return
 	}
 	// Validate that it is a function
@@ -628,23 +721,95 @@ func (r *registry) RegisterWorkflowWithOptions(
 	if strings.HasPrefix(alias, temporalPrefix) || strings.HasPrefix(registerName, temporalPrefix) {
 		panic(temporalPrefixError)
 	}
+	validateRegistrationAliases(registerName, options.Aliases)
+	if options.OnCompletion != nil && options.OnCompletion.Activity == nil {
+		panic("OnCompletion requires an activity")
+	}
+	validateWorkflowPanicPolicy(options.PanicPolicy)
+	validateWorkflowDeadlockDetectionTimeout(options.DeadlockDetectionTimeout)
 
 	r.Lock()
 	defer r.Unlock()
 
 	if !options.DisableAlreadyRegisteredCheck {
-		if _, ok := r.workflowFuncMap[registerName]; ok {
-			panic(fmt.Sprintf("workflow name \"%v\" is already registered", registerName))
-		}
+		r.checkWorkflowNamesNoLock(append([]string{registerName}, options.Aliases...))
 	}
 	r.workflowFuncMap[registerName] = wf
 	r.workflowVersioningBehaviorMap[registerName] = options.VersioningBehavior
+	r.workflowCompletionHookMap[registerName] = options.OnCompletion
+	r.setWorkflowPanicPolicyNoLock(registerName, options.PanicPolicy)
+	r.workflowStaticConfigMap[registerName] = maps.Clone(options.StaticConfig)
+	r.workflowInputValidatorMap[registerName] = newWorkflowInputValidator(options)
+	r.workflowDeadlockTimeoutMap[registerName] = options.DeadlockDetectionTimeout
+	r.registerWorkflowAliasesNoLock(registerName, wf, options)
 
 	if len(alias) > 0 && r.workflowAliasMap != nil {
 		r.workflowAliasMap[fnName] = alias
 	}
 }
 
+// checkWorkflowNamesNoLock panics if any of names is already registered, so a registration is checked before any of
+// its names is written.
+func (r *registry) checkWorkflowNamesNoLock(names []string) {
+	for _, name := range names {
+		if _, ok := r.workflowFuncMap[name]; ok {
+			panic(fmt.Sprintf("workflow name \"%v\" is already registered", name))
+		}
+	}
+}
+
+func (r *registry) registerWorkflowAliasesNoLock(registerName string, wf interface{}, options RegisterWorkflowOptions) {
+	for _, alias := range options.Aliases {
+		r.workflowPrimaryNameMap[alias] = registerName
+		r.workflowFuncMap[alias] = wf
+		r.workflowVersioningBehaviorMap[alias] = options.VersioningBehavior
+		r.workflowCompletionHookMap[alias] = options.OnCompletion
+		r.setWorkflowPanicPolicyNoLock(alias, options.PanicPolicy)
+		r.workflowStaticConfigMap[alias] = maps.Clone(options.StaticConfig)
+		r.workflowInputValidatorMap[alias] = newWorkflowInputValidator(options)
+		r.workflowDeadlockTimeoutMap[alias] = options.DeadlockDetectionTimeout
+	}
+}
+
+// newWorkflowInputValidator returns a function running the InputValidator of options that fails the workflow with an
+// InputValidationError, or nil if there is no InputValidator.
+func newWorkflowInputValidator(options RegisterWorkflowOptions) func(args []interface{}) error {
+	if options.InputValidator == nil {
+		return nil
+	}
+	return func(args []interface{}) error {
+		err := options.InputValidator(args)
+		if err == nil {
+			return nil
+		}
+		return NewApplicationErrorWithOptions(
+			fmt.Sprintf("workflow input validation failed: %v", err),
+			workflowInputValidationErrorType,
+			ApplicationErrorOptions{NonRetryable: !options.InputValidationRetryable, Cause: err},
+		)
+	}
+}
+
+func validateWorkflowPanicPolicy(policy *WorkflowPanicPolicy) {
+	if policy != nil && *policy != BlockWorkflow && *policy != FailWorkflow {
+		panic(fmt.Sprintf("unknown workflow panic policy %v", *policy))
+	}
+}
+
+func validateWorkflowDeadlockDetectionTimeout(timeout time.Duration) {
+	if timeout < 0 {
+		panic(fmt.Sprintf("workflow deadlock detection timeout must be positive, got %v", timeout))
+	}
+}
+
+func (r *registry) setWorkflowPanicPolicyNoLock(workflowType string, policy *WorkflowPanicPolicy) {
+	if policy != nil {
+		r.workflowPanicPolicyMap[workflowType] = *policy
+	} else {
+		delete(r.workflowPanicPolicyMap, workflowType)
+	}
+}
+
 func (r *registry) RegisterDynamicWorkflow(wf interface{}, options DynamicRegisterWorkflowOptions) {
 	r.Lock()
 	defer r.Unlock()
@@ -682,15 +847,29 @@ func (r *registry) RegisterActivityWithOptions(
 		if options.Name == "" {
 			panic("registration of activity interface requires name")
 		}
-		if strings.HasPrefix(options.Name, temporalPrefix) {
+		registerName := options.Prefix + options.Name
+		if strings.HasPrefix(registerName, temporalPrefix) {
 			panic(temporalPrefixError)
 		}
-		r.addActivityWithLock(options.Name, a)
+		validateRegistrationAliases(registerName, options.Aliases)
+		validateActivityDefaultOptions(options.DefaultOptions)
+		r.Lock()
+		defer r.Unlock()
+		if !options.DisableAlreadyRegisteredCheck {
+			r.checkActivityNamesNoLock(options.Aliases)
+		}
+		r.activityFuncMap[registerName] = a
+		r.registerActivityAliasesNoLock(registerName, a, options)
+		r.setActivityDefaultOptionsNoLock(registerName, options)
 		return
 	}
 	// Validate that it is a function
 	fnType := reflect.TypeOf(af)
+	validateActivityDefaultOptions(options.DefaultOptions)
 	if fnType.Kind() == reflect.Ptr && fnType.Elem().Kind() == reflect.Struct {
+		if len(options.Aliases) > 0 {
+			panic("aliases are not supported when registering an activity struct")
+		}
 		registerErr := r.registerActivityStructWithOptions(af, options)
 		if registerErr != nil {
 			panic(registerErr)
@@ -706,22 +885,54 @@ func (r *registry) RegisterActivityWithOptions(
 	if len(alias) > 0 {
 		registerName = alias
 	}
+	registerName = options.Prefix + registerName
 
 	if strings.HasPrefix(alias, temporalPrefix) || strings.HasPrefix(registerName, temporalPrefix) {
 		panic(temporalPrefixError)
 	}
+	validateRegistrationAliases(registerName, options.Aliases)
 
 	r.Lock()
 	defer r.Unlock()
 
 	if !options.DisableAlreadyRegisteredCheck {
-		if _, ok := r.activityFuncMap[registerName]; ok {
-			panic(fmt.Sprintf("activity type \"%v\" is already registered", registerName))
+		r.checkActivityNamesNoLock(append([]string{registerName}, options.Aliases...))
+	}
+	executor := &activityExecutor{name: registerName, fn: af}
+	r.activityFuncMap[registerName] = executor
+	r.registerActivityAliasesNoLock(registerName, executor, options)
+	r.setActivityDefaultOptionsNoLock(registerName, options)
+	if registerName != fnName && r.activityAliasMap != nil {
+		r.activityAliasMap[fnName] = registerName
+	}
+}
+
+// checkActivityNamesNoLock panics if any of names is already registered, so a registration is checked before any of
+// its names is written.
+func (r *registry) checkActivityNamesNoLock(names []string) {
+	for _, name := range names {
+		if _, ok := r.activityFuncMap[name]; ok {
+			panic(fmt.Sprintf("activity type \"%v\" is already registered", name))
 		}
 	}
-	r.activityFuncMap[registerName] = &activityExecutor{name: registerName, fn: af}
-	if len(alias) > 0 && r.activityAliasMap != nil {
-		r.activityAliasMap[fnName] = alias
+}
+
+func (r *registry) registerActivityAliasesNoLock(registerName string, a activity, options RegisterActivityOptions) {
+	for _, alias := range options.Aliases {
+		r.activityPrimaryNameMap[alias] = registerName
+		r.activityFuncMap[alias] = a
+	}
+}
+
+// setActivityDefaultOptionsNoLock sets the default options of the activity registered as registerName and under its
+// aliases. Registering an activity again without default options removes them.
+func (r *registry) setActivityDefaultOptionsNoLock(registerName string, options RegisterActivityOptions) {
+	for _, name := range append([]string{registerName}, options.Aliases...) {
+		if options.DefaultOptions != nil {
+			r.activityDefaultOptionsMap[name] = options.DefaultOptions
+		} else {
+			delete(r.activityDefaultOptionsMap, name)
+		}
 	}
 }
 
@@ -740,6 +951,9 @@ func (r *registry) registerActivityStructWithOptions(aStruct interface{}, option
 			continue
 		}
 		name := method.Name
+		if slices.Contains(options.ExcludeMethods, name) {
+			continue
+		}
 		if err := validateFnFormat(method.Type, false, false); err != nil {
 			if options.SkipInvalidStructFunctions {
 				continue
@@ -747,13 +961,14 @@ func (r *registry) registerActivityStructWithOptions(aStruct interface{}, option
 
 			return fmt.Errorf("method %s of %s: %w", name, structType.Name(), err)
 		}
-		registerName := options.Name + name
+		registerName := options.Prefix + options.Name + name
 		if !options.DisableAlreadyRegisteredCheck {
 			if _, ok := r.getActivityNoLock(registerName); ok {
 				return fmt.Errorf("activity type \"%v\" is already registered", registerName)
 			}
 		}
 		r.activityFuncMap[registerName] = &activityExecutor{name: registerName, fn: methodValue.Interface()}
+		r.setActivityDefaultOptionsNoLock(registerName, RegisterActivityOptions{DefaultOptions: options.DefaultOptions})
 		count++
 	}
 	if count == 0 {
@@ -796,6 +1011,35 @@ func (r *registry) RegisterNexusService(service *nexus.Service) {
 	r.nexusServices[service.Name] = service
 }
 
+// validateActivityDefaultOptions panics if any of the default timeouts of an activity is negative.
+func validateActivityDefaultOptions(options *ActivityDefaultOptions) {
+	if options == nil {
+		return
+	}
+	if options.ScheduleToCloseTimeout < 0 || options.ScheduleToStartTimeout < 0 ||
+		options.StartToCloseTimeout < 0 || options.HeartbeatTimeout < 0 {
+		panic("default activity timeouts must not be negative")
+	}
+}
+
+// validateRegistrationAliases panics if any of the additional names a workflow or activity is registered under is
+// empty, reserved, the same as the primary name or repeated.
+func validateRegistrationAliases(registerName string, aliases []string) {
+	seen := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		if alias == "" {
+			panic("registration alias must not be empty")
+		}
+		if strings.HasPrefix(alias, temporalPrefix) {
+			panic(temporalPrefixError)
+		}
+		if alias == registerName || seen[alias] {
+			panic(fmt.Sprintf("registration alias \"%v\" is duplicated", alias))
+		}
+		seen[alias] = true
+	}
+}
+
 func (r *registry) getWorkflowAlias(fnName string) (string, bool) {
 	r.Lock()
 	defer r.Unlock()
@@ -833,6 +1077,35 @@ func (r *registry) getActivityAlias(fnName string) (string, bool) {
 	return alias, ok
 }
 
+// getWorkflowPrimaryName returns the name the workflow type registered under the alias name was registered with, or
+// name itself if it is not an alias.
+func (r *registry) getWorkflowPrimaryName(name string) string {
+	r.Lock()
+	defer r.Unlock()
+	if primary, ok := r.workflowPrimaryNameMap[name]; ok {
+		return primary
+	}
+	return name
+}
+
+// getActivityPrimaryName returns the name the activity type registered under the alias name was registered with, or
+// name itself if it is not an alias.
+func (r *registry) getActivityPrimaryName(name string) string {
+	r.Lock()
+	defer r.Unlock()
+	if primary, ok := r.activityPrimaryNameMap[name]; ok {
+		return primary
+	}
+	return name
+}
+
+func (r *registry) getActivityDefaultOptions(activityType string) (*ActivityDefaultOptions, bool) {
+	r.Lock()
+	defer r.Unlock()
+	options, ok := r.activityDefaultOptionsMap[activityType]
+	return options, ok
+}
+
 func (r *registry) addActivityWithLock(fnName string, a activity) {
 	r.Lock()
 	defer r.Unlock()
@@ -902,10 +1175,67 @@ func (r *registry) getWorkflowDefinition(wt WorkflowType) (WorkflowDefinition, e
 		wf = r.dynamicWorkflow
 		dynamic = true
 	}
-	executor := &workflowExecutor{workflowType: lookup, fn: wf, interceptors: r.interceptors, dynamic: dynamic}
+	executor := &workflowExecutor{
+		workflowType:   lookup,
+		fn:             wf,
+		interceptors:   r.interceptors,
+		dynamic:        dynamic,
+		completionHook: r.getWorkflowCompletionHook(lookup),
+		staticConfig:   r.getWorkflowStaticConfig(lookup),
+		inputValidator: r.getWorkflowInputValidator(lookup),
+	}
 	return newSyncWorkflowDefinition(executor), nil
 }
 
+func (r *registry) getWorkflowCompletionHook(workflowType string) *WorkflowCompletionHook {
+	r.Lock()
+	defer r.Unlock()
+	return r.workflowCompletionHookMap[workflowType]
+}
+
+func (r *registry) getWorkflowStaticConfig(workflowType string) map[string]string {
+	r.Lock()
+	defer r.Unlock()
+	return r.workflowStaticConfigMap[workflowType]
+}
+
+func (r *registry) getWorkflowInputValidator(workflowType string) func(args []interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+	return r.workflowInputValidatorMap[workflowType]
+}
+
+// getWorkflowPanicPolicy returns the panic policy the workflow type was registered with, if any.
+func (r *registry) getWorkflowPanicPolicy(wt WorkflowType) (WorkflowPanicPolicy, bool) {
+	lookup := wt.Name
+	if alias, ok := r.getWorkflowAlias(lookup); ok {
+		lookup = alias
+	}
+	r.Lock()
+	defer r.Unlock()
+	policy, ok := r.workflowPanicPolicyMap[lookup]
+	return policy, ok
+}
+
+// getWorkflowDeadlockDetectionTimeout returns the deadlock detection timeout for workflow tasks of the workflow type:
+// the timeout the type was registered with if any, or the timeout of the worker otherwise. The timeout of the worker
+// is kept if deadlock detection is disabled.
+func (r *registry) getWorkflowDeadlockDetectionTimeout(wt WorkflowType, workerTimeout time.Duration) time.Duration {
+	if debugMode || workerTimeout == unlimitedDeadlockDetectionTimeout {
+		return workerTimeout
+	}
+	lookup := wt.Name
+	if alias, ok := r.getWorkflowAlias(lookup); ok {
+		lookup = alias
+	}
+	r.Lock()
+	defer r.Unlock()
+	if timeout := r.workflowDeadlockTimeoutMap[lookup]; timeout > 0 {
+		return timeout
+	}
+	return workerTimeout
+}
+
 func (r *registry) getWorkflowVersioningBehavior(wt WorkflowType) (VersioningBehavior, bool) {
 	lookup := wt.Name
 	if alias, ok := r.getWorkflowAlias(lookup); ok {
@@ -1006,7 +1336,15 @@ func newRegistryWithOptions(options registryOptions) *registry {
 	r := &registry{
 		workflowFuncMap:               make(map[string]interface{}),
 		workflowVersioningBehaviorMap: make(map[string]VersioningBehavior),
+		workflowCompletionHookMap:     make(map[string]*WorkflowCompletionHook),
+		workflowPanicPolicyMap:        make(map[string]WorkflowPanicPolicy),
+		workflowStaticConfigMap:       make(map[string]map[string]string),
+		workflowInputValidatorMap:     make(map[string]func(args []interface{}) error),
+		workflowDeadlockTimeoutMap:    make(map[string]time.Duration),
+		workflowPrimaryNameMap:        make(map[string]string),
 		activityFuncMap:               make(map[string]activity),
+		activityPrimaryNameMap:        make(map[string]string),
+		activityDefaultOptionsMap:     make(map[string]*ActivityDefaultOptions),
 		nexusServices:                 make(map[string]*nexus.Service),
 	}
 	if !options.disableAliasing {
@@ -1018,10 +1356,13 @@ func newRegistryWithOptions(options registryOptions) *registry {
 
 // Wrapper to execute workflow functions.
 type workflowExecutor struct {
-	workflowType string
-	fn           interface{}
-	interceptors []WorkerInterceptor
-	dynamic      bool
+	workflowType   string
+	fn             interface{}
+	interceptors   []WorkerInterceptor
+	dynamic        bool
+	completionHook *WorkflowCompletionHook
+	staticConfig   map[string]string
+	inputValidator func(args []interface{}) error
 }
 
 func (we *workflowExecutor) Execute(ctx Context, input *commonpb.Payloads) (*commonpb.Payloads, error) {
@@ -1044,9 +1385,20 @@ func (we *workflowExecutor) Execute(ctx Context, input *commonpb.Payloads) (*com
 
 	envInterceptor := getWorkflowEnvironmentInterceptor(ctx)
 	envInterceptor.fn = we.fn
+	envInterceptor.staticConfig = we.staticConfig
 
-	// Execute and serialize result
-	result, err := envInterceptor.inboundInterceptor.ExecuteWorkflow(ctx, &ExecuteWorkflowInput{Args: args})
+	if we.inputValidator != nil {
+		err = we.inputValidator(args)
+	}
+
+	// Execute and serialize result, the completion hook also runs for workflows failed by the input validator
+	var result interface{}
+	if err == nil {
+		result, err = envInterceptor.inboundInterceptor.ExecuteWorkflow(ctx, &ExecuteWorkflowInput{Args: args})
+	}
+	if we.completionHook != nil {
+		err = we.runCompletionHook(ctx, result, err)
+	}
 	var serializedResult *commonpb.Payloads
 	if err == nil && result != nil {
 		serializedResult, err = encodeArg(dataConverter, result)
@@ -1054,6 +1406,42 @@ func (we *workflowExecutor) Execute(ctx Context, input *commonpb.Payloads) (*com
 	return serializedResult, err
 }
 
+// runCompletionHook executes the OnCompletion activity of the workflow and returns the error the workflow should
+// complete with.
+func (we *workflowExecutor) runCompletionHook(ctx Context, result interface{}, err error) error {
+	completion := WorkflowCompletion{Outcome: WorkflowCompletionOutcomeCompleted}
+	if err != nil {
+		var continueAsNewErr *ContinueAsNewError
+		if errors.As(err, &continueAsNewErr) {
+			completion.Outcome = WorkflowCompletionOutcomeContinuedAsNew
+		} else if IsCanceledError(err) && ctx.Err() != nil {
+			completion.Outcome = WorkflowCompletionOutcomeCanceled
+		} else {
+			completion.Outcome = WorkflowCompletionOutcomeFailed
+		}
+		completion.Error = err.Error()
+		result = nil
+	}
+
+	// Disconnected so the hook also runs for canceled workflows
+	hookCtx, _ := NewDisconnectedContext(ctx)
+	var future Future
+	if we.completionHook.Local {
+		hookCtx = WithLocalActivityOptions(hookCtx, we.completionHook.LocalActivityOptions)
+		future = ExecuteLocalActivity(hookCtx, we.completionHook.Activity, completion, result)
+	} else {
+		hookCtx = WithActivityOptions(hookCtx, we.completionHook.ActivityOptions)
+		future = ExecuteActivity(hookCtx, we.completionHook.Activity, completion, result)
+	}
+	if hookErr := future.Get(hookCtx, nil); hookErr != nil {
+		if we.completionHook.FailurePolicy == WorkflowCompletionHookFailurePolicyFailWorkflow {
+			return hookErr
+		}
+		GetLogger(ctx).Warn("Workflow completion hook failed", tagError, hookErr)
+	}
+	return err
+}
+
 // Wrapper to execute activity functions.
 type activityExecutor struct {
 	name             string
@@ -1169,9 +1557,12 @@ type AggregatedWorker struct {
 	workerInstanceKey     string
 	plugins               []WorkerPlugin
 	pluginRegistryOptions *WorkerPluginConfigureWorkerRegistryOptions // Never nil
+	lifecycleInterceptor  WorkerLifecycleInterceptor
 
 	heartbeatMetrics  *heartbeatMetricsHandler
 	heartbeatCallback func() *workerpb.WorkerHeartbeat
+
+	effectiveOptions WorkerEffectiveOptions
 }
 
 // RegisterWorkflow registers workflow implementation with the AggregatedWorker
@@ -1262,7 +1653,11 @@ func (aw *AggregatedWorker) RegisterNexusService(service *nexus.Service) {
 // The actual work is done in the memoized "start" function to ensure duplicate calls are returned a consistent error.
 func (aw *AggregatedWorker) Start() error {
 	aw.assertNotStopped()
-	return aw.memoizedStart()
+	if err := aw.memoizedStart(); err != nil {
+		return err
+	}
+	addActiveWorker(aw)
+	return nil
 }
 
 // start the worker. This method is memoized using sync.OnceValue in memoizedStart.
@@ -1339,7 +1734,7 @@ func (aw *AggregatedWorker) start() error {
 			return fmt.Errorf("failed to create a nexus worker: %w", err)
 		}
 		aw.nexusWorker, err = newNexusWorker(nexusWorkerOptions{
-			executionParameters: aw.executionParams,
+			executionParameters: aw.executionParams.withIdentity(aw.executionParams.NexusIdentity),
 			client:              aw.client,
 			workflowService:     aw.client.workflowService,
 			handler:             handler,
@@ -1377,8 +1772,43 @@ func (aw *AggregatedWorker) assertNotStopped() {
 var (
 	binaryChecksum     string
 	binaryChecksumLock sync.Mutex
+
+	activeWorkers     = map[*AggregatedWorker]struct{}{}
+	activeWorkersLock sync.Mutex
 )
 
+// ActiveTaskQueues returns the sorted task queues of the workers in this process that have started and have not been
+// stopped yet. Workers that failed to start are not included.
+//
+// Exposed as: [go.temporal.io/sdk/worker.ActiveTaskQueues]
+func ActiveTaskQueues() []string {
+	activeWorkersLock.Lock()
+	defer activeWorkersLock.Unlock()
+	taskQueues := make([]string, 0, len(activeWorkers))
+	for aw := range activeWorkers {
+		taskQueues = append(taskQueues, aw.executionParams.TaskQueue)
+	}
+	slices.Sort(taskQueues)
+	return slices.Compact(taskQueues)
+}
+
+func addActiveWorker(aw *AggregatedWorker) {
+	activeWorkersLock.Lock()
+	defer activeWorkersLock.Unlock()
+	// The worker may have been stopped since it started
+	select {
+	case <-aw.stopC:
+	default:
+		activeWorkers[aw] = struct{}{}
+	}
+}
+
+func removeActiveWorker(aw *AggregatedWorker) {
+	activeWorkersLock.Lock()
+	defer activeWorkersLock.Unlock()
+	delete(activeWorkers, aw)
+}
+
 // SetBinaryChecksum sets the identifier of the binary(aka BinaryChecksum).
 // The identifier is mainly used in recording reset points when respondWorkflowTaskCompleted. For each workflow, the very first
 // workflow task completed by a binary will be associated as a auto-reset point for the binary. So that when a customer wants to
@@ -1436,6 +1866,32 @@ func (aw *AggregatedWorker) Run(interruptCh <-chan interface{}) error {
 	return nil
 }
 
+// RunWithContext runs the worker in a blocking fashion until ctx is done, then stops it gracefully, waiting up to
+// WorkerStopTimeout for in-flight tasks to complete.
+// Returns nil when stopped because ctx is done or by an external Stop() call, and an error if the worker fails to start
+// or there is a fatal error during execution.
+func (aw *AggregatedWorker) RunWithContext(ctx context.Context) error {
+	if err := aw.Start(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		aw.logger.Info("Worker has been stopped.", "Cause", context.Cause(ctx))
+		aw.Stop()
+	case <-aw.stopC:
+		aw.fatalErrLock.Lock()
+		defer aw.fatalErrLock.Unlock()
+		// This may be nil if this wasn't stopped due to fatal error
+		return aw.fatalErr
+	}
+	return nil
+}
+
+// EffectiveOptions returns the options the worker runs with once defaults are applied.
+func (aw *AggregatedWorker) EffectiveOptions() WorkerEffectiveOptions {
+	return aw.effectiveOptions
+}
+
 // Stop the worker.
 func (aw *AggregatedWorker) Stop() {
 	// Only attempt stop if we haven't attempted before
@@ -1445,26 +1901,13 @@ func (aw *AggregatedWorker) Stop() {
 	default:
 		close(aw.stopC)
 	}
+	removeActiveWorker(aw)
 
 	aw.shutdownWorker()
 
-	// Issue stop through plugins
-	stop := func(context.Context, WorkerPluginStopWorkerOptions) {
-		if !util.IsInterfaceNil(aw.workflowWorker) {
-			if aw.client.eagerDispatcher != nil {
-				aw.client.eagerDispatcher.deregisterWorker(aw.workflowWorker)
-			}
-			aw.workflowWorker.Stop()
-		}
-		if !util.IsInterfaceNil(aw.activityWorker) {
-			aw.activityWorker.Stop()
-		}
-		if !util.IsInterfaceNil(aw.sessionWorker) {
-			aw.sessionWorker.Stop()
-		}
-		if !util.IsInterfaceNil(aw.nexusWorker) {
-			aw.nexusWorker.Stop()
-		}
+	// Issue stop through plugins and then lifecycle interceptors
+	stop := func(ctx context.Context, _ WorkerPluginStopWorkerOptions) {
+		aw.lifecycleInterceptor.Stop(ctx, aw.lifecycleInfo())
 	}
 	for i := len(aw.plugins) - 1; i >= 0; i-- {
 		plugin := aw.plugins[i]
@@ -1482,6 +1925,55 @@ func (aw *AggregatedWorker) Stop() {
 	aw.logger.Info("Stopped Worker")
 }
 
+func (aw *AggregatedWorker) stopWorkers() {
+	if !util.IsInterfaceNil(aw.workflowWorker) {
+		if aw.client.eagerDispatcher != nil {
+			aw.client.eagerDispatcher.deregisterWorker(aw.workflowWorker)
+		}
+		aw.workflowWorker.Stop()
+	}
+	if !util.IsInterfaceNil(aw.activityWorker) {
+		aw.activityWorker.Stop()
+	}
+	if !util.IsInterfaceNil(aw.sessionWorker) {
+		aw.sessionWorker.Stop()
+	}
+	if !util.IsInterfaceNil(aw.nexusWorker) {
+		aw.nexusWorker.Stop()
+	}
+}
+
+func (aw *AggregatedWorker) lifecycleInfo() WorkerLifecycleInfo {
+	return WorkerLifecycleInfo{
+		Namespace:         aw.executionParams.Namespace,
+		TaskQueue:         aw.executionParams.TaskQueue,
+		Identity:          aw.executionParams.Identity,
+		WorkerInstanceKey: aw.workerInstanceKey,
+	}
+}
+
+// workerLifecycleInterceptorRoot is the last WorkerLifecycleInterceptor in the
+// chain, actually starting and stopping the worker.
+type workerLifecycleInterceptorRoot struct {
+	WorkerLifecycleInterceptorBase
+	aw      *AggregatedWorker
+	started bool
+}
+
+func (r *workerLifecycleInterceptorRoot) Start(context.Context, WorkerLifecycleInfo) error {
+	if err := r.aw.start(); err != nil {
+		return err
+	}
+	r.started = true
+	return nil
+}
+
+func (r *workerLifecycleInterceptorRoot) Stop(context.Context, WorkerLifecycleInfo) {
+	r.aw.stopWorkers()
+}
+
+func (r *workerLifecycleInterceptorRoot) OnFatalError(WorkerLifecycleInfo, error) {}
+
 func (aw *AggregatedWorker) registerHeartbeatWorker() error {
 	if aw.client.heartbeatManager == nil {
 		return nil
@@ -1668,7 +2160,7 @@ func (aw *WorkflowReplayer) ReplayWorkflowHistoryWithOptions(logger log.Logger,
 	controller := gomock.NewController(ilog.NewTestReporter(logger))
 	service := workflowservicemock.NewMockWorkflowServiceClient(controller)
 
-	return aw.replayWorkflowHistory(logger, service, ReplayNamespace, options.OriginalExecution, history)
+	return aw.replayWorkflowHistory(logger, service, ReplayNamespace, options.OriginalExecution, history, nil)
 }
 
 // ReplayWorkflowHistory executes a single workflow task for the given history.
@@ -1702,7 +2194,7 @@ func (aw *WorkflowReplayer) ReplayPartialWorkflowHistoryFromJSONFile(logger log.
 	controller := gomock.NewController(ilog.NewTestReporter(logger))
 	service := workflowservicemock.NewMockWorkflowServiceClient(controller)
 
-	return aw.replayWorkflowHistory(logger, service, ReplayNamespace, WorkflowExecution{}, history)
+	return aw.replayWorkflowHistory(logger, service, ReplayNamespace, WorkflowExecution{}, history, nil)
 }
 
 // ReplayWorkflowExecution replays workflow execution loading it from Temporal service.
@@ -1741,7 +2233,37 @@ func (aw *WorkflowReplayer) ReplayWorkflowExecution(ctx context.Context, service
 		}
 		request.NextPageToken = resp.NextPageToken
 	}
-	return aw.replayWorkflowHistory(logger, service, namespace, execution, &history)
+	return aw.replayWorkflowHistory(logger, service, namespace, execution, &history, nil)
+}
+
+// ReplayAndCaptureCommands executes a single workflow task for the given history and returns the commands the
+// current workflow code produces for the last workflow task of the history. Only histories of open workflows ending
+// with a started workflow task are accepted, since those are the only ones with a workflow task left to produce
+// commands for. Earlier workflow tasks are replayed and checked for determinism as usual. The commands are returned
+// as protos so they can be compared with proto.Equal or serialized with protojson for golden file tests.
+// The logger is an optional parameter. Defaults to the noop logger.
+func (aw *WorkflowReplayer) ReplayAndCaptureCommands(logger log.Logger, history *historypb.History, options ReplayWorkflowHistoryOptions) ([]*commandpb.Command, error) {
+	events := history.GetEvents()
+	if len(events) == 0 {
+		return nil, errors.New("empty events")
+	}
+	if lastEventType := events[len(events)-1].GetEventType(); lastEventType != enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED {
+		return nil, fmt.Errorf("history must end with a started workflow task, but ends with %v", lastEventType)
+	}
+
+	if logger == nil {
+		logger = ilog.NewDefaultLogger()
+	}
+
+	controller := gomock.NewController(ilog.NewTestReporter(logger))
+	service := workflowservicemock.NewMockWorkflowServiceClient(controller)
+
+	commands := []*commandpb.Command{}
+	err := aw.replayWorkflowHistory(logger, service, ReplayNamespace, options.OriginalExecution, history, &commands)
+	if err != nil {
+		return nil, err
+	}
+	return commands, nil
 }
 
 // GetWorkflowResult get the result of a succesfully replayed workflow.
@@ -1768,6 +2290,7 @@ func (aw *WorkflowReplayer) replayWorkflowHistory(
 	namespace string,
 	originalExecution WorkflowExecution,
 	history *historypb.History,
+	capturedCommands *[]*commandpb.Command,
 ) error {
 	replay := func(ctx context.Context, options WorkerPluginReplayWorkflowOptions) error {
 		return aw.replayWorkflowHistoryRoot(
@@ -1776,6 +2299,7 @@ func (aw *WorkflowReplayer) replayWorkflowHistory(
 			options.Namespace,
 			options.OriginalExecution,
 			options.History,
+			capturedCommands,
 		)
 	}
 	for i := len(aw.plugins) - 1; i >= 0; i-- {
@@ -1802,6 +2326,7 @@ func (aw *WorkflowReplayer) replayWorkflowHistoryRoot(
 	namespace string,
 	originalExecution WorkflowExecution,
 	history *historypb.History,
+	capturedCommands *[]*commandpb.Command,
 ) error {
 	taskQueue := "ReplayTaskQueue"
 	events := history.Events
@@ -1847,6 +2372,10 @@ func (aw *WorkflowReplayer) replayWorkflowHistoryRoot(
 		History:                history,
 		PreviousStartedEventId: math.MaxInt64,
 	}
+	if capturedCommands != nil {
+		// Treat the last workflow task as new, like the server does for a worker polling it
+		task.PreviousStartedEventId = previousStartedEventID(events)
+	}
 
 	iterator := &historyIteratorImpl{
 		nextPageToken: task.NextPageToken,
@@ -1883,6 +2412,7 @@ func (aw *WorkflowReplayer) replayWorkflowHistoryRoot(
 	if aw.disableDeadlockDetection {
 		params.DeadlockDetectionTimeout = math.MaxInt64
 	}
+	params.replayLastWorkflowTask = capturedCommands != nil
 	taskHandler := newWorkflowTaskHandler(params, nil, aw.registry)
 	wfctx, err := taskHandler.GetOrCreateWorkflowContext(task, iterator)
 	defer wfctx.Unlock(err)
@@ -1899,6 +2429,11 @@ func (aw *WorkflowReplayer) replayWorkflowHistoryRoot(
 			return fmt.Errorf("replay workflow failed with failure: %v", failedReq.GetFailure())
 		}
 	}
+	if capturedCommands != nil && resp != nil {
+		if completedReq, ok := resp.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest); ok {
+			*capturedCommands = completedReq.GetCommands()
+		}
+	}
 
 	if last.GetEventType() != enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED && last.GetEventType() != enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CONTINUED_AS_NEW {
 		return nil
@@ -1929,6 +2464,21 @@ func (aw *WorkflowReplayer) replayWorkflowHistoryRoot(
 	return fmt.Errorf("replay workflow doesn't return the same result as the last event, resp: %[1]T{%[1]v}, last: %[2]T{%[2]v}", rawRequest, last)
 }
 
+// previousStartedEventID returns the ID of the started event of the last completed workflow task in the given events,
+// or 0 if no workflow task was completed.
+func previousStartedEventID(events []*historypb.HistoryEvent) int64 {
+	var startedEventID, previousStartedEventID int64
+	for _, event := range events {
+		switch event.GetEventType() {
+		case enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED:
+			startedEventID = event.GetEventId()
+		case enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED:
+			previousStartedEventID = startedEventID
+		}
+	}
+	return previousStartedEventID
+}
+
 // HistoryFromJSON deserializes history from a reader of JSON bytes. This does
 // not close the reader if it is closeable.
 func HistoryFromJSON(r io.Reader, lastEventID int64) (*historypb.History, error) {
@@ -2053,6 +2603,23 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		panic("cannot set both EnableSessionWorker and UseBuildIDForVersioning")
 	}
 
+	if options.WorkflowTaskForcedHeartbeatThreshold < 0 || options.WorkflowTaskForcedHeartbeatThreshold >= 1 {
+		panic("WorkflowTaskForcedHeartbeatThreshold must be between 0 and 1")
+	}
+	if options.ShadowReplaySampleRate < 0 || options.ShadowReplaySampleRate > 1 {
+		panic("ShadowReplaySampleRate must be between 0 and 1")
+	}
+
+	if options.MaxHeartbeatThrottleInterval < 0 || options.DefaultHeartbeatThrottleInterval < 0 {
+		panic("MaxHeartbeatThrottleInterval and DefaultHeartbeatThrottleInterval must not be negative")
+	}
+	if options.DefaultHeartbeatThrottleInterval > options.MaxHeartbeatThrottleInterval {
+		client.logger.Warn("DefaultHeartbeatThrottleInterval is greater than MaxHeartbeatThrottleInterval, "+
+			"heartbeats of activities without a HeartbeatTimeout are throttled to MaxHeartbeatThrottleInterval",
+			"DefaultHeartbeatThrottleInterval", options.DefaultHeartbeatThrottleInterval,
+			"MaxHeartbeatThrottleInterval", options.MaxHeartbeatThrottleInterval)
+	}
+
 	if (options.DeploymentOptions.Version != WorkerDeploymentVersion{}) {
 		options.BuildID = options.DeploymentOptions.Version.BuildID
 	}
@@ -2073,6 +2640,7 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		aw.fatalErrLock.Unlock()
 		// Only do the rest if not already set
 		if !alreadySet {
+			aw.lifecycleInterceptor.OnFatalError(aw.lifecycleInfo(), err)
 			// Invoke the callback if present
 			if options.OnFatalError != nil {
 				options.OnFatalError(err)
@@ -2103,32 +2671,43 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 
 	cache := NewWorkerCache()
 	workerParams := workerExecutionParameters{
-		Namespace:                        client.namespace,
-		TaskQueue:                        taskQueue,
-		Tuner:                            options.Tuner,
-		WorkerActivitiesPerSecond:        options.WorkerActivitiesPerSecond,
-		WorkerLocalActivitiesPerSecond:   options.WorkerLocalActivitiesPerSecond,
-		Identity:                         client.identity,
-		WorkerBuildID:                    options.BuildID,
-		UseBuildIDForVersioning:          options.UseBuildIDForVersioning || options.DeploymentOptions.UseVersioning,
-		DeploymentOptions:                options.DeploymentOptions,
-		MetricsHandler:                   metricsHandler,
-		Logger:                           client.logger,
-		EnableLoggingInReplay:            options.EnableLoggingInReplay,
-		BackgroundContext:                backgroundActivityContext,
-		BackgroundContextCancel:          backgroundActivityContextCancel,
-		StickyScheduleToStartTimeout:     options.StickyScheduleToStartTimeout,
-		TaskQueueActivitiesPerSecond:     options.TaskQueueActivitiesPerSecond,
-		WorkflowPanicPolicy:              options.WorkflowPanicPolicy,
-		DataConverter:                    client.dataConverter,
-		FailureConverter:                 client.failureConverter,
-		WorkerStopTimeout:                options.WorkerStopTimeout,
-		WorkerFatalErrorCallback:         fatalErrorCallback,
-		ContextPropagators:               client.contextPropagators,
-		DeadlockDetectionTimeout:         options.DeadlockDetectionTimeout,
-		DefaultHeartbeatThrottleInterval: options.DefaultHeartbeatThrottleInterval,
-		MaxHeartbeatThrottleInterval:     options.MaxHeartbeatThrottleInterval,
-		cache:                            cache,
+		Namespace:                              client.namespace,
+		TaskQueue:                              taskQueue,
+		Tuner:                                  options.Tuner,
+		WorkerActivitiesPerSecond:              options.WorkerActivitiesPerSecond,
+		WorkerLocalActivitiesPerSecond:         options.WorkerLocalActivitiesPerSecond,
+		Identity:                               client.identity,
+		WorkerBuildID:                          options.BuildID,
+		UseBuildIDForVersioning:                options.UseBuildIDForVersioning || options.DeploymentOptions.UseVersioning,
+		DeploymentOptions:                      options.DeploymentOptions,
+		MetricsHandler:                         metricsHandler,
+		Logger:                                 client.logger,
+		EnableLoggingInReplay:                  options.EnableLoggingInReplay,
+		EnableWorkflowCommandLogging:           options.EnableWorkflowCommandLogging,
+		BackgroundContext:                      backgroundActivityContext,
+		BackgroundContextCancel:                backgroundActivityContextCancel,
+		StickyScheduleToStartTimeout:           options.StickyScheduleToStartTimeout,
+		StickyCachePrewarm:                     options.StickyCachePrewarm,
+		TaskQueueActivitiesPerSecond:           options.TaskQueueActivitiesPerSecond,
+		WorkflowPanicPolicy:                    options.WorkflowPanicPolicy,
+		DataConverter:                          client.dataConverter,
+		FailureConverter:                       client.failureConverter,
+		WorkerStopTimeout:                      options.WorkerStopTimeout,
+		WorkerFatalErrorCallback:               fatalErrorCallback,
+		ContextPropagators:                     client.contextPropagators,
+		DeadlockDetectionTimeout:               options.DeadlockDetectionTimeout,
+		WorkflowTaskForcedHeartbeatThreshold:   options.WorkflowTaskForcedHeartbeatThreshold,
+		SlowReplayThreshold:                    options.SlowReplayThreshold,
+		OnSlowReplay:                           options.OnSlowReplay,
+		ShadowReplaySampleRate:                 options.ShadowReplaySampleRate,
+		FailWorkflowTaskOnShadowReplayMismatch: options.FailWorkflowTaskOnShadowReplayMismatch,
+		ReportContinueAsNew:                    options.ReportContinueAsNew,
+		LogContinueAsNew:                       options.LogContinueAsNew,
+		DefaultHeartbeatThrottleInterval:       options.DefaultHeartbeatThrottleInterval,
+		MaxHeartbeatThrottleInterval:           options.MaxHeartbeatThrottleInterval,
+		OnActivityPanic:                        options.OnActivityPanic,
+		OnWorkflowPanic:                        options.OnWorkflowPanic,
+		cache:                                  cache,
 		eagerActivityExecutor: newEagerActivityExecutor(eagerActivityExecutorOptions{
 			disabled:      options.DisableEagerActivities,
 			taskQueue:     taskQueue,
@@ -2138,6 +2717,13 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		pollTimeTracker:   &pollTimeTracker{},
 		workerInstanceKey: workerInstanceKey,
 	}
+	if options.FailWorkflowTasksOnFatalError {
+		workerParams.WorkflowTaskFatalError = func() error {
+			aw.fatalErrLock.Lock()
+			defer aw.fatalErrLock.Unlock()
+			return aw.fatalErr
+		}
+	}
 
 	if options.MaxConcurrentWorkflowTaskPollers != 0 {
 		workerParams.WorkflowTaskPollerBehavior = NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{
@@ -2172,6 +2758,9 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 	if options.Identity != "" {
 		workerParams.Identity = options.Identity
 	}
+	workerParams.WorkflowIdentity = options.WorkflowIdentity
+	workerParams.ActivityIdentity = options.ActivityIdentity
+	workerParams.NexusIdentity = options.NexusIdentity
 
 	ensureRequiredParams(&workerParams)
 	workerParams.Logger = log.With(workerParams.Logger,
@@ -2200,22 +2789,23 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 	if !options.DisableWorkflowWorker {
 		testTags := getTestTags(options.BackgroundActivityContext)
 		if len(testTags) > 0 {
-			workflowWorker = newWorkflowWorkerWithPressurePoints(client, workerParams, testTags, registry)
+			workflowWorker = newWorkflowWorkerWithPressurePoints(client, workerParams.withIdentity(workerParams.WorkflowIdentity), testTags, registry)
 		} else {
-			workflowWorker = newWorkflowWorker(client, workerParams, nil, registry)
+			workflowWorker = newWorkflowWorker(client, workerParams.withIdentity(workerParams.WorkflowIdentity), nil, registry)
 		}
 	}
 
 	// activity types.
 	var activityWorker *activityWorker
 	if !options.LocalActivityWorkerOnly {
-		activityWorker = newActivityWorker(client, workerParams, nil, registry, nil)
+		activityWorker = newActivityWorker(client, workerParams.withIdentity(workerParams.ActivityIdentity), nil, registry, nil)
 		workerParams.eagerActivityExecutor.activityWorker = activityWorker.worker
 	}
 
 	var sessionWorker *sessionWorker
 	if options.EnableSessionWorker && !options.LocalActivityWorkerOnly {
-		sessionWorker = newSessionWorker(client, workerParams, registry, options.MaxConcurrentSessionExecutionSize)
+		sessionWorker = newSessionWorker(client, workerParams.withIdentity(workerParams.ActivityIdentity), registry,
+			options.MaxConcurrentSessionExecutionSize)
 		registry.RegisterActivityWithOptions(sessionCreationActivity, RegisterActivityOptions{
 			Name: sessionCreationActivityName,
 		})
@@ -2335,11 +2925,26 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		pluginRegistryOptions: &pluginRegistryOptions,
 		heartbeatMetrics:      heartbeatMetrics,
 		heartbeatCallback:     heartbeatCallback,
+		effectiveOptions:      newWorkerEffectiveOptions(options, workerParams, len(registry.interceptors), len(plugins)),
+	}
+
+	// Build the lifecycle interceptor chain with earlier interceptors wrapping later ones
+	lifecycleRoot := &workerLifecycleInterceptorRoot{aw: aw}
+	aw.lifecycleInterceptor = lifecycleRoot
+	for i := len(registry.interceptors) - 1; i >= 0; i-- {
+		aw.lifecycleInterceptor = registry.interceptors[i].InterceptWorkerLifecycle(aw.lifecycleInterceptor)
 	}
 
-	// Set memoized start as a once-value that invokes plugins first
+	// Set memoized start as a once-value that invokes plugins first and then lifecycle interceptors
 	aw.memoizedStart = sync.OnceValue(func() error {
-		start := func(context.Context, WorkerPluginStartWorkerOptions) error { return aw.start() }
+		start := func(ctx context.Context, _ WorkerPluginStartWorkerOptions) error {
+			err := aw.lifecycleInterceptor.Start(ctx, aw.lifecycleInfo())
+			if err != nil && lifecycleRoot.started {
+				// An interceptor failed after the worker started, so stop it again
+				aw.stopWorkers()
+			}
+			return err
+		}
 		for i := len(plugins) - 1; i >= 0; i-- {
 			plugin := plugins[i]
 			next := start
@@ -2451,6 +3056,55 @@ func getReadOnlyChannel(c chan struct{}) <-chan struct{} {
 	return c
 }
 
+// newWorkerEffectiveOptions builds the WorkerEffectiveOptions of a worker from its defaulted options and execution
+// parameters.
+func newWorkerEffectiveOptions(
+	options WorkerOptions,
+	params workerExecutionParameters,
+	numInterceptors int,
+	numPlugins int,
+) WorkerEffectiveOptions {
+	return WorkerEffectiveOptions{
+		Namespace:                               params.Namespace,
+		TaskQueue:                               params.TaskQueue,
+		Identity:                                params.Identity,
+		WorkflowIdentity:                        params.withIdentity(params.WorkflowIdentity).Identity,
+		ActivityIdentity:                        params.withIdentity(params.ActivityIdentity).Identity,
+		NexusIdentity:                           params.withIdentity(params.NexusIdentity).Identity,
+		BuildID:                                 params.getBuildID(),
+		WorkflowTaskSlotSupplierKind:            getSlotSupplierKind(options.Tuner.GetWorkflowTaskSlotSupplier()),
+		ActivityTaskSlotSupplierKind:            getSlotSupplierKind(options.Tuner.GetActivityTaskSlotSupplier()),
+		LocalActivitySlotSupplierKind:           getSlotSupplierKind(options.Tuner.GetLocalActivitySlotSupplier()),
+		NexusTaskSlotSupplierKind:               getSlotSupplierKind(options.Tuner.GetNexusSlotSupplier()),
+		MaxWorkflowTaskSlots:                    options.Tuner.GetWorkflowTaskSlotSupplier().MaxSlots(),
+		MaxActivityTaskSlots:                    options.Tuner.GetActivityTaskSlotSupplier().MaxSlots(),
+		MaxLocalActivitySlots:                   options.Tuner.GetLocalActivitySlotSupplier().MaxSlots(),
+		MaxNexusTaskSlots:                       options.Tuner.GetNexusSlotSupplier().MaxSlots(),
+		WorkflowTaskPollerBehavior:              newWorkerEffectivePollerBehavior(params.WorkflowTaskPollerBehavior),
+		ActivityTaskPollerBehavior:              newWorkerEffectivePollerBehavior(params.ActivityTaskPollerBehavior),
+		NexusTaskPollerBehavior:                 newWorkerEffectivePollerBehavior(params.NexusTaskPollerBehavior),
+		WorkerActivitiesPerSecond:               options.WorkerActivitiesPerSecond,
+		WorkerLocalActivitiesPerSecond:          options.WorkerLocalActivitiesPerSecond,
+		TaskQueueActivitiesPerSecond:            options.TaskQueueActivitiesPerSecond,
+		MaxConcurrentSessionExecutionSize:       options.MaxConcurrentSessionExecutionSize,
+		MaxConcurrentEagerActivityExecutionSize: options.MaxConcurrentEagerActivityExecutionSize,
+		StickyScheduleToStartTimeout:            params.StickyScheduleToStartTimeout,
+		DeadlockDetectionTimeout:                params.DeadlockDetectionTimeout,
+		WorkerStopTimeout:                       params.WorkerStopTimeout,
+		DefaultHeartbeatThrottleInterval:        params.DefaultHeartbeatThrottleInterval,
+		MaxHeartbeatThrottleInterval:            params.MaxHeartbeatThrottleInterval,
+		WorkflowPanicPolicy:                     params.WorkflowPanicPolicy,
+		DisableWorkflowWorker:                   options.DisableWorkflowWorker,
+		LocalActivityWorkerOnly:                 options.LocalActivityWorkerOnly,
+		EnableSessionWorker:                     options.EnableSessionWorker,
+		DisableEagerActivities:                  options.DisableEagerActivities,
+		DisableRegistrationAliasing:             options.DisableRegistrationAliasing,
+		UseVersioning:                           params.UseBuildIDForVersioning,
+		Interceptors:                            numInterceptors,
+		Plugins:                                 numPlugins,
+	}
+}
+
 func setWorkerOptionsDefaults(options *WorkerOptions) {
 	if options.Tuner != nil {
 		if options.MaxConcurrentWorkflowTaskExecutionSize != 0 ||
@@ -2702,3 +3356,38 @@ func collectPluginInfos(clientPluginNames []string, workerPlugins []WorkerPlugin
 
 	return result
 }
+
+// activityOptionsRegistry is the subset of a worker's registry needed for typed activity registration.
+type activityOptionsRegistry interface {
+	RegisterActivityWithOptions(a interface{}, options RegisterActivityOptions)
+}
+
+func registerTypedActivity(r activityOptionsRegistry, name string, fn interface{}) {
+	if name == "" {
+		panic("typed activity registration requires a non-empty name")
+	}
+	r.RegisterActivityWithOptions(fn, RegisterActivityOptions{Name: name})
+}
+
+// RegisterTypedActivity registers a statically typed activity function under the given name.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RegisterTypedActivity]
+func RegisterTypedActivity[In, Out any](r activityOptionsRegistry, name string, fn func(context.Context, In) (Out, error)) {
+	registerTypedActivity(r, name, fn)
+}
+
+// RegisterTypedActivityNoResult registers a statically typed activity function that only returns an error under the
+// given name.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RegisterTypedActivityNoResult]
+func RegisterTypedActivityNoResult[In any](r activityOptionsRegistry, name string, fn func(context.Context, In) error) {
+	registerTypedActivity(r, name, fn)
+}
+
+// RegisterTypedActivityNoInput registers a statically typed activity function that takes no input under the given
+// name.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RegisterTypedActivityNoInput]
+func RegisterTypedActivityNoInput[Out any](r activityOptionsRegistry, name string, fn func(context.Context) (Out, error)) {
+	registerTypedActivity(r, name, fn)
+}
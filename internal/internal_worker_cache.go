@@ -3,6 +3,7 @@ package internal
 import (
 	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"go.temporal.io/sdk/internal/common/cache"
 )
@@ -34,6 +35,32 @@ var sharedWorkerCacheLock sync.Mutex
 // Must be set before spawning any workers
 var desiredWorkflowCacheSize = defaultStickyCacheSize
 
+// Process-wide counters for the sticky workflow cache. These are never reset, so they are safe to read at any time
+// without holding sharedWorkerCacheLock.
+var stickyCacheCounters struct {
+	hits     atomic.Int64
+	misses   atomic.Int64
+	removals atomic.Int64
+}
+
+// StickyWorkflowCacheStats is a point-in-time snapshot of the process-wide sticky workflow cache statistics.
+//
+// Exposed as: [go.temporal.io/sdk/worker.StickyWorkflowCacheStats]
+type StickyWorkflowCacheStats struct {
+	// Hits is the number of workflow tasks that were processed using cached workflow state.
+	Hits int64
+	// Misses is the number of workflow tasks that required a full history replay because cached workflow state was
+	// missing or stale.
+	Misses int64
+	// Removals is the number of workflow executions removed from the cache for any reason, i.e. evicted to make room
+	// for others, discarded because their cached state was stale or failed, or purged.
+	Removals int64
+	// Size is the number of workflow executions currently in the cache.
+	Size int
+	// Capacity is the configured maximum number of workflow executions in the cache.
+	Capacity int
+}
+
 // SetStickyWorkflowCacheSize sets the cache size for sticky workflow cache. Sticky workflow execution is the affinity
 // between workflow tasks of a specific workflow execution to a specific worker. The benefit of sticky execution is that
 // the workflow does not have to reconstruct state by replaying history from the beginning. The cache is shared between
@@ -55,6 +82,28 @@ func PurgeStickyWorkflowCache() {
 	}
 }
 
+// GetStickyCacheStats returns a snapshot of the process-wide sticky workflow cache statistics. The counters are
+// cumulative for the life of the process.
+//
+// Exposed as: [go.temporal.io/sdk/worker.StickyCacheStats]
+func GetStickyCacheStats() StickyWorkflowCacheStats {
+	stats := StickyWorkflowCacheStats{
+		Hits:     stickyCacheCounters.hits.Load(),
+		Misses:   stickyCacheCounters.misses.Load(),
+		Removals: stickyCacheCounters.removals.Load(),
+	}
+
+	sharedWorkerCacheLock.Lock()
+	defer sharedWorkerCacheLock.Unlock()
+	if sharedWorkerCachePtr.workflowCache != nil {
+		stats.Size = (*sharedWorkerCachePtr.workflowCache).Size()
+		stats.Capacity = sharedWorkerCachePtr.maxWorkflowCacheSize
+	} else {
+		stats.Capacity = desiredWorkflowCacheSize
+	}
+	return stats
+}
+
 // NewWorkerCache Creates a new WorkerCache, and increases workerRefcount by one. Instances of WorkerCache decrement the refcounter as
 // a hook to runtime.SetFinalizer (ie: When they are freed by the GC). When there are no reachable instances of
 // WorkerCache, shared caches will be cleared
@@ -78,6 +127,7 @@ func newWorkerCache(storeIn *sharedWorkerCache, lock *sync.Mutex, cacheSize int)
 	if storeIn.workerRefcount == 0 {
 		newcache := cache.New(cacheSize-1, &cache.Options{
 			RemovedFunc: func(cachedEntity interface{}) {
+				stickyCacheCounters.removals.Add(1)
 				wc := cachedEntity.(*workflowExecutionContextImpl)
 				wc.onEviction()
 			},
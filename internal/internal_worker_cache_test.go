@@ -37,3 +37,9 @@ func (s *WorkerCacheSuite) TestCreateAndFree() {
 	s.Equal(cachePtr.workerRefcount, 0)
 	s.Nil(cachePtr.workflowCache)
 }
+
+func (s *WorkerCacheSuite) TestStickyCacheStats() {
+	stats := GetStickyCacheStats()
+	s.Equal(desiredWorkflowCacheSize, stats.Capacity)
+	s.LessOrEqual(stats.Size, stats.Capacity)
+}
@@ -129,6 +129,16 @@ func binaryChecksumWorkflowFunc(ctx Context) ([]string, error) {
 	return result, nil
 }
 
+func currentBuildIDWorkflowFunc(ctx Context) ([]string, error) {
+	var result []string
+	result = append(result, GetCurrentBuildID(ctx))
+	_ = Sleep(ctx, time.Hour)
+	result = append(result, GetCurrentBuildID(ctx))
+	_ = Sleep(ctx, time.Hour)
+	result = append(result, GetCurrentBuildID(ctx))
+	return result, nil
+}
+
 func helloWorldWorkflowCancelFunc(ctx Context, _ []byte) error {
 	activityName := "Greeter_Activity"
 	ao := ActivityOptions{
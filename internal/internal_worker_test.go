@@ -27,6 +27,7 @@ import (
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 
 	"go.temporal.io/sdk/converter"
 	iconverter "go.temporal.io/sdk/internal/converter"
@@ -290,6 +291,87 @@ func (s *internalWorkerTestSuite) TestReplayWorkflowHistory() {
 	require.NoError(s.T(), err)
 }
 
+func (s *internalWorkerTestSuite) TestBisectReplay() {
+	taskQueue := "taskQueue1"
+	history := &historypb.History{Events: []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{
+			WorkflowType: &commonpb.WorkflowType{Name: "testReplayWorkflow"},
+			TaskQueue:    &taskqueuepb.TaskQueue{Name: taskQueue},
+			Input:        testEncodeFunctionArgs(converter.GetDefaultDataConverter()),
+		}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{}),
+		createTestEventActivityTaskScheduled(5, &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:   "5",
+			ActivityType: &commonpb.ActivityType{Name: "testActivity"},
+			TaskQueue:    &taskqueuepb.TaskQueue{Name: taskQueue},
+		}),
+		createTestEventActivityTaskStarted(6, &historypb.ActivityTaskStartedEventAttributes{
+			ScheduledEventId: 5,
+		}),
+		createTestEventActivityTaskCompleted(7, &historypb.ActivityTaskCompletedEventAttributes{
+			ScheduledEventId: 5,
+			StartedEventId:   6,
+		}),
+		createTestEventWorkflowTaskScheduled(8, &historypb.WorkflowTaskScheduledEventAttributes{}),
+		createTestEventWorkflowTaskStarted(9),
+		createTestEventWorkflowTaskCompleted(10, &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId: 8,
+			StartedEventId:   9,
+		}),
+		createTestEventWorkflowExecutionCompleted(11, &historypb.WorkflowExecutionCompletedEventAttributes{
+			WorkflowTaskCompletedEventId: 10,
+		}),
+	}}
+	renamedActivityWorkflow := func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: time.Second})
+		return ExecuteActivity(ctx, "renamedActivity").Get(ctx, nil)
+	}
+
+	var replayed []string
+	newVariant := func(label string, workflow interface{}) ReplayVariant {
+		replayer, err := NewWorkflowReplayer(WorkflowReplayerOptions{})
+		s.NoError(err)
+		replayer.RegisterWorkflowWithOptions(workflow, RegisterWorkflowOptions{Name: "testReplayWorkflow"})
+		return ReplayVariant{Label: label, Replayer: replayFunc(func(logger log.Logger, history *historypb.History) error {
+			replayed = append(replayed, label)
+			return replayer.ReplayWorkflowHistory(logger, history)
+		})}
+	}
+	variants := []ReplayVariant{
+		newVariant("v1.0.0", testReplayWorkflow),
+		newVariant("v1.0.0-1-g1a2b3c4", testReplayWorkflow),
+		newVariant("v1.0.0-2-g2b3c4d5", testReplayWorkflow),
+		newVariant("v1.0.0-3-g3c4d5e6", renamedActivityWorkflow),
+		newVariant("v1.0.0-4-g4d5e6f7", renamedActivityWorkflow),
+	}
+
+	result, err := BisectReplay(getLogger(), history, variants)
+	s.NoError(err)
+	s.Equal(3, result.Index)
+	s.Equal("v1.0.0-3-g3c4d5e6", result.Label)
+	s.ErrorContains(result.Err, "[TMPRL1100]")
+	s.Equal(int64(5), result.Event.GetEventId())
+	s.Equal("renamedActivity", result.Command.GetScheduleActivityTaskCommandAttributes().GetActivityType().GetName())
+	s.Equal([]string{"v1.0.0-4-g4d5e6f7", "v1.0.0-2-g2b3c4d5", "v1.0.0-3-g3c4d5e6"}, replayed)
+
+	// No variant fails
+	result, err = BisectReplay(getLogger(), history, variants[:3])
+	s.NoError(err)
+	s.Equal(-1, result.Index)
+	s.Empty(result.Label)
+
+	_, err = BisectReplay(getLogger(), history, nil)
+	s.Error(err)
+}
+
+type replayFunc func(logger log.Logger, history *historypb.History) error
+
+func (f replayFunc) ReplayWorkflowHistory(logger log.Logger, history *historypb.History) error {
+	return f(logger, history)
+}
+
 func (s *internalWorkerTestSuite) TestReplayWorkflowHistory_IncompleteWorkflowExecution() {
 	taskQueue := "taskQueue1"
 	testEvents := []*historypb.HistoryEvent{
@@ -1582,6 +1664,59 @@ func (s *internalWorkerTestSuite) TestReplayWorkflowHistory_ReplayRunID() {
 	require.NoError(s.T(), err)
 }
 
+func (s *internalWorkerTestSuite) TestReplayAndCaptureCommands() {
+	taskQueue := "taskQueue1"
+	testEvents := []*historypb.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &historypb.WorkflowExecutionStartedEventAttributes{
+			WorkflowType: &commonpb.WorkflowType{Name: "testReplayWorkflow"},
+			TaskQueue:    &taskqueuepb.TaskQueue{Name: taskQueue},
+			Input:        testEncodeFunctionArgs(converter.GetDefaultDataConverter()),
+		}),
+		createTestEventWorkflowTaskScheduled(2, &historypb.WorkflowTaskScheduledEventAttributes{}),
+		createTestEventWorkflowTaskStarted(3),
+		createTestEventWorkflowTaskCompleted(4, &historypb.WorkflowTaskCompletedEventAttributes{}),
+		createTestEventActivityTaskScheduled(5, &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:   "5",
+			ActivityType: &commonpb.ActivityType{Name: "testActivity"},
+			TaskQueue:    &taskqueuepb.TaskQueue{Name: taskQueue},
+		}),
+		createTestEventActivityTaskStarted(6, &historypb.ActivityTaskStartedEventAttributes{
+			ScheduledEventId: 5,
+		}),
+		createTestEventActivityTaskCompleted(7, &historypb.ActivityTaskCompletedEventAttributes{
+			ScheduledEventId: 5,
+			StartedEventId:   6,
+		}),
+		createTestEventWorkflowTaskScheduled(8, &historypb.WorkflowTaskScheduledEventAttributes{}),
+		createTestEventWorkflowTaskStarted(9),
+	}
+
+	logger := getLogger()
+	replayer, err := NewWorkflowReplayer(WorkflowReplayerOptions{})
+	require.NoError(s.T(), err)
+	replayer.RegisterWorkflow(testReplayWorkflow)
+
+	// The first workflow task schedules the activity
+	commands, err := replayer.ReplayAndCaptureCommands(logger, &historypb.History{Events: testEvents[:3]}, ReplayWorkflowHistoryOptions{})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), commands, 1)
+	require.Equal(s.T(), enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK, commands[0].GetCommandType())
+	require.Equal(s.T(), "testActivity", commands[0].GetScheduleActivityTaskCommandAttributes().GetActivityType().GetName())
+
+	// The last workflow task completes the workflow, the same way on every replay
+	commands, err = replayer.ReplayAndCaptureCommands(logger, &historypb.History{Events: testEvents}, ReplayWorkflowHistoryOptions{})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), commands, 1)
+	require.Equal(s.T(), enumspb.COMMAND_TYPE_COMPLETE_WORKFLOW_EXECUTION, commands[0].GetCommandType())
+	commandsAgain, err := replayer.ReplayAndCaptureCommands(logger, &historypb.History{Events: testEvents}, ReplayWorkflowHistoryOptions{})
+	require.NoError(s.T(), err)
+	require.True(s.T(), proto.Equal(commands[0], commandsAgain[0]))
+
+	// Histories not ending with a workflow task are rejected
+	_, err = replayer.ReplayAndCaptureCommands(logger, &historypb.History{Events: testEvents[:7]}, ReplayWorkflowHistoryOptions{})
+	require.ErrorContains(s.T(), err, "must end with a started workflow task")
+}
+
 func (s *internalWorkerTestSuite) TestReplayWorkflowHistoryFromFileParent() {
 	logger := getLogger()
 	replayer, err := NewWorkflowReplayer(WorkflowReplayerOptions{})
@@ -1797,6 +1932,107 @@ func (s *internalWorkerTestSuite) TestCleanupIsBestEffort() {
 	assert.NotPanics(s.T(), func() { worker.Stop() })
 }
 
+type recordingLifecycleInterceptor struct {
+	WorkerInterceptorBase
+	WorkerLifecycleInterceptorBase
+	name     string
+	events   *[]string
+	startErr error
+}
+
+func (r *recordingLifecycleInterceptor) InterceptWorkerLifecycle(next WorkerLifecycleInterceptor) WorkerLifecycleInterceptor {
+	r.Next = next
+	return r
+}
+
+func (r *recordingLifecycleInterceptor) Start(ctx context.Context, info WorkerLifecycleInfo) error {
+	*r.events = append(*r.events, r.name+" start "+info.TaskQueue)
+	if err := r.Next.Start(ctx, info); err != nil {
+		return err
+	}
+	*r.events = append(*r.events, r.name+" started")
+	return r.startErr
+}
+
+func (r *recordingLifecycleInterceptor) Stop(ctx context.Context, info WorkerLifecycleInfo) {
+	*r.events = append(*r.events, r.name+" stop")
+	r.Next.Stop(ctx, info)
+}
+
+func (s *internalWorkerTestSuite) TestWorkerLifecycleInterceptor() {
+	namespace := "testNamespace"
+	service := workflowservicemock.NewMockWorkflowServiceClient(s.mockCtrl)
+	service.EXPECT().GetSystemInfo(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.GetSystemInfoResponse{}, nil).AnyTimes()
+	setupPollingMocks(namespace, service, 0.0)
+	service.EXPECT().ShutdownWorker(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.ShutdownWorkerResponse{}, nil).AnyTimes()
+	client := NewServiceClient(service, nil, ClientOptions{Namespace: namespace})
+
+	var events []string
+	worker := NewAggregatedWorker(client, "lifecycle-queue", WorkerOptions{
+		Interceptors: []WorkerInterceptor{
+			&recordingLifecycleInterceptor{name: "first", events: &events},
+			&recordingLifecycleInterceptor{name: "second", events: &events},
+		},
+	})
+	worker.registry = newRegistry()
+	s.NoError(worker.Start())
+	s.True(worker.workflowWorker.worker.isWorkerStarted)
+	worker.Stop()
+	s.False(worker.workflowWorker.worker.isWorkerStarted)
+	s.Equal([]string{
+		"first start lifecycle-queue",
+		"second start lifecycle-queue",
+		"second started",
+		"first started",
+		"first stop",
+		"second stop",
+	}, events)
+
+	// An error from an interceptor after the worker started stops it again
+	events = nil
+	worker = NewAggregatedWorker(client, "lifecycle-queue", WorkerOptions{
+		Interceptors: []WorkerInterceptor{
+			&recordingLifecycleInterceptor{name: "first", events: &events},
+			&recordingLifecycleInterceptor{name: "second", events: &events, startErr: errors.New("registration failed")},
+		},
+	})
+	worker.registry = newRegistry()
+	s.EqualError(worker.Start(), "registration failed")
+	s.False(worker.workflowWorker.worker.isWorkerStarted)
+	s.Equal([]string{"first start lifecycle-queue", "second start lifecycle-queue", "second started"}, events)
+}
+
+func (s *internalWorkerTestSuite) TestActiveTaskQueues() {
+	namespace := "testNamespace"
+	service := workflowservicemock.NewMockWorkflowServiceClient(s.mockCtrl)
+	service.EXPECT().GetSystemInfo(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.GetSystemInfoResponse{}, nil).AnyTimes()
+	setupPollingMocks(namespace, service, 0.0)
+	service.EXPECT().ShutdownWorker(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.ShutdownWorkerResponse{}, nil).AnyTimes()
+	client := NewServiceClient(service, nil, ClientOptions{Namespace: namespace})
+
+	worker := NewAggregatedWorker(client, "active-queue", WorkerOptions{})
+	worker.registry = newRegistry()
+	s.NotContains(ActiveTaskQueues(), "active-queue")
+	s.NoError(worker.Start())
+	s.Contains(ActiveTaskQueues(), "active-queue")
+	worker.Stop()
+	s.NotContains(ActiveTaskQueues(), "active-queue")
+
+	// Workers that fail to start are not active
+	failing := NewAggregatedWorker(client, "failing-queue", WorkerOptions{
+		Interceptors: []WorkerInterceptor{
+			&recordingLifecycleInterceptor{name: "failing", events: new([]string), startErr: errors.New("registration failed")},
+		},
+	})
+	failing.registry = newRegistry()
+	s.Error(failing.Start())
+	s.NotContains(ActiveTaskQueues(), "failing-queue")
+}
+
 func (s *internalWorkerTestSuite) TestStartWorkerAfterStopped() {
 	defer func() {
 		if r := recover(); r == nil {
@@ -2327,6 +2563,39 @@ func TestRegisterStructWithInvalidFnsWithoutSkipFails(t *testing.T) {
 	assert.Panics(t, testRegisterStructWithInvalidFnsWithoutSkipFails)
 }
 
+func TestRegisterActivities(t *testing.T) {
+	registry := newRegistry()
+	RegisterActivities(registry, RegisterActivityOptions{Prefix: "billing.", SkipInvalidStructFunctions: true},
+		&testActivityStruct{}, &testActivityStructWithFns{})
+	_, ok := registry.GetActivity("billing.SomeActivity")
+	assert.True(t, ok)
+	_, ok = registry.GetActivity("billing.ValidActivity")
+	assert.True(t, ok)
+
+	// Excluded methods are neither validated nor registered
+	registry = newRegistry()
+	registry.RegisterActivityWithOptions(&testActivityStructWithFns{}, RegisterActivityOptions{ExcludeMethods: []string{"InvalidActivity"}})
+	_, ok = registry.GetActivity("ValidActivity")
+	assert.True(t, ok)
+
+	// Functions get the prefix too
+	registry.RegisterActivityWithOptions(testActivityMultipleArgs, RegisterActivityOptions{Prefix: "billing."})
+	fnName, _ := getFunctionName(testActivityMultipleArgs)
+	_, ok = registry.GetActivity("billing." + fnName)
+	assert.True(t, ok)
+	assert.Equal(t, "billing."+fnName, getActivityFunctionName(registry, testActivityMultipleArgs))
+
+	// Collisions between structs are reported before registering any of them
+	registry = newRegistry()
+	assert.PanicsWithValue(t,
+		`activity type "SomeActivity" is defined by both *internal.testActivityStruct and *internal.testActivityStruct`,
+		func() {
+			RegisterActivities(registry, RegisterActivityOptions{}, &testActivityStruct{}, &testActivityStruct{})
+		})
+	_, ok = registry.GetActivity("SomeActivity")
+	assert.False(t, ok)
+}
+
 type testActivityStructWithFnWithWorkflowContext struct{}
 
 func (t *testActivityStructWithFnWithWorkflowContext) InvalidActivity(Context) error {
@@ -2358,6 +2627,87 @@ func TestRegisterStructWithInvalidActivityWithWorkflowContextFails(t *testing.T)
 	assert.Panics(t, testRegisterStructWithInvalidActivityWithWorkflowContextFails)
 }
 
+func TestRegisterTypedActivity(t *testing.T) {
+	registry := newRegistry()
+	RegisterTypedActivity(registry, "typed", func(ctx context.Context, in string) (int, error) {
+		return len(in), nil
+	})
+	RegisterTypedActivityNoResult(registry, "typedNoResult", func(ctx context.Context, in string) error {
+		return nil
+	})
+	RegisterTypedActivityNoInput(registry, "typedNoInput", func(ctx context.Context) (string, error) {
+		return "", nil
+	})
+	require.ElementsMatch(t, []string{"typed", "typedNoResult", "typedNoInput"}, registry.getRegisteredActivityTypes())
+
+	a, ok := registry.GetActivity("typed")
+	require.True(t, ok)
+	fn, ok := a.GetFunction().(func(context.Context, string) (int, error))
+	require.True(t, ok)
+	length, err := fn(context.Background(), "abc")
+	require.NoError(t, err)
+	require.Equal(t, 3, length)
+
+	require.Panics(t, func() {
+		RegisterTypedActivityNoInput(registry, "", func(ctx context.Context) (string, error) { return "", nil })
+	})
+	require.Panics(t, func() {
+		RegisterTypedActivityNoInput(registry, "typed", func(ctx context.Context) (string, error) { return "", nil })
+	})
+}
+
+func TestRegisterWithAliases(t *testing.T) {
+	registry := newRegistry()
+	registry.RegisterWorkflowWithOptions(testWorkflowSample, RegisterWorkflowOptions{
+		Name:    "NewWorkflow",
+		Aliases: []string{"OldWorkflow"},
+	})
+	require.ElementsMatch(t, []string{"NewWorkflow", "OldWorkflow"}, registry.getRegisteredWorkflowTypes())
+
+	registry.RegisterActivityWithOptions(testActivityNoResult, RegisterActivityOptions{
+		Name:    "NewActivity",
+		Aliases: []string{"OldActivity"},
+	})
+	a, ok := registry.GetActivity("OldActivity")
+	require.True(t, ok)
+	require.Equal(t, "NewActivity", a.ActivityType().Name)
+
+	require.Panics(t, func() {
+		registry.RegisterWorkflowWithOptions(testWorkflowNoArgs, RegisterWorkflowOptions{
+			Name:    "OtherWorkflow",
+			Aliases: []string{"OldWorkflow"},
+		})
+	})
+	require.Panics(t, func() {
+		registry.RegisterActivityWithOptions(testActivityNoResult, RegisterActivityOptions{
+			Name:    "OtherActivity",
+			Aliases: []string{"OldActivity"},
+		})
+	})
+	// A collision registers none of the names
+	require.ElementsMatch(t, []string{"NewWorkflow", "OldWorkflow"}, registry.getRegisteredWorkflowTypes())
+	_, ok = registry.GetActivity("OtherActivity")
+	require.False(t, ok)
+	require.Panics(t, func() {
+		registry.RegisterActivityWithOptions(testActivityNoResult, RegisterActivityOptions{
+			Name:    "OtherActivity",
+			Aliases: []string{"OtherActivity"},
+		})
+	})
+	require.Panics(t, func() {
+		registry.RegisterActivityWithOptions(testActivityNoResult, RegisterActivityOptions{
+			Name:    "OtherActivity",
+			Aliases: []string{""},
+		})
+	})
+	require.Panics(t, func() {
+		registry.RegisterActivityWithOptions(&testActivityStructWithFns{}, RegisterActivityOptions{
+			Aliases:                    []string{"Struct"},
+			SkipInvalidStructFunctions: true,
+		})
+	})
+}
+
 func TestVariousActivitySchedulingOption(t *testing.T) {
 	w := &activitiesCallingOptionsWorkflow{t: t}
 
@@ -2637,6 +2987,84 @@ func TestWorkerOptionInvalid(t *testing.T) {
 	require.Panics(t, func() {
 		NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{MaxConcurrentWorkflowTaskPollers: 1})
 	})
+	require.Panics(t, func() {
+		NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{WorkflowTaskForcedHeartbeatThreshold: 1})
+	})
+	require.Panics(t, func() {
+		NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{WorkflowTaskForcedHeartbeatThreshold: -0.5})
+	})
+	require.Panics(t, func() {
+		NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{MaxHeartbeatThrottleInterval: -time.Second})
+	})
+	require.Panics(t, func() {
+		NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{DefaultHeartbeatThrottleInterval: -time.Second})
+	})
+}
+
+func TestWorkerOptionHeartbeatThrottleIntervalWarning(t *testing.T) {
+	logger := ilog.NewMemoryLogger()
+	NewAggregatedWorker(&WorkflowClient{logger: logger}, "worker-options-tq", WorkerOptions{
+		DefaultHeartbeatThrottleInterval: 2 * time.Minute,
+	})
+	require.Len(t, logger.Lines(), 1)
+	require.Contains(t, logger.Lines()[0], "DefaultHeartbeatThrottleInterval is greater than MaxHeartbeatThrottleInterval")
+}
+
+func TestWorkerEffectiveOptions(t *testing.T) {
+	aggWorker := NewAggregatedWorker(&WorkflowClient{namespace: DefaultNamespace}, "worker-options-tq", WorkerOptions{
+		Identity:                           "effective-options-identity",
+		MaxConcurrentActivityExecutionSize: 10,
+		ActivityTaskPollerBehavior: NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{
+			MaximumNumberOfPollers: 20,
+		}),
+		Interceptors: []WorkerInterceptor{&InterceptorBase{}},
+	})
+	options := aggWorker.EffectiveOptions()
+	require.Equal(t, DefaultNamespace, options.Namespace)
+	require.Equal(t, "worker-options-tq", options.TaskQueue)
+	require.Equal(t, "effective-options-identity", options.Identity)
+	require.Equal(t, "effective-options-identity", options.WorkflowIdentity)
+	require.Equal(t, "effective-options-identity", options.ActivityIdentity)
+	require.Equal(t, "effective-options-identity", options.NexusIdentity)
+
+	require.Equal(t, "Fixed", options.ActivityTaskSlotSupplierKind)
+	require.Equal(t, 10, options.MaxActivityTaskSlots)
+	require.Equal(t, defaultMaxConcurrentTaskExecutionSize, options.MaxWorkflowTaskSlots)
+	require.Equal(t, defaultMaxConcurrentLocalActivityExecutionSize, options.MaxLocalActivitySlots)
+
+	require.Equal(t, WorkerEffectivePollerBehavior{MaximumNumberOfPollers: defaultConcurrentPollRoutineSize},
+		options.WorkflowTaskPollerBehavior)
+	require.Equal(t, WorkerEffectivePollerBehavior{
+		Autoscaling:            true,
+		MinimumNumberOfPollers: defaultAutoscalingMinimumNumberOfPollers,
+		InitialNumberOfPollers: defaultAutoscalingInitialNumberOfPollers,
+		MaximumNumberOfPollers: 20,
+	}, options.ActivityTaskPollerBehavior)
+
+	require.Equal(t, float64(defaultWorkerActivitiesPerSecond), options.WorkerActivitiesPerSecond)
+	require.Equal(t, defaultDeadlockDetectionTimeout, options.DeadlockDetectionTimeout)
+	require.Equal(t, defaultMaxHeartbeatThrottleInterval, options.MaxHeartbeatThrottleInterval)
+	require.Equal(t, BlockWorkflow, options.WorkflowPanicPolicy)
+	require.Equal(t, 1, options.Interceptors)
+	require.Zero(t, options.Plugins)
+}
+
+func TestWorkerOptionTaskTypeIdentities(t *testing.T) {
+	aggWorker := NewAggregatedWorker(&WorkflowClient{}, "worker-options-tq", WorkerOptions{
+		Identity:            "worker-identity",
+		WorkflowIdentity:    "workflow-identity",
+		ActivityIdentity:    "activity-identity",
+		EnableSessionWorker: true,
+	})
+	require.Equal(t, "worker-identity", aggWorker.executionParams.Identity)
+	require.Equal(t, "workflow-identity", aggWorker.workflowWorker.executionParameters.Identity)
+	require.Equal(t, "activity-identity", aggWorker.activityWorker.executionParameters.Identity)
+	require.Equal(t, "activity-identity", aggWorker.sessionWorker.activityWorker.executionParameters.Identity)
+
+	options := aggWorker.EffectiveOptions()
+	require.Equal(t, "workflow-identity", options.WorkflowIdentity)
+	require.Equal(t, "activity-identity", options.ActivityIdentity)
+	require.Equal(t, "worker-identity", options.NexusIdentity)
 }
 
 func TestWorkerOptionDefaults(t *testing.T) {
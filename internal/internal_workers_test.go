@@ -744,6 +744,28 @@ func (s *WorkersTestSuite) TestWorkerMultipleStop() {
 	worker.Stop()
 }
 
+func (s *WorkersTestSuite) TestWorkerRunWithContext() {
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	s.service.EXPECT().PollWorkflowTaskQueue(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.PollWorkflowTaskQueueResponse{}, nil).AnyTimes()
+	s.service.EXPECT().PollActivityTaskQueue(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.PollActivityTaskQueueResponse{}, nil).AnyTimes()
+	s.service.EXPECT().ShutdownWorker(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.ShutdownWorkerResponse{}, nil).Times(1)
+
+	client := NewServiceClient(s.service, nil, ClientOptions{Identity: "run-with-context-identity"})
+	worker := NewAggregatedWorker(client, "run-with-context-tq", WorkerOptions{})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.NoError(worker.RunWithContext(ctx))
+	// The worker is stopped once the context is done
+	select {
+	case <-worker.stopC:
+	default:
+		s.Fail("worker not stopped")
+	}
+}
+
 func (s *WorkersTestSuite) TestWorkerTaskQueueLimitDisableEager() {
 	client := NewServiceClient(s.service, nil, ClientOptions{Identity: "task-queue-limit-disable-eager"})
 	worker := NewAggregatedWorker(client, "task-queue-limit-disable-eager", WorkerOptions{
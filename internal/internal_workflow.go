@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"runtime"
 	"slices"
@@ -18,6 +19,7 @@ import (
 
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
+	failurepb "go.temporal.io/api/failure/v1"
 	"go.temporal.io/api/sdk/v1"
 
 	"go.temporal.io/sdk/converter"
@@ -72,6 +74,12 @@ type (
 		locked bool
 	}
 
+	// Implements Counter interface
+	counterImpl struct {
+		dispatcher *dispatcherImpl
+		value      int64
+	}
+
 	// Implements Semaphore interface
 	semaphoreImpl struct {
 		size int64
@@ -172,6 +180,7 @@ type (
 		logger           log.Logger
 		deadlockDetector *deadlockDetector
 		readOnly         bool
+		handlingQuery    bool // true while a query handler is running outside of ExecuteUntilAllBlocked
 		// allBlockedCallback is called when all coroutines are blocked,
 		// returns true if the callback updated any coroutines state and there may be more work
 		allBlockedCallback func() bool
@@ -216,6 +225,8 @@ type (
 		// currentDetails is the user-set string returned on metadata query as
 		// WorkflowMetadata.current_details
 		currentDetails string
+		// acceptedUpdateIDs is the set of IDs of updates accepted in this run.
+		acceptedUpdateIDs map[string]struct{}
 	}
 
 	// ExecuteWorkflowParams parameters of the workflow invocation
@@ -227,12 +238,15 @@ type (
 		attempt              int32              // used by test framework to support child workflow retry
 		scheduledTime        time.Time          // used by test framework to support child workflow retry
 		lastCompletionResult *commonpb.Payloads // used by test framework to support cron
+		lastFailure          *failurepb.Failure // used by test framework to support child workflow retry
 	}
 
 	// decodeFutureImpl
 	decodeFutureImpl struct {
 		*futureImpl
 		fn interface{}
+		// nonRetryableDecodeError makes decode failures in Get non-retryable application errors
+		nonRetryableDecodeError bool
 	}
 
 	childWorkflowFutureImpl struct {
@@ -338,6 +352,18 @@ type workflowEnvironmentInterceptor struct {
 	inboundInterceptor  WorkflowInboundInterceptor
 	fn                  interface{}
 	outboundInterceptor WorkflowOutboundInterceptor
+	// Number of idempotency keys generated so far, by scope
+	idempotencyKeyCounters map[string]int
+	// Number of successful upserts by this run, by search attribute name
+	searchAttributeVersions map[string]int
+	// Futures of activities started by ExecuteActivityCached, by cache key
+	activityResultCache map[string]Future
+	// Timers that have neither fired nor been canceled, in the order they were created
+	pendingTimers []PendingTimer
+	// RegisterWorkflowOptions.StaticConfig of the workflow type
+	staticConfig map[string]string
+	// Header the workflow was started with
+	startHeader map[string]*commonpb.Payload
 }
 
 func (wc *workflowEnvironmentInterceptor) Go(ctx Context, name string, f func(ctx Context)) Context {
@@ -554,6 +580,7 @@ func (d *syncWorkflowDefinition) Execute(env WorkflowEnvironment, header *common
 			*rpp = r
 		}, getWorkflowEnvironment(rootCtx).DrainUnhandledUpdates)
 
+	envInterceptor.startHeader = maps.Clone(header.GetFields())
 	// set the information from the headers that is to be propagated in the workflow context
 	rootCtx, err = workflowContextWithHeaderPropagated(rootCtx, header, env.GetContextPropagators())
 	if err != nil {
@@ -630,6 +657,9 @@ func (d *syncWorkflowDefinition) Execute(env WorkflowEnvironment, header *common
 			}
 
 			// Invoke
+			dispatcher := rootCtx.Value(coroutinesContextKey).(*coroutineState).dispatcher
+			dispatcher.setIsHandlingQuery(true)
+			defer dispatcher.setIsHandlingQuery(false)
 			result, err := envInterceptor.inboundInterceptor.HandleQuery(
 				rootCtx,
 				&HandleQueryInput{QueryType: queryType, Args: args},
@@ -1010,6 +1040,9 @@ func (c *channelImpl) Close() {
 
 // Takes a value and assigns that 'to' value. logs a metric if it is unable to deserialize
 func (c *channelImpl) assignValue(from interface{}, to interface{}) error {
+	if decoder, ok := to.(channelValueDecoder); ok {
+		return decoder.decodeChannelValue(c, from)
+	}
 	err := decodeAndAssignValue(c.dataConverter, from, to)
 	// add to metrics
 	if err != nil {
@@ -1319,6 +1352,18 @@ func (d *dispatcherImpl) setIsReadOnly(readOnly bool) {
 	d.readOnly = readOnly
 }
 
+func (d *dispatcherImpl) getIsHandlingQuery() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.handlingQuery
+}
+
+func (d *dispatcherImpl) setIsHandlingQuery(handlingQuery bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.handlingQuery = handlingQuery
+}
+
 func (d *dispatcherImpl) Close() {
 	d.mutex.Lock()
 	if d.closed {
@@ -1352,6 +1397,9 @@ func (d *dispatcherImpl) StackTrace() string {
 }
 
 func (s *selectorImpl) AddReceive(c ReceiveChannel, f func(c ReceiveChannel, more bool)) Selector {
+	if _, ok := c.(*rateLimitedChannel); ok {
+		panic("a rate limited signal channel cannot be used with a Selector")
+	}
 	s.cases = append(s.cases, &selectCase{channel: c.(*channelImpl), receiveFunc: &f})
 	return s
 }
@@ -1374,6 +1422,14 @@ func (s *selectorImpl) AddDefault(f func()) {
 	s.defaultFunc = &f
 }
 
+func (s *selectorImpl) ClearDefault() {
+	s.defaultFunc = nil
+}
+
+func (s *selectorImpl) HasDefault() bool {
+	return s.defaultFunc != nil
+}
+
 func (s *selectorImpl) HasPending() bool {
 	for _, pair := range s.cases {
 		if pair.receiveFunc != nil && pair.channel.CanReceiveWithoutBlocking() {
@@ -1573,6 +1629,7 @@ func setWorkflowEnvOptionsIfNotExist(ctx Context) Context {
 		newOptions.queryHandlers = make(map[string]*queryHandler)
 		newOptions.updateHandlers = make(map[string]*updateHandler)
 		newOptions.runningUpdatesHandles = make(map[string]UpdateInfo)
+		newOptions.acceptedUpdateIDs = make(map[string]struct{})
 	}
 	if newOptions.DataConverter == nil {
 		newOptions.DataConverter = converter.GetDefaultDataConverter()
@@ -1614,6 +1671,78 @@ func GetUnhandledSignalNames(ctx Context) []string {
 	return getWorkflowEnvOptions(ctx).getUnhandledSignalNames()
 }
 
+// DrainSignals processes the signals currently buffered on the named signal channels with the given handlers and
+// returns the number of signals drained per signal name.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.DrainSignals]
+func DrainSignals(ctx Context, handlers map[string]func(ctx Context, ch ReceiveChannel)) map[string]int {
+	assertNotInReadOnlyState(ctx)
+	// Drain in a stable order to stay deterministic
+	names := make([]string, 0, len(handlers))
+	for name := range handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	drained := make(map[string]int, len(names))
+	for _, name := range names {
+		// The length of the channel also grows with signals arriving while a handler blocks, so the receipt of the
+		// buffered signals is counted by the channel passed to the handler
+		ch := &drainingReceiveChannel{ReceiveChannel: GetSignalChannel(ctx, name)}
+		buffered := ch.Len()
+		for ch.received < buffered {
+			before := ch.received
+			handlers[name](ctx, ch)
+			if ch.received == before {
+				// The handler did not receive the signal, discard it so it is not handled again
+				if ch.ReceiveAsync(nil) {
+					continue
+				}
+				break
+			}
+		}
+		drained[name] = buffered
+	}
+	return drained
+}
+
+// drainingReceiveChannel counts the values received from a signal channel by a DrainSignals handler.
+type drainingReceiveChannel struct {
+	ReceiveChannel
+	received int
+}
+
+func (c *drainingReceiveChannel) Receive(ctx Context, valuePtr interface{}) (more bool) {
+	more = c.ReceiveChannel.Receive(ctx, valuePtr)
+	if more {
+		c.received++
+	}
+	return more
+}
+
+func (c *drainingReceiveChannel) ReceiveWithTimeout(ctx Context, timeout time.Duration, valuePtr interface{}) (ok, more bool) {
+	ok, more = c.ReceiveChannel.ReceiveWithTimeout(ctx, timeout, valuePtr)
+	if ok {
+		c.received++
+	}
+	return ok, more
+}
+
+func (c *drainingReceiveChannel) ReceiveAsync(valuePtr interface{}) (ok bool) {
+	ok = c.ReceiveChannel.ReceiveAsync(valuePtr)
+	if ok {
+		c.received++
+	}
+	return ok
+}
+
+func (c *drainingReceiveChannel) ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, more bool) {
+	ok, more = c.ReceiveChannel.ReceiveAsyncWithMoreFlag(valuePtr)
+	if ok {
+		c.received++
+	}
+	return ok, more
+}
+
 // GetCurrentDetails gets the previously-set current details.
 //
 // NOTE: Experimental
@@ -1719,7 +1848,8 @@ func (d *decodeFutureImpl) Get(ctx Context, valuePtr interface{}) error {
 	dataConverter := getDataConverterFromWorkflowContext(ctx)
 	err := dataConverter.FromPayloads(d.futureImpl.value.(*commonpb.Payloads), valuePtr)
 	if err != nil {
-		return err
+		typeName, _ := d.fn.(string)
+		return newDecodeApplicationError(typeName, valuePtr, err, d.nonRetryableDecodeError)
 	}
 	return d.futureImpl.err
 }
@@ -1728,7 +1858,9 @@ func (d *decodeFutureImpl) Get(ctx Context, valuePtr interface{}) error {
 // fn - the decoded value needs to be validated against a function.
 func newDecodeFuture(ctx Context, fn interface{}) (Future, Settable) {
 	impl := &decodeFutureImpl{
-		&futureImpl{channel: NewChannel(ctx).(*channelImpl)}, fn}
+		futureImpl: &futureImpl{channel: NewChannel(ctx).(*channelImpl)},
+		fn:         fn,
+	}
 	return impl, impl
 }
 
@@ -1959,6 +2091,28 @@ func (m *mutexImpl) IsLocked() bool {
 	return m.locked
 }
 
+func (c *counterImpl) Add(delta int64) int64 {
+	c.assertInDispatcher()
+	if c.dispatcher.getIsReadOnly() || c.dispatcher.getIsHandlingQuery() {
+		panic(panicIllegalAccessCoroutineState)
+	}
+	c.value += delta
+	return c.value
+}
+
+func (c *counterImpl) Load() int64 {
+	c.assertInDispatcher()
+	return c.value
+}
+
+// assertInDispatcher panics if the counter is used while no workflow coroutine or query handler is running, e.g.
+// from a native goroutine started by the workflow.
+func (c *counterImpl) assertInDispatcher() {
+	if !c.dispatcher.IsExecuting() && !c.dispatcher.getIsHandlingQuery() {
+		panic("workflow Counter used outside of the workflow dispatcher, e.g. from a native goroutine")
+	}
+}
+
 func (s *semaphoreImpl) Acquire(ctx Context, n int64) error {
 	err := Await(ctx, func() bool {
 		return s.size-s.cur >= n
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/url"
 	"reflect"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"go.temporal.io/api/serviceerror"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
 	updatepb "go.temporal.io/api/update/v1"
+	workflowpb "go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
 
 	"go.temporal.io/sdk/converter"
@@ -61,6 +64,7 @@ type (
 	// WorkflowClient is the client for starting a workflow execution.
 	WorkflowClient struct {
 		workflowService           workflowservice.WorkflowServiceClient
+		operatorService           operatorservice.OperatorServiceClient
 		conn                      *grpc.ClientConn
 		namespace                 string
 		registry                  *registry
@@ -73,6 +77,7 @@ type (
 		workerPlugins             []WorkerPlugin
 		workerInterceptors        []WorkerInterceptor
 		clientPluginNames         []string
+		interceptors              []ClientInterceptor
 		interceptor               ClientOutboundInterceptor
 		excludeInternalFromRetry  *atomic.Bool
 		capabilities              *workflowservice.GetSystemInfoResponse_Capabilities
@@ -84,10 +89,14 @@ type (
 		workerHeartbeatInterval   time.Duration
 		workerGroupingKey         string
 		heartbeatManager          *heartbeatManager
+		longPollRetryOptions      *LongPollRetryOptions
 
 		// The pointer value is shared across multiple clients. If non-nil, only
 		// access/mutate atomically.
 		unclosedClients *int32
+		// Set for clients created with ClientWithNamespace. The connection is
+		// closed with the client they were created from instead.
+		connOwnedByParent bool
 	}
 
 	// namespaceClient is the client for managing namespaces.
@@ -148,6 +157,11 @@ type (
 		// if the workflow returned a ContinueAsNewError, has a later cron, or is
 		// retried on failure.
 		DisableFollowingRuns bool
+
+		// DataConverter, if set, is used instead of the data converter of the
+		// run to decode the result, e.g. when the workflow was started with
+		// StartWorkflowOptions.DataConverter.
+		DataConverter converter.DataConverter
 	}
 
 	// workflowRunImpl is an implementation of WorkflowRun
@@ -335,12 +349,12 @@ func (wc *WorkflowClient) SignalWithStartWorkflow(ctx context.Context, workflowI
 func (wc *WorkflowClient) NewWithStartWorkflowOperation(options StartWorkflowOptions, workflow interface{}, args ...interface{}) WithStartWorkflowOperation {
 	op := &withStartWorkflowOperationImpl{doneCh: make(chan struct{})}
 	if options.WorkflowIDConflictPolicy == enumspb.WORKFLOW_ID_CONFLICT_POLICY_UNSPECIFIED {
-		op.err = errors.New("WorkflowIDConflictPolicy must be set in StartWorkflowOptions for update-with-start")
+		op.set(nil, errors.New("WorkflowIDConflictPolicy must be set in StartWorkflowOptions for update-with-start"))
 		return op
 	}
 	input, err := createStartWorkflowInput(options, workflow, args, wc.registry)
 	if err != nil {
-		op.err = err
+		op.set(nil, err)
 	}
 	op.input = input
 	return op
@@ -435,7 +449,11 @@ func (wc *WorkflowClient) getWorkflowExecutionHistory(ctx context.Context, rpcMe
 		return nil, err
 	}
 
-	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler), grpcLongPoll(isLongPoll), defaultGrpcRetryParameters(ctx), func(builder *grpcContextBuilder) {
+	retryParameters := defaultGrpcRetryParameters(ctx)
+	if isLongPoll {
+		retryParameters = wc.longPollGrpcRetryParameters(ctx)
+	}
+	grpcCtx, cancel := newGRPCContext(ctx, grpcMetricsHandler(rpcMetricsHandler), grpcLongPoll(isLongPoll), retryParameters, func(builder *grpcContextBuilder) {
 		if isLongPoll {
 			builder.Timeout = defaultGetHistoryTimeout
 		}
@@ -702,6 +720,149 @@ func (wc *WorkflowClient) CountWorkflow(ctx context.Context, request *workflowse
 	return response, nil
 }
 
+// CountWorkflowWithOptions implementation
+func (wc *WorkflowClient) CountWorkflowWithOptions(ctx context.Context, options CountWorkflowOptions) (*CountWorkflowResult, error) {
+	start := time.Now()
+	countResponse, err := wc.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{Query: options.Query})
+	if err != nil {
+		return nil, err
+	}
+	result := &CountWorkflowResult{
+		Count:    countResponse.GetCount(),
+		Query:    options.Query,
+		Duration: time.Since(start),
+	}
+
+	sampleSize := min(options.SampleFailedUpdates, maxCountWorkflowSampleSize)
+	if sampleSize <= 0 || result.Count == 0 {
+		return result, nil
+	}
+	listResponse, err := wc.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Query:    options.Query,
+		PageSize: int32(sampleSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	executions := listResponse.GetExecutions()
+	if len(executions) > sampleSize {
+		executions = executions[:sampleSize]
+	}
+	for _, execution := range executions {
+		failed, err := wc.hasFailedUpdate(ctx, execution.GetExecution())
+		if err != nil {
+			return nil, err
+		}
+		result.Sampled++
+		if failed {
+			result.SampledWithFailedUpdate++
+		}
+	}
+	return result, nil
+}
+
+func (wc *WorkflowClient) hasFailedUpdate(ctx context.Context, execution *commonpb.WorkflowExecution) (bool, error) {
+	iter := wc.GetWorkflowHistory(ctx, execution.GetWorkflowId(), execution.GetRunId(), false,
+		enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return false, err
+		}
+		if event.GetWorkflowExecutionUpdateCompletedEventAttributes().GetOutcome().GetFailure() != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListStuckWorkflows implementation
+func (wc *WorkflowClient) ListStuckWorkflows(ctx context.Context, options ListStuckWorkflowsOptions) ([]StuckWorkflow, error) {
+	minAttempts := options.MinWorkflowTaskAttempts
+	if minAttempts <= 0 {
+		minAttempts = defaultStuckWorkflowTaskAttempts
+	}
+	maxWorkflows := options.MaxWorkflows
+	if maxWorkflows <= 0 {
+		maxWorkflows = defaultMaxStuckWorkflowsInspected
+	}
+	query := "ExecutionStatus = 'Running'"
+	if options.Query != "" {
+		query += " AND (" + options.Query + ")"
+	}
+
+	var stuck []StuckWorkflow
+	var nextPageToken []byte
+	inspected := 0
+	for {
+		listResponse, err := wc.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			PageSize:      int32(min(maxWorkflows-inspected, 1000)),
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range listResponse.GetExecutions() {
+			if inspected >= maxWorkflows {
+				return stuck, nil
+			}
+			inspected++
+			workflow, err := wc.stuckWorkflow(ctx, info, minAttempts)
+			if err != nil {
+				return nil, err
+			} else if workflow != nil {
+				stuck = append(stuck, *workflow)
+			}
+		}
+		nextPageToken = listResponse.GetNextPageToken()
+		if len(nextPageToken) == 0 || inspected >= maxWorkflows {
+			return stuck, nil
+		}
+	}
+}
+
+// stuckWorkflow returns the workflow as a StuckWorkflow if its pending workflow task has been attempted at least
+// minAttempts times, or nil otherwise.
+func (wc *WorkflowClient) stuckWorkflow(ctx context.Context, info *workflowpb.WorkflowExecutionInfo, minAttempts int32) (*StuckWorkflow, error) {
+	execution := info.GetExecution()
+	describeResponse, err := wc.DescribeWorkflowExecution(ctx, execution.GetWorkflowId(), execution.GetRunId())
+	if _, ok := err.(*serviceerror.NotFound); ok {
+		// Deleted since it was listed
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	pendingTask := describeResponse.GetPendingWorkflowTask()
+	if describeResponse.GetWorkflowExecutionInfo().GetStatus() != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING ||
+		pendingTask.GetAttempt() < minAttempts {
+		return nil, nil
+	}
+	workflow := &StuckWorkflow{
+		WorkflowID:                execution.GetWorkflowId(),
+		RunID:                     execution.GetRunId(),
+		WorkflowType:              info.GetType().GetName(),
+		WorkflowTaskAttempt:       pendingTask.GetAttempt(),
+		WorkflowTaskScheduledTime: pendingTask.GetOriginalScheduledTime().AsTime(),
+	}
+	if pendingTask.GetOriginalScheduledTime() == nil {
+		workflow.WorkflowTaskScheduledTime = pendingTask.GetScheduledTime().AsTime()
+	}
+	iter := wc.GetWorkflowHistory(ctx, execution.GetWorkflowId(), execution.GetRunId(), false,
+		enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if attributes := event.GetWorkflowTaskFailedEventAttributes(); attributes != nil {
+			workflow.LastFailureMessage = attributes.GetFailure().GetMessage()
+			workflow.LastFailureCause = attributes.GetCause()
+		}
+	}
+	return workflow, nil
+}
+
 // GetSearchAttributes implementation
 func (wc *WorkflowClient) GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error) {
 	if err := wc.ensureInitialized(ctx); err != nil {
@@ -790,6 +951,96 @@ func (wc *WorkflowClient) QueryWorkflow(ctx context.Context, workflowID string,
 	})
 }
 
+// maxCountWorkflowSampleSize is the maximum number of workflows CountWorkflowWithOptions will sample.
+const maxCountWorkflowSampleSize = 100
+
+// CountWorkflowOptions are options for Client.CountWorkflowWithOptions.
+//
+// NOTE: Experimental
+//
+// Exposed as: [go.temporal.io/sdk/client.CountWorkflowOptions]
+type CountWorkflowOptions struct {
+	// Query is the visibility query, the same as for CountWorkflow. Empty matches all workflows.
+	Query string
+
+	// SampleFailedUpdates is the number of workflows matching the query to inspect for failed updates. Zero
+	// disables sampling, and values above 100 are capped to 100. Sampling lists the matching workflows and fetches
+	// the full history of each sampled workflow, so it costs one list call plus at least one history call per
+	// sampled workflow, and is much more expensive than counting alone. Updates rejected by a validator are not
+	// recorded in history and so are not detected.
+	SampleFailedUpdates int
+}
+
+// CountWorkflowResult is the result of Client.CountWorkflowWithOptions.
+//
+// NOTE: Experimental
+//
+// Exposed as: [go.temporal.io/sdk/client.CountWorkflowResult]
+type CountWorkflowResult struct {
+	// Count is the number of workflows matching the query.
+	Count int64
+	// Query is the query the count was made with.
+	Query string
+	// Duration is how long the count call took. It does not include sampling.
+	Duration time.Duration
+	// Sampled is the number of workflows inspected for failed updates.
+	Sampled int
+	// SampledWithFailedUpdate is the number of sampled workflows with at least one failed update.
+	SampledWithFailedUpdate int
+}
+
+const (
+	// defaultStuckWorkflowTaskAttempts is the default for ListStuckWorkflowsOptions.MinWorkflowTaskAttempts.
+	defaultStuckWorkflowTaskAttempts = 3
+	// defaultMaxStuckWorkflowsInspected is the default for ListStuckWorkflowsOptions.MaxWorkflows.
+	defaultMaxStuckWorkflowsInspected = 1000
+)
+
+// ListStuckWorkflowsOptions are options for Client.ListStuckWorkflows.
+//
+// NOTE: Experimental
+//
+// Exposed as: [go.temporal.io/sdk/client.ListStuckWorkflowsOptions]
+type ListStuckWorkflowsOptions struct {
+	// Query is a visibility query narrowing down the workflows to inspect, the same as for ListWorkflow. Only running
+	// workflows are inspected, so the query does not need to filter by execution status. Empty matches all running
+	// workflows.
+	Query string
+
+	// MinWorkflowTaskAttempts is the number of attempts of the pending workflow task from which a workflow is
+	// considered stuck.
+	//
+	// Optional: defaults to 3.
+	MinWorkflowTaskAttempts int32
+
+	// MaxWorkflows is the maximum number of running workflows matching the query to inspect. Each inspected workflow
+	// costs a describe call, and each stuck workflow additionally a history fetch.
+	//
+	// Optional: defaults to 1000.
+	MaxWorkflows int
+}
+
+// StuckWorkflow is a workflow whose workflow task keeps failing or timing out, as returned by
+// Client.ListStuckWorkflows.
+//
+// NOTE: Experimental
+//
+// Exposed as: [go.temporal.io/sdk/client.StuckWorkflow]
+type StuckWorkflow struct {
+	WorkflowID   string
+	RunID        string
+	WorkflowType string
+	// WorkflowTaskAttempt is the attempt of the pending workflow task.
+	WorkflowTaskAttempt int32
+	// WorkflowTaskScheduledTime is when the first attempt of the pending workflow task was scheduled.
+	WorkflowTaskScheduledTime time.Time
+	// LastFailureMessage is the message of the last workflow task failure recorded in history. It is empty if the
+	// workflow task attempts timed out rather than failed, or if the server did not record a failure.
+	LastFailureMessage string
+	// LastFailureCause is the cause of the last workflow task failure recorded in history.
+	LastFailureCause enumspb.WorkflowTaskFailedCause
+}
+
 // UpdateWorkflowOptions is the request to UpdateWorkflow
 type UpdateWorkflowOptions struct {
 	// UpdateID is an application-layer identifier for the requested update. It
@@ -1080,6 +1331,39 @@ func (wc *WorkflowClient) ResetWorkflowExecution(ctx context.Context, request *w
 	return resp, nil
 }
 
+// ForceNewWorkflowTask makes the server schedule a new workflow task for a running workflow by sending it an
+// internal signal that is dropped by the worker. Returns nil if the workflow is already closed.
+func (wc *WorkflowClient) ForceNewWorkflowTask(ctx context.Context, workflowID string, runID string) error {
+	if err := wc.ensureInitialized(ctx); err != nil {
+		return err
+	}
+
+	request := &workflowservice.SignalWorkflowExecutionRequest{
+		Namespace: wc.namespace,
+		WorkflowExecution: &commonpb.WorkflowExecution{
+			WorkflowId: workflowID,
+			RunId:      runID,
+		},
+		SignalName: forceWorkflowTaskSignalName,
+		Identity:   wc.identity,
+		RequestId:  uuid.NewString(),
+	}
+
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	_, err := wc.workflowService.SignalWorkflowExecution(grpcCtx, request)
+	var notFound *serviceerror.NotFound
+	if !errors.As(err, &notFound) {
+		return err
+	}
+	// Signaling a closed workflow fails with NotFound, but so does signaling one that doesn't exist.
+	resp, describeErr := wc.DescribeWorkflowExecution(ctx, workflowID, runID)
+	if describeErr == nil && resp.GetWorkflowExecutionInfo().GetStatus() != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return nil
+	}
+	return err
+}
+
 // UpdateWorkerBuildIdCompatibility allows you to update the worker-build-id based version sets for a particular
 // task queue. This is used in conjunction with workers who specify their build id and thus opt into the
 // feature.
@@ -1131,12 +1415,20 @@ func (wc *WorkflowClient) GetWorkerTaskReachability(ctx context.Context, options
 		return nil, err
 	}
 
+	buildIDs := options.BuildIDs
+	if len(buildIDs) == 0 {
+		var err error
+		if buildIDs, err = wc.getDefaultBuildIDs(ctx, options.TaskQueues); err != nil {
+			return nil, err
+		}
+	}
+
 	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
 	defer cancel()
 
 	request := &workflowservice.GetWorkerTaskReachabilityRequest{
 		Namespace:    wc.namespace,
-		BuildIds:     options.BuildIDs,
+		BuildIds:     buildIDs,
 		TaskQueues:   options.TaskQueues,
 		Reachability: taskReachabilityToProto(options.Reachability),
 	}
@@ -1148,6 +1440,24 @@ func (wc *WorkflowClient) GetWorkerTaskReachability(ctx context.Context, options
 	return converted, nil
 }
 
+// getDefaultBuildIDs returns the distinct default build IDs of the given task queues.
+func (wc *WorkflowClient) getDefaultBuildIDs(ctx context.Context, taskQueues []string) ([]string, error) {
+	if len(taskQueues) == 0 {
+		return nil, errors.New("at least one build ID or task queue must be provided")
+	}
+	var buildIDs []string
+	for _, taskQueue := range taskQueues {
+		sets, err := wc.GetWorkerBuildIdCompatibility(ctx, &GetWorkerBuildIdCompatibilityOptions{TaskQueue: taskQueue, MaxSets: 1})
+		if err != nil {
+			return nil, err
+		}
+		if buildID := sets.Default(); !slices.Contains(buildIDs, buildID) {
+			buildIDs = append(buildIDs, buildID)
+		}
+	}
+	return buildIDs, nil
+}
+
 // UpdateWorkflowExecutionOptions partially overrides the [WorkflowExecutionOptions] of an existing workflow execution,
 // and returns the new [WorkflowExecutionOptions] after applying the changes.
 // It is intended for building tools that can selectively apply ad-hoc workflow configuration changes.
@@ -1358,7 +1668,229 @@ func (wc *WorkflowClient) WorkflowService() workflowservice.WorkflowServiceClien
 
 // OperatorService implements Client.OperatorService.
 func (wc *WorkflowClient) OperatorService() operatorservice.OperatorServiceClient {
-	return operatorservice.NewOperatorServiceClient(wc.conn)
+	return wc.operatorService
+}
+
+// AddSearchAttributesOptions are options for Client.AddSearchAttributes.
+//
+// Exposed as: [go.temporal.io/sdk/client.AddSearchAttributesOptions]
+type AddSearchAttributesOptions struct {
+	// Namespace to add the search attributes to. Defaults to the namespace of the client.
+	Namespace string
+
+	// SearchAttributes maps the names of the custom search attributes to add to their types. Required.
+	SearchAttributes map[string]enumspb.IndexedValueType
+}
+
+// ListSearchAttributesOptions are options for Client.ListSearchAttributes.
+//
+// Exposed as: [go.temporal.io/sdk/client.ListSearchAttributesOptions]
+type ListSearchAttributesOptions struct {
+	// Namespace to list the search attributes of. Defaults to the namespace of the client.
+	Namespace string
+}
+
+// SearchAttributesSchema is the result of Client.ListSearchAttributes.
+//
+// Exposed as: [go.temporal.io/sdk/client.SearchAttributesSchema]
+type SearchAttributesSchema struct {
+	// Custom maps the names of the custom search attributes of the namespace to their types.
+	Custom map[string]enumspb.IndexedValueType
+	// System maps the names of the search attributes defined by the server to their types.
+	System map[string]enumspb.IndexedValueType
+}
+
+// NamespaceDescription is the result of Client.DescribeNamespace.
+//
+// Exposed as: [go.temporal.io/sdk/client.NamespaceDescription]
+type NamespaceDescription struct {
+	// Name of the namespace.
+	Name string
+	// ID of the namespace.
+	ID string
+	// State of the namespace, e.g. registered or deleted.
+	State enumspb.NamespaceState
+	// Description of the namespace.
+	Description string
+	// OwnerEmail of the namespace.
+	OwnerEmail string
+	// Data is the custom data of the namespace.
+	Data map[string]string
+	// Retention is how long closed workflows of the namespace are retained.
+	Retention time.Duration
+	// HistoryArchival is the archival configuration of workflow histories.
+	HistoryArchival NamespaceArchivalConfig
+	// VisibilityArchival is the archival configuration of visibility records.
+	VisibilityArchival NamespaceArchivalConfig
+	// IsGlobalNamespace is whether the namespace is replicated across clusters.
+	IsGlobalNamespace bool
+	// ActiveClusterName is the name of the cluster the namespace is active in.
+	ActiveClusterName string
+	// Clusters are the names of the clusters the namespace is replicated to.
+	Clusters []string
+	// SearchAttributes are the search attributes registered on the namespace, nil if the caller is not allowed to list
+	// them or the server does not support listing them.
+	SearchAttributes *SearchAttributesSchema
+	// CustomSearchAttributeAliases maps the field names of the custom search attributes of the namespace to
+	// their aliases, on servers where custom search attributes have aliases.
+	CustomSearchAttributeAliases map[string]string
+}
+
+// NamespaceArchivalConfig is the archival configuration of a namespace.
+//
+// Exposed as: [go.temporal.io/sdk/client.NamespaceArchivalConfig]
+type NamespaceArchivalConfig struct {
+	// State is whether archival is enabled.
+	State enumspb.ArchivalState
+	// URI is where archived data is stored.
+	URI string
+}
+
+// DescribeNamespace implements Client.DescribeNamespace.
+func (wc *WorkflowClient) DescribeNamespace(ctx context.Context, namespace string) (*NamespaceDescription, error) {
+	if err := wc.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = wc.namespace
+	}
+
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	resp, err := wc.workflowService.DescribeNamespace(grpcCtx, &workflowservice.DescribeNamespaceRequest{
+		Namespace: namespace,
+	})
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		// Older servers report a missing namespace as a plain not found error
+		return nil, serviceerror.NewNamespaceNotFound(namespace)
+	} else if err != nil {
+		return nil, err
+	}
+	// The search attributes are best effort, the caller may not be allowed to use the operator service or the server
+	// may not implement it
+	searchAttributes, err := wc.ListSearchAttributes(ctx, ListSearchAttributesOptions{Namespace: namespace})
+	var permissionDenied *serviceerror.PermissionDenied
+	var unimplemented *serviceerror.Unimplemented
+	if err != nil && !errors.As(err, &permissionDenied) && !errors.As(err, &unimplemented) {
+		return nil, err
+	}
+
+	info, config, replication := resp.GetNamespaceInfo(), resp.GetConfig(), resp.GetReplicationConfig()
+	description := &NamespaceDescription{
+		Name:        info.GetName(),
+		ID:          info.GetId(),
+		State:       info.GetState(),
+		Description: info.GetDescription(),
+		OwnerEmail:  info.GetOwnerEmail(),
+		Data:        info.GetData(),
+		Retention:   config.GetWorkflowExecutionRetentionTtl().AsDuration(),
+		HistoryArchival: NamespaceArchivalConfig{
+			State: config.GetHistoryArchivalState(),
+			URI:   config.GetHistoryArchivalUri(),
+		},
+		VisibilityArchival: NamespaceArchivalConfig{
+			State: config.GetVisibilityArchivalState(),
+			URI:   config.GetVisibilityArchivalUri(),
+		},
+		IsGlobalNamespace:            resp.GetIsGlobalNamespace(),
+		ActiveClusterName:            replication.GetActiveClusterName(),
+		SearchAttributes:             searchAttributes,
+		CustomSearchAttributeAliases: config.GetCustomSearchAttributeAliases(),
+	}
+	for _, cluster := range replication.GetClusters() {
+		description.Clusters = append(description.Clusters, cluster.GetClusterName())
+	}
+	return description, nil
+}
+
+// AddSearchAttributes implements Client.AddSearchAttributes.
+func (wc *WorkflowClient) AddSearchAttributes(ctx context.Context, options AddSearchAttributesOptions) error {
+	if len(options.SearchAttributes) == 0 {
+		return errors.New("no search attributes to add")
+	}
+	namespace := options.Namespace
+	if namespace == "" {
+		namespace = wc.namespace
+	}
+	schema, err := wc.ListSearchAttributes(ctx, ListSearchAttributesOptions{Namespace: namespace})
+	if err != nil {
+		return err
+	}
+	missing, err := missingSearchAttributes(schema, options.SearchAttributes)
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	_, err = wc.operatorService.AddSearchAttributes(grpcCtx, &operatorservice.AddSearchAttributesRequest{
+		SearchAttributes: missing,
+		Namespace:        namespace,
+	})
+	var alreadyExists *serviceerror.AlreadyExists
+	if errors.As(err, &alreadyExists) {
+		// Added concurrently, e.g. by another instance of the application. Succeed if the types match.
+		if schema, listErr := wc.ListSearchAttributes(ctx, ListSearchAttributesOptions{Namespace: namespace}); listErr == nil {
+			if missing, listErr = missingSearchAttributes(schema, options.SearchAttributes); listErr == nil && len(missing) == 0 {
+				return nil
+			}
+		}
+	}
+	return wrapSearchAttributesPermissionError(err, "add", namespace)
+}
+
+// ListSearchAttributes implements Client.ListSearchAttributes.
+func (wc *WorkflowClient) ListSearchAttributes(ctx context.Context, options ListSearchAttributesOptions) (*SearchAttributesSchema, error) {
+	if err := wc.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+	namespace := options.Namespace
+	if namespace == "" {
+		namespace = wc.namespace
+	}
+
+	grpcCtx, cancel := newGRPCContext(ctx, defaultGrpcRetryParameters(ctx))
+	defer cancel()
+	resp, err := wc.operatorService.ListSearchAttributes(grpcCtx, &operatorservice.ListSearchAttributesRequest{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, wrapSearchAttributesPermissionError(err, "list", namespace)
+	}
+	return &SearchAttributesSchema{
+		Custom: resp.GetCustomAttributes(),
+		System: resp.GetSystemAttributes(),
+	}, nil
+}
+
+// missingSearchAttributes returns the search attributes that are not in the schema yet, or an error if one of them is
+// already in the schema with a different type.
+func missingSearchAttributes(schema *SearchAttributesSchema, searchAttributes map[string]enumspb.IndexedValueType) (map[string]enumspb.IndexedValueType, error) {
+	missing := make(map[string]enumspb.IndexedValueType, len(searchAttributes))
+	for name, valueType := range searchAttributes {
+		existing, ok := schema.Custom[name]
+		if !ok {
+			existing, ok = schema.System[name]
+		}
+		if !ok {
+			missing[name] = valueType
+		} else if existing != valueType {
+			return nil, fmt.Errorf("search attribute %q already exists with type %v instead of %v", name, existing, valueType)
+		}
+	}
+	return missing, nil
+}
+
+// wrapSearchAttributesPermissionError adds the namespace and operation to a permission denied error, as changing the
+// search attributes schema needs more permissions than using the search attributes.
+func wrapSearchAttributesPermissionError(err error, operation string, namespace string) error {
+	var permissionDenied *serviceerror.PermissionDenied
+	if errors.As(err, &permissionDenied) {
+		return fmt.Errorf("not permitted to %s search attributes of namespace %q, which requires the operator service "+
+			"and an admin role: %w", operation, namespace, err)
+	}
+	return err
 }
 
 // Get capabilities, lazily fetching from server if not already obtained.
@@ -1472,6 +2004,9 @@ func (wc *WorkflowClient) recordWorkerHeartbeat(ctx context.Context, request *wo
 
 // Close client and clean up underlying resources.
 func (wc *WorkflowClient) Close() {
+	if wc.connOwnedByParent {
+		return
+	}
 	// If there's a set of unclosed clients, we have to decrement it and then
 	// set it to a new pointer of max to prevent decrementing on repeated Close
 	// calls to this client. If the count has not reached zero, this close call is
@@ -1642,7 +2177,7 @@ func (workflowRun *workflowRunImpl) GetWithOptions(
 		if rf.Type().Kind() != reflect.Ptr {
 			return errors.New("value parameter is not a pointer")
 		}
-		return workflowRun.dataConverter.FromPayloads(attributes.Result, valuePtr)
+		return workflowRun.getDataConverter(options).FromPayloads(attributes.Result, valuePtr)
 	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_FAILED:
 		attributes := closeEvent.GetWorkflowExecutionFailedEventAttributes()
 		if !options.DisableFollowingRuns && attributes.NewExecutionRunId != "" {
@@ -1651,7 +2186,7 @@ func (workflowRun *workflowRunImpl) GetWithOptions(
 		err = workflowRun.failureConverter.FailureToError(attributes.GetFailure())
 	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_CANCELED:
 		attributes := closeEvent.GetWorkflowExecutionCanceledEventAttributes()
-		details := newEncodedValues(attributes.Details, workflowRun.dataConverter)
+		details := newEncodedValues(attributes.Details, workflowRun.getDataConverter(options))
 		err = NewCanceledError(details)
 	case enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED:
 		err = newTerminatedError()
@@ -1707,6 +2242,14 @@ func (workflowRun *workflowRunImpl) follow(
 	return workflowRun.GetWithOptions(ctx, valuePtr, options)
 }
 
+// getDataConverter returns the data converter of the options, falling back to the one of the run.
+func (workflowRun *workflowRunImpl) getDataConverter(options WorkflowRunGetOptions) converter.DataConverter {
+	if options.DataConverter != nil {
+		return options.DataConverter
+	}
+	return workflowRun.dataConverter
+}
+
 // encodeMemoValue encodes a single memo value. useUserDC controls whether the user's data converter
 // is attempted first. Client-side callers should pass sdkFlagsAllowed[SDKFlagMemoUserDCEncode];
 // workflow-side callers should pass the result of TryUse(SDKFlagMemoUserDCEncode) for replay safety.
@@ -1783,6 +2326,30 @@ func createStartWorkflowInput(
 	}, nil
 }
 
+func convertToPBCompletionCallbacks(callbacks []CompletionCallback) ([]*commonpb.Callback, error) {
+	var result []*commonpb.Callback
+	for _, callback := range callbacks {
+		u, err := url.Parse(callback.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid completion callback URL %q: %w", callback.URL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid completion callback URL %q: must be an absolute http or https URL", callback.URL)
+		}
+		for name := range callback.Header {
+			if name == "" {
+				return nil, fmt.Errorf("invalid completion callback header for URL %q: empty header name", callback.URL)
+			}
+		}
+		result = append(result, &commonpb.Callback{
+			Variant: &commonpb.Callback_Nexus_{
+				Nexus: &commonpb.Callback_Nexus{Url: callback.URL, Header: callback.Header},
+			},
+		})
+	}
+	return result, nil
+}
+
 func (w *workflowClientInterceptor) createStartWorkflowRequest(
 	ctx context.Context,
 	in *ClientExecuteWorkflowInput,
@@ -1793,11 +2360,16 @@ func (w *workflowClientInterceptor) createStartWorkflowRequest(
 		return nil, fmt.Errorf("no workflow ID in options")
 	}
 
+	completionCallbacks, err := convertToPBCompletionCallbacks(in.Options.CompletionCallbacks)
+	if err != nil {
+		return nil, err
+	}
+
 	executionTimeout := in.Options.WorkflowExecutionTimeout
 	runTimeout := in.Options.WorkflowRunTimeout
 	workflowTaskTimeout := in.Options.WorkflowTaskTimeout
 
-	dataConverter := WithContext(ctx, w.client.dataConverter)
+	dataConverter := WithContext(ctx, w.startDataConverter(in.Options))
 	if dataConverter == nil {
 		dataConverter = converter.GetDefaultDataConverter()
 	}
@@ -1823,6 +2395,9 @@ func (w *workflowClientInterceptor) createStartWorkflowRequest(
 	if err != nil {
 		return nil, err
 	}
+	if err := setStartWorkflowHeaders(header, in.Options.Headers, dataConverter); err != nil {
+		return nil, err
+	}
 
 	// run propagators to extract information about tracing and other stuff, store in headers field
 	startRequest := &workflowservice.StartWorkflowExecutionRequest{
@@ -1842,7 +2417,7 @@ func (w *workflowClientInterceptor) createStartWorkflowRequest(
 		Memo:                     memo,
 		SearchAttributes:         searchAttr,
 		Header:                   header,
-		CompletionCallbacks:      in.Options.callbacks,
+		CompletionCallbacks:      slices.Concat(in.Options.callbacks, completionCallbacks),
 		Links:                    in.Options.links,
 		VersioningOverride:       versioningOverrideToProto(in.Options.VersioningOverride),
 		OnConflictOptions:        in.Options.onConflictOptions.ToProto(),
@@ -1891,7 +2466,8 @@ func (w *workflowClientInterceptor) ExecuteWorkflow(
 	response, err := w.client.workflowService.StartWorkflowExecution(grpcCtx, startRequest)
 
 	eagerWorkflowTask := response.GetEagerWorkflowTask()
-	if eagerWorkflowTask != nil && eagerExecutor != nil {
+	eagerStarted := eagerWorkflowTask != nil && eagerExecutor != nil
+	if eagerStarted {
 		eagerExecutor.handleResponse(eagerWorkflowTask)
 	} else if eagerExecutor != nil {
 		eagerExecutor.releaseUnused()
@@ -1904,6 +2480,8 @@ func (w *workflowClientInterceptor) ExecuteWorkflow(
 		return nil, err
 	} else {
 		runID = response.RunId
+		w.client.metricsHandler.WithTags(metrics.WorkflowStartTags(in.WorkflowType, in.Options.TaskQueue, eagerStarted)).
+			Counter(metrics.WorkflowStartCounter).Inc(1)
 	}
 
 	if responseInfo := in.Options.responseInfo; responseInfo != nil {
@@ -1924,16 +2502,24 @@ func (w *workflowClientInterceptor) ExecuteWorkflow(
 		firstRunID:       runID,
 		currentRunID:     &curRunIDCell,
 		iterFn:           iterFn,
-		dataConverter:    w.client.dataConverter,
+		dataConverter:    w.startDataConverter(in.Options),
 		failureConverter: w.client.failureConverter,
 		registry:         w.client.registry,
 	}, nil
 }
 
+// startDataConverter returns the data converter to start a workflow with the given options.
+func (w *workflowClientInterceptor) startDataConverter(options *StartWorkflowOptions) converter.DataConverter {
+	if options.DataConverter != nil {
+		return options.DataConverter
+	}
+	return w.client.dataConverter
+}
+
 func (w *workflowClientInterceptor) UpdateWithStartWorkflow(
 	ctx context.Context,
 	in *ClientUpdateWithStartWorkflowInput,
-) (WorkflowUpdateHandle, error) {
+) (_ WorkflowUpdateHandle, err error) {
 	startOp, ok := in.StartWorkflowOperation.(*withStartWorkflowOperationImpl)
 	if !ok {
 		return nil, fmt.Errorf("%w: startOperation must be created by NewWithStartWorkflowOperation", errInvalidWithStartWorkflowOperation)
@@ -1946,6 +2532,14 @@ func (w *workflowClientInterceptor) UpdateWithStartWorkflow(
 	if err := startOp.markExecuted(); err != nil {
 		return nil, fmt.Errorf("%w: %w", errInvalidWithStartWorkflowOperation, err)
 	}
+	startOpSet := false
+	defer func() {
+		// Get of the start operation must not block if the workflow was not started, e.g. because it was already
+		// running and the WorkflowIDConflictPolicy is FAIL
+		if err != nil && !startOpSet {
+			startOp.set(nil, err)
+		}
+	}()
 	startReq, err := w.createStartWorkflowRequest(ctx, startOp.input)
 	if err != nil {
 		return nil, err
@@ -1972,6 +2566,7 @@ func (w *workflowClientInterceptor) UpdateWithStartWorkflow(
 			enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT, metricsHandler)
 	}
 	onStart := func(startResp *workflowservice.StartWorkflowExecutionResponse) {
+		startOpSet = true
 		runIDCell := util.PopulatedOnceCell(startResp.RunId)
 		startOp.set(&workflowRunImpl{
 			workflowType:     startOp.input.WorkflowType,
@@ -1979,7 +2574,7 @@ func (w *workflowClientInterceptor) UpdateWithStartWorkflow(
 			firstRunID:       startResp.RunId,
 			currentRunID:     &runIDCell,
 			iterFn:           iterFn,
-			dataConverter:    w.client.dataConverter,
+			dataConverter:    w.startDataConverter(startOp.input.Options),
 			failureConverter: w.client.failureConverter,
 			registry:         w.client.registry,
 		}, nil)
@@ -1993,11 +2588,7 @@ func (w *workflowClientInterceptor) UpdateWithStartWorkflow(
 		return nil, err
 	}
 
-	handle, err := w.updateHandleFromResponse(ctx, updateReq.WaitPolicy.LifecycleStage, updateResp)
-	if err != nil {
-		return nil, err
-	}
-	return handle, nil
+	return w.updateHandleFromResponse(ctx, updateReq.WaitPolicy.LifecycleStage, updateResp)
 }
 
 // Perform update-with-start using the MultiOperation API. As with
@@ -2038,7 +2629,7 @@ func (w *workflowClientInterceptor) updateWithStartWorkflow(
 				grpcTimeout(pollUpdateTimeout),
 				grpcLongPoll(true),
 				grpcMetricsHandler(rpcMetricsHandler),
-				defaultGrpcRetryParameters(ctx))
+				w.client.longPollGrpcRetryParameters(ctx))
 			defer cancel()
 
 			multiResp, err := w.client.workflowService.ExecuteMultiOperation(grpcCtx, &multiRequest)
@@ -2173,7 +2764,10 @@ func (w *workflowClientInterceptor) SignalWithStartWorkflow(
 	ctx context.Context,
 	in *ClientSignalWithStartWorkflowInput,
 ) (WorkflowRun, error) {
-	dataConverter := WithContext(ctx, w.client.dataConverter)
+	if len(in.Options.CompletionCallbacks) > 0 {
+		return nil, errors.New("completion callbacks are not supported by SignalWithStartWorkflow")
+	}
+	dataConverter := WithContext(ctx, w.startDataConverter(in.Options))
 	signalInput, err := encodeArg(dataConverter, in.SignalArg)
 	if err != nil {
 		return nil, err
@@ -2204,6 +2798,9 @@ func (w *workflowClientInterceptor) SignalWithStartWorkflow(
 	if err != nil {
 		return nil, err
 	}
+	if err := setStartWorkflowHeaders(header, in.Options.Headers, dataConverter); err != nil {
+		return nil, err
+	}
 
 	signalWithStartRequest := &workflowservice.SignalWithStartWorkflowExecutionRequest{
 		Namespace:                w.client.namespace,
@@ -2263,7 +2860,7 @@ func (w *workflowClientInterceptor) SignalWithStartWorkflow(
 		firstRunID:       response.GetRunId(),
 		currentRunID:     &curRunIDCell,
 		iterFn:           iterFn,
-		dataConverter:    w.client.dataConverter,
+		dataConverter:    w.startDataConverter(in.Options),
 		failureConverter: w.client.failureConverter,
 		registry:         w.client.registry,
 	}, nil
@@ -2446,7 +3043,7 @@ func (w *workflowClientInterceptor) UpdateWorkflow(
 	for {
 		var err error
 		resp, err = func() (*workflowservice.UpdateWorkflowExecutionResponse, error) {
-			grpcCtx, cancel := newGRPCContext(ctx, grpcTimeout(pollUpdateTimeout), grpcLongPoll(true), defaultGrpcRetryParameters(ctx))
+			grpcCtx, cancel := newGRPCContext(ctx, grpcTimeout(pollUpdateTimeout), grpcLongPoll(true), w.client.longPollGrpcRetryParameters(ctx))
 			defer cancel()
 
 			return w.client.workflowService.UpdateWorkflowExecution(grpcCtx, req)
@@ -2567,7 +3164,7 @@ func (w *workflowClientInterceptor) PollWorkflowUpdate(
 		ctx = context.WithValue(
 			ctx,
 			retry.ConfigKey,
-			createDynamicServiceRetryPolicy(ctx).GrpcRetryConfig(),
+			w.client.longPollRetryPolicy(ctx).GrpcRetryConfig(),
 		)
 		resp, err := w.client.workflowService.PollWorkflowExecutionUpdate(ctx, &pollReq)
 		cancel()
@@ -9,12 +9,15 @@ import (
 	"time"
 
 	activitypb "go.temporal.io/api/activity/v1"
+	failurepb "go.temporal.io/api/failure/v1"
 	updatepb "go.temporal.io/api/update/v1"
 	workflowpb "go.temporal.io/api/workflow/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	ilog "go.temporal.io/sdk/internal/log"
 
@@ -24,7 +27,12 @@ import (
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
+	namespacepb "go.temporal.io/api/namespace/v1"
+	"go.temporal.io/api/operatorservice/v1"
+	"go.temporal.io/api/operatorservicemock/v1"
+	replicationpb "go.temporal.io/api/replication/v1"
 	"go.temporal.io/api/serviceerror"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 
@@ -405,6 +413,59 @@ func (s *workflowRunSuite) TestExecuteWorkflow_NoDup_Success() {
 	s.Equal(workflowResult, decodedResult)
 }
 
+func (s *workflowRunSuite) TestExecuteWorkflow_DataConverterOverride() {
+	dc := iconverter.NewTestDataConverter()
+	input := "tenant-input"
+	createResponse := &workflowservice.StartWorkflowExecutionResponse{
+		RunId: runID,
+	}
+	s.workflowServiceClient.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(createResponse, nil).
+		Do(func(_ interface{}, req *workflowservice.StartWorkflowExecutionRequest, _ ...interface{}) {
+			encodedInput, err := dc.ToPayloads(input)
+			s.NoError(err)
+			s.Equal(encodedInput, req.Input)
+		}).Times(1)
+
+	workflowResult := "tenant-result"
+	encodedResult, _ := encodeArg(dc, workflowResult)
+	getRequest := getGetWorkflowExecutionHistoryRequest(enumspb.HISTORY_EVENT_FILTER_TYPE_CLOSE_EVENT)
+	getResponse := &workflowservice.GetWorkflowExecutionHistoryResponse{
+		History: &historypb.History{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{
+						Result: encodedResult,
+					}},
+				},
+			},
+		},
+	}
+	s.workflowServiceClient.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), getRequest, gomock.Any()).Return(getResponse, nil).Times(2)
+
+	workflowRun, err := s.workflowClient.ExecuteWorkflow(
+		context.Background(),
+		StartWorkflowOptions{
+			ID:                       workflowID,
+			TaskQueue:                taskqueue,
+			WorkflowExecutionTimeout: timeoutInSeconds * time.Second,
+			WorkflowTaskTimeout:      timeoutInSeconds * time.Second,
+			DataConverter:            dc,
+		}, workflowType, input,
+	)
+	s.NoError(err)
+	s.Equal(converter.GetDefaultDataConverter(), s.workflowClient.(*WorkflowClient).dataConverter)
+	var decodedResult string
+	s.NoError(workflowRun.Get(context.Background(), &decodedResult))
+	s.Equal(workflowResult, decodedResult)
+
+	// A run obtained separately needs the converter passed explicitly
+	decodedResult = ""
+	workflowRun = s.workflowClient.GetWorkflow(context.Background(), workflowID, runID)
+	s.NoError(workflowRun.GetWithOptions(context.Background(), &decodedResult, WorkflowRunGetOptions{DataConverter: dc}))
+	s.Equal(workflowResult, decodedResult)
+}
+
 func (s *workflowRunSuite) TestExecuteWorkflow_NoDup_RawHistory_Success() {
 	createResponse := &workflowservice.StartWorkflowExecutionResponse{
 		RunId: runID,
@@ -1265,6 +1326,50 @@ func (s *workflowRunSuite) TestExecuteWorkflowWithUpdate_Errors() {
 	}
 }
 
+func (s *workflowRunSuite) TestExecuteWorkflowWithUpdate_AlreadyStarted() {
+	s.workflowServiceClient.EXPECT().
+		ExecuteMultiOperation(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewMultiOperationExecution("MultiOperation failed", []error{
+			serviceerror.NewWorkflowExecutionAlreadyStarted("already started", "", runID),
+			serviceerror.NewMultiOperationAborted("aborted Update"),
+		})).Times(1)
+
+	startOp := s.workflowClient.NewWithStartWorkflowOperation(
+		StartWorkflowOptions{
+			ID:                       workflowID,
+			WorkflowIDConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL,
+			TaskQueue:                taskqueue,
+		}, workflowType,
+	)
+	_, err := s.workflowClient.UpdateWithStartWorkflow(
+		context.Background(),
+		UpdateWithStartWorkflowOptions{
+			UpdateOptions: UpdateWorkflowOptions{
+				UpdateName:   "update",
+				WaitForStage: WorkflowUpdateStageCompleted,
+			},
+			StartWorkflowOperation: startOp,
+		},
+	)
+	var alreadyStartedErr *serviceerror.WorkflowExecutionAlreadyStarted
+	s.ErrorAs(err, &alreadyStartedErr)
+	s.Equal(runID, alreadyStartedErr.RunId)
+
+	// Get of the start operation returns the error instead of blocking
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	run, getErr := startOp.Get(ctx)
+	s.Nil(run)
+	s.Equal(err, getErr)
+
+	// So does Get of an invalid start operation
+	startOp = s.workflowClient.NewWithStartWorkflowOperation(
+		StartWorkflowOptions{ID: workflowID, TaskQueue: taskqueue}, workflowType,
+	)
+	_, getErr = startOp.Get(ctx)
+	s.ErrorContains(getErr, "WorkflowIDConflictPolicy must be set")
+}
+
 func (s *workflowRunSuite) TestExecuteWorkflowWithUpdate_ServerResponseCountMismatch() {
 	s.workflowServiceClient.EXPECT().
 		ExecuteMultiOperation(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -1607,12 +1712,15 @@ func (s *workflowClientTestSuite) TestEagerStartWorkflowNoWorker() {
 	}
 	s.service.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(createResponse, nil)
 
+	metricsHandler := metrics.NewCapturingHandler()
+	client.metricsHandler = metricsHandler
 	resp, err := client.ExecuteWorkflow(context.Background(), options, f1, []byte("test"))
 	s.Equal(converter.GetDefaultDataConverter(), client.dataConverter)
 	s.Nil(err)
 	s.Equal(createResponse.GetRunId(), resp.GetRunID())
 	s.False(processTask)
 	s.False(eagerMock.releaseCalled)
+	s.Equal(map[string]int64{"normal": 1}, workflowStartCounts(metricsHandler))
 }
 
 func (s *workflowClientTestSuite) TestEagerStartWorkflow() {
@@ -1645,6 +1753,8 @@ func (s *workflowClientTestSuite) TestEagerStartWorkflow() {
 	}
 	s.service.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(createResponse, nil)
 
+	metricsHandler := metrics.NewCapturingHandler()
+	client.metricsHandler = metricsHandler
 	resp, err := client.ExecuteWorkflow(context.Background(), options, f1, []byte("test"))
 	s.Equal(converter.GetDefaultDataConverter(), client.dataConverter)
 	s.Nil(err)
@@ -1653,6 +1763,17 @@ func (s *workflowClientTestSuite) TestEagerStartWorkflow() {
 	// Release will not have been called, since there is no real processor to call it
 	// when the task is done.
 	s.False(eagerMock.releaseCalled)
+	s.Equal(map[string]int64{"eager": 1}, workflowStartCounts(metricsHandler))
+}
+
+func workflowStartCounts(handler *metrics.CapturingHandler) map[string]int64 {
+	counts := map[string]int64{}
+	for _, counter := range handler.Counters() {
+		if counter.Name == metrics.WorkflowStartCounter {
+			counts[counter.Tags[metrics.StartTypeTagName]] += counter.Value()
+		}
+	}
+	return counts
 }
 
 func (s *workflowClientTestSuite) TestEagerStartWorkflowStartRequestFail() {
@@ -1791,6 +1912,27 @@ func (s *workflowClientTestSuite) TestStartWorkflowWithMemoAndSearchAttr() {
 	_, _ = s.client.ExecuteWorkflow(context.Background(), options, wf)
 }
 
+func (s *workflowClientTestSuite) TestStartWorkflowWithHeaders() {
+	options := StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: taskqueue,
+		Headers:   map[string]interface{}{"tenant": "acme"},
+	}
+	wf := func(ctx Context) string {
+		panic("this is just a stub")
+	}
+
+	s.service.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.StartWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.StartWorkflowExecutionRequest, _ ...interface{}) {
+			var tenant string
+			s.NoError(converter.GetDefaultDataConverter().FromPayload(req.Header.Fields[startWorkflowHeaderPrefix+"tenant"], &tenant))
+			s.Equal("acme", tenant)
+		})
+	_, err := s.client.ExecuteWorkflow(context.Background(), options, wf)
+	s.NoError(err)
+}
+
 func (s *workflowClientTestSuite) TestSignalWithStartWorkflowWithMemoAndSearchAttr() {
 	memo := map[string]interface{}{
 		"testMemo": "memo value",
@@ -1863,6 +2005,69 @@ func (s *workflowClientTestSuite) TestStartWorkflowWithVersioningOverride() {
 	_, _ = s.client.ExecuteWorkflow(context.Background(), options, wf)
 }
 
+func (s *workflowClientTestSuite) TestStartWorkflowWithCompletionCallbacks() {
+	options := StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: taskqueue,
+		CompletionCallbacks: []CompletionCallback{
+			{URL: "https://callback.example.com/complete", Header: map[string]string{"tenant": "t1"}},
+		},
+	}
+	wf := func(ctx Context) string {
+		panic("this is just a stub")
+	}
+
+	s.service.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.StartWorkflowExecutionResponse{}, nil).
+		Do(func(_ interface{}, req *workflowservice.StartWorkflowExecutionRequest, _ ...interface{}) {
+			s.Len(req.CompletionCallbacks, 1)
+			s.Equal("https://callback.example.com/complete", req.CompletionCallbacks[0].GetNexus().GetUrl())
+			s.Equal(map[string]string{"tenant": "t1"}, req.CompletionCallbacks[0].GetNexus().GetHeader())
+		})
+	_, err := s.client.ExecuteWorkflow(context.Background(), options, wf)
+	s.NoError(err)
+
+	// Invalid callbacks fail before calling the server
+	options.CompletionCallbacks = []CompletionCallback{{URL: "/complete"}}
+	_, err = s.client.ExecuteWorkflow(context.Background(), options, wf)
+	s.ErrorContains(err, "must be an absolute http or https URL")
+	options.CompletionCallbacks = []CompletionCallback{{URL: "https://callback.example.com", Header: map[string]string{"": "v"}}}
+	_, err = s.client.ExecuteWorkflow(context.Background(), options, wf)
+	s.ErrorContains(err, "empty header name")
+
+	_, err = s.client.SignalWithStartWorkflow(context.Background(), workflowID, "signal", nil, options, wf)
+	s.ErrorContains(err, "not supported by SignalWithStartWorkflow")
+}
+
+func (s *workflowClientTestSuite) TestDecodeMemo() {
+	dc := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(),
+		converter.NewZlibCodec(converter.ZlibCodecOptions{AlwaysEncode: true}))
+	client := NewServiceClient(s.service, nil, ClientOptions{DataConverter: dc})
+	memo, err := getWorkflowMemo(map[string]interface{}{"owner": "alice", "priority": 3}, dc, true)
+	s.NoError(err)
+
+	owner, ok, err := DecodeMemo[string](client, memo, "owner")
+	s.NoError(err)
+	s.True(ok)
+	s.Equal("alice", owner)
+
+	_, ok, err = DecodeMemo[string](client, memo, "missing")
+	s.NoError(err)
+	s.False(ok)
+
+	_, ok, err = DecodeMemo[string](client, memo, "priority")
+	s.True(ok)
+	s.ErrorContains(err, `unable to decode memo "priority"`)
+
+	// Decoding with another converter than the one the memo was encoded with fails
+	_, _, err = DecodeMemo[string](s.client, memo, "owner")
+	s.Error(err)
+
+	values, err := DecodeMemoValues[interface{}](client, memo)
+	s.NoError(err)
+	s.Equal(map[string]interface{}{"owner": "alice", "priority": float64(3)}, values)
+}
+
 func (s *workflowClientTestSuite) TestSignalWithStartWorkflowWithVersioningOverride() {
 	versioningOverride := &PinnedVersioningOverride{
 		Version: WorkerDeploymentVersion{
@@ -2154,6 +2359,185 @@ func (s *workflowClientTestSuite) TestScanWorkflow() {
 	s.IsType(&serviceerror.InvalidArgument{}, err)
 }
 
+func (s *workflowClientTestSuite) TestForceNewWorkflowTask() {
+	s.service.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.SignalWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.SignalWorkflowExecutionResponse, error) {
+			s.Equal(forceWorkflowTaskSignalName, req.GetSignalName())
+			s.Equal(workflowID, req.GetWorkflowExecution().GetWorkflowId())
+			return &workflowservice.SignalWorkflowExecutionResponse{}, nil
+		})
+	s.NoError(s.client.ForceNewWorkflowTask(context.Background(), workflowID, runID))
+
+	// Closed workflow
+	notFound := serviceerror.NewNotFound("workflow execution already completed")
+	s.service.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, notFound).Times(2)
+	s.service.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED},
+		}, nil)
+	s.NoError(s.client.ForceNewWorkflowTask(context.Background(), workflowID, runID))
+
+	// Missing workflow
+	s.service.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewNotFound("workflow not found"))
+	s.Equal(notFound, s.client.ForceNewWorkflowTask(context.Background(), workflowID, runID))
+}
+
+func (s *workflowClientTestSuite) TestAddSearchAttributes() {
+	operatorService := operatorservicemock.NewMockOperatorServiceClient(s.mockCtrl)
+	s.client.(*WorkflowClient).operatorService = operatorService
+	listResponse := &operatorservice.ListSearchAttributesResponse{
+		CustomAttributes: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+		SystemAttributes: map[string]enumspb.IndexedValueType{"WorkflowType": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+	}
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(listResponse, nil).Times(4)
+
+	// Only missing search attributes are added
+	operatorService.EXPECT().AddSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *operatorservice.AddSearchAttributesRequest, _ ...interface{}) (*operatorservice.AddSearchAttributesResponse, error) {
+			s.Equal("other-namespace", req.GetNamespace())
+			s.Equal(map[string]enumspb.IndexedValueType{"OrderCount": enumspb.INDEXED_VALUE_TYPE_INT}, req.GetSearchAttributes())
+			return &operatorservice.AddSearchAttributesResponse{}, nil
+		})
+	s.NoError(s.client.AddSearchAttributes(context.Background(), AddSearchAttributesOptions{
+		Namespace: "other-namespace",
+		SearchAttributes: map[string]enumspb.IndexedValueType{
+			"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD,
+			"OrderCount": enumspb.INDEXED_VALUE_TYPE_INT,
+		},
+	}))
+
+	// Nothing to add
+	s.NoError(s.client.AddSearchAttributes(context.Background(), AddSearchAttributesOptions{
+		SearchAttributes: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+	}))
+
+	// Existing search attribute with a different type
+	err := s.client.AddSearchAttributes(context.Background(), AddSearchAttributesOptions{
+		SearchAttributes: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_TEXT},
+	})
+	s.ErrorContains(err, `search attribute "CustomerId" already exists`)
+
+	// Permission denied
+	permissionDenied := serviceerror.NewPermissionDenied("request unauthorized", "")
+	operatorService.EXPECT().AddSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, permissionDenied)
+	err = s.client.AddSearchAttributes(context.Background(), AddSearchAttributesOptions{
+		SearchAttributes: map[string]enumspb.IndexedValueType{"OrderCount": enumspb.INDEXED_VALUE_TYPE_INT},
+	})
+	s.ErrorIs(err, permissionDenied)
+	s.ErrorContains(err, "not permitted to add search attributes")
+}
+
+func (s *workflowClientTestSuite) TestListSearchAttributes() {
+	operatorService := operatorservicemock.NewMockOperatorServiceClient(s.mockCtrl)
+	s.client.(*WorkflowClient).operatorService = operatorService
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *operatorservice.ListSearchAttributesRequest, _ ...interface{}) (*operatorservice.ListSearchAttributesResponse, error) {
+			s.Equal(DefaultNamespace, req.GetNamespace())
+			return &operatorservice.ListSearchAttributesResponse{
+				CustomAttributes: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+				SystemAttributes: map[string]enumspb.IndexedValueType{"WorkflowType": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+			}, nil
+		})
+	schema, err := s.client.ListSearchAttributes(context.Background(), ListSearchAttributesOptions{})
+	s.NoError(err)
+	s.Equal(&SearchAttributesSchema{
+		Custom: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+		System: map[string]enumspb.IndexedValueType{"WorkflowType": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+	}, schema)
+}
+
+func (s *workflowClientTestSuite) TestDescribeNamespace() {
+	operatorService := operatorservicemock.NewMockOperatorServiceClient(s.mockCtrl)
+	s.client.(*WorkflowClient).operatorService = operatorService
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.DescribeNamespaceRequest, _ ...interface{}) (*workflowservice.DescribeNamespaceResponse, error) {
+			s.Equal(DefaultNamespace, req.GetNamespace())
+			return &workflowservice.DescribeNamespaceResponse{
+				NamespaceInfo: &namespacepb.NamespaceInfo{
+					Name:  DefaultNamespace,
+					Id:    "namespace-id",
+					State: enumspb.NAMESPACE_STATE_REGISTERED,
+				},
+				Config: &namespacepb.NamespaceConfig{
+					WorkflowExecutionRetentionTtl: durationpb.New(72 * time.Hour),
+					HistoryArchivalState:          enumspb.ARCHIVAL_STATE_ENABLED,
+					HistoryArchivalUri:            "s3://archive",
+					VisibilityArchivalState:       enumspb.ARCHIVAL_STATE_DISABLED,
+				},
+				ReplicationConfig: &replicationpb.NamespaceReplicationConfig{
+					ActiveClusterName: "active",
+					Clusters: []*replicationpb.ClusterReplicationConfig{
+						{ClusterName: "active"},
+						{ClusterName: "standby"},
+					},
+				},
+				IsGlobalNamespace: true,
+			}, nil
+		})
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&operatorservice.ListSearchAttributesResponse{
+			CustomAttributes: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+		}, nil)
+	description, err := s.client.DescribeNamespace(context.Background(), "")
+	s.NoError(err)
+	s.Equal(&NamespaceDescription{
+		Name:               DefaultNamespace,
+		ID:                 "namespace-id",
+		State:              enumspb.NAMESPACE_STATE_REGISTERED,
+		Retention:          72 * time.Hour,
+		HistoryArchival:    NamespaceArchivalConfig{State: enumspb.ARCHIVAL_STATE_ENABLED, URI: "s3://archive"},
+		VisibilityArchival: NamespaceArchivalConfig{State: enumspb.ARCHIVAL_STATE_DISABLED},
+		IsGlobalNamespace:  true,
+		ActiveClusterName:  "active",
+		Clusters:           []string{"active", "standby"},
+		SearchAttributes: &SearchAttributesSchema{
+			Custom: map[string]enumspb.IndexedValueType{"CustomerId": enumspb.INDEXED_VALUE_TYPE_KEYWORD},
+		},
+	}, description)
+
+	// Search attributes are left empty if they cannot be listed
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.DescribeNamespaceResponse{
+			NamespaceInfo: &namespacepb.NamespaceInfo{Name: "restricted"},
+		}, nil)
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewPermissionDenied("operator service not allowed", ""))
+	description, err = s.client.DescribeNamespace(context.Background(), "restricted")
+	s.NoError(err)
+	s.Equal("restricted", description.Name)
+	s.Nil(description.SearchAttributes)
+
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.DescribeNamespaceResponse{
+			NamespaceInfo: &namespacepb.NamespaceInfo{Name: "old-server"},
+		}, nil)
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewUnimplemented("unknown service"))
+	description, err = s.client.DescribeNamespace(context.Background(), "old-server")
+	s.NoError(err)
+	s.Nil(description.SearchAttributes)
+
+	// Other errors listing search attributes are returned
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.DescribeNamespaceResponse{
+			NamespaceInfo: &namespacepb.NamespaceInfo{Name: "invalid"},
+		}, nil)
+	operatorService.EXPECT().ListSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewInvalidArgument("bad namespace"))
+	_, err = s.client.DescribeNamespace(context.Background(), "invalid")
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+
+	// Missing namespace
+	s.service.EXPECT().DescribeNamespace(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewNotFound("namespace not found"))
+	_, err = s.client.DescribeNamespace(context.Background(), "missing")
+	var namespaceNotFound *serviceerror.NamespaceNotFound
+	s.ErrorAs(err, &namespaceNotFound)
+	s.Equal("missing", namespaceNotFound.Namespace)
+}
+
 func (s *workflowClientTestSuite) TestCountWorkflow() {
 	request := &workflowservice.CountWorkflowExecutionsRequest{}
 	response := &workflowservice.CountWorkflowExecutionsResponse{}
@@ -2174,6 +2558,137 @@ func (s *workflowClientTestSuite) TestCountWorkflow() {
 	s.IsType(&serviceerror.InvalidArgument{}, err)
 }
 
+func (s *workflowClientTestSuite) TestCountWorkflowWithOptions() {
+	query := "WorkflowType = 'foo'"
+	s.service.EXPECT().CountWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.CountWorkflowExecutionsRequest, _ ...interface{}) (*workflowservice.CountWorkflowExecutionsResponse, error) {
+			s.Equal(query, req.GetQuery())
+			return &workflowservice.CountWorkflowExecutionsResponse{Count: 10}, nil
+		}).Times(2)
+
+	// No sampling
+	result, err := s.client.CountWorkflowWithOptions(context.Background(), CountWorkflowOptions{Query: query})
+	s.NoError(err)
+	s.Equal(int64(10), result.Count)
+	s.Equal(query, result.Query)
+	s.Zero(result.Sampled)
+
+	// Sampling
+	s.service.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.ListWorkflowExecutionsRequest, _ ...interface{}) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+			s.Equal(int32(2), req.GetPageSize())
+			return &workflowservice.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "failed", RunId: runID}},
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "ok", RunId: runID}},
+				},
+			}, nil
+		})
+	s.service.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.GetWorkflowExecutionHistoryRequest, _ ...interface{}) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+			var events []*historypb.HistoryEvent
+			if req.GetExecution().GetWorkflowId() == "failed" {
+				events = append(events, &historypb.HistoryEvent{
+					EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_UPDATE_COMPLETED,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionUpdateCompletedEventAttributes{
+						WorkflowExecutionUpdateCompletedEventAttributes: &historypb.WorkflowExecutionUpdateCompletedEventAttributes{
+							Outcome: &updatepb.Outcome{Value: &updatepb.Outcome_Failure{Failure: &failurepb.Failure{Message: "boom"}}},
+						},
+					},
+				})
+			}
+			return &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: events}}, nil
+		}).Times(2)
+	result, err = s.client.CountWorkflowWithOptions(context.Background(), CountWorkflowOptions{Query: query, SampleFailedUpdates: 2})
+	s.NoError(err)
+	s.Equal(int64(10), result.Count)
+	s.Equal(2, result.Sampled)
+	s.Equal(1, result.SampledWithFailedUpdate)
+}
+
+func (s *workflowClientTestSuite) TestListStuckWorkflows() {
+	scheduledTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.service.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.ListWorkflowExecutionsRequest, _ ...interface{}) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+			s.Equal("ExecutionStatus = 'Running' AND (WorkflowType = 'foo')", req.GetQuery())
+			return &workflowservice.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "stuck", RunId: runID}, Type: &commonpb.WorkflowType{Name: "foo"}},
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "healthy", RunId: runID}, Type: &commonpb.WorkflowType{Name: "foo"}},
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "deleted", RunId: runID}, Type: &commonpb.WorkflowType{Name: "foo"}},
+				},
+			}, nil
+		})
+	s.service.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.DescribeWorkflowExecutionRequest, _ ...interface{}) (*workflowservice.DescribeWorkflowExecutionResponse, error) {
+			attempt := int32(1)
+			switch req.GetExecution().GetWorkflowId() {
+			case "deleted":
+				return nil, serviceerror.NewNotFound("not found")
+			case "stuck":
+				attempt = 5
+			}
+			return &workflowservice.DescribeWorkflowExecutionResponse{
+				WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING},
+				PendingWorkflowTask: &workflowpb.PendingWorkflowTaskInfo{
+					Attempt:               attempt,
+					OriginalScheduledTime: timestamppb.New(scheduledTime),
+				},
+			}, nil
+		}).Times(3)
+	s.service.EXPECT().GetWorkflowExecutionHistory(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.GetWorkflowExecutionHistoryRequest, _ ...interface{}) (*workflowservice.GetWorkflowExecutionHistoryResponse, error) {
+			s.Equal("stuck", req.GetExecution().GetWorkflowId())
+			return &workflowservice.GetWorkflowExecutionHistoryResponse{History: &historypb.History{Events: []*historypb.HistoryEvent{
+				{
+					EventType: enumspb.EVENT_TYPE_WORKFLOW_TASK_FAILED,
+					Attributes: &historypb.HistoryEvent_WorkflowTaskFailedEventAttributes{
+						WorkflowTaskFailedEventAttributes: &historypb.WorkflowTaskFailedEventAttributes{
+							Cause:   enumspb.WORKFLOW_TASK_FAILED_CAUSE_WORKFLOW_WORKER_UNHANDLED_FAILURE,
+							Failure: &failurepb.Failure{Message: "nondeterminism"},
+						},
+					},
+				},
+			}}}, nil
+		})
+
+	stuck, err := s.client.ListStuckWorkflows(context.Background(), ListStuckWorkflowsOptions{Query: "WorkflowType = 'foo'"})
+	s.NoError(err)
+	s.Equal([]StuckWorkflow{{
+		WorkflowID:                "stuck",
+		RunID:                     runID,
+		WorkflowType:              "foo",
+		WorkflowTaskAttempt:       5,
+		WorkflowTaskScheduledTime: scheduledTime,
+		LastFailureMessage:        "nondeterminism",
+		LastFailureCause:          enumspb.WORKFLOW_TASK_FAILED_CAUSE_WORKFLOW_WORKER_UNHANDLED_FAILURE,
+	}}, stuck)
+}
+
+func (s *workflowClientTestSuite) TestListStuckWorkflowsMaxWorkflows() {
+	s.service.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.ListWorkflowExecutionsRequest, _ ...interface{}) (*workflowservice.ListWorkflowExecutionsResponse, error) {
+			s.Equal("ExecutionStatus = 'Running'", req.GetQuery())
+			s.Equal(int32(1), req.GetPageSize())
+			return &workflowservice.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "first", RunId: runID}},
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: "second", RunId: runID}},
+				},
+				NextPageToken: []byte("next"),
+			}, nil
+		})
+	s.service.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&workflowservice.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING},
+			PendingWorkflowTask:   &workflowpb.PendingWorkflowTaskInfo{Attempt: 2},
+		}, nil)
+
+	stuck, err := s.client.ListStuckWorkflows(context.Background(), ListStuckWorkflowsOptions{MaxWorkflows: 1})
+	s.NoError(err)
+	s.Empty(stuck)
+}
+
 func (s *workflowClientTestSuite) TestGetSearchAttributes() {
 	response := &workflowservice.GetSearchAttributesResponse{}
 	s.service.EXPECT().GetSearchAttributes(gomock.Any(), gomock.Any(), gomock.Any()).Return(response, nil)
@@ -2690,3 +3205,86 @@ func (s *workflowClientTestSuite) TestPollActivityResultUsesPerIterationContext(
 	s.NoError(err)
 	s.NotNil(out.Result)
 }
+
+func TestLongPollRetryOptions(t *testing.T) {
+	policy := createLongPollRetryPolicy(context.Background(), &LongPollRetryOptions{
+		InitialInterval:    time.Second,
+		MaximumInterval:    2 * time.Second,
+		ExpirationInterval: 10 * time.Second,
+	})
+	require.LessOrEqual(t, policy.ComputeNextDelay(0, 5), 2*time.Second)
+	require.GreaterOrEqual(t, policy.ComputeNextDelay(0, 1), 800*time.Millisecond)
+	require.Less(t, policy.ComputeNextDelay(11*time.Second, 1), time.Duration(0))
+
+	for _, options := range []LongPollRetryOptions{
+		{InitialInterval: -time.Second},
+		{BackoffCoefficient: 0.5},
+		{InitialInterval: time.Second, MaximumInterval: time.Millisecond},
+	} {
+		_, err := NewClient(context.Background(), ClientOptions{LongPollRetryOptions: &options})
+		require.ErrorContains(t, err, "LongPollRetryOptions")
+	}
+}
+
+func TestClientWithNamespace(t *testing.T) {
+	server, err := startTestGRPCServer()
+	require.NoError(t, err)
+	defer server.Stop()
+	dataConverter := converter.NewCompositeDataConverter(converter.NewJSONPayloadConverter())
+	client, err := DialClient(context.Background(), ClientOptions{
+		HostPort:      server.addr,
+		Namespace:     "ns1",
+		DataConverter: dataConverter,
+	})
+	require.NoError(t, err)
+	workflowClient := client.(*WorkflowClient)
+
+	client2, err := ClientWithNamespace(client, "ns2")
+	require.NoError(t, err)
+	workflowClient2 := client2.(*WorkflowClient)
+	require.Equal(t, "ns1", workflowClient.namespace)
+	require.Equal(t, "ns2", workflowClient2.namespace)
+	require.Same(t, workflowClient.conn, workflowClient2.conn)
+	require.Equal(t, dataConverter, workflowClient2.dataConverter)
+	require.EqualValues(t, 1, atomic.LoadInt32(workflowClient.unclosedClients))
+
+	// Requests use the new namespace
+	require.NoError(t, client2.SignalWorkflow(context.Background(), "wid", "", "signal", nil))
+	require.Equal(t, "ns2", server.lastSignalWorkflowExecutionRequest.GetNamespace())
+
+	// Closing the namespace client doesn't close the connection, closing the existing one does
+	client2.Close()
+	require.Less(t, workflowClient.conn.GetState(), connectivity.Shutdown)
+	client.Close()
+	require.Equal(t, connectivity.Shutdown, workflowClient2.conn.GetState())
+
+	_, err = ClientWithNamespace(client, "")
+	require.Error(t, err)
+}
+
+func (s *workflowClientTestSuite) TestGetWorkerTaskReachability_DefaultBuildIDs() {
+	s.service.EXPECT().GetWorkerBuildIdCompatibility(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.GetWorkerBuildIdCompatibilityRequest, _ ...interface{}) (*workflowservice.GetWorkerBuildIdCompatibilityResponse, error) {
+			s.EqualValues(1, req.GetMaxSets())
+			if req.GetTaskQueue() == "unversioned" {
+				return &workflowservice.GetWorkerBuildIdCompatibilityResponse{}, nil
+			}
+			return &workflowservice.GetWorkerBuildIdCompatibilityResponse{
+				MajorVersionSets: []*taskqueuepb.CompatibleVersionSet{{BuildIds: []string{"1.0", "1.1"}}},
+			}, nil
+		}).Times(3)
+	s.service.EXPECT().GetWorkerTaskReachability(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *workflowservice.GetWorkerTaskReachabilityRequest, _ ...interface{}) (*workflowservice.GetWorkerTaskReachabilityResponse, error) {
+			s.Equal([]string{"1.1", UnversionedBuildID}, req.GetBuildIds())
+			s.Equal([]string{"tq1", "unversioned", "tq2"}, req.GetTaskQueues())
+			return &workflowservice.GetWorkerTaskReachabilityResponse{}, nil
+		})
+
+	_, err := s.client.GetWorkerTaskReachability(context.Background(), &GetWorkerTaskReachabilityOptions{
+		TaskQueues: []string{"tq1", "unversioned", "tq2"},
+	})
+	s.NoError(err)
+
+	_, err = s.client.GetWorkerTaskReachability(context.Background(), &GetWorkerTaskReachabilityOptions{})
+	s.Error(err)
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+
+	commandpb "go.temporal.io/api/command/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"google.golang.org/protobuf/proto"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+	"go.temporal.io/sdk/log"
+)
+
+// workflowShadowReplayer checks a sample of completed workflow tasks for non-determinism by replaying the
+// workflow from the start of its history on a fresh state and comparing the commands produced for the last
+// workflow task with the ones the worker completed the task with.
+type workflowShadowReplayer struct {
+	params         workerExecutionParameters
+	registry       *registry
+	service        workflowservice.WorkflowServiceClient
+	sampleRate     float64
+	failOnMismatch bool
+	logger         log.Logger
+	metricsHandler metrics.Handler
+}
+
+func newWorkflowShadowReplayer(
+	params workerExecutionParameters,
+	registry *registry,
+	service workflowservice.WorkflowServiceClient,
+) *workflowShadowReplayer {
+	r := &workflowShadowReplayer{
+		registry:       registry,
+		service:        service,
+		sampleRate:     params.ShadowReplaySampleRate,
+		failOnMismatch: params.FailWorkflowTaskOnShadowReplayMismatch,
+		logger:         params.Logger,
+		metricsHandler: params.MetricsHandler,
+	}
+	// The replay must not share cached state with the worker, and the workflow code of the last task must not
+	// emit logs, metrics or eager activities a second time.
+	params.cache = newWorkerCache(&sharedWorkerCache{}, &sync.Mutex{}, 0)
+	params.Logger = ilog.NewNopLogger()
+	params.MetricsHandler = metrics.NopHandler
+	params.EnableLoggingInReplay = false
+	params.eagerActivityExecutor = nil
+	params.OnSlowReplay = nil
+	params.OnWorkflowPanic = nil
+	params.SlowReplayThreshold = 0
+	params.ReportContinueAsNew = false
+	r.params = params
+	return r
+}
+
+// check shadow replays the task if it is sampled. It returns a non-determinism error to fail the task with if the
+// commands do not match and FailWorkflowTaskOnShadowReplayMismatch is set, nil otherwise.
+func (r *workflowShadowReplayer) check(
+	task *workflowservice.PollWorkflowTaskQueueResponse,
+	completion *workflowTaskCompletion,
+) error {
+	if completion == nil || rand.Float64() >= r.sampleRate {
+		return nil
+	}
+	completed, ok := completion.rawRequest.(*workflowservice.RespondWorkflowTaskCompletedRequest)
+	if !ok {
+		return nil
+	}
+	// Local activities are not executed by the shadow replay, so the commands that follow them can not be compared.
+	for _, command := range completed.GetCommands() {
+		if command.GetRecordMarkerCommandAttributes().GetMarkerName() == localActivityMarkerName {
+			return nil
+		}
+	}
+
+	replayed, err := r.replay(task)
+	if err != nil {
+		if _, ok := err.(historyMismatchError); !ok {
+			r.logger.Warn("Unable to shadow replay workflow task.",
+				tagWorkflowType, task.WorkflowType.GetName(),
+				tagWorkflowID, task.WorkflowExecution.GetWorkflowId(),
+				tagRunID, task.WorkflowExecution.GetRunId(),
+				tagError, err)
+			return nil
+		}
+	} else {
+		err = compareShadowReplayCommands(completed.GetCommands(), replayed)
+	}
+	if err == nil {
+		return nil
+	}
+
+	r.logger.Warn("Shadow replay of workflow task does not match its completion.",
+		tagWorkflowType, task.WorkflowType.GetName(),
+		tagWorkflowID, task.WorkflowExecution.GetWorkflowId(),
+		tagRunID, task.WorkflowExecution.GetRunId(),
+		tagAttempt, task.Attempt,
+		tagError, err)
+	r.metricsHandler.WithTags(metrics.WorkflowTags(task.WorkflowType.GetName())).
+		Counter(metrics.WorkflowTaskShadowReplayMismatchCounter).Inc(1)
+	if r.failOnMismatch {
+		return err
+	}
+	return nil
+}
+
+// replay processes the task on a fresh state, rebuilt from the full history of the workflow up to the task, and
+// returns the commands it completes with. A non-determinism detected while replaying is returned as a
+// historyMismatchError.
+func (r *workflowShadowReplayer) replay(task *workflowservice.PollWorkflowTaskQueueResponse) ([]*commandpb.Command, error) {
+	shadowTask := proto.Clone(task).(*workflowservice.PollWorkflowTaskQueueResponse)
+	shadowTask.TaskToken = []byte("ShadowReplayTaskToken")
+	iterator := &historyIteratorImpl{
+		execution:      shadowTask.WorkflowExecution,
+		nextPageToken:  shadowTask.NextPageToken,
+		namespace:      r.params.Namespace,
+		service:        r.service,
+		maxEventID:     shadowTask.GetStartedEventId(),
+		metricsHandler: metrics.NopHandler,
+		taskQueue:      r.params.TaskQueue,
+	}
+
+	taskHandler := newWorkflowTaskHandler(r.params, nil, r.registry)
+	wfctx, err := taskHandler.GetOrCreateWorkflowContext(shadowTask, iterator)
+	if err != nil {
+		return nil, err
+	}
+	defer wfctx.Unlock(err)
+	var resp *workflowTaskCompletion
+	resp, err = taskHandler.ProcessWorkflowTask(&workflowTask{task: shadowTask, historyIterator: iterator}, wfctx, nil)
+	if err != nil {
+		return nil, historyMismatchErrorf("shadow replay failed: %v", err)
+	}
+	switch req := resp.rawRequest.(type) {
+	case *workflowservice.RespondWorkflowTaskCompletedRequest:
+		return req.GetCommands(), nil
+	case *workflowservice.RespondWorkflowTaskFailedRequest:
+		return nil, historyMismatchErrorf("shadow replay failed the workflow task: %v", req.GetFailure().GetMessage())
+	default:
+		return nil, fmt.Errorf("unexpected shadow replay response %T", req)
+	}
+}
+
+// compareShadowReplayCommands compares commands by type and the attributes that identify them, but not by their
+// inputs, which are allowed to differ, e.g. in side effect markers.
+func compareShadowReplayCommands(completed, replayed []*commandpb.Command) error {
+	completedSummaries := summarizeShadowReplayCommands(completed)
+	replayedSummaries := summarizeShadowReplayCommands(replayed)
+	if slices.Equal(completedSummaries, replayedSummaries) {
+		return nil
+	}
+	for i := 0; i < max(len(completedSummaries), len(replayedSummaries)); i++ {
+		var c, s string
+		if i < len(completedSummaries) {
+			c = completedSummaries[i]
+		}
+		if i < len(replayedSummaries) {
+			s = replayedSummaries[i]
+		}
+		if c != s {
+			return historyMismatchErrorf("shadow replay mismatch at command %d: task completed with %q, replay produced %q", i, c, s)
+		}
+	}
+	return nil
+}
+
+func summarizeShadowReplayCommands(commands []*commandpb.Command) []string {
+	summaries := make([]string, 0, len(commands))
+	for _, command := range commands {
+		summaries = append(summaries, summarizeShadowReplayCommand(command))
+	}
+	return summaries
+}
+
+func summarizeShadowReplayCommand(command *commandpb.Command) string {
+	switch command.GetCommandType() {
+	case enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+		attr := command.GetScheduleActivityTaskCommandAttributes()
+		return fmt.Sprintf("ScheduleActivityTask(ID: %s, Type: %s)", attr.GetActivityId(), attr.GetActivityType().GetName())
+	case enumspb.COMMAND_TYPE_REQUEST_CANCEL_ACTIVITY_TASK:
+		return fmt.Sprintf("RequestCancelActivityTask(ScheduledEventID: %d)",
+			command.GetRequestCancelActivityTaskCommandAttributes().GetScheduledEventId())
+	case enumspb.COMMAND_TYPE_START_TIMER:
+		return fmt.Sprintf("StartTimer(ID: %s)", command.GetStartTimerCommandAttributes().GetTimerId())
+	case enumspb.COMMAND_TYPE_CANCEL_TIMER:
+		return fmt.Sprintf("CancelTimer(ID: %s)", command.GetCancelTimerCommandAttributes().GetTimerId())
+	case enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+		attr := command.GetStartChildWorkflowExecutionCommandAttributes()
+		return fmt.Sprintf("StartChildWorkflowExecution(ID: %s, Type: %s)", attr.GetWorkflowId(), attr.GetWorkflowType().GetName())
+	case enumspb.COMMAND_TYPE_RECORD_MARKER:
+		return fmt.Sprintf("RecordMarker(Name: %s)", command.GetRecordMarkerCommandAttributes().GetMarkerName())
+	case enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION:
+		attr := command.GetSignalExternalWorkflowExecutionCommandAttributes()
+		return fmt.Sprintf("SignalExternalWorkflowExecution(ID: %s, Signal: %s)", attr.GetExecution().GetWorkflowId(), attr.GetSignalName())
+	case enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION:
+		return fmt.Sprintf("RequestCancelExternalWorkflowExecution(ID: %s)",
+			command.GetRequestCancelExternalWorkflowExecutionCommandAttributes().GetWorkflowId())
+	case enumspb.COMMAND_TYPE_SCHEDULE_NEXUS_OPERATION:
+		attr := command.GetScheduleNexusOperationCommandAttributes()
+		return fmt.Sprintf("ScheduleNexusOperation(Endpoint: %s, Service: %s, Operation: %s)", attr.GetEndpoint(), attr.GetService(), attr.GetOperation())
+	case enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:
+		return fmt.Sprintf("ContinueAsNewWorkflowExecution(Type: %s)",
+			command.GetContinueAsNewWorkflowExecutionCommandAttributes().GetWorkflowType().GetName())
+	default:
+		return command.GetCommandType().String()
+	}
+}
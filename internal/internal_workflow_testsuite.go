@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +27,7 @@ import (
 	enumspb "go.temporal.io/api/enums/v1"
 	failurepb "go.temporal.io/api/failure/v1"
 	nexuspb "go.temporal.io/api/nexus/v1"
+	"go.temporal.io/api/sdk/v1"
 	"go.temporal.io/api/serviceerror"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
 	"go.temporal.io/api/workflowservice/v1"
@@ -69,6 +72,12 @@ type (
 		heartbeatDetails *commonpb.Payloads
 		token            testActivityToken
 		task             *workflowservice.PollActivityTaskQueueResponse
+		// waitForCancellation is set if the workflow waits for the activity to complete once it requested its
+		// cancellation, and cancelRequested once the cancellation of a started activity was requested. The activity
+		// then learns about the cancellation from its heartbeat response, as with a real server.
+		waitForCancellation bool
+		started             bool
+		cancelRequested     bool
 		// Timeout tracking
 		startTime         time.Time // when activity started executing
 		lastHeartbeatTime time.Time
@@ -143,6 +152,14 @@ type (
 		dataConverter converter.DataConverter
 	}
 
+	// mockAttemptReturns is set as the only return argument of a mock by MockCallWrapper.ReturnByAttempt. Each call of
+	// the mock consumes the next element of returns, repeating the last one once they are exhausted.
+	mockAttemptReturns struct {
+		sync.Mutex
+		returns  []interface{}
+		attempts int
+	}
+
 	taskQueueSpecificActivity struct {
 		fn         interface{}
 		taskQueues map[string]struct{}
@@ -192,6 +209,8 @@ type (
 
 		runningCount int
 
+		emittedSignals []TestEmittedSignal
+
 		expectedWorkflowMockCalls map[string]struct{}
 		expectedActivityMockCalls map[string]struct{}
 		expectedNexusMockCalls    map[string]struct{}
@@ -255,6 +274,16 @@ type (
 		bufferedUpdateRequests    map[string][]func()
 
 		sdkFlags *sdkFlags
+
+		// Commands of the workflow in the order they were issued, recorded only if recordCommands is set to detect
+		// non-determinism.
+		recordCommands bool
+		commands       []string
+	}
+
+	testDelayedCallback struct {
+		callback func()
+		delay    time.Duration
 	}
 
 	testSessionEnvironmentImpl struct {
@@ -312,6 +341,7 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 				ID:    defaultTestWorkflowID,
 				RunID: defaultTestRunID,
 			},
+			FirstRunID:    defaultTestRunID,
 			WorkflowType:  WorkflowType{Name: workflowTypeNotSpecified},
 			TaskQueueName: defaultTestTaskQueue,
 
@@ -358,11 +388,11 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 	mockCtrl := gomock.NewController(ilog.NewTestReporter(env.logger))
 	mockService := workflowservicemock.NewMockWorkflowServiceClient(mockCtrl)
 
-	mockHeartbeatFn := func(c context.Context, r *workflowservice.RecordActivityTaskHeartbeatRequest, opts ...grpc.CallOption) error {
+	mockHeartbeatFn := func(c context.Context, r *workflowservice.RecordActivityTaskHeartbeatRequest, opts ...grpc.CallOption) (bool, error) {
 		token, ok := activityTokenFromBytes(r.TaskToken)
 		if !ok {
 			env.logger.Debug("RecordActivityTaskHeartbeat: Invalid activity token.")
-			return serviceerror.NewNotFound("")
+			return false, serviceerror.NewNotFound("")
 		}
 		env.locker.Lock() // need lock as this is running in activity worker's goroutinue
 		activityHandle, ok := env.getActivityHandle(token)
@@ -370,10 +400,11 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 			env.locker.Unlock()
 			env.logger.Debug("RecordActivityTaskHeartbeat: Activity token not found, could be already completed or canceled.",
 				tagActivityID, token.activityID)
-			return serviceerror.NewNotFound("")
+			return false, serviceerror.NewNotFound("")
 		}
 		activityHandle.heartbeatDetails = r.Details
 		activityHandle.lastHeartbeatTime = time.Now()
+		cancelRequested := activityHandle.cancelRequested
 		env.locker.Unlock()
 		activityInfo := activityHandle.getActivityInfo()
 		if env.onActivityHeartbeatListener != nil {
@@ -389,7 +420,7 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 		}
 
 		env.logger.Debug("RecordActivityTaskHeartbeat", tagActivityID, token.activityID)
-		return nil
+		return cancelRequested, nil
 	}
 
 	mockService.EXPECT().RecordActivityTaskHeartbeat(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(
@@ -397,10 +428,11 @@ func newTestWorkflowEnvironmentImpl(s *WorkflowTestSuite, parentRegistry *regist
 		r *workflowservice.RecordActivityTaskHeartbeatRequest,
 		opts ...grpc.CallOption,
 	) (*workflowservice.RecordActivityTaskHeartbeatResponse, error) {
-		if err := mockHeartbeatFn(ctx, r, opts...); err != nil {
+		cancelRequested, err := mockHeartbeatFn(ctx, r, opts...)
+		if err != nil {
 			return nil, err
 		}
-		return &workflowservice.RecordActivityTaskHeartbeatResponse{CancelRequested: false}, nil
+		return &workflowservice.RecordActivityTaskHeartbeatResponse{CancelRequested: cancelRequested}, nil
 	}).AnyTimes()
 
 	env.service = mockService
@@ -474,12 +506,15 @@ func (env *testWorkflowEnvironmentImpl) newTestWorkflowEnvironmentForChild(
 	childEnv.workflowInfo.Attempt = params.attempt
 	childEnv.workflowInfo.WorkflowExecution.ID = params.WorkflowID
 	childEnv.workflowInfo.WorkflowExecution.RunID = params.WorkflowID + "_RunID"
+	childEnv.workflowInfo.FirstRunID = childEnv.workflowInfo.WorkflowExecution.RunID
 	childEnv.workflowInfo.Namespace = params.Namespace
 	childEnv.workflowInfo.TaskQueueName = params.TaskQueueName
 	childEnv.workflowInfo.WorkflowExecutionTimeout = params.WorkflowExecutionTimeout
 	childEnv.workflowInfo.WorkflowRunTimeout = params.WorkflowRunTimeout
 	childEnv.workflowInfo.WorkflowTaskTimeout = params.WorkflowTaskTimeout
+	childEnv.workflowInfo.RetryPolicy = convertFromPBWorkflowRetryPolicy(params.RetryPolicy)
 	childEnv.workflowInfo.lastCompletionResult = params.lastCompletionResult
+	childEnv.workflowInfo.lastFailure = params.lastFailure
 	childEnv.workflowInfo.CronSchedule = cronSchedule
 	childEnv.workflowInfo.ParentWorkflowNamespace = env.workflowInfo.Namespace
 	childEnv.workflowInfo.ParentWorkflowExecution = &env.workflowInfo.WorkflowExecution
@@ -526,6 +561,69 @@ func (env *testWorkflowEnvironmentImpl) newTestWorkflowEnvironmentForChild(
 	return childEnv, nil
 }
 
+// newTestWorkflowEnvironmentForNonDeterminismDetection creates an environment executing the workflow of env once more
+// with the same configuration, so the commands of both executions can be compared.
+func (env *testWorkflowEnvironmentImpl) newTestWorkflowEnvironmentForNonDeterminismDetection() *testWorkflowEnvironmentImpl {
+	detectionEnv := newTestWorkflowEnvironmentImpl(env.testSuite, env.registry)
+	detectionEnv.setStartTime(env.mockClock.Now())
+	workflowInfo := *env.workflowInfo
+	detectionEnv.workflowInfo = &workflowInfo
+	detectionEnv.workerOptions = env.workerOptions
+	detectionEnv.dataConverter = env.dataConverter
+	detectionEnv.failureConverter = env.failureConverter
+	detectionEnv.runTimeout = env.runTimeout
+	detectionEnv.heartbeatDetails = env.heartbeatDetails
+	detectionEnv.executeActivitiesInWorkflow = env.executeActivitiesInWorkflow
+	detectionEnv.taskQueueSpecificActivities = env.taskQueueSpecificActivities
+	detectionEnv.identity = env.identity
+	detectionEnv.header = env.header
+	detectionEnv.contextPropagators = env.contextPropagators
+	detectionEnv.detachedChildWaitDisabled = env.detachedChildWaitDisabled
+	detectionEnv.testTimeout = env.testTimeout
+	detectionEnv.activityTimeoutGracePeriod = env.activityTimeoutGracePeriod
+	detectionEnv.expectedWorkflowMockCalls = env.expectedWorkflowMockCalls
+	detectionEnv.expectedActivityMockCalls = env.expectedActivityMockCalls
+	detectionEnv.expectedNexusMockCalls = env.expectedNexusMockCalls
+	detectionEnv.recordCommands = true
+	return detectionEnv
+}
+
+func (env *testWorkflowEnvironmentImpl) recordCommand(format string, args ...interface{}) {
+	if env.recordCommands {
+		env.commands = append(env.commands, fmt.Sprintf(format, args...))
+	}
+}
+
+func (env *testWorkflowEnvironmentImpl) payloadsToString(input *commonpb.Payloads) string {
+	return "[" + strings.Join(env.GetDataConverter().ToStrings(input), ", ") + "]"
+}
+
+// compareCommands returns an error describing the first command that differs between two executions of a workflow.
+func compareCommands(expected, actual []string) error {
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		switch {
+		case i >= len(actual):
+			return fmt.Errorf("nondeterministic workflow: command %d is %s in the first execution and missing in the second", i+1, expected[i])
+		case i >= len(expected):
+			return fmt.Errorf("nondeterministic workflow: command %d is missing in the first execution and %s in the second", i+1, actual[i])
+		case expected[i] != actual[i]:
+			return fmt.Errorf("nondeterministic workflow: command %d is %s in the first execution and %s in the second", i+1, expected[i], actual[i])
+		}
+	}
+	return nil
+}
+
+// copyMock returns a mock with copies of the expected calls of m, so calls to it do not use up the expectations of m.
+func copyMock(m *mock.Mock) *mock.Mock {
+	c := &mock.Mock{}
+	for _, call := range m.ExpectedCalls {
+		callCopy := *call
+		callCopy.Parent = c
+		c.ExpectedCalls = append(c.ExpectedCalls, &callCopy)
+	}
+	return c
+}
+
 func (env *testWorkflowEnvironmentImpl) setWorkerOptions(options WorkerOptions) {
 	env.workerOptions = options
 	env.registry.interceptors = options.Interceptors
@@ -598,7 +696,7 @@ func (env *testWorkflowEnvironmentImpl) executeWorkflowInternal(delayStart time.
 		// https://github.com/temporalio/go-sdk/issues/50
 		panic(fmt.Sprintf("Current TestWorkflowEnvironment is used to execute %v. Please create a new TestWorkflowEnvironment for %v.", wInfo.WorkflowType.Name, workflowType))
 	}
-	wInfo.WorkflowType.Name = workflowType
+	wInfo.WorkflowType.Name = env.registry.getWorkflowPrimaryName(workflowType)
 	if wInfo.WorkflowRunTimeout == 0 {
 		wInfo.WorkflowRunTimeout = env.runTimeout
 	}
@@ -645,6 +743,50 @@ func (env *testWorkflowEnvironmentImpl) executeWorkflowInternal(delayStart time.
 	env.startMainLoop()
 }
 
+func (env *testWorkflowEnvironmentImpl) executeContinuedAsNewWorkflow(info *TestContinueAsNewInfo) {
+	env.setStartWorkflowOptions(StartWorkflowOptions{
+		ID:                  info.workflowID,
+		TaskQueue:           info.TaskQueueName,
+		WorkflowRunTimeout:  info.WorkflowRunTimeout,
+		WorkflowTaskTimeout: info.WorkflowTaskTimeout,
+		RetryPolicy:         info.RetryPolicy,
+	})
+	env.workflowInfo.WorkflowExecution.RunID = uuid.NewString()
+	env.workflowInfo.ContinuedExecutionRunID = info.runID
+	env.workflowInfo.FirstRunID = info.firstRunID
+	env.workflowInfo.Memo = info.Memo
+	env.workflowInfo.SearchAttributes = info.searchAttributes
+	if info.header != nil {
+		env.header = info.header
+	}
+	env.setStartTime(info.continuedAt)
+	env.executeWorkflowInternal(0, info.WorkflowType, info.input)
+}
+
+func (env *testWorkflowEnvironmentImpl) newContinueAsNewInfo(continueAsNewErr *ContinueAsNewError) *TestContinueAsNewInfo {
+	retryPolicy := continueAsNewErr.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = env.workflowInfo.RetryPolicy
+	}
+	return &TestContinueAsNewInfo{
+		WorkflowType:          continueAsNewErr.WorkflowType.Name,
+		TaskQueueName:         continueAsNewErr.TaskQueueName,
+		Args:                  newEncodedValues(continueAsNewErr.Input, env.GetDataConverter()),
+		Memo:                  env.workflowInfo.Memo,
+		TypedSearchAttributes: convertToTypedSearchAttributes(env.logger, env.workflowInfo.SearchAttributes.GetIndexedFields()),
+		WorkflowRunTimeout:    continueAsNewErr.WorkflowRunTimeout,
+		WorkflowTaskTimeout:   continueAsNewErr.WorkflowTaskTimeout,
+		RetryPolicy:           retryPolicy,
+		workflowID:            env.workflowInfo.WorkflowExecution.ID,
+		runID:                 env.workflowInfo.WorkflowExecution.RunID,
+		firstRunID:            env.workflowInfo.FirstRunID,
+		input:                 continueAsNewErr.Input,
+		header:                continueAsNewErr.Header,
+		searchAttributes:      env.workflowInfo.SearchAttributes,
+		continuedAt:           env.Now(),
+	}
+}
+
 func (env *testWorkflowEnvironmentImpl) getWorkflowDefinition(wt WorkflowType) (WorkflowDefinition, error) {
 	wf, ok := env.registry.getWorkflowFn(wt.Name)
 	if !ok {
@@ -657,8 +799,16 @@ func (env *testWorkflowEnvironmentImpl) getWorkflowDefinition(wt WorkflowType) (
 		dynamic = true
 	}
 	wd := &workflowExecutorWrapper{
-		workflowExecutor: &workflowExecutor{workflowType: wt.Name, fn: wf, interceptors: env.registry.interceptors, dynamic: dynamic},
-		env:              env,
+		workflowExecutor: &workflowExecutor{
+			workflowType:   wt.Name,
+			fn:             wf,
+			interceptors:   env.registry.interceptors,
+			dynamic:        dynamic,
+			completionHook: env.registry.getWorkflowCompletionHook(wt.Name),
+			staticConfig:   env.registry.getWorkflowStaticConfig(wt.Name),
+			inputValidator: env.registry.getWorkflowInputValidator(wt.Name),
+		},
+		env: env,
 	}
 	return newSyncWorkflowDefinition(wd), nil
 }
@@ -832,7 +982,8 @@ func (env *testWorkflowEnvironmentImpl) executeLocalActivity(
 
 func (env *testWorkflowEnvironmentImpl) startWorkflowTask() {
 	if !env.isWorkflowCompleted {
-		env.workflowDef.OnWorkflowTaskStarted(env.workerOptions.DeadlockDetectionTimeout)
+		env.workflowDef.OnWorkflowTaskStarted(
+			env.registry.getWorkflowDeadlockDetectionTimeout(env.workflowInfo.WorkflowType, env.workerOptions.DeadlockDetectionTimeout))
 	}
 }
 
@@ -1009,6 +1160,14 @@ func (env *testWorkflowEnvironmentImpl) RequestCancelActivity(activityID Activit
 	}
 	activityInfo := handle.getActivityInfo()
 	env.logger.Debug("RequestCancelActivity", tagActivityID, activityID)
+	if handle.waitForCancellation && handle.started {
+		// The activity is resolved once it completes, with the details of the CanceledError it may return
+		handle.cancelRequested = true
+		if env.onActivityCanceledListener != nil {
+			env.postCallback(func() { env.onActivityCanceledListener(activityInfo) }, true)
+		}
+		return
+	}
 	env.deleteHandle(token)
 	env.postCallback(func() {
 		handle.callback(nil, NewCanceledError())
@@ -1043,6 +1202,11 @@ func (env *testWorkflowEnvironmentImpl) Complete(result *commonpb.Payloads, err
 		return
 	}
 	env.workflowDef.Close()
+	if err != nil {
+		env.recordCommand("FailWorkflowExecution %v", err)
+	} else {
+		env.recordCommand("CompleteWorkflowExecution %s", env.payloadsToString(result))
+	}
 
 	dc := env.GetDataConverter()
 	env.isWorkflowCompleted = true
@@ -1177,6 +1341,7 @@ func (h *testWorkflowHandle) rerunAsChild() bool {
 			// the childWorkflowID will be the same for retry run.
 			delete(env.runningWorkflows, env.workflowInfo.WorkflowExecution.ID)
 			params.attempt++
+			params.lastFailure = env.failureConverter.ErrorToFailure(env.testError)
 			env.parentEnv.executeChildWorkflowWithDelay(backoff, *params, h.callback, nil /* child workflow already started */)
 
 			return true
@@ -1259,6 +1424,7 @@ func (env *testWorkflowEnvironmentImpl) GetContextPropagators() []ContextPropaga
 }
 
 func (env *testWorkflowEnvironmentImpl) ExecuteActivity(parameters ExecuteActivityParams, callback ResultHandler) ActivityID {
+	env.recordCommand("ScheduleActivityTask %s %s", parameters.ActivityType.Name, env.payloadsToString(parameters.Input))
 	ensureDefaultRetryPolicy(&parameters)
 	scheduleTaskAttr := &commandpb.ScheduleActivityTaskCommandAttributes{}
 	if parameters.ActivityID == "" {
@@ -1291,6 +1457,7 @@ func (env *testWorkflowEnvironmentImpl) ExecuteActivity(parameters ExecuteActivi
 
 	taskHandler := env.newTestActivityTaskHandler(parameters.TaskQueueName, parameters.DataConverter)
 	activityHandle := env.addNewActivityHandle(task, callback)
+	activityHandle.waitForCancellation = parameters.WaitForCancellation
 	env.runningCount++
 	activityToken := activityHandle.token
 
@@ -1369,6 +1536,7 @@ func (env *testWorkflowEnvironmentImpl) ExecuteActivity(parameters ExecuteActivi
 	// do callback in a defer to handle calls to runtime.Goexit inside the activity (which is done by t.FailNow)
 	go func() {
 		var result interface{}
+		var lastFailure *failurepb.Failure
 		defer func() {
 			// Stop timeout monitoring
 			if timeoutWatchDone != nil {
@@ -1412,11 +1580,15 @@ func (env *testWorkflowEnvironmentImpl) ExecuteActivity(parameters ExecuteActivi
 
 			// post activity result to workflow dispatcher
 			env.postCallback(func() {
+				// Like the server, report only the final attempt of a retried activity, right before its result.
+				if _, ok := env.getActivityHandle(activityToken); ok && parameters.OnRetry != nil && lastFailure != nil {
+					parameters.OnRetry(task.GetAttempt(), env.failureConverter.FailureToError(lastFailure))
+				}
 				env.handleActivityResult(activityHandle, result, parameters.DataConverter)
 				env.runningCount--
 			}, false /* do not auto schedule workflow task, because activity might be still pending */)
 		}()
-		result = env.executeActivityWithRetryForTest(taskHandler, parameters, task)
+		result, lastFailure = env.executeActivityWithRetryForTest(taskHandler, parameters, task)
 	}()
 
 	return activityID
@@ -1624,7 +1796,7 @@ func (env *testWorkflowEnvironmentImpl) executeActivityWithRetryForTest(
 	taskHandler ActivityTaskHandler,
 	parameters ExecuteActivityParams,
 	task *workflowservice.PollActivityTaskQueueResponse,
-) (result interface{}) {
+) (result interface{}, lastFailure *failurepb.Failure) {
 	var expireTime time.Time
 	if parameters.ScheduleToCloseTimeout > 0 {
 		expireTime = env.Now().Add(parameters.ScheduleToCloseTimeout)
@@ -1635,7 +1807,7 @@ func (env *testWorkflowEnvironmentImpl) executeActivityWithRetryForTest(
 		result, err = taskHandler.Execute(parameters.TaskQueueName, task)
 		if err != nil {
 			if err == context.DeadlineExceeded {
-				return err
+				return err, lastFailure
 			}
 			panic(err)
 		}
@@ -1669,6 +1841,7 @@ func (env *testWorkflowEnvironmentImpl) executeActivityWithRetryForTest(
 				env.postCallback(func() { env.runningCount-- }, false)
 
 				<-waitCh
+				lastFailure = failure
 				continue
 			}
 		}
@@ -1730,6 +1903,7 @@ func (env *testWorkflowEnvironmentImpl) ExecuteLocalActivity(params ExecuteLocal
 		// local activity could be registered, if so use the registered name. This name is only used to find a mock.
 		ae.name = at.Name
 	}
+	env.recordCommand("RecordLocalActivityMarker %s %v", ae.name, params.InputArgs)
 	// We have to skip the interceptors on the first call because
 	// ExecuteWithActualArgs is actually invoked twice to support a mock activity
 	// function result
@@ -1972,7 +2146,10 @@ func (a *activityExecutorWrapper) Execute(ctx context.Context, input *commonpb.P
 	// If activity handle cannot be found, we assume it was cancelled
 	if ok {
 		a.env.locker.Lock()
-		_, ok = a.env.getActivityHandle(token)
+		var handle *testActivityHandle
+		if handle, ok = a.env.getActivityHandle(token); ok {
+			handle.started = true
+		}
 		a.env.locker.Unlock()
 	}
 	if !ok {
@@ -2011,6 +2188,7 @@ func (a *activityExecutorWrapper) ExecuteWithActualArgs(ctx context.Context, inp
 
 	m := &mockWrapper{env: a.env, name: a.name, fn: a.fn, isWorkflow: false}
 	if mockRet := m.getActivityMockReturnWithActualArgs(ctx, inputArgs); mockRet != nil {
+		mockRet = m.resolveAttemptReturns(mockRet)
 		// check if mock returns function which must match to the actual function.
 		if mockFn := m.getMockFn(mockRet); mockFn != nil {
 			executor := &activityExecutor{name: m.name, fn: mockFn}
@@ -2252,6 +2430,58 @@ func (m *mockWrapper) getMockValue(mockRet mock.Arguments) (*commonpb.Payloads,
 	}
 }
 
+// resolveAttemptReturns replaces mock returns configured with MockCallWrapper.ReturnByAttempt with the returns of the
+// current attempt. Other mock returns are returned unchanged.
+func (m *mockWrapper) resolveAttemptReturns(mockRet mock.Arguments) mock.Arguments {
+	if len(mockRet) != 1 {
+		return mockRet
+	}
+	attemptReturns, ok := mockRet.Get(0).(*mockAttemptReturns)
+	if !ok {
+		return mockRet
+	}
+	ret := attemptReturns.next()
+
+	fnType := reflect.TypeOf(m.fn)
+	if retType := reflect.TypeOf(ret); retType != nil && retType.Kind() == reflect.Func {
+		// a mock function with the same signature as the mocked function, see getMockFn
+		return mock.Arguments{ret}
+	}
+	err, isErr := ret.(error)
+	switch fnType.NumOut() {
+	case 1:
+		if isErr {
+			return mock.Arguments{err}
+		}
+		return mock.Arguments{nil}
+	case 2:
+		if isErr {
+			return mock.Arguments{reflect.Zero(fnType.Out(0)).Interface(), err}
+		}
+		return mock.Arguments{ret, nil}
+	default:
+		// this will never happen, panic just in case
+		panic("mock should either have 1 return value (error) or 2 return values (result, error)")
+	}
+}
+
+func (r *mockAttemptReturns) next() interface{} {
+	r.Lock()
+	defer r.Unlock()
+	i := r.attempts
+	if i >= len(r.returns) {
+		i = len(r.returns) - 1
+	}
+	r.attempts++
+	return r.returns[i]
+}
+
+func (r *mockAttemptReturns) getAttempts() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.attempts
+}
+
 func (m *mockWrapper) executeMock(ctx interface{}, input *commonpb.Payloads, mockRet mock.Arguments) (result *commonpb.Payloads, err error) {
 	// have to handle panics here to support calling ExecuteChildWorkflow(...).GetChildWorkflowExecution().Get(...)
 	// when a child is mocked.
@@ -2263,11 +2493,16 @@ func (m *mockWrapper) executeMock(ctx interface{}, input *commonpb.Payloads, moc
 	}()
 
 	fnName := m.name
+	mockRet = m.resolveAttemptReturns(mockRet)
 	// check if mock returns function which must match to the actual function.
 	if mockFn := m.getMockFn(mockRet); mockFn != nil {
 		// we found a mock function that matches to actual function, so call that mockFn
 		if m.isWorkflow {
-			executor := &workflowExecutor{workflowType: fnName, fn: mockFn}
+			executor := &workflowExecutor{
+				workflowType: fnName,
+				fn:           mockFn,
+				staticConfig: getWorkflowEnvironmentInterceptor(ctx.(Context)).staticConfig,
+			}
 			return executor.Execute(ctx.(Context), input)
 		}
 		executor := &activityExecutor{name: fnName, fn: mockFn}
@@ -2394,6 +2629,7 @@ func (env *testWorkflowEnvironmentImpl) NewTimer(
 	options TimerOptions,
 	callback ResultHandler,
 ) *TimerID {
+	env.recordCommand("StartTimer %v", d)
 	return env.newTimer(d, options, callback, true)
 }
 
@@ -2470,6 +2706,7 @@ func (env *testWorkflowEnvironmentImpl) RequestCancelChildWorkflow(_, workflowID
 }
 
 func (env *testWorkflowEnvironmentImpl) RequestCancelExternalWorkflow(namespace, workflowID, runID string, callback ResultHandler) {
+	env.recordCommand("RequestCancelExternalWorkflowExecution %s", workflowID)
 	if env.workflowInfo.WorkflowExecution.ID == workflowID {
 		cancelFunc := func() {
 			env.workflowCancelHandler()
@@ -2544,6 +2781,16 @@ func (env *testWorkflowEnvironmentImpl) SignalExternalWorkflow(
 	childWorkflowOnly bool,
 	callback ResultHandler,
 ) {
+	env.recordCommand("SignalExternalWorkflowExecution %s %s %s", workflowID, signalName, env.payloadsToString(input))
+	env.emittedSignals = append(env.emittedSignals, TestEmittedSignal{
+		SenderWorkflowID: env.workflowInfo.WorkflowExecution.ID,
+		Namespace:        namespace,
+		WorkflowID:       workflowID,
+		RunID:            runID,
+		SignalName:       signalName,
+		Arg:              newEncodedValue(input, env.GetDataConverter()),
+	})
+
 	// check if target workflow is a known workflow
 	if childHandle, ok := env.runningWorkflows[workflowID]; ok {
 		// target workflow is a child
@@ -2590,6 +2837,7 @@ func (env *testWorkflowEnvironmentImpl) SignalExternalWorkflow(
 }
 
 func (env *testWorkflowEnvironmentImpl) ExecuteChildWorkflow(params ExecuteWorkflowParams, callback ResultHandler, startedHandler func(r WorkflowExecution, e error)) {
+	env.recordCommand("StartChildWorkflowExecution %s %s", params.WorkflowType.Name, env.payloadsToString(params.Input))
 	env.executeChildWorkflowWithDelay(0, params, callback, startedHandler)
 }
 
@@ -2640,6 +2888,7 @@ func (env *testWorkflowEnvironmentImpl) ExecuteNexusOperation(
 	callback func(*commonpb.Payload, error),
 	startedHandler func(opID string, e error),
 ) int64 {
+	env.recordCommand("ScheduleNexusOperation %s %s %s", params.client.Service(), params.operation, env.payloadsToString(&commonpb.Payloads{Payloads: []*commonpb.Payload{params.input}}))
 	seq := env.nextID()
 	// Use lower case header values to simulate how the Nexus SDK (used internally by the "real" server) would transmit
 	// these headers over the wire.
@@ -2987,6 +3236,7 @@ func (env *testWorkflowEnvironmentImpl) makeUniqueNexusOperationToken(
 }
 
 func (env *testWorkflowEnvironmentImpl) SideEffect(f func() (*commonpb.Payloads, error), callback ResultHandler, _ string) {
+	env.recordCommand("RecordSideEffectMarker")
 	mockMethod := mockMethodForSideEffect
 	if _, ok := env.expectedWorkflowMockCalls[mockMethod]; !ok {
 		callback(f())
@@ -3081,6 +3331,7 @@ func getMockMethodForGetVersion(changeID string) string {
 }
 
 func (env *testWorkflowEnvironmentImpl) UpsertSearchAttributes(attributes map[string]interface{}) error {
+	env.recordCommand("UpsertWorkflowSearchAttributes %v", attributes)
 	attr, err := validateAndSerializeSearchAttributes(attributes)
 
 	env.workflowInfo.SearchAttributes = mergeSearchAttributes(env.workflowInfo.SearchAttributes, attr)
@@ -3111,6 +3362,7 @@ func validateAndSerializeTypedSearchAttributes(searchAttributes map[SearchAttrib
 }
 
 func (env *testWorkflowEnvironmentImpl) UpsertTypedSearchAttributes(attributes SearchAttributes) error {
+	env.recordCommand("UpsertWorkflowSearchAttributes %v", attributes.untypedValue)
 	// Don't immediately return the error from validateAndSerializeTypedSearchAttributes, as we may need to call the mock
 	rawSearchAttributes, err := validateAndSerializeTypedSearchAttributes(attributes.untypedValue)
 
@@ -3129,6 +3381,7 @@ func (env *testWorkflowEnvironmentImpl) UpsertTypedSearchAttributes(attributes S
 }
 
 func (env *testWorkflowEnvironmentImpl) UpsertMemo(memoMap map[string]interface{}) error {
+	env.recordCommand("ModifyWorkflowProperties %v", memoMap)
 	memo, err := validateAndSerializeMemo(memoMap, env.dataConverter, env.TryUse(SDKFlagMemoUserDCEncode))
 
 	env.workflowInfo.Memo = mergeMemo(env.workflowInfo.Memo, memo)
@@ -3270,6 +3523,27 @@ func (env *testWorkflowEnvironmentImpl) queryWorkflow(queryType string, args ...
 	return newEncodedValue(blob, env.GetDataConverter()), nil
 }
 
+func (env *testWorkflowEnvironmentImpl) getQueryTypes() ([]string, error) {
+	result, err := env.queryWorkflow(QueryTypeWorkflowMetadata)
+	if err != nil {
+		return nil, err
+	}
+	var metadata sdk.WorkflowMetadata
+	if err := result.Get(&metadata); err != nil {
+		return nil, err
+	}
+	var queryTypes []string
+	for _, definition := range metadata.GetDefinition().GetQueryDefinitions() {
+		switch definition.GetName() {
+		case QueryTypeStackTrace, QueryTypeOpenSessions, QueryTypeWorkflowMetadata:
+		default:
+			queryTypes = append(queryTypes, definition.GetName())
+		}
+	}
+	slices.Sort(queryTypes)
+	return queryTypes, nil
+}
+
 func (env *testWorkflowEnvironmentImpl) updateWorkflow(name string, id string, uc UpdateCallbacks, args ...interface{}) {
 	data, err := encodeArgs(env.GetDataConverter(), args)
 	if err != nil {
@@ -3439,6 +3713,19 @@ func (env *testWorkflowEnvironmentImpl) setStartWorkflowOptions(options StartWor
 	if len(options.TaskQueue) > 0 {
 		wf.TaskQueueName = options.TaskQueue
 	}
+	if options.RetryPolicy != nil {
+		wf.RetryPolicy = options.RetryPolicy
+	}
+	if len(options.Headers) > 0 {
+		header := &commonpb.Header{Fields: maps.Clone(env.header.GetFields())}
+		if header.Fields == nil {
+			header.Fields = map[string]*commonpb.Payload{}
+		}
+		if err := setStartWorkflowHeaders(header, options.Headers, env.GetDataConverter()); err != nil {
+			panic(err)
+		}
+		env.header = header
+	}
 }
 
 func newTestSessionEnvironment(testWorkflowEnvironment *testWorkflowEnvironmentImpl,
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -953,6 +955,53 @@ func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflow_BasicWithDataConverter()
 	s.Equal("hello_activity hello_world", actualResult)
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_ActivityCancelDetails() {
+	activityFn := func(ctx context.Context) (int, error) {
+		progress := 0
+		for {
+			progress++
+			RecordActivityHeartbeat(ctx, progress)
+			select {
+			case <-ctx.Done():
+				return 0, NewCanceledError(progress)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+	workflowFn := func(ctx Context, wait bool) (int, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			HeartbeatTimeout:    2 * time.Second,
+			WaitForCancellation: wait,
+		})
+		ctx, cancel := WithCancel(ctx)
+		f := ExecuteActivity(ctx, activityFn)
+		_ = NewTimer(ctx, time.Second).Get(ctx, nil)
+		cancel()
+		err := f.Get(ctx, nil)
+		s.True(IsCanceledError(err))
+		var progress int
+		if err := GetCanceledErrorDetails(err, &progress); err != nil {
+			return 0, err
+		}
+		return progress, nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(activityFn)
+	env.ExecuteWorkflow(workflowFn, true)
+	s.NoError(env.GetWorkflowError())
+	var progress int
+	s.NoError(env.GetWorkflowResult(&progress))
+	s.Positive(progress)
+
+	// Without waiting for cancellation, the details of the activity are not available
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(activityFn)
+	env.ExecuteWorkflow(workflowFn, false)
+	s.ErrorContains(env.GetWorkflowError(), ErrNoData.Error())
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflowCancel() {
 	workflowFn := func(ctx Context) error {
 		cwo := ChildWorkflowOptions{
@@ -2020,6 +2069,96 @@ func (s *WorkflowTestSuiteUnitTest) Test_MockUpsertMemo() {
 	// mix no-mock and mock is not support
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_UpsertWorkflowMetadata() {
+	statusKey := NewSearchAttributeKeyKeyword("CustomKeywordField")
+	workflowFn := func(ctx Context) error {
+		s.Error(UpsertWorkflowMetadata(ctx, MetadataUpdate{}))
+
+		err := UpsertWorkflowMetadata(ctx, MetadataUpdate{
+			Memo:             map[string]interface{}{"Status": "paid"},
+			SearchAttributes: []SearchAttributeUpdate{statusKey.ValueSet("paid")},
+		})
+		s.NoError(err)
+
+		// A key with a different type than the one set fails without upserting the memo
+		err = UpsertWorkflowMetadata(ctx, MetadataUpdate{
+			Memo:             map[string]interface{}{"Status": "shipped"},
+			SearchAttributes: []SearchAttributeUpdate{NewSearchAttributeKeyInt64("CustomKeywordField").ValueSet(1)},
+		})
+		s.ErrorContains(err, "CustomKeywordField")
+		err = UpsertWorkflowMetadata(ctx, MetadataUpdate{
+			SearchAttributes: []SearchAttributeUpdate{NewSearchAttributeKeyKeyword(TemporalChangeVersion).ValueSet("v1")},
+		})
+		s.Error(err)
+
+		// Only a memo
+		s.NoError(UpsertWorkflowMetadata(ctx, MetadataUpdate{Memo: map[string]interface{}{"Priority": 1}}))
+
+		var status string
+		s.NoError(converter.GetDefaultDataConverter().FromPayload(GetWorkflowInfo(ctx).Memo.Fields["Status"], &status))
+		s.Equal("paid", status)
+		s.Contains(GetWorkflowInfo(ctx).Memo.Fields, "Priority")
+		value, ok := GetTypedSearchAttributes(ctx).GetKeyword(statusKey)
+		s.True(ok)
+		s.Equal("paid", value)
+		return nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.Nil(env.GetWorkflowError())
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_InheritedChildWorkflowOptions() {
+	childWorkflowFn := func(ctx Context) (string, error) {
+		return GetWorkflowInfo(ctx).TaskQueueName, nil
+	}
+	workflowFn := func(ctx Context) (string, error) {
+		if err := UpsertMemo(ctx, map[string]interface{}{"team": "payments"}); err != nil {
+			return "", err
+		}
+		if err := UpsertTypedSearchAttributes(ctx, NewSearchAttributeKeyKeyword("CustomKeywordField").ValueSet("payments")); err != nil {
+			return "", err
+		}
+		// The patch is recorded in the TemporalChangeVersion search attribute of the parent only
+		GetVersion(ctx, "parent-patch", DefaultVersion, 1)
+		s.True(GetTypedSearchAttributes(ctx).ContainsKey(NewSearchAttributeKeyKeywordList(TemporalChangeVersion)))
+		options := InheritedChildWorkflowOptions(ctx)
+		s.Empty(options.WorkflowID)
+		s.Equal("parent-task-queue", options.TaskQueue)
+		s.Equal(time.Hour, options.WorkflowExecutionTimeout)
+		s.Equal(10*time.Minute, options.WorkflowRunTimeout)
+		s.Equal(5*time.Second, options.WorkflowTaskTimeout)
+		s.Len(options.Memo, 1)
+		var team string
+		s.NoError(converter.GetDefaultDataConverter().FromPayload(options.Memo["team"].(converter.RawValue).Payload(), &team))
+		s.Equal("payments", team)
+		s.Equal(1, options.TypedSearchAttributes.Size())
+		customKeyword, _ := options.TypedSearchAttributes.GetKeyword(NewSearchAttributeKeyKeyword("CustomKeywordField"))
+		s.Equal("payments", customKeyword)
+
+		var childTaskQueue string
+		err := ExecuteChildWorkflow(WithChildWorkflowOptions(ctx, options), childWorkflowFn).Get(ctx, &childTaskQueue)
+		return childTaskQueue, err
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(childWorkflowFn)
+	env.SetStartWorkflowOptions(StartWorkflowOptions{
+		TaskQueue:                "parent-task-queue",
+		WorkflowExecutionTimeout: time.Hour,
+		WorkflowRunTimeout:       10 * time.Minute,
+		WorkflowTaskTimeout:      5 * time.Second,
+	})
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var childTaskQueue string
+	s.NoError(env.GetWorkflowResult(&childTaskQueue))
+	s.Equal("parent-task-queue", childTaskQueue)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_ActivityWithPointerTypes() {
 	var actualValues []string
 	retVal := "retVal"
@@ -2175,6 +2314,211 @@ func (s *WorkflowTestSuiteUnitTest) Test_ActivityFriendlyName() {
 	s.Equal("foo", called[1])
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_StaticConfig() {
+	workflowFn := func(ctx Context) (string, error) {
+		config := GetStaticConfig(ctx)
+		// Changes to the returned map must not leak into other runs
+		config["region"] = "changed"
+		return GetStaticConfig(ctx)["region"], nil
+	}
+	config := map[string]string{"region": "us-east"}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "static-config", StaticConfig: config})
+	// Changes after registration must not be observed either
+	config["region"] = "eu-west"
+	env.ExecuteWorkflow("static-config")
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("us-east", result)
+
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(func(ctx Context) (bool, error) {
+		return GetStaticConfig(ctx) == nil, nil
+	}, RegisterWorkflowOptions{Name: "no-static-config"})
+	env.ExecuteWorkflow("no-static-config")
+	s.NoError(env.GetWorkflowError())
+	var isNil bool
+	s.NoError(env.GetWorkflowResult(&isNil))
+	s.True(isNil)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_InputValidator() {
+	var executed bool
+	workflowFn := func(ctx Context, name string, count int) (string, error) {
+		executed = true
+		return strings.Repeat(name, count), nil
+	}
+	validator := func(args []interface{}) error {
+		s.Len(args, 2)
+		if args[0].(string) == "" {
+			return errors.New("name is required")
+		}
+		if args[1].(int) <= 0 {
+			return fmt.Errorf("count must be positive, got %v", args[1])
+		}
+		return nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "validated", InputValidator: validator})
+	env.ExecuteWorkflow("validated", "ab", 2)
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("abab", result)
+
+	executed = false
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "validated", InputValidator: validator})
+	env.ExecuteWorkflow("validated", "ab", 0)
+	s.False(executed)
+	var appErr *ApplicationError
+	s.True(errors.As(env.GetWorkflowError(), &appErr))
+	s.Equal("InputValidationError", appErr.Type())
+	s.True(appErr.NonRetryable())
+	s.ErrorContains(appErr, "count must be positive, got 0")
+
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{
+		Name:                     "validated",
+		InputValidator:           validator,
+		InputValidationRetryable: true,
+	})
+	env.ExecuteWorkflow("validated", "", 1)
+	s.True(errors.As(env.GetWorkflowError(), &appErr))
+	s.Equal("InputValidationError", appErr.Type())
+	s.False(appErr.NonRetryable())
+	s.ErrorContains(appErr, "name is required")
+
+	// The completion hook runs for workflows failed by the validator
+	var completions []WorkflowCompletion
+	hook := func(ctx context.Context, completion WorkflowCompletion, result *string) error {
+		completions = append(completions, completion)
+		return nil
+	}
+	executed = false
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(hook)
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{
+		Name:           "validated",
+		InputValidator: validator,
+		OnCompletion: &WorkflowCompletionHook{
+			Activity:        hook,
+			ActivityOptions: ActivityOptions{StartToCloseTimeout: time.Minute},
+		},
+	})
+	env.ExecuteWorkflow("validated", "ab", 0)
+	s.False(executed)
+	s.True(errors.As(env.GetWorkflowError(), &appErr))
+	s.Equal("InputValidationError", appErr.Type())
+	s.Len(completions, 1)
+	s.Equal(WorkflowCompletionOutcomeFailed, completions[0].Outcome)
+	s.Contains(completions[0].Error, "count must be positive, got 0")
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_Scheduler() {
+	workflowFn := func(ctx Context) ([]string, error) {
+		start := Now(ctx)
+		var ran []string
+		scheduler := NewScheduler(ctx)
+		schedule := func(name string, after time.Duration) *ScheduledCallback {
+			return scheduler.Schedule(start.Add(after), func(ctx Context) {
+				ran = append(ran, fmt.Sprintf("%v@%v", name, Now(ctx).Sub(start)))
+			})
+		}
+		schedule("a", 3*time.Minute)
+		schedule("b", time.Minute)
+		schedule("c", time.Minute)
+		canceled := schedule("d", 2*time.Minute)
+		schedule("e", -time.Minute)
+		scheduler.Schedule(start.Add(90*time.Second), func(ctx Context) {
+			// Callbacks can schedule and cancel others
+			s.True(canceled.Cancel())
+			s.False(canceled.Cancel())
+			schedule("f", 4*time.Minute)
+		})
+		if err := Sleep(ctx, 10*time.Minute); err != nil {
+			return nil, err
+		}
+		return ran, nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.NoError(env.GetWorkflowError())
+	var ran []string
+	s.NoError(env.GetWorkflowResult(&ran))
+	s.Equal([]string{"e@0s", "b@1m0s", "c@1m0s", "a@3m0s", "f@4m0s"}, ran)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_ActivityBoundedByRun() {
+	workflowFn := func(ctx Context) ([]time.Duration, error) {
+		timeout := func(ctx Context) time.Duration {
+			return getActivityOptions(WithActivityBoundedByRun(ctx)).ScheduleToCloseTimeout
+		}
+		if err := Sleep(ctx, 10*time.Minute); err != nil {
+			return nil, err
+		}
+		timeouts := []time.Duration{
+			timeout(ctx),
+			timeout(WithScheduleToCloseTimeout(ctx, 5*time.Minute)),
+			timeout(WithScheduleToCloseTimeout(ctx, 2*time.Hour)),
+		}
+		if err := Sleep(ctx, 50*time.Minute); err != nil {
+			return nil, err
+		}
+		return append(timeouts, timeout(ctx)), nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.SetWorkflowRunTimeout(time.Hour + time.Minute)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var timeouts []time.Duration
+	s.NoError(env.GetWorkflowResult(&timeouts))
+	s.Equal([]time.Duration{51 * time.Minute, 5 * time.Minute, 51 * time.Minute, time.Minute}, timeouts)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_AwaitMinimumRunDuration() {
+	workflowFn := func(ctx Context) ([]time.Duration, error) {
+		start := GetWorkflowInfo(ctx).WorkflowStartTime
+		if err := Sleep(ctx, 3*time.Minute); err != nil {
+			return nil, err
+		}
+		if err := AwaitMinimumRunDuration(ctx, 10*time.Minute); err != nil {
+			return nil, err
+		}
+		elapsed := []time.Duration{Now(ctx).Sub(start)}
+		// Already passed
+		if err := AwaitMinimumRunDuration(ctx, 5*time.Minute); err != nil {
+			return nil, err
+		}
+		return append(elapsed, Now(ctx).Sub(start)), nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var elapsed []time.Duration
+	s.NoError(env.GetWorkflowResult(&elapsed))
+	s.Equal([]time.Duration{10 * time.Minute, 10 * time.Minute}, elapsed)
+
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Minute)
+	env.ExecuteWorkflow(func(ctx Context) error {
+		return AwaitMinimumRunDuration(ctx, time.Hour)
+	})
+	s.True(env.IsWorkflowCompleted())
+	var canceledErr *CanceledError
+	s.ErrorAs(env.GetWorkflowError(), &canceledErr)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_WorkflowFriendlyName() {
 
 	workflowFn := func(ctx Context) error {
@@ -2767,6 +3111,111 @@ func (s *WorkflowTestSuiteUnitTest) Test_SignalExternalWorkflow() {
 	s.NoError(env.GetWorkflowError())
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_StartWorkflowHeaders() {
+	childWorkflowFn := func(ctx Context) (string, error) {
+		var tenant string
+		err := GetHeader(ctx, "tenant", &tenant)
+		return tenant, err
+	}
+	workflowFn := func(ctx Context, continued bool) (string, error) {
+		var tenant string
+		if err := GetHeader(ctx, "tenant", &tenant); err != nil {
+			return "", err
+		}
+		if err := GetHeader(ctx, "missing", &tenant); !errors.Is(err, ErrNoData) {
+			return "", fmt.Errorf("expected ErrNoData, got %w", err)
+		}
+		if continued {
+			return tenant, nil
+		}
+		var childTenant string
+		ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{})
+		if err := ExecuteChildWorkflow(ctx, childWorkflowFn).Get(ctx, &childTenant); err != nil {
+			return "", err
+		}
+		if childTenant != tenant {
+			return "", fmt.Errorf("child workflow got tenant %q", childTenant)
+		}
+		return "", NewContinueAsNewError(ctx, "continued", true)
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(childWorkflowFn)
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "continued"})
+	env.SetStartWorkflowOptions(StartWorkflowOptions{Headers: map[string]interface{}{"tenant": "acme"}})
+	env.ExecuteWorkflow("continued", false)
+	s.True(env.IsWorkflowCompleted())
+	var continueAsNewErr *ContinueAsNewError
+	s.ErrorAs(env.GetWorkflowError(), &continueAsNewErr)
+
+	info := env.GetContinueAsNewInfo()
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(childWorkflowFn)
+	env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "continued"})
+	env.ExecuteContinuedAsNewWorkflow(info)
+	s.NoError(env.GetWorkflowError())
+	var tenant string
+	s.NoError(env.GetWorkflowResult(&tenant))
+	s.Equal("acme", tenant)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_EmittedSignalsAndQueryTypes() {
+	workflowFn := func(ctx Context) error {
+		ctx = WithWorkflowNamespace(ctx, "test-namespace")
+		if err := SetQueryHandler(ctx, "state", func() (string, error) { return "started", nil }); err != nil {
+			return err
+		}
+		if err := SignalExternalWorkflow(ctx, "other-workflow", "", "progress", 50).Get(ctx, nil); err != nil {
+			return err
+		}
+		if err := Sleep(ctx, time.Minute); err != nil {
+			return err
+		}
+		if err := SetQueryHandler(ctx, "state", func() (string, error) { return "done", nil }); err != nil {
+			return err
+		}
+		if err := SetQueryHandler(ctx, "progress", func() (int, error) { return 100, nil }); err != nil {
+			return err
+		}
+		return SignalExternalWorkflow(ctx, "other-workflow", "other-run", "progress", 100).Get(ctx, nil)
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.OnSignalExternalWorkflow(mock.Anything, "other-workflow", mock.Anything, "progress", mock.Anything).Return(nil)
+	env.RegisterDelayedCallback(func() {
+		queryTypes, err := env.GetQueryTypes()
+		s.NoError(err)
+		s.Equal([]string{"state"}, queryTypes)
+		result, err := env.QueryWorkflow("state")
+		s.NoError(err)
+		var state string
+		s.NoError(result.Get(&state))
+		s.Equal("started", state)
+	}, 30*time.Second)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	signals := env.GetEmittedSignals()
+	s.Len(signals, 2)
+	var progress []int
+	for _, signal := range signals {
+		s.Equal(defaultTestWorkflowID, signal.SenderWorkflowID)
+		s.Equal("test-namespace", signal.Namespace)
+		s.Equal("other-workflow", signal.WorkflowID)
+		s.Equal("progress", signal.SignalName)
+		var value int
+		s.NoError(signal.Arg.Get(&value))
+		progress = append(progress, value)
+	}
+	s.Equal("other-run", signals[1].RunID)
+	s.Equal([]int{50, 100}, progress)
+
+	queryTypes, err := env.GetQueryTypes()
+	s.NoError(err)
+	s.Equal([]string{"progress", "state"}, queryTypes)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_CancelChildWorkflow() {
 	childWorkflowFn := func(ctx Context) error {
 		var err error
@@ -3135,6 +3584,48 @@ func (s *WorkflowTestSuiteUnitTest) Test_ActivityRetry() {
 	s.Equal(4, attempt2Count)
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_ActivityRetry_ReturnByAttempt() {
+	activityFn := func(ctx context.Context) (string, error) {
+		return "real", nil
+	}
+
+	workflowFn := func(ctx Context) ([]string, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy: &RetryPolicy{
+				MaximumAttempts: 5,
+				InitialInterval: time.Second,
+			},
+		})
+		var results []string
+		for i := 0; i < 2; i++ {
+			var result string
+			if err := ExecuteActivity(ctx, activityFn).Get(ctx, &result); err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(activityFn)
+	call := env.OnActivity(activityFn, mock.Anything).ReturnByAttempt([]interface{}{
+		NewApplicationError("first", "", false, nil),
+		errors.New("second"),
+		"mocked",
+	})
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var results []string
+	s.NoError(env.GetWorkflowResult(&results))
+	// The first activity succeeds on its third attempt, the second one reuses the last return
+	s.Equal([]string{"mocked", "mocked"}, results)
+	s.Equal(4, call.Attempts())
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_ActivityRetry_DefaultRetry() {
 	attemptCount1 := 0
 	activityFn := func(ctx context.Context) (string, error) {
@@ -3547,6 +4038,48 @@ func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflowRetry() {
 	s.Equal("retry-done", result)
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflowPreviousAttemptError() {
+	childWorkflowFn := func(ctx Context) (string, error) {
+		prevErr := GetPreviousAttemptError(ctx)
+		if GetWorkflowInfo(ctx).Attempt == 1 {
+			s.NoError(prevErr)
+			return "", NewApplicationError("bad-luck", "", false, nil)
+		}
+		if prevErr == nil {
+			return "", NewApplicationError("missing previous attempt error", "", true, nil)
+		}
+		return prevErr.Error(), nil
+	}
+
+	workflowFn := func(ctx Context) (string, error) {
+		ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{
+			WorkflowRunTimeout: time.Minute,
+			RetryPolicy:        &RetryPolicy{MaximumAttempts: 2, InitialInterval: time.Second},
+		})
+		var childResult string
+		err := ExecuteChildWorkflow(ctx, childWorkflowFn).Get(ctx, &childResult)
+		return childResult, err
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(childWorkflowFn)
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("bad-luck", result)
+
+	// The failure of a previous cron or schedule run is not a previous attempt error
+	env = s.NewTestWorkflowEnvironment()
+	env.SetLastError(errors.New("previous run failed"))
+	env.ExecuteWorkflow(func(ctx Context) error {
+		return GetPreviousAttemptError(ctx)
+	})
+	s.NoError(env.GetWorkflowError())
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflowTypedSearchAttributes() {
 
 	childWorkflowFn := func(ctx Context) error {
@@ -4262,6 +4795,50 @@ func (s *WorkflowTestSuiteUnitTest) Test_ActivityHeartbeatTimeout_WithDetails()
 	s.Equal("last-heartbeat-data", details)
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_ActivityLogs() {
+	activityFn := func(ctx context.Context) error {
+		logs := NewActivityLogBuffer(2)
+		for i := 1; i <= 3; i++ {
+			logs.Printf("processed batch %d", i)
+		}
+		logs.RecordHeartbeat(ctx, 3)
+		time.Sleep(2 * time.Second)
+		return nil
+	}
+
+	workflowFn := func(ctx Context) error {
+		logs, err := NewActivityLogs(ctx, "activity-logs")
+		if err != nil {
+			return err
+		}
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			ActivityID:          "batch",
+			StartToCloseTimeout: 10 * time.Second,
+			HeartbeatTimeout:    500 * time.Millisecond,
+			RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+		})
+		err = ExecuteActivity(ctx, activityFn).Get(ctx, nil)
+		s.True(logs.Record(err))
+		s.False(logs.Record(errors.New("not an activity error")))
+		var progress int
+		s.NoError(GetActivityLastHeartbeatDetails(err, &progress))
+		s.Equal(3, progress)
+		return nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(activityFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	result, err := env.QueryWorkflow("activity-logs")
+	s.NoError(err)
+	var lines map[string][]string
+	s.NoError(result.Get(&lines))
+	s.Equal(map[string][]string{"batch": {"processed batch 2", "processed batch 3"}}, lines)
+}
+
 // Test_ActivityStartToCloseTimeout tests that an activity that exceeds its
 // StartToCloseTimeout will fail with a start-to-close timeout error, even if
 // the activity ignores context cancellation.
@@ -4560,6 +5137,45 @@ func (s *WorkflowTestSuiteUnitTest) Test_AwaitWithTimeoutConditionMet_WithoutFla
 	s.True(timerExists, "Timer should NOT be cancelled (still in map) when SDKFlagCancelAwaitTimerOnCondition is disabled")
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_AwaitWithOptions() {
+	workflowFn := func(ctx Context, timeout time.Duration) (bool, error) {
+		conditionMet := false
+		Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, time.Minute)
+			conditionMet = true
+		})
+		options := AwaitOptions{Timeout: timeout, TimerOptions: TimerOptions{Summary: "awaiting approval"}}
+		return AwaitWithOptions(ctx, options, func() bool { return conditionMet })
+	}
+
+	// The timer is canceled once the condition is met
+	env := s.NewTestWorkflowEnvironment()
+	env.impl.sdkFlags.set(SDKFlagCancelAwaitTimerOnCondition)
+	var timerID string
+	env.SetOnTimerScheduledListener(func(id string, duration time.Duration) {
+		if duration == time.Hour {
+			timerID = id
+		}
+	})
+	env.ExecuteWorkflow(workflowFn, time.Hour)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result bool
+	s.NoError(env.GetWorkflowResult(&result))
+	s.True(result)
+	s.NotEmpty(timerID)
+	_, timerExists := env.impl.timers[timerID]
+	s.False(timerExists)
+
+	// The await times out before the condition is met
+	env = s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowFn, time.Second)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	s.NoError(env.GetWorkflowResult(&result))
+	s.False(result)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_NoDetachedChildWait() {
 	// One cron+abandon and one request-cancel
 	childOptionSet := []ChildWorkflowOptions{
@@ -4951,4 +5567,61 @@ func (s *WorkflowTestSuiteUnitTest) Test_OnWorkflowMockSeesHeaderContext() {
     s.NoError(env.GetWorkflowError())
     s.True(headerSeen, "OnWorkflow mock should see propagated header in context")
     env.AssertExpectations(s.T())
-}
\ No newline at end of file
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_NonDeterminismDetection() {
+	workflowFn := func(ctx Context, minutes map[string]int, sorted bool) error {
+		GetSignalChannel(ctx, "start").Receive(ctx, nil)
+		names := make([]string, 0, len(minutes))
+		for name := range minutes {
+			names = append(names, name)
+		}
+		if sorted {
+			sort.Strings(names)
+		}
+		var timers []Future
+		for _, name := range names {
+			timers = append(timers, NewTimer(ctx, time.Duration(minutes[name])*time.Minute))
+		}
+		for _, timer := range timers {
+			if err := timer.Get(ctx, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	minutes := make(map[string]int)
+	for i := 1; i <= 100; i++ {
+		minutes[fmt.Sprintf("timer%v", i)] = i
+	}
+	execute := func(sorted bool) error {
+		env := s.NewTestWorkflowEnvironment().EnableNonDeterminismDetection()
+		env.RegisterDelayedCallback(func() { env.SignalWorkflow("start", nil) }, time.Minute)
+		env.ExecuteWorkflow(workflowFn, minutes, sorted)
+		s.True(env.IsWorkflowCompleted())
+		return env.GetWorkflowError()
+	}
+
+	s.NoError(execute(true))
+
+	// Callbacks registered before enabling detection also run in both executions
+	env := s.NewTestWorkflowEnvironment()
+	var signals int
+	env.RegisterDelayedCallback(func() {
+		signals++
+		env.SignalWorkflow("start", nil)
+	}, time.Minute)
+	env.EnableNonDeterminismDetection()
+	env.ExecuteWorkflow(workflowFn, minutes, true)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	s.Equal(2, signals)
+
+	// Both executions can iterate the map in the same order by chance, so retry a few times
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = execute(false)
+	}
+	s.ErrorContains(err, "nondeterministic workflow: command")
+	s.ErrorContains(err, "is StartTimer")
+}
@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is an operation of a JSON patch (RFC 6902).
+//
+// Exposed as: [go.temporal.io/sdk/workflow.JSONPatchOperation]
+type JSONPatchOperation struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" and "test".
+	Op string `json:"op"`
+	// Path is the JSON pointer (RFC 6901) of the location the operation applies to.
+	Path string `json:"path"`
+	// From is the JSON pointer of the location to move or copy from.
+	From string `json:"from,omitempty"`
+	// Value is the value to add, replace or test with.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONMerge applies a JSON merge patch (RFC 7396) to current and decodes the result into a new T.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ApplyJSONMerge]
+func ApplyJSONMerge[T any](current T, patch interface{}) (T, error) {
+	var result T
+	doc, err := toJSONDocument(current)
+	if err != nil {
+		return result, err
+	}
+	patchDoc, err := toJSONDocument(patch)
+	if err != nil {
+		return result, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	err = fromJSONDocument(mergeJSONPatch(doc, patchDoc), &result)
+	return result, err
+}
+
+// ApplyJSONPatch applies the operations of a JSON patch (RFC 6902) in order to current and decodes the result into a
+// new T. If an operation fails, none of the operations are applied.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ApplyJSONPatch]
+func ApplyJSONPatch[T any](current T, patch []JSONPatchOperation) (T, error) {
+	var result T
+	doc, err := toJSONDocument(current)
+	if err != nil {
+		return result, err
+	}
+	for i, op := range patch {
+		if doc, err = applyJSONPatchOperation(doc, op); err != nil {
+			return result, fmt.Errorf("json patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	err = fromJSONDocument(doc, &result)
+	return result, err
+}
+
+// toJSONDocument converts v into its generic JSON representation. Raw JSON is parsed as is, other values are encoded
+// to JSON first. Numbers are kept as json.Number so they are not rounded.
+func toJSONDocument(v interface{}) (interface{}, error) {
+	var data []byte
+	switch raw := v.(type) {
+	case json.RawMessage:
+		data = raw
+	case []byte:
+		data = raw
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// fromJSONDocument decodes a generic JSON representation into valuePtr. Object keys are encoded in sorted order, so
+// the result does not depend on map iteration order.
+func fromJSONDocument(doc interface{}, valuePtr interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if raw, ok := valuePtr.(*json.RawMessage); ok {
+		*raw = data
+		return nil
+	}
+	if raw, ok := valuePtr.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, valuePtr)
+}
+
+func mergeJSONPatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergeJSONPatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+func applyJSONPatchOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add", "replace":
+		value, err := toJSONDocument(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return setJSONValue(doc, path, value, op.Op == "add")
+	case "remove":
+		doc, _, err = removeJSONValue(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if op.Op == "move" {
+			if op.Path == op.From {
+				return doc, nil
+			}
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, errors.New("cannot move a value into itself")
+			}
+			if doc, value, err = removeJSONValue(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = getJSONValue(doc, from); err != nil {
+				return nil, err
+			}
+			// Operations on the copy must not change the original
+			if value, err = toJSONDocument(value); err != nil {
+				return nil, err
+			}
+		}
+		return setJSONValue(doc, path, value, true)
+	case "test":
+		actual, err := getJSONValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := toJSONDocument(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func jsonArrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > length || (index == length && !allowEnd) || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+func getJSONValue(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			index, err := jsonArrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[index]
+		default:
+			return nil, fmt.Errorf("cannot traverse %q in a JSON value that is not an object or array", token)
+		}
+	}
+	return doc, nil
+}
+
+// updateJSONParent calls update with the container of the last token of path, which is not empty, and returns doc
+// with the container replaced by the one update returns.
+func updateJSONParent(
+	doc interface{},
+	path []string,
+	update func(container interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+	child, err := getJSONValue(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = updateJSONParent(child, path[1:], update); err != nil {
+		return nil, err
+	}
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		container[path[0]] = child
+	case []interface{}:
+		index, _ := jsonArrayIndex(path[0], len(container), false)
+		container[index] = child
+	}
+	return doc, nil
+}
+
+func setJSONValue(doc interface{}, path []string, value interface{}, insert bool) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateJSONParent(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok && !insert {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			index, err := jsonArrayIndex(token, len(container), insert)
+			if err != nil {
+				return nil, err
+			}
+			if !insert {
+				container[index] = value
+				return container, nil
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		default:
+			return nil, fmt.Errorf("cannot set %q in a JSON value that is not an object or array", token)
+		}
+	})
+}
+
+func removeJSONValue(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := updateJSONParent(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			removed = value
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			index, err := jsonArrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = container[index]
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q from a JSON value that is not an object or array", token)
+		}
+	})
+	return doc, removed, err
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type jsonMergeOrder struct {
+	Status  string            `json:"status"`
+	Coupon  string            `json:"coupon,omitempty"`
+	Items   []string          `json:"items"`
+	Address map[string]string `json:"address"`
+	Total   int64             `json:"total"`
+}
+
+func TestApplyJSONMerge(t *testing.T) {
+	order := jsonMergeOrder{
+		Status:  "paid",
+		Coupon:  "SAVE10",
+		Items:   []string{"book"},
+		Address: map[string]string{"city": "San Francisco", "zip": "94105"},
+		Total:   9007199254740993,
+	}
+	merged, err := ApplyJSONMerge(order, map[string]interface{}{
+		"status":  "shipped",
+		"coupon":  nil,
+		"items":   []string{"book", "pen"},
+		"address": map[string]interface{}{"zip": "94107"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, jsonMergeOrder{
+		Status:  "shipped",
+		Items:   []string{"book", "pen"},
+		Address: map[string]string{"city": "San Francisco", "zip": "94107"},
+		Total:   9007199254740993,
+	}, merged)
+	// The current value is not modified
+	require.Equal(t, "94105", order.Address["zip"])
+
+	// Raw JSON is merged with object keys in sorted order
+	raw, err := ApplyJSONMerge(json.RawMessage(`{"b":1,"a":{"y":1,"x":2}}`), []byte(`{"c":3,"a":{"y":null}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"a":{"x":2},"b":1,"c":3}`, string(raw))
+
+	_, err = ApplyJSONMerge(order, []byte(`{`))
+	require.ErrorContains(t, err, "invalid merge patch")
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := json.RawMessage(`{"status":"paid","items":["book"],"address":{"zip":"94105"}}`)
+	patched, err := ApplyJSONPatch(doc, []JSONPatchOperation{
+		{Op: "test", Path: "/status", Value: "paid"},
+		{Op: "replace", Path: "/status", Value: "shipped"},
+		{Op: "add", Path: "/items/-", Value: "pen"},
+		{Op: "add", Path: "/items/0", Value: "lamp"},
+		{Op: "copy", From: "/address", Path: "/billing"},
+		{Op: "replace", Path: "/billing/zip", Value: "10001"},
+		{Op: "move", From: "/items/2", Path: "/gift"},
+		{Op: "remove", Path: "/address"},
+		{Op: "add", Path: "/a~1b", Value: map[string]int{"n": 1}},
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"a/b":{"n":1},"billing":{"zip":"10001"},"gift":"pen","items":["lamp","book"],"status":"shipped"}`,
+		string(patched))
+
+	for _, op := range []JSONPatchOperation{
+		{Op: "test", Path: "/status", Value: "shipped"},
+		{Op: "remove", Path: "/missing"},
+		{Op: "replace", Path: "/items/1", Value: "pen"},
+		{Op: "add", Path: "/items/01", Value: "pen"},
+		{Op: "move", From: "/address", Path: "/address/old"},
+		{Op: "add", Path: "status", Value: "shipped"},
+		{Op: "merge", Path: "/status"},
+	} {
+		result, err := ApplyJSONPatch(doc, []JSONPatchOperation{{Op: "remove", Path: "/status"}, op})
+		require.ErrorContains(t, err, "json patch operation 1 ("+op.Op, op)
+		require.Nil(t, result)
+	}
+	require.Contains(t, string(doc), `"status":"paid"`)
+}
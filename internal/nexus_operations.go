@@ -479,6 +479,36 @@ func (t *testSuiteClientForNexusOperations) CountWorkflow(ctx context.Context, r
 	panic("not implemented in the test environment")
 }
 
+// AddSearchAttributes implements Client.
+func (t *testSuiteClientForNexusOperations) AddSearchAttributes(ctx context.Context, options AddSearchAttributesOptions) error {
+	panic("not implemented in the test environment")
+}
+
+// ListSearchAttributes implements Client.
+func (t *testSuiteClientForNexusOperations) ListSearchAttributes(ctx context.Context, options ListSearchAttributesOptions) (*SearchAttributesSchema, error) {
+	panic("not implemented in the test environment")
+}
+
+// DescribeNamespace implements Client.
+func (t *testSuiteClientForNexusOperations) DescribeNamespace(ctx context.Context, namespace string) (*NamespaceDescription, error) {
+	panic("not implemented in the test environment")
+}
+
+// ForceNewWorkflowTask implements Client.
+func (t *testSuiteClientForNexusOperations) ForceNewWorkflowTask(ctx context.Context, workflowID string, runID string) error {
+	panic("not implemented in the test environment")
+}
+
+// CountWorkflowWithOptions implements Client.
+func (t *testSuiteClientForNexusOperations) CountWorkflowWithOptions(ctx context.Context, options CountWorkflowOptions) (*CountWorkflowResult, error) {
+	panic("not implemented in the test environment")
+}
+
+// ListStuckWorkflows implements Client.
+func (t *testSuiteClientForNexusOperations) ListStuckWorkflows(ctx context.Context, options ListStuckWorkflowsOptions) ([]StuckWorkflow, error) {
+	panic("not implemented in the test environment")
+}
+
 // DescribeTaskQueue implements Client.
 func (t *testSuiteClientForNexusOperations) DescribeTaskQueue(ctx context.Context, taskqueue string, taskqueueType enums.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
 	panic("not implemented in the test environment")
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// OrderedSet is a set that keeps its elements sorted, so iterating over it is
+// deterministic, unlike iterating over a map. It is not safe for concurrent
+// use outside of a workflow.
+//
+// The zero value is an empty set ordered like NewOrderedSet, so it is only
+// usable with element types whose underlying type is ordered. It marshals to
+// JSON as the array of its sorted elements.
+type OrderedSet[T any] struct {
+	cmp    func(a, b T) int
+	values []T
+}
+
+// NewOrderedSet creates an empty OrderedSet of ordered values.
+func NewOrderedSet[T cmp.Ordered]() *OrderedSet[T] {
+	return NewOrderedSetFunc[T](cmp.Compare[T])
+}
+
+// NewOrderedSetFunc creates an empty OrderedSet whose elements are ordered by
+// cmp. cmp(a, b) should return a negative number when a < b, a positive number
+// when a > b and zero when a and b are the same element.
+func NewOrderedSetFunc[T any](cmp func(a, b T) int) *OrderedSet[T] {
+	if cmp == nil {
+		panic("cmp must not be nil")
+	}
+	return &OrderedSet[T]{cmp: cmp}
+}
+
+// Add adds v to the set and reports whether it was not already present.
+func (s *OrderedSet[T]) Add(v T) bool {
+	i, found := slices.BinarySearchFunc(s.values, v, s.compare())
+	if found {
+		return false
+	}
+	s.values = slices.Insert(s.values, i, v)
+	return true
+}
+
+// Remove removes v from the set and reports whether it was present.
+func (s *OrderedSet[T]) Remove(v T) bool {
+	if len(s.values) == 0 {
+		return false
+	}
+	i, found := slices.BinarySearchFunc(s.values, v, s.compare())
+	if !found {
+		return false
+	}
+	s.values = slices.Delete(s.values, i, i+1)
+	return true
+}
+
+// Contains reports whether v is in the set.
+func (s *OrderedSet[T]) Contains(v T) bool {
+	if len(s.values) == 0 {
+		return false
+	}
+	_, found := slices.BinarySearchFunc(s.values, v, s.compare())
+	return found
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[T]) Len() int {
+	return len(s.values)
+}
+
+// Values returns the elements of the set in sorted order. The returned slice
+// is a copy, so it can be modified without affecting the set.
+func (s *OrderedSet[T]) Values() []T {
+	return slices.Clone(s.values)
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements in sorted order.
+func (s *OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	if s.values == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.values)
+}
+
+// UnmarshalJSON replaces the elements of the set with the ones of a JSON
+// array, keeping the order of the set. To decode a set whose element type is
+// not ordered, create it with NewOrderedSetFunc before decoding into it.
+func (s *OrderedSet[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	s.values = nil
+	for _, v := range values {
+		s.Add(v)
+	}
+	return nil
+}
+
+// compare returns the order of the set, choosing the natural order of the
+// element type on first use of a zero value.
+func (s *OrderedSet[T]) compare() func(a, b T) int {
+	if s.cmp == nil {
+		s.cmp = orderedCompare[T]()
+	}
+	return s.cmp
+}
+
+// orderedCompare returns a function that compares values whose underlying
+// type is ordered, or panics for other types.
+func orderedCompare[T any]() func(a, b T) int {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int { return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int { return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint()) }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int { return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float()) }
+	case reflect.String:
+		return func(a, b T) int { return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String()) }
+	default:
+		panic(fmt.Sprintf("zero value OrderedSet of unordered type %v, use NewOrderedSetFunc", t))
+	}
+}
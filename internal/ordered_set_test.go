@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSet[string]()
+	require.True(t, s.Add("c"))
+	require.True(t, s.Add("a"))
+	require.True(t, s.Add("b"))
+	require.False(t, s.Add("a"))
+	require.Equal(t, 3, s.Len())
+	require.Equal(t, []string{"a", "b", "c"}, s.Values())
+	require.True(t, s.Contains("b"))
+	require.False(t, s.Contains("d"))
+
+	require.True(t, s.Remove("b"))
+	require.False(t, s.Remove("b"))
+	require.False(t, s.Contains("b"))
+	require.Equal(t, []string{"a", "c"}, s.Values())
+
+	// Values returns a fresh slice each call
+	values := s.Values()
+	values[0] = "z"
+	values = append(values[:1], "y")
+	require.Equal(t, []string{"a", "c"}, s.Values())
+	require.Equal(t, []string{"z", "y"}, values)
+}
+
+func TestOrderedSetFunc(t *testing.T) {
+	type item struct {
+		name string
+	}
+	s := NewOrderedSetFunc(func(a, b item) int {
+		return strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+	})
+	require.True(t, s.Add(item{"B"}))
+	require.True(t, s.Add(item{"a"}))
+	require.False(t, s.Add(item{"b"}))
+	require.Equal(t, []item{{"a"}, {"B"}}, s.Values())
+	require.True(t, s.Contains(item{"A"}))
+
+	require.Panics(t, func() { NewOrderedSetFunc[item](nil) })
+}
+
+func TestOrderedSetZeroValue(t *testing.T) {
+	type name string
+	var s OrderedSet[name]
+	require.False(t, s.Contains("a"))
+	require.False(t, s.Remove("a"))
+	require.True(t, s.Add("b"))
+	require.True(t, s.Add("a"))
+	require.Equal(t, []name{"a", "b"}, s.Values())
+
+	var unordered OrderedSet[struct{}]
+	require.Zero(t, unordered.Len())
+	require.Panics(t, func() { unordered.Add(struct{}{}) })
+}
+
+func TestOrderedSetJSON(t *testing.T) {
+	type state struct {
+		Pending *OrderedSet[int]
+		Done    OrderedSet[int]
+	}
+	in := state{Pending: NewOrderedSet[int]()}
+	in.Pending.Add(3)
+	in.Pending.Add(1)
+	in.Done.Add(2)
+	data, err := json.Marshal(&in)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Pending":[1,3],"Done":[2]}`, string(data))
+
+	var out state
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, []int{1, 3}, out.Pending.Values())
+	require.Equal(t, []int{2}, out.Done.Values())
+
+	// Decoding keeps the order of the set and replaces its elements
+	s := NewOrderedSetFunc(func(a, b string) int { return strings.Compare(b, a) })
+	s.Add("x")
+	require.NoError(t, json.Unmarshal([]byte(`["a","c","b","a"]`), s))
+	require.Equal(t, []string{"c", "b", "a"}, s.Values())
+
+	data, err = json.Marshal(&OrderedSet[int]{})
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(data))
+}
@@ -0,0 +1,96 @@
+package internal
+
+import "container/heap"
+
+// PriorityQueue is a queue that pops its items in priority order, breaking
+// ties by insertion order, so the order items are popped in only depends on
+// the order they were pushed in. It is not safe for concurrent use outside of
+// a workflow.
+type PriorityQueue[T any] struct {
+	items priorityQueueItems[T]
+	seq   uint64
+}
+
+type priorityQueueItem[T any] struct {
+	value T
+	seq   uint64
+}
+
+// priorityQueueItems implements heap.Interface.
+type priorityQueueItems[T any] struct {
+	less   func(a, b T) bool
+	values []priorityQueueItem[T]
+}
+
+// NewPriorityQueue creates an empty PriorityQueue whose items are popped
+// lowest first according to less. less must be deterministic and a strict
+// weak ordering.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	if less == nil {
+		panic("less must not be nil")
+	}
+	return &PriorityQueue[T]{items: priorityQueueItems[T]{less: less}}
+}
+
+// Push adds v to the queue.
+func (q *PriorityQueue[T]) Push(v T) {
+	heap.Push(&q.items, priorityQueueItem[T]{value: v, seq: q.seq})
+	q.seq++
+}
+
+// Pop removes and returns the item with the highest priority. It returns
+// false if the queue is empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.items.values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&q.items).(priorityQueueItem[T]).value, true
+}
+
+// Peek returns the item with the highest priority without removing it. It
+// returns false if the queue is empty.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items.values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items.values[0].value, true
+}
+
+// Len returns the number of items in the queue.
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items.values)
+}
+
+func (h *priorityQueueItems[T]) Len() int {
+	return len(h.values)
+}
+
+func (h *priorityQueueItems[T]) Less(i, j int) bool {
+	a, b := h.values[i], h.values[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (h *priorityQueueItems[T]) Swap(i, j int) {
+	h.values[i], h.values[j] = h.values[j], h.values[i]
+}
+
+func (h *priorityQueueItems[T]) Push(x any) {
+	h.values = append(h.values, x.(priorityQueueItem[T]))
+}
+
+func (h *priorityQueueItems[T]) Pop() any {
+	last := len(h.values) - 1
+	item := h.values[last]
+	// Release the reference to the value
+	h.values[last] = priorityQueueItem[T]{}
+	h.values = h.values[:last]
+	return item
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+	_, ok := q.Pop()
+	require.False(t, ok)
+	_, ok = q.Peek()
+	require.False(t, ok)
+
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(v)
+	}
+	require.Equal(t, 5, q.Len())
+	v, ok := q.Peek()
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	require.Equal(t, 5, q.Len())
+
+	var popped []int
+	for q.Len() > 0 {
+		v, ok := q.Pop()
+		require.True(t, ok)
+		popped = append(popped, v)
+	}
+	require.Equal(t, []int{1, 2, 3, 4, 5}, popped)
+}
+
+func TestPriorityQueue_StableForEqualPriorities(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+	q := NewPriorityQueue(func(a, b job) bool { return a.priority > b.priority })
+	for i, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		q.Push(job{name: name, priority: i % 2})
+	}
+	// Interleave pushes and pops, equal priority items must still come out in
+	// insertion order
+	var popped []string
+	for range 3 {
+		j, _ := q.Pop()
+		popped = append(popped, j.name)
+	}
+	q.Push(job{name: "i", priority: 1})
+	q.Push(job{name: "j", priority: 0})
+	for q.Len() > 0 {
+		j, _ := q.Pop()
+		popped = append(popped, j.name)
+	}
+	require.Equal(t, []string{"b", "d", "f", "h", "i", "a", "c", "e", "g", "j"}, popped)
+}
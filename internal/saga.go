@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"errors"
+)
+
+type (
+	// SagaOptions are options for NewSaga.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SagaOptions]
+	SagaOptions struct {
+		// ParallelCompensation runs all compensations concurrently instead of one after another in reverse order
+		// of registration. Compensate waits for all of them to finish.
+		ParallelCompensation bool
+
+		// ContinueWithError keeps running the remaining compensations after one fails. By default, sequential
+		// compensation stops at the first failure. Compensations always all run when ParallelCompensation is set.
+		ContinueWithError bool
+	}
+
+	// Saga collects compensations for the steps of a workflow so they can be run if a later step fails.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.Saga]
+	Saga struct {
+		options       SagaOptions
+		compensations []func(ctx Context) error
+	}
+)
+
+// NewSaga creates a Saga with no compensations.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewSaga]
+func NewSaga(options SagaOptions) *Saga {
+	return &Saga{options: options}
+}
+
+// AddCompensation registers a compensation for a step that has completed.
+func (s *Saga) AddCompensation(fn func(ctx Context) error) {
+	s.compensations = append(s.compensations, fn)
+}
+
+// Compensate runs the registered compensations and removes them from the saga. The returned error joins the errors of
+// all failed compensations.
+func (s *Saga) Compensate(ctx Context) error {
+	assertNotInReadOnlyState(ctx)
+	compensations := s.compensations
+	s.compensations = nil
+	if s.options.ParallelCompensation {
+		futures := make([]Future, len(compensations))
+		for i, compensation := range compensations {
+			compensation := compensation
+			future, settable := NewFuture(ctx)
+			Go(ctx, func(ctx Context) {
+				settable.SetError(compensation(ctx))
+			})
+			futures[i] = future
+		}
+		var errs []error
+		for _, future := range futures {
+			if err := future.Get(ctx, nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	var errs []error
+	for i := len(compensations) - 1; i >= 0; i-- {
+		if err := compensations[i](ctx); err != nil {
+			errs = append(errs, err)
+			if !s.options.ContinueWithError {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sagaTestActivities struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (a *sagaTestActivities) Step(_ context.Context, name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, name)
+	if name == "fail" {
+		return errors.New("step failed")
+	}
+	return nil
+}
+
+func runSagaTestWorkflow(t *testing.T, options SagaOptions, compensations []string) ([]string, error) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	activities := &sagaTestActivities{}
+	env.RegisterActivity(activities)
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+		})
+		saga := NewSaga(options)
+		for i, step := range []string{"reserve-hotel", "reserve-flight", "fail"} {
+			if err := ExecuteActivity(ctx, activities.Step, step).Get(ctx, nil); err != nil {
+				return errors.Join(err, saga.Compensate(ctx))
+			}
+			compensation := compensations[i]
+			saga.AddCompensation(func(ctx Context) error {
+				return ExecuteActivity(ctx, activities.Step, compensation).Get(ctx, nil)
+			})
+		}
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	return activities.calls, env.GetWorkflowError()
+}
+
+func TestSagaCompensatesInReverseOrder(t *testing.T) {
+	calls, err := runSagaTestWorkflow(t, SagaOptions{}, []string{"cancel-hotel", "cancel-flight", ""})
+	require.ErrorContains(t, err, "step failed")
+	require.Equal(t, []string{"reserve-hotel", "reserve-flight", "fail", "cancel-flight", "cancel-hotel"}, calls)
+}
+
+func TestSagaStopsAtFirstCompensationFailure(t *testing.T) {
+	calls, err := runSagaTestWorkflow(t, SagaOptions{}, []string{"cancel-hotel", "fail", ""})
+	require.Error(t, err)
+	require.Equal(t, []string{"reserve-hotel", "reserve-flight", "fail", "fail"}, calls)
+}
+
+func TestSagaContinueWithError(t *testing.T) {
+	calls, err := runSagaTestWorkflow(t, SagaOptions{ContinueWithError: true}, []string{"cancel-hotel", "fail", ""})
+	require.Error(t, err)
+	require.Equal(t, []string{"reserve-hotel", "reserve-flight", "fail", "fail", "cancel-hotel"}, calls)
+}
+
+func TestSagaParallelCompensation(t *testing.T) {
+	calls, err := runSagaTestWorkflow(t, SagaOptions{ParallelCompensation: true}, []string{"fail", "cancel-flight", ""})
+	require.Error(t, err)
+	require.Equal(t, []string{"reserve-hotel", "reserve-flight", "fail"}, calls[:3])
+	compensationCalls := calls[3:]
+	sort.Strings(compensationCalls)
+	require.Equal(t, []string{"cancel-flight", "fail"}, compensationCalls)
+}
@@ -596,6 +596,27 @@ type (
 		Backfill []ScheduleBackfill
 	}
 
+	// ScheduleBackfillResult describes the Actions taken by a backfill.
+	//
+	// Exposed as: [go.temporal.io/sdk/client.ScheduleBackfillResult]
+	ScheduleBackfillResult struct {
+		// MatchingTimes - Times in the backfilled periods matched by the schedule spec, i.e. the times the backfill
+		// attempted to take an Action for, sorted per period.
+		MatchingTimes []time.Time
+
+		// Actions - Actions taken by the backfill, i.e. recent Actions of the Schedule whose ScheduleTime is one of
+		// MatchingTimes. The Server only retains a limited number of recent Actions, so this may be incomplete for
+		// large backfills.
+		Actions []ScheduleActionResult
+
+		// OverlapSkipped - Number of Actions skipped due to the overlap policy while waiting for the backfill.
+		OverlapSkipped int64
+
+		// BufferDropped - Number of Actions dropped because the buffer of the overlap policy was full while waiting
+		// for the backfill.
+		BufferDropped int64
+	}
+
 	// ScheduleHandle represents a created schedule.
 	ScheduleHandle interface {
 		// GetID returns the schedule ID associated with this handle.
@@ -607,6 +628,15 @@ type (
 		// Backfill the schedule by going though the specified time periods and taking Actions as if that time passed by right now, all at once.
 		Backfill(ctx context.Context, options ScheduleBackfillOptions) error
 
+		// BackfillAndWait backfills the schedule like Backfill, and waits until the Server reports that an Action was
+		// taken, skipped or dropped for every time matched by the schedule spec in the backfilled periods. Actions
+		// buffered by the overlap policy are waited for, so the context should have a deadline. The counts of the
+		// Schedule are shared with regular Actions and other backfills taking place at the same time, which makes the
+		// result inaccurate in that case.
+		//
+		// Requires a Server supporting ListScheduleMatchingTimes, which is available wherever schedules are.
+		BackfillAndWait(ctx context.Context, options ScheduleBackfillOptions) (*ScheduleBackfillResult, error)
+
 		// Update the Schedule.
 		//
 		// NOTE: If two Update calls are made in parallel to the same Schedule there is the potential
@@ -626,6 +656,29 @@ type (
 
 		// Unpause the Schedule will also overwrite the Schedules current note with the new note.
 		Unpause(ctx context.Context, options ScheduleUnpauseOptions) error
+
+		// ListRecentActions returns up to the n most recent Actions taken by the Schedule, including manual
+		// triggers, sorted from older start time to newer. If n is not positive, all recent Actions known to the
+		// Server are returned. The Server only retains a limited number of recent Actions. Returns an empty slice if
+		// the Schedule has never taken an Action.
+		ListRecentActions(ctx context.Context, n int) ([]ScheduleActionResult, error)
+
+		// TriggerAndWait triggers an Action to be taken immediately, like Trigger, and waits until the Server
+		// reports the resulting workflow run, returning a handle to it. The Action is identified by its scheduled
+		// time, so Actions the Schedule takes concurrently are not mistaken for it. Because the Schedule's state is
+		// eventually consistent, this polls Describe until the Action is visible. An error is returned if the
+		// Schedule skips an Action without taking one, e.g. due to the overlap policy, or if the Action is not
+		// reported within about a minute.
+		TriggerAndWait(ctx context.Context, options ScheduleTriggerOptions) (WorkflowRun, error)
+
+		// AwaitNextRun waits until the Schedule takes its next Action, scheduled or triggered by anyone, and returns
+		// a handle to the workflow run it started. This is mostly useful in integration tests of scheduled
+		// workflows. Like TriggerAndWait, it polls Describe, so the context should have a deadline.
+		//
+		// If the Schedule fires multiple times between two polls, the earliest run started after the call is
+		// returned. Actions skipped due to the overlap policy are not waited for. If the Schedule is paused when
+		// called or becomes paused while waiting, an error wrapping ErrSchedulePaused is returned.
+		AwaitNextRun(ctx context.Context) (WorkflowRun, error)
 	}
 
 	// ScheduleActionResult describes when a schedule action took place
@@ -641,6 +694,10 @@ type (
 		// StartWorkflowResult - If action was ScheduleWorkflowAction, returns the
 		// ID of the workflow.
 		StartWorkflowResult *ScheduleWorkflowExecution
+
+		// StartWorkflowStatus - If action was ScheduleWorkflowAction, an eventually-consistent
+		// view of the status of the started workflow. Unspecified if not reported by the Server.
+		StartWorkflowStatus enumspb.WorkflowExecutionStatus
 	}
 
 	// ScheduleListEntry
@@ -2,6 +2,9 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"slices"
 	"time"
 
 	deploymentpb "go.temporal.io/api/deployment/v1"
@@ -196,6 +199,16 @@ type (
 		// default: false
 		EnableLoggingInReplay bool
 
+		// Optional: Log every command sent to the server on workflow task completion at debug level with the
+		// workflow logger, with the command type and its key attributes like the activity ID or timer ID. Each entry
+		// includes the index of the command in the task and the started event ID of the workflow task, the events
+		// created for the commands follow the WorkflowTaskCompleted event in the same order. Commands regenerated
+		// while replaying history are not logged. This is only useful for debugging purpose and is more targeted
+		// than verbose logging.
+		//
+		// default: false
+		EnableWorkflowCommandLogging bool
+
 		// Optional: Sticky schedule to start timeout.
 		// The resolution is seconds.
 		//
@@ -209,6 +222,14 @@ type (
 		// default: 5s
 		StickyScheduleToStartTimeout time.Duration
 
+		// Optional: Workflows whose state is loaded into the sticky cache in the background when the worker starts,
+		// so that their next workflow task does not replay the full history, e.g. to avoid a latency spike after a
+		// deploy. Best-effort: workflows that fail to load are skipped, and no more workflows are loaded than fit in
+		// the cache. See StickyCachePrewarmOptions for the cost.
+		//
+		// NOTE: Experimental
+		StickyCachePrewarm StickyCachePrewarmOptions
+
 		// Optional: sets root context for all activities. The context can be used to pass external dependencies
 		// like DB connections to activity functions.
 		// Note that this method of passing dependencies is not recommended anymore.
@@ -221,6 +242,7 @@ type (
 		// definition changes) and other panics raised from workflow code.
 		//
 		// default: BlockWorkflow, which just logs error but doesn't fail workflow.
+		// Can be overridden per workflow type with RegisterWorkflowOptions.PanicPolicy.
 		WorkflowPanicPolicy WorkflowPanicPolicy
 
 		// Optional: worker graceful stop timeout
@@ -264,25 +286,112 @@ type (
 		// default: client identity
 		Identity string
 
+		// Optional: If set overwrites Identity for the pollers of workflow tasks and the RPCs that complete them, so
+		// the workflow and activity pollers of a process can be told apart, e.g. in the pollers listed by
+		// DescribeTaskQueue.
+		//
+		// default: Identity
+		WorkflowIdentity string
+
+		// Optional: If set overwrites Identity for the pollers of activity tasks, including the ones of sessions,
+		// and the RPCs that complete and heartbeat them.
+		//
+		// default: Identity
+		ActivityIdentity string
+
+		// Optional: If set overwrites Identity for the pollers of Nexus tasks and the RPCs that complete them.
+		//
+		// default: Identity
+		NexusIdentity string
+
 		// Optional: If set defines maximum amount of time that workflow task will be allowed to run. Defaults to 1 sec.
+		// Can be overridden per workflow type with RegisterWorkflowOptions.DeadlockDetectionTimeout.
 		DeadlockDetectionTimeout time.Duration
 
+		// Optional: The fraction of the workflow task timeout after which a workflow task that is still waiting on
+		// local activities is completed and a new one is started (a forced workflow task heartbeat). Must be between 0
+		// and 1 exclusive. Lower values make it less likely that a slow, e.g. CPU-heavy, workflow task times out, at
+		// the cost of more workflow task events in history.
+		//
+		// default: 0.8
+		WorkflowTaskForcedHeartbeatThreshold float64
+
+		// Optional: If set, a workflow task whose replay of previous history takes longer than this increments the
+		// temporal_workflow_task_slow_replay metric and calls OnSlowReplay. Slow replays usually mean the history of a
+		// workflow has grown large enough that it should continue-as-new sooner.
+		//
+		// default: 0, disabled
+		SlowReplayThreshold time.Duration
+
+		// Optional: Callback invoked when the replay of a workflow task exceeds SlowReplayThreshold. It is called on
+		// the workflow task processing path, so it should return quickly.
+		OnSlowReplay func(SlowReplayInfo)
+
+		// Optional: The fraction of completed workflow tasks, between 0 and 1, that are checked for non-determinism
+		// by replaying the workflow from the start of its history on a fresh, uncached state and comparing the
+		// commands it produces against the ones the task completed with. A mismatch means the cached workflow state
+		// has diverged from what replaying its history produces, which would otherwise only surface as a
+		// non-determinism error after the workflow is evicted from the cache or moves to another worker. Each mismatch
+		// is logged and increments the temporal_workflow_task_shadow_replay_mismatch metric.
+		//
+		// This is expensive: every sampled task fetches the full workflow history from the server and replays all of
+		// it, so keep the rate low in production. The workflow code of the sampled task runs a second time, with its
+		// logs and metrics suppressed. Tasks that ran local activities are not checked.
+		//
+		// NOTE: Experimental
+		//
+		// default: 0, disabled
+		ShadowReplaySampleRate float64
+
+		// Optional: Fail workflow tasks whose shadow replay, see ShadowReplaySampleRate, does not match, instead of
+		// only reporting the mismatch. The task is failed as non-deterministic and its cached state is evicted, so the
+		// server retries it on a state rebuilt from history.
+		//
+		// NOTE: Experimental
+		//
+		// default: false
+		FailWorkflowTaskOnShadowReplayMismatch bool
+
+		// Optional: Report each continue-as-new of a workflow by recording the length of the history it continued as
+		// new from in the temporal_workflow_continue_as_new_history_length gauge, tagged by workflow type. Together
+		// with the temporal_workflow_continue_as_new counter this shows workflow types that continue-as-new too often,
+		// churning through runs, or too rarely, growing large histories. Only the workflow task that issues the
+		// continue-as-new reports it, replays never do.
+		//
+		// default: false
+		ReportContinueAsNew bool
+
+		// Optional: Also log each continue-as-new reported with ReportContinueAsNew, with the workflow execution, the
+		// history length and the workflow type and task queue of the new run.
+		//
+		// default: false
+		LogContinueAsNew bool
+
 		// Optional: The maximum amount of time between sending each pending heartbeat to the server. Regardless of
 		// heartbeat timeout, no pending heartbeat will wait longer than this amount of time to send. To effectively disable
 		// heartbeat throttling, this can be set to something like 1 nanosecond, but it is not recommended.
 		//
+		// Activities only learn that they were canceled from the response to a heartbeat sent to the server, so the
+		// throttle interval bounds how long cancellation takes to be detected. Lowering it detects cancellation sooner,
+		// but sends more heartbeat requests to the server for activities that heartbeat often. Must not be negative.
+		//
 		// default: 60 seconds
 		MaxHeartbeatThrottleInterval time.Duration
 
 		// Optional: The default amount of time between sending each pending heartbeat to the server. This is used if the
-		// ActivityOptions do not provide a HeartbeatTimeout. Otherwise, the interval becomes a value a bit smaller than the
-		// given HeartbeatTimeout.
+		// ActivityOptions do not provide a HeartbeatTimeout. Otherwise, the interval becomes 80% of the given
+		// HeartbeatTimeout, so throttling never causes a heartbeat timeout. In both cases the interval is capped to
+		// MaxHeartbeatThrottleInterval, and a warning is logged when the worker is created if this is greater. See
+		// MaxHeartbeatThrottleInterval for the trade-off between cancellation detection and server load. Must not be
+		// negative.
 		//
 		// default: 30 seconds
 		DefaultHeartbeatThrottleInterval time.Duration
 
 		// Interceptors to apply to the worker. Earlier interceptors wrap later
-		// interceptors.
+		// interceptors. To position interceptors from several sources relative
+		// to each other by name, build this with
+		// interceptor.NewWorkerInterceptorChain.
 		//
 		// When worker interceptors are here and in client options, the ones in
 		// client options wrap the ones here. The same interceptor should not be set
@@ -293,6 +402,31 @@ type (
 		// returns, Worker.Stop() will be called.
 		OnFatalError func(error)
 
+		// Optional: When the worker stops because of a fatal error, see OnFatalError, fail the workflow tasks it
+		// polled but has not started processing yet and those waiting on local activities, instead of leaving them to
+		// time out. The server then retries them, possibly on another worker, sooner. Workflow tasks that are running
+		// workflow code are completed as usual within WorkerStopTimeout, and each workflow task is responded to at most
+		// once.
+		//
+		// This causes workflow task retries, not workflow failures. Like other workflow task failures, only the first
+		// attempt of a workflow task is reported as failed, later attempts time out.
+		//
+		// default: false
+		FailWorkflowTasksOnFatalError bool
+
+		// Optional: Callback invoked when any activity run by this worker panics, local activities included, before
+		// the panic is converted to the activity's failure. It receives the activity context and info, the recovered
+		// value and the stack trace, which makes it a single place to report activity panics to an error tracker. The
+		// activity still fails regardless of what this does, and a panic in the callback itself is recovered and
+		// logged.
+		OnActivityPanic func(ctx context.Context, info ActivityInfo, recovered any, stack string)
+
+		// Optional: Callback invoked when workflow code run by this worker panics, before the WorkflowPanicPolicy is
+		// applied. It receives the workflow info, the recovered value and the stack trace. With the default
+		// BlockWorkflow policy it is called for every attempt of the failing workflow task. The callback can't
+		// change how the panic is handled, and a panic in the callback itself is recovered and logged.
+		OnWorkflowPanic func(info WorkflowInfo, recovered any, stack string)
+
 		// Optional: Disable eager activities. If set to true, activities will not
 		// be requested to execute eagerly from the same workflow regardless of
 		// MaxConcurrentEagerActivityExecutionSize.
@@ -393,8 +527,105 @@ type (
 		// NOTE: Experimental
 		Plugins []WorkerPlugin
 	}
+
+	// WorkerEffectiveOptions is a snapshot of the options a worker runs with once defaults are applied, for
+	// diagnostics. Options holding code, like interceptors and plugins, are represented by their count.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.EffectiveOptions]
+	WorkerEffectiveOptions struct {
+		Namespace string
+		TaskQueue string
+		Identity  string
+		BuildID   string
+
+		// Identities of the pollers of each task type.
+		WorkflowIdentity string
+		ActivityIdentity string
+		NexusIdentity    string
+
+		// Kind of the slot supplier of each task type, "Fixed", "ResourceBased" or "Custom".
+		WorkflowTaskSlotSupplierKind  string
+		ActivityTaskSlotSupplierKind  string
+		LocalActivitySlotSupplierKind string
+		NexusTaskSlotSupplierKind     string
+		// Maximum number of slots of each task type, 0 if the slot supplier has no upper limit.
+		MaxWorkflowTaskSlots  int
+		MaxActivityTaskSlots  int
+		MaxLocalActivitySlots int
+		MaxNexusTaskSlots     int
+
+		WorkflowTaskPollerBehavior WorkerEffectivePollerBehavior
+		ActivityTaskPollerBehavior WorkerEffectivePollerBehavior
+		NexusTaskPollerBehavior    WorkerEffectivePollerBehavior
+
+		WorkerActivitiesPerSecond               float64
+		WorkerLocalActivitiesPerSecond          float64
+		TaskQueueActivitiesPerSecond            float64
+		MaxConcurrentSessionExecutionSize       int
+		MaxConcurrentEagerActivityExecutionSize int
+
+		StickyScheduleToStartTimeout     time.Duration
+		DeadlockDetectionTimeout         time.Duration
+		WorkerStopTimeout                time.Duration
+		DefaultHeartbeatThrottleInterval time.Duration
+		MaxHeartbeatThrottleInterval     time.Duration
+
+		WorkflowPanicPolicy         WorkflowPanicPolicy
+		DisableWorkflowWorker       bool
+		LocalActivityWorkerOnly     bool
+		EnableSessionWorker         bool
+		DisableEagerActivities      bool
+		DisableRegistrationAliasing bool
+		UseVersioning               bool
+
+		// Number of worker interceptors, including those set on the client.
+		Interceptors int
+		// Number of worker plugins, including those set on the client.
+		Plugins int
+	}
+
+	// WorkerEffectivePollerBehavior describes the PollerBehavior of a task type in WorkerEffectiveOptions.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.EffectivePollerBehavior]
+	WorkerEffectivePollerBehavior struct {
+		// Autoscaling is true for NewPollerBehaviorAutoscaling and false for NewPollerBehaviorSimpleMaximum.
+		Autoscaling bool
+		// Minimum and initial number of pollers, only set when autoscaling.
+		MinimumNumberOfPollers int
+		InitialNumberOfPollers int
+		MaximumNumberOfPollers int
+	}
 )
 
+// StickyCachePrewarmOptions selects the workflows whose state is loaded into the sticky cache when the worker starts,
+// see WorkerOptions.StickyCachePrewarm. Only running workflows of the task queue of the worker are loaded.
+//
+// Pre-warming a workflow costs the same as a cache miss: its full history is fetched and replayed. It only pays off
+// for workflows that receive a workflow task soon after the worker starts, before their state is evicted, and only
+// if this worker picks up that task, since the first task after a restart can go to any worker of the task queue.
+//
+// Exposed as: [go.temporal.io/sdk/worker.StickyCachePrewarmOptions]
+type StickyCachePrewarmOptions struct {
+	// WorkflowIDs are the IDs of the workflows to load, in order.
+	WorkflowIDs []string
+	// Query is a visibility list filter, e.g. "WorkflowType = 'OrderWorkflow'". If set, the running workflows of the
+	// task queue matching it are loaded after WorkflowIDs, in the order returned by visibility, which is by most
+	// recent start time by default.
+	Query string
+}
+
+// SlowReplayInfo describes a workflow task whose replay exceeded WorkerOptions.SlowReplayThreshold.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlowReplayInfo]
+type SlowReplayInfo struct {
+	WorkflowType      string
+	WorkflowExecution WorkflowExecution
+	// HistoryLength is the number of events in history up to and including the started event of the workflow task.
+	HistoryLength int64
+	// ReplayDuration is how long replaying the events of previous workflow tasks took.
+	ReplayDuration time.Duration
+}
+
 // WorkflowPanicPolicy is used for configuring how worker deals with workflow
 // code panicking which includes non backwards compatible changes to the workflow code without appropriate
 // versioning (see workflow.GetVersion).
@@ -448,6 +679,44 @@ func NewWorker(
 	return NewAggregatedWorker(workflowClient, taskQueue, options)
 }
 
+// RegisterActivities registers the activities of several structs with the same options, e.g. a Prefix shared by all of
+// them, as if each struct was registered with RegisterActivityWithOptions. Before registering any of them, it panics if
+// a value is not a pointer to a struct or if two structs define activities with the same name, naming both structs.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RegisterActivities]
+func RegisterActivities(
+	r interface {
+		RegisterActivityWithOptions(a interface{}, options RegisterActivityOptions)
+	},
+	options RegisterActivityOptions,
+	activityStructs ...interface{},
+) {
+	definedBy := make(map[string]reflect.Type)
+	for _, aStruct := range activityStructs {
+		structType := reflect.TypeOf(aStruct)
+		if structType == nil || structType.Kind() != reflect.Ptr || structType.Elem().Kind() != reflect.Struct {
+			panic(fmt.Sprintf("activities must be registered from pointers to structs, got %v", structType))
+		}
+		for i := 0; i < structType.NumMethod(); i++ {
+			method := structType.Method(i)
+			if method.PkgPath != "" || slices.Contains(options.ExcludeMethods, method.Name) {
+				continue
+			}
+			if options.SkipInvalidStructFunctions && validateFnFormat(method.Type, false, false) != nil {
+				continue
+			}
+			name := options.Prefix + options.Name + method.Name
+			if other, ok := definedBy[name]; ok {
+				panic(fmt.Sprintf("activity type \"%v\" is defined by both %v and %v", name, other, structType))
+			}
+			definedBy[name] = structType
+		}
+	}
+	for _, aStruct := range activityStructs {
+		r.RegisterActivityWithOptions(aStruct, options)
+	}
+}
+
 func workerDeploymentOptionsToProto(useVersioning bool, version WorkerDeploymentVersion) *deploymentpb.WorkerDeploymentOptions {
 	if (version != WorkerDeploymentVersion{}) {
 		var workerVersioningMode enumspb.WorkerVersioningMode
@@ -473,6 +742,23 @@ func (p *pollerBehaviorSimpleMaximum) isPollerBehavior() {
 func (p *pollerBehaviorAutoscaling) isPollerBehavior() {
 }
 
+// newWorkerEffectivePollerBehavior describes the given PollerBehavior.
+func newWorkerEffectivePollerBehavior(behavior PollerBehavior) WorkerEffectivePollerBehavior {
+	switch behavior := behavior.(type) {
+	case *pollerBehaviorSimpleMaximum:
+		return WorkerEffectivePollerBehavior{MaximumNumberOfPollers: behavior.maximumNumberOfPollers}
+	case *pollerBehaviorAutoscaling:
+		return WorkerEffectivePollerBehavior{
+			Autoscaling:            true,
+			MinimumNumberOfPollers: behavior.minimumNumberOfPollers,
+			InitialNumberOfPollers: behavior.initialNumberOfPollers,
+			MaximumNumberOfPollers: behavior.maximumNumberOfPollers,
+		}
+	default:
+		return WorkerEffectivePollerBehavior{}
+	}
+}
+
 // NewPollerBehaviorSimpleMaximum creates a PollerBehavior that allows the worker to start up to a maximum number of pollers.
 //
 // Exposed as: [go.temporal.io/sdk/worker.NewPollerBehaviorSimpleMaximum]
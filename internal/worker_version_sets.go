@@ -189,7 +189,10 @@ type GetWorkerBuildIdCompatibilityOptions struct {
 
 // Exposed as: [go.temporal.io/sdk/client.GetWorkerTaskReachabilityOptions]
 type GetWorkerTaskReachabilityOptions struct {
-	// BuildIDs - The build IDs to query the reachability of. At least one build ID must be provided.
+	// BuildIDs - The build IDs to query the reachability of.
+	//
+	// Optional: defaults to the default build ID of each of TaskQueues, or UnversionedBuildID for task queues
+	// without one. At least one task queue must be provided in that case.
 	BuildIDs []string
 	// TaskQueues - The task queues with Build IDs defined on them that the request is
 	// concerned with.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"slices"
 	"strings"
@@ -42,6 +43,26 @@ const (
 	HandlerUnfinishedPolicyAbandon
 )
 
+// AwaitResult is the outcome of AwaitWithReason.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.AwaitResult]
+type AwaitResult int
+
+const (
+	// AwaitResultConditionMet means the condition returned true.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.AwaitResultConditionMet]
+	AwaitResultConditionMet AwaitResult = iota
+	// AwaitResultTimedOut means the timeout passed before the condition returned true.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.AwaitResultTimedOut]
+	AwaitResultTimedOut
+	// AwaitResultCanceled means the context was canceled before the condition returned true.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.AwaitResultCanceled]
+	AwaitResultCanceled
+)
+
 // VersioningBehavior specifies when existing workflows could change their Build ID.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.VersioningBehavior]
@@ -67,6 +88,37 @@ const (
 	VersioningBehaviorAutoUpgrade
 )
 
+const (
+	// WorkflowCompletionHookFailurePolicyIgnore logs the failure of the completion hook and closes the workflow
+	// with its own outcome.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionHookFailurePolicyIgnore]
+	WorkflowCompletionHookFailurePolicyIgnore WorkflowCompletionHookFailurePolicy = iota
+	// WorkflowCompletionHookFailurePolicyFailWorkflow fails the workflow with the error of the completion hook.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionHookFailurePolicyFailWorkflow]
+	WorkflowCompletionHookFailurePolicyFailWorkflow
+)
+
+const (
+	// WorkflowCompletionOutcomeCompleted means the workflow returned without an error.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionOutcomeCompleted]
+	WorkflowCompletionOutcomeCompleted WorkflowCompletionOutcome = iota
+	// WorkflowCompletionOutcomeFailed means the workflow returned an error.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionOutcomeFailed]
+	WorkflowCompletionOutcomeFailed
+	// WorkflowCompletionOutcomeCanceled means the workflow was canceled.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionOutcomeCanceled]
+	WorkflowCompletionOutcomeCanceled
+	// WorkflowCompletionOutcomeContinuedAsNew means the workflow returned a ContinueAsNewError.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionOutcomeContinuedAsNew]
+	WorkflowCompletionOutcomeContinuedAsNew
+)
+
 // ContinueAsNewVersioningBehavior specifies how the new workflow run after ContinueAsNew should change its Build ID.
 //
 // NOTE: Upgrade-on-Continue-as-New is currently experimental.
@@ -259,7 +311,14 @@ type (
 		// AddDefault register callback function to be called if none of other branches matched.
 		// The callback is called when Select(ctx) is called.
 		// When the default branch is registered Select never blocks.
+		// Calling AddDefault again replaces the callback of the default branch.
 		AddDefault(f func())
+		// ClearDefault removes the default branch registered with AddDefault, if any, so Select blocks again until
+		// one of the other branches matches. This allows reusing a Selector across loop iterations that need a
+		// default branch only some of the time.
+		ClearDefault()
+		// HasDefault returns true if a default branch is registered, i.e. if Select never blocks.
+		HasDefault() bool
 		// Select checks if any of the registered branches satisfies its condition blocking if necessary.
 		// When a branch becomes eligible its callback is invoked.
 		// If multiple branches are eligible only one of them (picked randomly) is invoked per Select call.
@@ -309,6 +368,17 @@ type (
 		IsLocked() bool
 	}
 
+	// Counter is an int64 counter that can be shared between coroutines, e.g. update and signal handlers, of a
+	// workflow. Use workflow.NewCounter(ctx) method to create a new Counter instance.
+	Counter interface {
+		// Add adds delta to the counter and returns the new value.
+		// It is a run-time error to call it outside of the workflow dispatcher or from a query handler.
+		Add(delta int64) int64
+		// Load returns the current value of the counter.
+		// It is a run-time error to call it outside of the workflow dispatcher.
+		Load() int64
+	}
+
 	// Semaphore must be used instead of semaphore.Weighted by
 	// workflow code. Use workflow.NewSemaphore(ctx) method to create
 	// a new Semaphore instance
@@ -346,6 +416,29 @@ type (
 		IsReady() bool
 	}
 
+	// TypedFuture is a Future whose result has a static type.
+	TypedFuture[T any] interface {
+		Future
+		// GetTyped blocks until the future is ready and returns its result or error.
+		GetTyped(ctx Context) (T, error)
+	}
+
+	// TypedReceiveChannel is a signal channel whose values are decoded into T.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.TypedReceiveChannel]
+	TypedReceiveChannel[T any] interface {
+		// Name returns the name of the signal.
+		Name() string
+		// Receive blocks until a signal is received and returns its value. more is false once the channel is closed.
+		Receive(ctx Context) (value T, more bool)
+		// ReceiveAsync returns the value of a buffered signal, or ok false if there is none.
+		ReceiveAsync() (value T, ok bool)
+		// Len returns the number of buffered signals.
+		Len() int
+		// Channel returns the underlying untyped channel, e.g. to add it to a Selector with AddReceive.
+		Channel() ReceiveChannel
+	}
+
 	// Settable is used to set value or error on a future.
 	// See more: workflow.NewFuture(ctx).
 	Settable interface {
@@ -356,6 +449,12 @@ type (
 	}
 
 	// ChildWorkflowFuture represents the result of a child workflow execution
+	//
+	// The parent does not observe the attempts of a child workflow with a retry policy: the server retries the child
+	// as new runs of the same workflow ID without recording them in the history of the parent, and the child started
+	// event, which is the one resolving GetChildWorkflowExecution, only identifies the first run and carries no
+	// attempt. A parent that needs the attempt of its child deterministically, e.g. to escalate, can have the child
+	// report GetWorkflowInfo(ctx).Attempt to it with a signal.
 	ChildWorkflowFuture interface {
 		Future
 		// GetChildWorkflowExecution returns a future that will be ready when child workflow execution started. You can
@@ -542,6 +641,81 @@ type (
 		// when WorkerOptions does not specify [DeploymentOptions.DefaultVersioningBehavior],
 		// [DeploymentOptions.DeploymentSeriesName] is set, and [UseBuildIDForVersioning] is true.
 		VersioningBehavior VersioningBehavior
+		// Optional: Additional workflow type names this workflow is registered under, e.g. to keep an old name
+		// resolving during a rename. Each alias is subject to the same already-registered check as the primary name.
+		// The workflow reports its primary name as its type in WorkflowInfo, even when started by an alias.
+		Aliases []string
+		// Optional: An activity to run with the outcome of the workflow before it completes, fails, is canceled
+		// or continues as new. Not supported for WorkflowDefinitionFactory registrations.
+		OnCompletion *WorkflowCompletionHook
+		// Optional: How workflows of this type deal with panics and detected non-determinism. It takes precedence
+		// over WorkerOptions.WorkflowPanicPolicy, so a worker can e.g. fail workflows of a canary type fast while
+		// blocking all others. Must be BlockWorkflow or FailWorkflow if set. Defaults to the policy of the worker.
+		PanicPolicy *WorkflowPanicPolicy
+		// Optional: Deploy-time configuration of the workflow type, e.g. read from environment variables when the
+		// worker starts, that workflow code reads with GetStaticConfig instead of reading the environment or
+		// globals. The map is copied at registration. Not supported for WorkflowDefinitionFactory registrations.
+		//
+		// The values are not recorded in history, so replay uses the values of the worker replaying. Every worker
+		// registering the workflow type must therefore use the same values, and values must only change in ways
+		// that do not change the commands of running workflows. Configuration that may change while workflows run
+		// must be read with MutableSideEffect (see NewConfigValue) instead, which records the values in history.
+		StaticConfig map[string]string
+		// Optional: Validates the decoded arguments of each workflow of this type before the workflow function and
+		// the interceptors run. Dynamic workflows receive a single converter.EncodedValues argument. If it returns an
+		// error, the workflow fails with an ApplicationError of type "InputValidationError" wrapping that error,
+		// which is non-retryable unless InputValidationRetryable is set. OnCompletion still runs for workflows failed
+		// this way. The validator runs as part of the workflow so it must be deterministic. Not supported for
+		// WorkflowDefinitionFactory registrations.
+		InputValidator func(args []interface{}) error
+		// Optional: Whether workflows failed by InputValidator are retried according to their retry policy. By
+		// default they are not, since the same input fails validation again.
+		InputValidationRetryable bool
+		// Optional: The maximum amount of time a workflow task of this type is allowed to run, overriding
+		// WorkerOptions.DeadlockDetectionTimeout. Use it for workflow types that legitimately do heavy deterministic
+		// computation instead of raising the timeout for all workflows of the worker. Must be positive if set. It
+		// has no effect when deadlock detection is disabled for the worker, e.g. while debugging. To exempt data
+		// conversion from deadlock detection, use DataConverterWithoutDeadlockDetection instead.
+		DeadlockDetectionTimeout time.Duration
+	}
+
+	// WorkflowCompletionHook is an activity run with the outcome of a workflow before the workflow closes, e.g. to
+	// publish the result to an external system. The activity is called with a WorkflowCompletion and the result of
+	// the workflow, which is nil unless the workflow completed successfully.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionHook]
+	WorkflowCompletionHook struct {
+		// Activity is the activity function or registered activity name to run. Required.
+		Activity interface{}
+		// ActivityOptions are the options of the activity. Used unless Local is set.
+		ActivityOptions ActivityOptions
+		// Local runs the activity as a local activity with LocalActivityOptions.
+		Local bool
+		// LocalActivityOptions are the options of the activity when Local is set.
+		LocalActivityOptions LocalActivityOptions
+		// FailurePolicy decides what happens when the activity fails. By default, the failure is logged and the
+		// workflow closes with its own outcome.
+		FailurePolicy WorkflowCompletionHookFailurePolicy
+	}
+
+	// WorkflowCompletionHookFailurePolicy decides what happens when the WorkflowCompletionHook activity fails.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionHookFailurePolicy]
+	WorkflowCompletionHookFailurePolicy int
+
+	// WorkflowCompletionOutcome is how a workflow is closing.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CompletionOutcome]
+	WorkflowCompletionOutcome int
+
+	// WorkflowCompletion is the first argument of the WorkflowCompletionHook activity.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.Completion]
+	WorkflowCompletion struct {
+		// Outcome is how the workflow is closing.
+		Outcome WorkflowCompletionOutcome
+		// Error is the message of the error the workflow returned. Empty if it completed successfully.
+		Error string
 	}
 
 	// LoadDynamicRuntimeOptionsDetails is used as input to the LoadDynamicRuntimeOptions callback for dynamic workflows
@@ -639,6 +813,18 @@ type (
 		Summary string
 	}
 
+	// PendingTimer describes a timer of the workflow that has neither fired nor been canceled.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.PendingTimer]
+	PendingTimer struct {
+		// ID is the ID of the timer in history, unique in the workflow run.
+		ID string
+		// FireTime is the workflow time the timer fires at.
+		FireTime time.Time
+		// Summary is the summary set in TimerOptions, "Sleep" for timers started by Sleep.
+		Summary string
+	}
+
 	// AwaitOptions are options set when creating an await.
 	//
 	// NOTE: Experimental
@@ -777,6 +963,24 @@ func (wc *workflowEnvironmentInterceptor) AwaitWithOptions(ctx Context, options
 	return wc.awaitWithOptions(ctx, options, condition, "AwaitWithOptions")
 }
 
+// AwaitWithReason blocks the calling thread until condition() returns true, the timeout passes or the ctx is canceled,
+// and returns which one happened. The error is a CanceledError if the ctx is canceled and nil otherwise.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.AwaitWithReason]
+func AwaitWithReason(ctx Context, timeout time.Duration, condition func() bool) (AwaitResult, error) {
+	// The timeout timer is created on a child context so it can be canceled once the condition is met,
+	// regardless of whether SDKFlagCancelAwaitTimerOnCondition is in use.
+	timerCtx, cancelTimer := WithCancel(ctx)
+	defer cancelTimer()
+	ok, err := AwaitWithTimeout(timerCtx, timeout, condition)
+	if err != nil {
+		return AwaitResultCanceled, err
+	} else if !ok {
+		return AwaitResultTimedOut, nil
+	}
+	return AwaitResultConditionMet, nil
+}
+
 // NewChannel create new Channel instance
 //
 // Exposed as: [go.temporal.io/sdk/workflow.NewChannel]
@@ -847,6 +1051,14 @@ func NewMutex(ctx Context) Mutex {
 	return &mutexImpl{}
 }
 
+// NewCounter creates a new Counter instance starting at zero.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewCounter]
+func NewCounter(ctx Context) Counter {
+	assertNotInReadOnlyState(ctx)
+	return &counterImpl{dispatcher: getState(ctx).dispatcher}
+}
+
 // NewSemaphore creates a new Semaphore instance with an initial weight.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.NewSemaphore]
@@ -876,6 +1088,43 @@ func GoNamed(ctx Context, name string, f func(ctx Context)) {
 	state.dispatcher.interceptor.Go(ctx, name, f)
 }
 
+// GoTyped creates a new coroutine running fn and returns a future resolved with its result. If ctx is canceled
+// before fn returns, the future is resolved with the cancellation error.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GoTyped]
+func GoTyped[T any](ctx Context, fn func(ctx Context) (T, error)) TypedFuture[T] {
+	assertNotInReadOnlyState(ctx)
+	resultFuture, resultSettable := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		resultSettable.Set(fn(ctx))
+	})
+
+	future, settable := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		NewSelector(ctx).
+			AddFuture(resultFuture, func(f Future) {
+				var result T
+				err := f.Get(ctx, &result)
+				settable.Set(result, err)
+			}).
+			AddReceive(ctx.Done(), func(c ReceiveChannel, more bool) {
+				settable.SetError(ctx.Err())
+			}).
+			Select(ctx)
+	})
+	return typedFutureImpl[T]{Future: future}
+}
+
+type typedFutureImpl[T any] struct {
+	Future
+}
+
+func (f typedFutureImpl[T]) GetTyped(ctx Context) (T, error) {
+	var result T
+	err := f.Get(ctx, &result)
+	return result, err
+}
+
 // NewFuture creates a new future as well as associated Settable that is used to set its value.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.NewFuture]
@@ -996,6 +1245,42 @@ func ExecuteActivity(ctx Context, activity interface{}, args ...interface{}) Fut
 	return i.ExecuteActivity(ctx, activityType, args...)
 }
 
+// ExecuteActivityMethod requests execution of the activity registered for a method of an activity struct, referenced
+// by its method expression, e.g. (*Activities).Activity1, and returns a future of its typed result. The activity type
+// name is derived from the method like ExecuteActivity does from a method value.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ExecuteActivityMethod]
+func ExecuteActivityMethod[S any, A any, R any](
+	ctx Context,
+	method func(S, context.Context, A) (R, error),
+	input A,
+) TypedFuture[R] {
+	return typedFutureImpl[R]{Future: ExecuteActivity(ctx, method, input)}
+}
+
+// ExecuteActivityCached schedules an activity like ExecuteActivity, but returns the Future of the first execution for
+// a given cacheKey instead of scheduling the activity again.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ExecuteActivityCached]
+func ExecuteActivityCached(ctx Context, cacheKey string, activity interface{}, args ...interface{}) Future {
+	assertNotInReadOnlyState(ctx)
+	if cacheKey == "" {
+		future, settable := NewFuture(ctx)
+		settable.SetError(errors.New("cache key must not be empty"))
+		return future
+	}
+	wc := getWorkflowEnvironmentInterceptor(ctx)
+	if future, ok := wc.activityResultCache[cacheKey]; ok {
+		return future
+	}
+	future := ExecuteActivity(ctx, activity, args...)
+	if wc.activityResultCache == nil {
+		wc.activityResultCache = map[string]Future{}
+	}
+	wc.activityResultCache[cacheKey] = future
+	return future
+}
+
 func (wc *workflowEnvironmentInterceptor) ExecuteActivity(ctx Context, typeName string, args ...interface{}) Future {
 	// Validate type and its arguments.
 	dataConverter := getDataConverterFromWorkflowContext(ctx)
@@ -1008,6 +1293,17 @@ func (wc *workflowEnvironmentInterceptor) ExecuteActivity(ctx Context, typeName
 	}
 	// Validate context options.
 	options := getActivityOptions(ctx)
+	defaults, hasDefaults := registry.getActivityDefaultOptions(activityType.Name)
+	if options == nil && hasDefaults {
+		// The registered defaults are enough to run the activity on a context without activity options
+		options = getActivityOptions(setActivityParametersIfNotExist(ctx))
+	}
+	if options != nil {
+		future.(*decodeFutureImpl).nonRetryableDecodeError = options.NonRetryableDecodeError
+		if hasDefaults {
+			options = applyActivityDefaultOptions(options, defaults)
+		}
+	}
 
 	// Validate session state.
 	if sessionInfo := getSessionInfo(ctx); sessionInfo != nil {
@@ -1033,6 +1329,13 @@ func (wc *workflowEnvironmentInterceptor) ExecuteActivity(ctx Context, typeName
 		settable.Set(nil, err)
 		return future
 	}
+	if options.SkipIfWorkflowCancelled {
+		header, err = withSkipIfWorkflowCancelledHeader(header)
+		if err != nil {
+			settable.Set(nil, err)
+			return future
+		}
+	}
 
 	input, err := encodeArgs(dataConverter, args)
 	if err != nil {
@@ -1136,6 +1439,9 @@ func ExecuteLocalActivity(ctx Context, activity interface{}, args ...interface{}
 
 func (wc *workflowEnvironmentInterceptor) ExecuteLocalActivity(ctx Context, typeName string, args ...interface{}) Future {
 	future, settable := newDecodeFuture(ctx, typeName)
+	if options := getLocalActivityOptions(ctx); options != nil {
+		future.(*decodeFutureImpl).nonRetryableDecodeError = options.NonRetryableDecodeError
+	}
 
 	envOptions := getWorkflowEnvOptions(ctx)
 	header, err := workflowHeaderPropagated(ctx, envOptions.ContextPropagators)
@@ -1208,6 +1514,7 @@ func (wc *workflowEnvironmentInterceptor) ExecuteLocalActivity(ctx Context, type
 		ScheduledTime:               Now(ctx), // initial scheduled time
 		Header:                      header,
 		Attempt:                     1, // Attempts always start at one
+		primaryActivityType:         getRegistryFromWorkflowContext(ctx).getActivityPrimaryName(typeName),
 	}
 
 	Go(ctx, func(ctx Context) {
@@ -1220,6 +1527,9 @@ func (wc *workflowEnvironmentInterceptor) ExecuteLocalActivity(ctx Context, type
 				_ = Sleep(ctx, retryErr.Backoff)
 				// increase the attempt, and retry the local activity
 				params.Attempt = retryErr.Attempt + 1
+				if params.OnRetry != nil && !IsReplaying(ctx) {
+					params.OnRetry(params.Attempt, retryErr.Err)
+				}
 				continue
 			}
 
@@ -1235,6 +1545,7 @@ func (wc *workflowEnvironmentInterceptor) ExecuteLocalActivity(ctx Context, type
 type needRetryError struct {
 	Backoff time.Duration
 	Attempt int32
+	Err     error
 }
 
 func (e *needRetryError) Error() string {
@@ -1257,7 +1568,7 @@ func (wc *workflowEnvironmentInterceptor) scheduleLocalActivity(ctx Context, par
 		}
 
 		// set retry error, and it will be handled by workflow.ExecuteLocalActivity().
-		f.Set(nil, &needRetryError{Backoff: lar.Backoff, Attempt: lar.Attempt})
+		f.Set(nil, &needRetryError{Backoff: lar.Backoff, Attempt: lar.Attempt, Err: lar.Err})
 	})
 
 	if cancellable {
@@ -1355,6 +1666,7 @@ func (wc *workflowEnvironmentInterceptor) ExecuteChildWorkflow(ctx Context, chil
 		mainSettable.Set(nil, err)
 		return result
 	}
+	propagateStartWorkflowHeaders(ctx, header)
 
 	params := ExecuteWorkflowParams{
 		WorkflowOptions: *options,
@@ -1408,9 +1720,12 @@ type WorkflowInfo struct {
 	// The original runID before resetting. Using it instead of current runID can make workflow decision deterministic after reset. See also FirstRunId
 	OriginalRunID string
 	// The very first original RunId of the current Workflow Execution preserved along the chain of ContinueAsNew, Retry, Cron and Reset. Identifies the whole Runs chain of Workflow Execution.
-	FirstRunID               string
-	WorkflowType             WorkflowType
-	TaskQueueName            string
+	// Unlike WorkflowExecution.RunID, it is the same for every run of the chain. See also GetFirstRunID.
+	FirstRunID    string
+	WorkflowType  WorkflowType
+	TaskQueueName string
+	// Timeouts of the workflow as recorded in the start event, so they are safe to use in workflow logic, e.g. to size
+	// the timeouts of children relative to the workflow's own. Zero if not set.
 	WorkflowExecutionTimeout time.Duration
 	WorkflowRunTimeout       time.Duration
 	WorkflowTaskTimeout      time.Duration
@@ -1430,7 +1745,9 @@ type WorkflowInfo struct {
 	Memo                  *commonpb.Memo // Value can be decoded using data converter (defaultDataConverter, or custom one if set).
 	// Deprecated: use [Workflow.GetTypedSearchAttributes] instead.
 	SearchAttributes *commonpb.SearchAttributes // Value can be decoded using defaultDataConverter.
-	RetryPolicy      *RetryPolicy
+	// RetryPolicy of the workflow as recorded in the start event, so it is safe to use in workflow logic. Nil if the
+	// workflow was started without a retry policy.
+	RetryPolicy *RetryPolicy
 	// Priority settings that control relative ordering of task processing when workflow tasks are backed up in a queue.
 	// If no priority is set, the default value is the zero value.
 	//
@@ -1445,6 +1762,8 @@ type WorkflowInfo struct {
 	// which is currently or about to be executing. If no longer replaying will be set to the ID of
 	// this worker
 	currentTaskBuildID string
+	// currentTaskVersioned is true if the worker that processed the current task used versioning
+	currentTaskVersioned bool
 
 	continueAsNewSuggested        bool
 	continueAsNewSuggestedReasons []ContinueAsNewSuggestedReason
@@ -1529,10 +1848,66 @@ func GetWorkflowInfo(ctx Context) *WorkflowInfo {
 	return i.GetInfo(ctx)
 }
 
+// GetCurrentBuildID returns the Build ID of the worker that processed the current workflow task, for example to log
+// which version a workflow runs on during a migration between worker versions. Like WorkflowInfo.GetCurrentBuildID,
+// it returns the Build ID recorded in history by the worker that originally processed the task when replaying, so it
+// is safe to use for branching, but it returns an empty string if that worker did not use versioning instead of
+// falling back to the binary checksum.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetCurrentBuildID]
+func GetCurrentBuildID(ctx Context) string {
+	info := GetWorkflowInfo(ctx)
+	if !info.currentTaskVersioned {
+		return ""
+	}
+	return info.currentTaskBuildID
+}
+
 func (wc *workflowEnvironmentInterceptor) GetInfo(ctx Context) *WorkflowInfo {
 	return wc.env.WorkflowInfo()
 }
 
+// GetFirstRunID returns the run ID of the first run of the chain of runs the current run belongs to.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetFirstRunID]
+func GetFirstRunID(ctx Context) string {
+	return GetWorkflowInfo(ctx).FirstRunID
+}
+
+// GetStaticConfig returns a copy of the RegisterWorkflowOptions.StaticConfig the workflow type was registered with,
+// or nil if there is none.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetStaticConfig]
+func GetStaticConfig(ctx Context) map[string]string {
+	return maps.Clone(getWorkflowEnvironmentInterceptor(ctx).staticConfig)
+}
+
+// GetHeader decodes the value of the StartWorkflowOptions.Headers entry key the workflow was started with into
+// valuePtr. It returns an error wrapping ErrNoData if there is no such entry.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetHeader]
+func GetHeader(ctx Context, key string, valuePtr interface{}) error {
+	payload, ok := getWorkflowEnvironmentInterceptor(ctx).startHeader[startWorkflowHeaderPrefix+key]
+	if !ok {
+		return fmt.Errorf("no header %q: %w", key, ErrNoData)
+	}
+	return getDataConverterFromWorkflowContext(ctx).FromPayload(payload, valuePtr)
+}
+
+// NewIdempotencyKey returns a key that uniquely and deterministically identifies a logical operation within the
+// current workflow run.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewIdempotencyKey]
+func NewIdempotencyKey(ctx Context, scope string) string {
+	wc := getWorkflowEnvironmentInterceptor(ctx)
+	if wc.idempotencyKeyCounters == nil {
+		wc.idempotencyKeyCounters = map[string]int{}
+	}
+	wc.idempotencyKeyCounters[scope]++
+	runID := wc.env.WorkflowInfo().WorkflowExecution.RunID
+	return fmt.Sprintf("%s:%s:%d", runID, scope, wc.idempotencyKeyCounters[scope])
+}
+
 // Exposed as: [go.temporal.io/sdk/workflow.GetTypedSearchAttributes]
 func GetTypedSearchAttributes(ctx Context) SearchAttributes {
 	i := getWorkflowOutboundInterceptor(ctx)
@@ -1551,6 +1926,14 @@ func GetCurrentUpdateInfo(ctx Context) *UpdateInfo {
 	return i.GetCurrentUpdateInfo(ctx)
 }
 
+// HasProcessedUpdate returns whether an update with the given ID has been accepted in this workflow run.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.HasProcessedUpdate]
+func HasProcessedUpdate(ctx Context, updateID string) bool {
+	_, ok := getWorkflowEnvOptions(ctx).acceptedUpdateIDs[updateID]
+	return ok
+}
+
 func (wc *workflowEnvironmentInterceptor) GetCurrentUpdateInfo(ctx Context) *UpdateInfo {
 	uc := ctx.Value(updateInfoContextKey)
 	if uc == nil {
@@ -1646,18 +2029,29 @@ func (wc *workflowEnvironmentInterceptor) NewTimerWithOptions(
 
 	ctxDone, cancellable := ctx.Done().(*channelImpl)
 	cancellationCallback := &receiveCallback{}
-	timerID := wc.env.NewTimer(d, options, func(r *commonpb.Payloads, e error) {
+	fireTime := wc.env.Now().Add(d)
+	var done bool
+	var timerID *TimerID
+	timerID = wc.env.NewTimer(d, options, func(r *commonpb.Payloads, e error) {
+		done = true
+		if timerID != nil {
+			wc.removePendingTimer(timerID.id)
+		}
 		settable.Set(nil, e)
 		if cancellable {
 			// future is done, we don't need cancellation anymore
 			ctxDone.removeReceiveCallback(cancellationCallback)
 		}
 	})
+	if timerID != nil && !done {
+		wc.pendingTimers = append(wc.pendingTimers, PendingTimer{ID: timerID.id, FireTime: fireTime, Summary: options.Summary})
+	}
 
 	if timerID != nil && cancellable {
 		cancellationCallback.fn = func(v interface{}, more bool) bool {
 			assertNotInReadOnlyStateCancellation(ctx)
 			if !future.IsReady() {
+				wc.removePendingTimer(timerID.id)
 				wc.env.RequestCancelTimer(*timerID)
 			}
 			return false
@@ -1670,6 +2064,23 @@ func (wc *workflowEnvironmentInterceptor) NewTimerWithOptions(
 	return future
 }
 
+func (wc *workflowEnvironmentInterceptor) removePendingTimer(id string) {
+	for i, timer := range wc.pendingTimers {
+		if timer.ID == id {
+			wc.pendingTimers = append(wc.pendingTimers[:i], wc.pendingTimers[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetPendingTimers returns the timers of the workflow that have neither fired nor been canceled, in the order they
+// were created.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetPendingTimers]
+func GetPendingTimers(ctx Context) []PendingTimer {
+	return slices.Clone(getWorkflowEnvironmentInterceptor(ctx).pendingTimers)
+}
+
 // Sleep pauses the current workflow for at least the duration d. A negative or zero duration causes Sleep to return
 // immediately. Workflow code needs to use this Sleep() to sleep instead of the Go lang library one(timer.Sleep()).
 // You can cancel the pending sleep by cancel the Context (using context from workflow.WithCancel(ctx)).
@@ -1690,6 +2101,20 @@ func (wc *workflowEnvironmentInterceptor) Sleep(ctx Context, d time.Duration) (e
 	return
 }
 
+// AwaitMinimumRunDuration pauses the current workflow until at least the duration d has passed since
+// WorkflowInfo.WorkflowStartTime, the start of the current run, and returns nil immediately if it already has.
+// Elapsed time is measured with workflow time, see Now, so it is deterministic. Like Sleep, it returns *CanceledError if
+// the ctx is canceled before the duration has passed.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.AwaitMinimumRunDuration]
+func AwaitMinimumRunDuration(ctx Context, d time.Duration) error {
+	remaining := GetWorkflowInfo(ctx).WorkflowStartTime.Add(d).Sub(Now(ctx))
+	if remaining <= 0 {
+		return nil
+	}
+	return Sleep(ctx, remaining)
+}
+
 // RequestCancelExternalWorkflow can be used to request cancellation of an external workflow.
 // Input workflowID is the workflow ID of target workflow.
 // Input runID indicates the instance of a workflow. Input runID is optional (default is ""). When runID is not specified,
@@ -1851,7 +2276,13 @@ func (wc *workflowEnvironmentInterceptor) UpsertSearchAttributes(ctx Context, at
 	if _, ok := attributes[TemporalChangeVersion]; ok {
 		return errors.New("TemporalChangeVersion is a reserved key that cannot be set, please use other key")
 	}
-	return wc.env.UpsertSearchAttributes(attributes)
+	if err := wc.env.UpsertSearchAttributes(attributes); err != nil {
+		return err
+	}
+	for name := range attributes {
+		wc.recordSearchAttributeUpsert(name)
+	}
+	return nil
 }
 
 // Exposed as: [go.temporal.io/sdk/workflow.UpsertTypedSearchAttributes]
@@ -1868,7 +2299,37 @@ func (wc *workflowEnvironmentInterceptor) UpsertTypedSearchAttributes(ctx Contex
 	for _, attribute := range attributes {
 		attribute(&sa)
 	}
-	return wc.env.UpsertTypedSearchAttributes(sa)
+	if err := wc.env.UpsertTypedSearchAttributes(sa); err != nil {
+		return err
+	}
+	for key := range sa.untypedValue {
+		wc.recordSearchAttributeUpsert(key.GetName())
+	}
+	return nil
+}
+
+func (wc *workflowEnvironmentInterceptor) recordSearchAttributeUpsert(name string) {
+	if wc.searchAttributeVersions == nil {
+		wc.searchAttributeVersions = map[string]int{}
+	}
+	wc.searchAttributeVersions[name]++
+}
+
+// GetTypedSearchAttributesInfo returns the current search attributes of the workflow along with where each was set.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetTypedSearchAttributesInfo]
+func GetTypedSearchAttributesInfo(ctx Context) map[SearchAttributeKey]SearchAttributeInfo {
+	wc := getWorkflowEnvironmentInterceptor(ctx)
+	values := GetTypedSearchAttributes(ctx).GetUntypedValues()
+	result := make(map[SearchAttributeKey]SearchAttributeInfo, len(values))
+	for key, value := range values {
+		info := SearchAttributeInfo{Value: value, Version: wc.searchAttributeVersions[key.GetName()]}
+		if info.Version > 0 {
+			info.Source = SearchAttributeSourceUpsert
+		}
+		result[key] = info
+	}
+	return result
 }
 
 // UpsertMemo is used to add or update workflow memo.
@@ -1909,6 +2370,82 @@ func (wc *workflowEnvironmentInterceptor) UpsertMemo(ctx Context, memo map[strin
 	return wc.env.UpsertMemo(memo)
 }
 
+// MetadataUpdate is the memo and search attributes to upsert together with UpsertWorkflowMetadata.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.MetadataUpdate]
+type MetadataUpdate struct {
+	// Memo to merge into the workflow memo like by UpsertMemo.
+	//
+	// Optional: default nil, which leaves the memo unchanged.
+	Memo map[string]interface{}
+
+	// SearchAttributes to set or unset like by UpsertTypedSearchAttributes.
+	//
+	// Optional: default nil, which leaves the search attributes unchanged.
+	SearchAttributes []SearchAttributeUpdate
+}
+
+// UpsertWorkflowMetadata upserts the memo and the search attributes of update together. Both are validated before
+// anything is upserted, so on error neither is changed. A search attribute whose key has a different value type than
+// the key of the same name already set on the workflow, or than another key of the same name in update, is an error.
+//
+// The server records a memo change and a search attribute change as separate events, so the commands for both are
+// sent in the same workflow task completion and recorded in history together.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.UpsertWorkflowMetadata]
+func UpsertWorkflowMetadata(ctx Context, update MetadataUpdate) error {
+	assertNotInReadOnlyState(ctx)
+	if len(update.Memo) == 0 && len(update.SearchAttributes) == 0 {
+		return errors.New("metadata update is empty")
+	}
+	if len(update.SearchAttributes) > 0 {
+		if err := validateSearchAttributeUpdates(GetTypedSearchAttributes(ctx), update.SearchAttributes); err != nil {
+			return err
+		}
+	}
+	// The search attributes are validated, so only the memo can fail once commands are added
+	i := getWorkflowOutboundInterceptor(ctx)
+	if len(update.Memo) > 0 {
+		if err := i.UpsertMemo(ctx, update.Memo); err != nil {
+			return err
+		}
+	}
+	if len(update.SearchAttributes) > 0 {
+		return i.UpsertTypedSearchAttributes(ctx, update.SearchAttributes...)
+	}
+	return nil
+}
+
+// validateSearchAttributeUpdates returns an error if updates cannot be upserted on a workflow with the current search
+// attributes.
+func validateSearchAttributeUpdates(current SearchAttributes, updates []SearchAttributeUpdate) error {
+	keys := make(map[string]SearchAttributeKey, current.Size())
+	for key := range current.GetUntypedValues() {
+		keys[key.GetName()] = key
+	}
+	for _, update := range updates {
+		sa := SearchAttributes{untypedValue: make(map[SearchAttributeKey]interface{})}
+		update(&sa)
+		for key := range sa.untypedValue {
+			name := key.GetName()
+			if name == "" {
+				return errors.New("search attribute key name is empty")
+			}
+			if name == TemporalChangeVersion {
+				return errors.New("TemporalChangeVersion is a reserved key that cannot be set, please use other key")
+			}
+			if known, ok := keys[name]; ok && known.GetValueType() != key.GetValueType() {
+				return fmt.Errorf("search attribute %q is of type %v, not %v", name, known.GetValueType(), key.GetValueType())
+			}
+			keys[name] = key
+		}
+		if _, err := serializeTypedSearchAttributes(sa.untypedValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WithChildWorkflowOptions adds all workflow options to the context.
 // The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
 // subjected to change in the future.
@@ -1973,6 +2510,57 @@ func GetChildWorkflowOptions(ctx Context) ChildWorkflowOptions {
 	}
 }
 
+// InheritedChildWorkflowOptions returns ChildWorkflowOptions populated from the current (parent) workflow, to be
+// adjusted as needed and passed to WithChildWorkflowOptions. It inherits:
+//   - TaskQueue from the task queue of the parent.
+//   - WorkflowExecutionTimeout, WorkflowRunTimeout and WorkflowTaskTimeout from the timeouts of the parent.
+//   - Memo from the current memo of the parent, including upserted fields.
+//   - TypedSearchAttributes from the current search attributes of the parent, including upserted ones. Reserved
+//     attributes whose names start with "Temporal", such as TemporalChangeVersion holding the patches of the parent
+//     or the attributes of the schedule that started it, are not inherited.
+//
+// WorkflowID is intentionally left empty, so children get a generated ID unless one is set, and all other fields
+// have their zero values. Since the values come from the workflow info and search attributes recorded in history,
+// the result is deterministic.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.InheritedChildWorkflowOptions]
+func InheritedChildWorkflowOptions(ctx Context) ChildWorkflowOptions {
+	info := GetWorkflowInfo(ctx)
+	options := ChildWorkflowOptions{
+		TaskQueue:                info.TaskQueueName,
+		WorkflowExecutionTimeout: info.WorkflowExecutionTimeout,
+		WorkflowRunTimeout:       info.WorkflowRunTimeout,
+		WorkflowTaskTimeout:      info.WorkflowTaskTimeout,
+		TypedSearchAttributes:    inheritableSearchAttributes(GetTypedSearchAttributes(ctx)),
+	}
+	if fields := info.Memo.GetFields(); len(fields) > 0 {
+		// Pass the memo through as raw values, decoding them first so the data converter does not apply its codec
+		// a second time when encoding the memo of the child.
+		dc := getDataConverterFromWorkflowContext(ctx)
+		options.Memo = make(map[string]interface{}, len(fields))
+		for k, payload := range fields {
+			var value converter.RawValue
+			if err := dc.FromPayload(payload, &value); err != nil {
+				value = converter.NewRawValue(payload)
+			}
+			options.Memo[k] = value
+		}
+	}
+	return options
+}
+
+// inheritableSearchAttributes returns the search attributes without the reserved ones managed by the SDK and the
+// server, which describe the parent rather than the child.
+func inheritableSearchAttributes(searchAttributes SearchAttributes) SearchAttributes {
+	inherited := NewSearchAttributes()
+	for key, value := range searchAttributes.GetUntypedValues() {
+		if !strings.HasPrefix(key.GetName(), "Temporal") {
+			inherited.untypedValue[key] = value
+		}
+	}
+	return inherited
+}
+
 // WithWorkflowNamespace adds a namespace to the context.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.WithWorkflowNamespace]
@@ -2091,6 +2679,61 @@ func GetSignalChannelWithOptions(ctx Context, signalName string, options SignalC
 	return i.GetSignalChannelWithOptions(ctx, signalName, options)
 }
 
+// GetTypedSignalChannel returns the channel corresponding to the signal name with its values decoded into T. A signal
+// whose value cannot be decoded into T panics, failing the workflow task, instead of being dropped like by the channel
+// returned by GetSignalChannel.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetTypedSignalChannel]
+func GetTypedSignalChannel[T any](ctx Context, signalName string) TypedReceiveChannel[T] {
+	return &typedSignalChannel[T]{ch: GetSignalChannel(ctx, signalName)}
+}
+
+// channelValueDecoder is a value pointer passed to channelImpl receive methods that decodes the received value itself.
+type channelValueDecoder interface {
+	decodeChannelValue(c *channelImpl, v interface{}) error
+}
+
+type typedSignalChannel[T any] struct {
+	ch ReceiveChannel
+}
+
+// typedSignalValue decodes a signal value into T and panics if it cannot be decoded, so the workflow task fails the
+// same way on every replay.
+type typedSignalValue[T any] struct {
+	value T
+}
+
+func (v *typedSignalValue[T]) decodeChannelValue(c *channelImpl, from interface{}) error {
+	if err := decodeAndAssignValue(c.dataConverter, from, &v.value); err != nil {
+		panic(fmt.Sprintf("unable to decode signal %s into %T: %v", c.name, v.value, err))
+	}
+	return nil
+}
+
+func (c *typedSignalChannel[T]) Name() string {
+	return c.ch.Name()
+}
+
+func (c *typedSignalChannel[T]) Receive(ctx Context) (value T, more bool) {
+	var v typedSignalValue[T]
+	more = c.ch.Receive(ctx, &v)
+	return v.value, more
+}
+
+func (c *typedSignalChannel[T]) ReceiveAsync() (value T, ok bool) {
+	var v typedSignalValue[T]
+	ok = c.ch.ReceiveAsync(&v)
+	return v.value, ok
+}
+
+func (c *typedSignalChannel[T]) Len() int {
+	return c.ch.Len()
+}
+
+func (c *typedSignalChannel[T]) Channel() ReceiveChannel {
+	return c.ch
+}
+
 func (wc *workflowEnvironmentInterceptor) GetSignalChannel(ctx Context, signalName string) ReceiveChannel {
 	return wc.GetSignalChannelWithOptions(ctx, signalName, SignalChannelOptions{})
 }
@@ -2112,6 +2755,107 @@ func (wc *workflowEnvironmentInterceptor) GetSignalChannelWithOptions(
 	return ch
 }
 
+// rateLimitedChannel paces receives from a signal channel to at most maxPerInterval per interval of workflow time.
+// Signals that are not yet received stay buffered in the underlying channel.
+type rateLimitedChannel struct {
+	ReceiveChannel
+	// ctx is the context the channel was created with, used to read workflow time in ReceiveAsync
+	ctx            Context
+	maxPerInterval int
+	interval       time.Duration
+	windowStart    time.Time
+	received       int
+}
+
+// NewRateLimitedSignalChannel returns the channel of the signal name that lets at most maxPerInterval signals be
+// received per interval of workflow time. Receive and ReceiveWithTimeout block on a timer once the limit of the current
+// interval is reached, and ReceiveAsync reports no value until the next interval starts.
+//
+// This only paces the consumption of signals, it does not stop them from arriving. Signals not received yet stay
+// buffered in the signal channel, so they count towards the history size and are reported by GetUnhandledSignalNames.
+// Pacing is based on workflow time and timers, so it is replay safe.
+//
+// The returned channel cannot be used with a Selector, Selector.AddReceive panics if it is passed one. Receive stops
+// pacing once ctx is canceled.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewRateLimitedSignalChannel]
+func NewRateLimitedSignalChannel(ctx Context, signalName string, maxPerInterval int, interval time.Duration) ReceiveChannel {
+	if maxPerInterval <= 0 {
+		panic("maxPerInterval must be positive")
+	} else if interval <= 0 {
+		panic("interval must be positive")
+	}
+	return &rateLimitedChannel{
+		ReceiveChannel: GetSignalChannel(ctx, signalName),
+		ctx:            ctx,
+		maxPerInterval: maxPerInterval,
+		interval:       interval,
+	}
+}
+
+// waitTime returns how long to wait until another signal may be received, starting a new interval if the current one
+// has passed.
+func (c *rateLimitedChannel) waitTime(ctx Context) time.Duration {
+	now := Now(ctx)
+	if c.windowStart.IsZero() || !now.Before(c.windowStart.Add(c.interval)) {
+		c.windowStart = now
+		c.received = 0
+	}
+	if c.received < c.maxPerInterval {
+		return 0
+	}
+	return c.windowStart.Add(c.interval).Sub(now)
+}
+
+// recordReceived counts a received signal in the interval it was received in.
+func (c *rateLimitedChannel) recordReceived(ctx Context) {
+	c.waitTime(ctx)
+	c.received++
+}
+
+func (c *rateLimitedChannel) Receive(ctx Context, valuePtr interface{}) (more bool) {
+	if wait := c.waitTime(ctx); wait > 0 {
+		_ = Sleep(ctx, wait)
+	}
+	more = c.ReceiveChannel.Receive(ctx, valuePtr)
+	c.recordReceived(ctx)
+	return more
+}
+
+func (c *rateLimitedChannel) ReceiveWithTimeout(ctx Context, timeout time.Duration, valuePtr interface{}) (ok, more bool) {
+	if wait := c.waitTime(ctx); wait > 0 {
+		if wait >= timeout {
+			_ = Sleep(ctx, timeout)
+			return false, true
+		}
+		if err := Sleep(ctx, wait); err != nil {
+			return false, true
+		}
+		timeout -= wait
+	}
+	ok, more = c.ReceiveChannel.ReceiveWithTimeout(ctx, timeout, valuePtr)
+	if ok {
+		c.recordReceived(ctx)
+	}
+	return ok, more
+}
+
+func (c *rateLimitedChannel) ReceiveAsync(valuePtr interface{}) (ok bool) {
+	ok, _ = c.ReceiveAsyncWithMoreFlag(valuePtr)
+	return ok
+}
+
+func (c *rateLimitedChannel) ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, more bool) {
+	if c.waitTime(c.ctx) > 0 {
+		return false, true
+	}
+	ok, more = c.ReceiveChannel.ReceiveAsyncWithMoreFlag(valuePtr)
+	if ok {
+		c.recordReceived(c.ctx)
+	}
+	return ok, more
+}
+
 func newEncodedValue(value *commonpb.Payloads, dc converter.DataConverter) converter.EncodedValue {
 	if dc == nil {
 		dc = converter.GetDefaultDataConverter()
@@ -2244,6 +2988,30 @@ func MutableSideEffect(ctx Context, id string, f func(ctx Context) interface{},
 	return i.MutableSideEffect(ctx, id, f, equals)
 }
 
+// NewConfigValue returns the current value of dynamic config with the given id, fetching it with fetch and using
+// MutableSideEffect to only record a new marker when the value changes.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewConfigValue]
+func NewConfigValue[T comparable](ctx Context, id string, fetch func(ctx Context) T) (T, error) {
+	return NewConfigValueFunc(ctx, id, fetch, func(a, b T) bool { return a == b })
+}
+
+// NewConfigValueFunc is like NewConfigValue, but compares values with equals.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewConfigValueFunc]
+func NewConfigValueFunc[T any](ctx Context, id string, fetch func(ctx Context) T, equals func(a, b T) bool) (T, error) {
+	encoded := MutableSideEffect(ctx, id, func(ctx Context) interface{} {
+		return fetch(ctx)
+	}, func(a, b interface{}) bool {
+		aT, _ := a.(T)
+		bT, _ := b.(T)
+		return equals(aT, bT)
+	})
+	var value T
+	err := encoded.Get(&value)
+	return value, err
+}
+
 // MutableSideEffectWithOptions executes the provided function once, then it looks up the history for the value with the given id.
 // If there is no existing value, then it records the function result as a value with the given id on history;
 // otherwise, it compares whether the existing value from history has changed from the new function result by calling
@@ -2556,6 +3324,19 @@ func (wc *workflowEnvironmentInterceptor) GetLastError(ctx Context) error {
 	return wc.env.GetFailureConverter().FailureToError(info.lastFailure)
 }
 
+// GetPreviousAttemptError returns the failure of the previous attempt of this workflow run when it is retried because
+// of its RetryPolicy, or nil on the first attempt. Unlike GetLastError, it does not return the failure of a previous
+// cron or schedule run, since those start a new first attempt.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.GetPreviousAttemptError]
+func GetPreviousAttemptError(ctx Context) error {
+	info := GetWorkflowInfo(ctx)
+	if info.Attempt <= 1 || info.lastFailure == nil {
+		return nil
+	}
+	return getWorkflowEnvironment(ctx).GetFailureConverter().FailureToError(info.lastFailure)
+}
+
 // Needed so this can properly be considered an inbound interceptor
 func (*workflowEnvironmentInterceptor) mustEmbedWorkflowInboundInterceptorBase() {}
 
@@ -2585,6 +3366,9 @@ func WithActivityOptions(ctx Context, options ActivityOptions) Context {
 	eap.VersioningIntent = options.VersioningIntent
 	eap.Priority = convertToPBPriority(options.Priority)
 	eap.Summary = options.Summary
+	eap.NonRetryableDecodeError = options.NonRetryableDecodeError
+	eap.SkipIfWorkflowCancelled = options.SkipIfWorkflowCancelled
+	eap.OnRetry = options.OnRetry
 	return ctx1
 }
 
@@ -2601,6 +3385,8 @@ func WithLocalActivityOptions(ctx Context, options LocalActivityOptions) Context
 	opts.StartToCloseTimeout = options.StartToCloseTimeout
 	opts.RetryPolicy = applyRetryPolicyDefaultsForLocalActivity(options.RetryPolicy)
 	opts.Summary = options.Summary
+	opts.NonRetryableDecodeError = options.NonRetryableDecodeError
+	opts.OnRetry = options.OnRetry
 	return ctx1
 }
 
@@ -2629,6 +3415,19 @@ func WithTaskQueue(ctx Context, name string) Context {
 	return ctx1
 }
 
+// WithSameTaskQueue sets the task queue of both the activity options and the child workflow options in the copy of
+// the context to the task queue of the current workflow.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.WithSameTaskQueue]
+func WithSameTaskQueue(ctx Context) Context {
+	taskQueue := GetWorkflowInfo(ctx).TaskQueueName
+	ctx1 := setActivityParametersIfNotExist(ctx)
+	getActivityOptions(ctx1).TaskQueueName = taskQueue
+	ctx1 = setWorkflowEnvOptionsIfNotExist(ctx1)
+	getWorkflowEnvOptions(ctx1).TaskQueueName = taskQueue
+	return ctx1
+}
+
 // GetActivityOptions returns all activity options present on the context.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.GetActivityOptions]
@@ -2638,18 +3437,21 @@ func GetActivityOptions(ctx Context) ActivityOptions {
 		return ActivityOptions{}
 	}
 	return ActivityOptions{
-		TaskQueue:              opts.TaskQueueName,
-		ScheduleToCloseTimeout: opts.ScheduleToCloseTimeout,
-		ScheduleToStartTimeout: opts.ScheduleToStartTimeout,
-		StartToCloseTimeout:    opts.StartToCloseTimeout,
-		HeartbeatTimeout:       opts.HeartbeatTimeout,
-		WaitForCancellation:    opts.WaitForCancellation,
-		ActivityID:             opts.ActivityID,
-		RetryPolicy:            convertFromPBRetryPolicy(opts.RetryPolicy),
-		DisableEagerExecution:  opts.DisableEagerExecution,
-		VersioningIntent:       opts.VersioningIntent,
-		Priority:               convertFromPBPriority(opts.Priority),
-		Summary:                opts.Summary,
+		TaskQueue:               opts.TaskQueueName,
+		ScheduleToCloseTimeout:  opts.ScheduleToCloseTimeout,
+		ScheduleToStartTimeout:  opts.ScheduleToStartTimeout,
+		StartToCloseTimeout:     opts.StartToCloseTimeout,
+		HeartbeatTimeout:        opts.HeartbeatTimeout,
+		WaitForCancellation:     opts.WaitForCancellation,
+		ActivityID:              opts.ActivityID,
+		RetryPolicy:             convertFromPBRetryPolicy(opts.RetryPolicy),
+		DisableEagerExecution:   opts.DisableEagerExecution,
+		VersioningIntent:        opts.VersioningIntent,
+		Priority:                convertFromPBPriority(opts.Priority),
+		Summary:                 opts.Summary,
+		NonRetryableDecodeError: opts.NonRetryableDecodeError,
+		SkipIfWorkflowCancelled: opts.SkipIfWorkflowCancelled,
+		OnRetry:                 opts.OnRetry,
 	}
 }
 
@@ -2662,10 +3464,12 @@ func GetLocalActivityOptions(ctx Context) LocalActivityOptions {
 		return LocalActivityOptions{}
 	}
 	return LocalActivityOptions{
-		ScheduleToCloseTimeout: opts.ScheduleToCloseTimeout,
-		StartToCloseTimeout:    opts.StartToCloseTimeout,
-		RetryPolicy:            opts.RetryPolicy,
-		Summary:                opts.Summary,
+		ScheduleToCloseTimeout:  opts.ScheduleToCloseTimeout,
+		StartToCloseTimeout:     opts.StartToCloseTimeout,
+		RetryPolicy:             opts.RetryPolicy,
+		Summary:                 opts.Summary,
+		NonRetryableDecodeError: opts.NonRetryableDecodeError,
+		OnRetry:                 opts.OnRetry,
 	}
 }
 
@@ -2680,6 +3484,30 @@ func WithScheduleToCloseTimeout(ctx Context, d time.Duration) Context {
 	return ctx1
 }
 
+// minActivityBoundedByRunTimeout is the smallest ScheduleToCloseTimeout set by WithActivityBoundedByRun, so activities
+// scheduled right before the run times out still get scheduled.
+const minActivityBoundedByRunTimeout = time.Second
+
+// WithActivityBoundedByRun returns a copy of the context whose activities have a ScheduleToCloseTimeout no longer
+// than the remaining run time of the workflow, WorkflowInfo.WorkflowStartTime plus WorkflowInfo.WorkflowRunTimeout
+// minus the current workflow time, so they do not outlive the run. The timeout is at least one second. A shorter
+// ScheduleToCloseTimeout already set on the context is kept. The context is returned unchanged if the workflow has no
+// run timeout.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.WithActivityBoundedByRun]
+func WithActivityBoundedByRun(ctx Context) Context {
+	info := GetWorkflowInfo(ctx)
+	if info.WorkflowRunTimeout <= 0 {
+		return ctx
+	}
+	remaining := max(info.WorkflowStartTime.Add(info.WorkflowRunTimeout).Sub(Now(ctx)), minActivityBoundedByRunTimeout)
+	if options := getActivityOptions(ctx); options != nil &&
+		options.ScheduleToCloseTimeout > 0 && options.ScheduleToCloseTimeout <= remaining {
+		return ctx
+	}
+	return WithScheduleToCloseTimeout(ctx, remaining)
+}
+
 // WithScheduleToStartTimeout adds a timeout to the copy of the context.
 // The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
 // subjected to change in the future.
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// backoffIdempotencyScope is the NewIdempotencyKey scope used to seed the jitter of backoffs.
+const backoffIdempotencyScope = "__temporal_backoff"
+
+// Backoff computes exponentially growing delays for retry loops in workflows, to be used with Sleep. It only
+// computes durations, so it records nothing in history, and it is deterministic as long as it is used
+// deterministically. A Backoff is not safe for concurrent use by multiple coroutines.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.Backoff]
+type Backoff struct {
+	initial     time.Duration
+	max         time.Duration
+	coefficient float64
+	next        time.Duration
+	jitter      float64
+	rand        *rand.Rand
+}
+
+// NewBackoff creates a Backoff whose first delay is initial, multiplied by coefficient after every delay until it
+// reaches max. It panics if initial is not positive, max is less than initial or coefficient is less than 1.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewBackoff]
+func NewBackoff(initial, max time.Duration, coefficient float64) *Backoff {
+	if initial <= 0 {
+		panic("initial must be positive")
+	} else if max < initial {
+		panic("max must not be less than initial")
+	} else if coefficient < 1 {
+		panic("coefficient must be at least 1")
+	}
+	return &Backoff{initial: initial, max: max, coefficient: coefficient, next: initial}
+}
+
+// WithJitter makes every delay returned by Next shorter by a random amount of up to fraction of the delay, so the
+// retries of many workflows do not line up. fraction must be between 0 and 1. The random numbers are seeded from the
+// workflow run and the number of backoffs with jitter created before in the run, so they are the same on replay as
+// long as backoffs are created in the same order.
+func (b *Backoff) WithJitter(ctx Context, fraction float64) *Backoff {
+	if fraction < 0 || fraction > 1 {
+		panic("fraction must be between 0 and 1")
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(NewIdempotencyKey(ctx, backoffIdempotencyScope)))
+	b.jitter = fraction
+	b.rand = rand.New(rand.NewPCG(h.Sum64(), 0))
+	return b
+}
+
+// Next returns the delay to wait before the next attempt and grows the following delay.
+func (b *Backoff) Next() time.Duration {
+	d := b.next
+	if b.next < b.max {
+		b.next = time.Duration(min(float64(b.next)*b.coefficient, float64(b.max)))
+	}
+	if b.rand != nil {
+		d -= time.Duration(float64(d) * b.jitter * b.rand.Float64())
+	}
+	return d
+}
+
+// Reset makes the next delay the initial one again, e.g. after an attempt succeeded.
+func (b *Backoff) Reset() {
+	b.next = b.initial
+}
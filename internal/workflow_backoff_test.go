@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	b := NewBackoff(time.Second, 5*time.Second, 2)
+	var delays []time.Duration
+	for i := 0; i < 5; i++ {
+		delays = append(delays, b.Next())
+	}
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+	b.Reset()
+	require.Equal(t, time.Second, b.Next())
+
+	// A coefficient of 1 keeps the delay constant
+	b = NewBackoff(time.Second, time.Minute, 1)
+	require.Equal(t, time.Second, b.Next())
+	require.Equal(t, time.Second, b.Next())
+
+	require.Panics(t, func() { NewBackoff(0, time.Second, 2) })
+	require.Panics(t, func() { NewBackoff(time.Second, time.Millisecond, 2) })
+	require.Panics(t, func() { NewBackoff(time.Second, time.Minute, 0.5) })
+}
+
+func TestBackoffWithJitter(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(func(ctx Context) ([][]time.Duration, error) {
+		var result [][]time.Duration
+		for i := 0; i < 2; i++ {
+			b := NewBackoff(time.Second, time.Minute, 2).WithJitter(ctx, 0.5)
+			var delays []time.Duration
+			for j := 0; j < 10; j++ {
+				delays = append(delays, b.Next())
+			}
+			result = append(result, delays)
+		}
+		return result, nil
+	}, RegisterWorkflowOptions{Name: "backoff"})
+	env.ExecuteWorkflow("backoff")
+	require.NoError(t, env.GetWorkflowError())
+	var result [][]time.Duration
+	require.NoError(t, env.GetWorkflowResult(&result))
+
+	for _, delays := range result {
+		expected := time.Second
+		for _, d := range delays {
+			require.LessOrEqual(t, d, expected)
+			require.GreaterOrEqual(t, d, expected/2)
+			expected = min(2*expected, time.Minute)
+		}
+	}
+	// Each backoff gets its own random numbers
+	require.NotEqual(t, result[0], result[1])
+}
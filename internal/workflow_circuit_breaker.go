@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultCircuitBreakerFailureThreshold = 5
+	defaultCircuitBreakerCooldown         = time.Minute
+)
+
+const (
+	// CircuitBreakerClosed is the state in which activities are executed.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreakerClosed]
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerOpen is the state in which activities are short-circuited until the cooldown elapses.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreakerOpen]
+	CircuitBreakerOpen
+	// CircuitBreakerHalfOpen is the state after the cooldown in which a single probe activity is executed to decide
+	// whether to close the circuit breaker again.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreakerHalfOpen]
+	CircuitBreakerHalfOpen
+)
+
+// ErrCircuitBreakerOpen is the error of activities short-circuited by an open CircuitBreaker.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ErrCircuitBreakerOpen]
+var ErrCircuitBreakerOpen = errors.New("circuit breaker is open")
+
+type (
+	// CircuitBreakerState is the state of a CircuitBreaker.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreakerState]
+	CircuitBreakerState int
+
+	// CircuitBreakerSettings configures a CircuitBreaker.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreakerSettings]
+	CircuitBreakerSettings struct {
+		// FailureThreshold is the number of consecutive failed activities that opens the circuit breaker.
+		//
+		// Optional: defaults to 5.
+		FailureThreshold int
+		// Cooldown is how long the circuit breaker stays open before letting a probe activity through.
+		//
+		// Optional: defaults to 1 minute.
+		Cooldown time.Duration
+		// IsFailure reports whether an activity error counts as a failure of the dependency. Errors it returns false
+		// for are returned to the caller without affecting the circuit breaker.
+		//
+		// Optional: defaults to counting all errors except cancellation.
+		IsFailure func(err error) bool
+	}
+
+	// CircuitBreaker executes activities and stops scheduling them while the dependency they call is failing, so
+	// workflows fail fast during outages instead of retrying. After FailureThreshold consecutive failures it opens
+	// and returns ErrCircuitBreakerOpen without scheduling activities. Once Cooldown has elapsed it is half-open and
+	// lets a single probe activity through, short-circuiting the others: it closes if the probe succeeds, and opens
+	// for another cooldown if it fails.
+	//
+	// The state is workflow state, kept deterministically with counters and a workflow timer. It is per workflow run
+	// and starts closed again after continue-as-new.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.CircuitBreaker]
+	CircuitBreaker struct {
+		settings CircuitBreakerSettings
+		state    CircuitBreakerState
+		failures int
+		cooldown Future
+		probing  bool
+	}
+)
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewCircuitBreaker]
+func NewCircuitBreaker(ctx Context, settings CircuitBreakerSettings) *CircuitBreaker {
+	assertNotInReadOnlyState(ctx)
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = defaultCircuitBreakerFailureThreshold
+	}
+	if settings.Cooldown <= 0 {
+		settings.Cooldown = defaultCircuitBreakerCooldown
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return !IsCanceledError(err) }
+	}
+	return &CircuitBreaker{settings: settings}
+}
+
+// Execute executes the activity like ExecuteActivity if the circuit breaker lets it through, and returns a future
+// that is ready with ErrCircuitBreakerOpen otherwise. The result of the activity updates the state of the circuit
+// breaker before the returned future is ready.
+func (cb *CircuitBreaker) Execute(ctx Context, activity interface{}, args ...interface{}) Future {
+	future, settable := NewFuture(ctx)
+	if !cb.allow() {
+		settable.SetError(ErrCircuitBreakerOpen)
+		return future
+	}
+	probe := cb.state == CircuitBreakerHalfOpen
+	cb.probing = probe
+	activityFuture := ExecuteActivity(ctx, activity, args...)
+	Go(ctx, func(ctx Context) {
+		err := activityFuture.Get(ctx, nil)
+		cb.record(ctx, err, probe)
+		settable.Set(activityFuture.(asyncFuture).GetValueAndError())
+	})
+	return future
+}
+
+// State returns the current state of the circuit breaker.
+func (cb *CircuitBreaker) State() CircuitBreakerState {
+	if cb.state == CircuitBreakerOpen && cb.cooldown.IsReady() {
+		cb.state = CircuitBreakerHalfOpen
+	}
+	return cb.state
+}
+
+func (cb *CircuitBreaker) allow() bool {
+	switch cb.State() {
+	case CircuitBreakerOpen:
+		return false
+	case CircuitBreakerHalfOpen:
+		return !cb.probing
+	default:
+		return true
+	}
+}
+
+func (cb *CircuitBreaker) record(ctx Context, err error, probe bool) {
+	if probe {
+		cb.probing = false
+	}
+	if err != nil && !cb.settings.IsFailure(err) {
+		return
+	}
+	if err == nil {
+		// A success of an activity that was let through before the circuit breaker opened does not close it
+		if probe || cb.state == CircuitBreakerClosed {
+			cb.state = CircuitBreakerClosed
+			cb.failures = 0
+		}
+		return
+	}
+	cb.failures++
+	if probe || (cb.state == CircuitBreakerClosed && cb.failures >= cb.settings.FailureThreshold) {
+		cb.state = CircuitBreakerOpen
+		// The cooldown must elapse even if the context of the activity is canceled
+		timerCtx, _ := NewDisconnectedContext(ctx)
+		cb.cooldown = NewTimer(timerCtx, cb.settings.Cooldown)
+	}
+}
@@ -0,0 +1,133 @@
+package internal
+
+import "time"
+
+// Scheduler runs callbacks at given workflow times. Callbacks are started in the order of their times, and callbacks
+// scheduled for the same time in the order they were scheduled, so they run the same way on replay.
+//
+// The scheduler waits with a single timer for the earliest pending callback. Every timer is recorded in history, so
+// the history grows with the number of distinct callback times, plus a canceled timer whenever a callback earlier than
+// the pending one is scheduled or the pending one is canceled. A Scheduler is not safe for concurrent use outside of a
+// workflow.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.Scheduler]
+type Scheduler struct {
+	ctx     Context
+	pending *PriorityQueue[*ScheduledCallback]
+	// Notifies the scheduler coroutine that callbacks were scheduled or canceled
+	changed Channel
+}
+
+// ScheduledCallback is a callback scheduled with Scheduler.Schedule.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.ScheduledCallback]
+type ScheduledCallback struct {
+	scheduler *Scheduler
+	at        time.Time
+	fn        func(ctx Context)
+	started   bool
+	canceled  bool
+}
+
+// NewScheduler creates a Scheduler running callbacks in coroutines of ctx. Callbacks stop being started once ctx is
+// canceled.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewScheduler]
+func NewScheduler(ctx Context) *Scheduler {
+	s := &Scheduler{
+		ctx:     ctx,
+		pending: NewPriorityQueue(func(a, b *ScheduledCallback) bool { return a.at.Before(b.at) }),
+		changed: NewBufferedChannel(ctx, 1),
+	}
+	Go(ctx, s.run)
+	return s
+}
+
+// Schedule runs fn in a new coroutine once the workflow time reaches at. fn runs as soon as possible if at is not
+// after the current workflow time.
+func (s *Scheduler) Schedule(at time.Time, fn func(ctx Context)) *ScheduledCallback {
+	if fn == nil {
+		panic("fn must not be nil")
+	}
+	callback := &ScheduledCallback{scheduler: s, at: at, fn: fn}
+	s.pending.Push(callback)
+	s.notify()
+	return callback
+}
+
+// Time returns the workflow time the callback is scheduled for.
+func (c *ScheduledCallback) Time() time.Time {
+	return c.at
+}
+
+// Cancel prevents the callback from running. It returns false if the callback was already started or canceled.
+func (c *ScheduledCallback) Cancel() bool {
+	if c.started || c.canceled {
+		return false
+	}
+	c.canceled = true
+	c.scheduler.notify()
+	return true
+}
+
+func (s *Scheduler) notify() {
+	// A pending notification already makes the scheduler look at the callbacks again
+	s.changed.SendAsync(nil)
+}
+
+// next returns the earliest callback that is not canceled, dropping the canceled ones before it.
+func (s *Scheduler) next() (*ScheduledCallback, bool) {
+	for {
+		callback, ok := s.pending.Peek()
+		if !ok || !callback.canceled {
+			return callback, ok
+		}
+		s.pending.Pop()
+	}
+}
+
+func (s *Scheduler) run(ctx Context) {
+	for {
+		callback, ok := s.next()
+		if !ok {
+			s.changed.Receive(ctx, nil)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		if d := callback.at.Sub(Now(ctx)); d > 0 {
+			if !s.waitFor(ctx, callback, d) {
+				return
+			}
+			continue
+		}
+		s.pending.Pop()
+		callback.started = true
+		Go(ctx, callback.fn)
+	}
+}
+
+// waitFor waits until d has passed or callback stops being the earliest pending callback. It returns false if ctx is
+// canceled.
+func (s *Scheduler) waitFor(ctx Context, callback *ScheduledCallback, d time.Duration) bool {
+	timerCtx, cancelTimer := WithCancel(ctx)
+	defer cancelTimer()
+	timer := NewTimer(timerCtx, d)
+	for {
+		var fired bool
+		NewSelector(ctx).
+			AddFuture(timer, func(Future) { fired = true }).
+			AddReceive(s.changed, func(c ReceiveChannel, _ bool) { c.Receive(ctx, nil) }).
+			Select(ctx)
+		if ctx.Err() != nil {
+			return false
+		}
+		if fired {
+			return true
+		}
+		if next, ok := s.next(); !ok || next != callback {
+			return true
+		}
+	}
+}
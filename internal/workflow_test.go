@@ -51,34 +51,46 @@ func TestGetChildWorkflowOptions(t *testing.T) {
 
 func TestGetActivityOptions(t *testing.T) {
 	opts := ActivityOptions{
-		TaskQueue:              "foo",
-		ScheduleToCloseTimeout: time.Millisecond,
-		ScheduleToStartTimeout: time.Second,
-		StartToCloseTimeout:    time.Minute,
-		HeartbeatTimeout:       time.Hour,
-		WaitForCancellation:    true,
-		ActivityID:             "bar",
-		RetryPolicy:            newTestRetryPolicy(),
-		DisableEagerExecution:  true,
-		VersioningIntent:       VersioningIntentDefault,
-		Summary:                "activity summary",
-		Priority:               newPriority(),
+		TaskQueue:               "foo",
+		ScheduleToCloseTimeout:  time.Millisecond,
+		ScheduleToStartTimeout:  time.Second,
+		StartToCloseTimeout:     time.Minute,
+		HeartbeatTimeout:        time.Hour,
+		WaitForCancellation:     true,
+		ActivityID:              "bar",
+		RetryPolicy:             newTestRetryPolicy(),
+		DisableEagerExecution:   true,
+		VersioningIntent:        VersioningIntentDefault,
+		Summary:                 "activity summary",
+		Priority:                newPriority(),
+		NonRetryableDecodeError: true,
+		SkipIfWorkflowCancelled: true,
+		OnRetry:                 func(int32, error) {},
 	}
 
 	assertNonZero(t, opts)
-	assert.Equal(t, opts, GetActivityOptions(WithActivityOptions(newTestWorkflowContext(), opts)))
+	actual := GetActivityOptions(WithActivityOptions(newTestWorkflowContext(), opts))
+	// Functions are only equal when nil
+	assert.NotNil(t, actual.OnRetry)
+	opts.OnRetry, actual.OnRetry = nil, nil
+	assert.Equal(t, opts, actual)
 }
 
 func TestGetLocalActivityOptions(t *testing.T) {
 	opts := LocalActivityOptions{
-		ScheduleToCloseTimeout: time.Minute,
-		StartToCloseTimeout:    time.Hour,
-		RetryPolicy:            newTestRetryPolicy(),
-		Summary:                "local activity summary",
+		ScheduleToCloseTimeout:  time.Minute,
+		StartToCloseTimeout:     time.Hour,
+		RetryPolicy:             newTestRetryPolicy(),
+		Summary:                 "local activity summary",
+		NonRetryableDecodeError: true,
+		OnRetry:                 func(int32, error) {},
 	}
 
 	assertNonZero(t, opts)
-	assert.Equal(t, opts, GetLocalActivityOptions(WithLocalActivityOptions(newTestWorkflowContext(), opts)))
+	actual := GetLocalActivityOptions(WithLocalActivityOptions(newTestWorkflowContext(), opts))
+	assert.NotNil(t, actual.OnRetry)
+	opts.OnRetry, actual.OnRetry = nil, nil
+	assert.Equal(t, opts, actual)
 }
 
 func TestConvertRetryPolicy(t *testing.T) {
@@ -2,8 +2,10 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -49,6 +51,9 @@ type (
 		activityMock mock.Mock
 		nexusMock    mock.Mock
 		impl         *testWorkflowEnvironmentImpl
+
+		nonDeterminismDetection bool
+		delayedCallbacks        []testDelayedCallback
 	}
 
 	// TestActivityEnvironment is the environment that you use to test activity
@@ -65,8 +70,9 @@ type (
 		call *mock.Call
 		env  *TestWorkflowEnvironment
 
-		runFn        func(args mock.Arguments)
-		waitDuration func() time.Duration
+		runFn          func(args mock.Arguments)
+		waitDuration   func() time.Duration
+		attemptReturns *mockAttemptReturns
 	}
 
 	// TestUpdateCallback is a basic implementation of the UpdateCallbacks interface for testing purposes.
@@ -81,6 +87,60 @@ type (
 		OnReject   func(error)
 		OnComplete func(interface{}, error)
 	}
+
+	// TestEmittedSignal is a signal sent to another workflow by a workflow running in a TestWorkflowEnvironment, as
+	// returned by TestWorkflowEnvironment.GetEmittedSignals.
+	//
+	// Exposed as: [go.temporal.io/sdk/testsuite.TestEmittedSignal]
+	TestEmittedSignal struct {
+		// SenderWorkflowID is the ID of the workflow that sent the signal, which is the test workflow or one of its
+		// child workflows.
+		SenderWorkflowID string
+		// Namespace is the namespace of the signaled workflow.
+		Namespace string
+		// WorkflowID is the ID of the signaled workflow.
+		WorkflowID string
+		// RunID is the run ID of the signaled workflow, empty if the signal was sent to the current run.
+		RunID string
+		// SignalName is the name of the signal.
+		SignalName string
+		// Arg is the argument of the signal, decoded with the data converter of the sender.
+		Arg converter.EncodedValue
+	}
+
+	// TestContinueAsNewInfo describes the next run of a workflow that continued as new in a TestWorkflowEnvironment,
+	// including the state carried over to it. It is returned by TestWorkflowEnvironment.GetContinueAsNewInfo and can be
+	// passed to TestWorkflowEnvironment.ExecuteContinuedAsNewWorkflow to execute the next run.
+	//
+	// Exposed as: [go.temporal.io/sdk/testsuite.TestContinueAsNewInfo]
+	TestContinueAsNewInfo struct {
+		// WorkflowType is the workflow type of the next run.
+		WorkflowType string
+		// TaskQueueName is the task queue of the next run.
+		TaskQueueName string
+		// Args are the encoded arguments of the next run.
+		Args converter.EncodedValues
+		// Memo is the memo carried over to the next run, including any upserts of the previous run.
+		Memo *commonpb.Memo
+		// TypedSearchAttributes are the search attributes carried over to the next run, including any upserts of the
+		// previous run.
+		TypedSearchAttributes SearchAttributes
+		// WorkflowRunTimeout is the run timeout of the next run.
+		WorkflowRunTimeout time.Duration
+		// WorkflowTaskTimeout is the workflow task timeout of the next run.
+		WorkflowTaskTimeout time.Duration
+		// RetryPolicy is the retry policy of the next run. It is the retry policy of the previous run unless
+		// overridden with NewContinueAsNewErrorWithOptions.
+		RetryPolicy *RetryPolicy
+
+		workflowID       string
+		runID            string
+		firstRunID       string
+		input            *commonpb.Payloads
+		header           *commonpb.Header
+		searchAttributes *commonpb.SearchAttributes
+		continuedAt      time.Time
+	}
 )
 
 func newEncodedValues(values *commonpb.Payloads, dc converter.DataConverter) converter.EncodedValues {
@@ -834,6 +894,31 @@ func (c *MockCallWrapper) Return(returnArguments ...interface{}) *MockCallWrappe
 	return c
 }
 
+// ReturnByAttempt specifies a different return for each call of the mock, which is useful to test retries of a
+// mocked activity. The first call returns returns[0], the second returns[1] and so on, and calls beyond the end of
+// the slice keep returning the last element. An element that is an error fails the call with that error; any other
+// element is the result of a successful call (use nil for activities that only return an error). An element may also
+// be a function with the same signature as the mocked function, like in Return.
+//
+// Retries driven by the activity's RetryPolicy call the mock once per attempt, so Attempts reports how many attempts
+// were made.
+func (c *MockCallWrapper) ReturnByAttempt(returns []interface{}) *MockCallWrapper {
+	if len(returns) == 0 {
+		panic("ReturnByAttempt requires at least one return value")
+	}
+	c.attemptReturns = &mockAttemptReturns{returns: returns}
+	c.call.Return(c.attemptReturns)
+	return c
+}
+
+// Attempts returns how many times a mock configured with ReturnByAttempt has been called.
+func (c *MockCallWrapper) Attempts() int {
+	if c.attemptReturns == nil {
+		return 0
+	}
+	return c.attemptReturns.getAttempts()
+}
+
 // Panic specifies if the function call should fail and the panic message
 func (c *MockCallWrapper) Panic(msg string) *MockCallWrapper {
 	c.call.Panic(msg)
@@ -872,12 +957,69 @@ func (uc *TestUpdateCallback) Complete(success interface{}, err error) {
 // ExecuteWorkflow executes a workflow, wait until workflow complete. It will fail the test if workflow is blocked and
 // cannot complete within TestTimeout (set by SetTestTimeout()).
 func (e *TestWorkflowEnvironment) ExecuteWorkflow(workflowFn interface{}, args ...interface{}) {
+	if e.nonDeterminismDetection {
+		e.executeWorkflowWithNonDeterminismDetection(workflowFn, args...)
+		return
+	}
+	e.delayedCallbacks = nil
 	e.impl.workflowMock = &e.workflowMock
 	e.impl.activityMock = &e.activityMock
 	e.impl.nexusMock = &e.nexusMock
 	e.impl.executeWorkflow(workflowFn, args...)
 }
 
+// EnableNonDeterminismDetection makes ExecuteWorkflow execute the workflow twice and fail it with an error describing
+// the first command that differs between the two executions. Arguments and results are decoded anew for each
+// execution, so ranging over a map decoded from them, or over any other map, iterates in a different order. This
+// detects workflows whose commands depend on map iteration order, which fail to replay.
+//
+// The executions share the registered workflows, activities and mocks, and callbacks registered with
+// RegisterDelayedCallback run in both, so mock Run functions and activities are called once for each execution.
+// Listeners are only called for the second execution, whose result is returned by GetWorkflowResult. Detection can
+// report false positives for workflows whose commands depend on the wall clock order in which activities complete.
+func (e *TestWorkflowEnvironment) EnableNonDeterminismDetection() *TestWorkflowEnvironment {
+	e.nonDeterminismDetection = true
+	return e
+}
+
+func (e *TestWorkflowEnvironment) executeWorkflowWithNonDeterminismDetection(workflowFn interface{}, args ...interface{}) {
+	impl := e.impl
+	detectionEnv := impl.newTestWorkflowEnvironmentForNonDeterminismDetection()
+	detectionEnv.workflowMock = copyMock(&e.workflowMock)
+	detectionEnv.activityMock = copyMock(&e.activityMock)
+	detectionEnv.nexusMock = copyMock(&e.nexusMock)
+	// Delayed callbacks usually call back into e, so point e at the first execution while it runs
+	e.impl = detectionEnv
+	for _, c := range e.delayedCallbacks {
+		detectionEnv.registerDelayedCallback(c.callback, c.delay)
+	}
+	detectionEnv.executeWorkflow(workflowFn, args...)
+	e.impl = impl
+
+	impl.recordCommands = true
+	impl.workflowMock = &e.workflowMock
+	impl.activityMock = &e.activityMock
+	impl.nexusMock = &e.nexusMock
+	impl.executeWorkflow(workflowFn, args...)
+	if err := compareCommands(detectionEnv.commands, impl.commands); err != nil {
+		impl.testError = err
+	}
+}
+
+// ExecuteContinuedAsNewWorkflow executes the next run of a workflow that continued as new, as returned by
+// GetContinueAsNewInfo of the environment that executed the previous run, and waits until it completes.
+//
+// Like ExecuteWorkflow, this can only be called once per environment, so each run needs a new environment with the
+// workflows, activities and mocks it uses registered. The next run keeps the workflow ID of the previous run, gets a new
+// run ID with GetInfo(ctx).ContinuedExecutionRunID set to the previous one, and starts at the workflow time the previous
+// run completed.
+func (e *TestWorkflowEnvironment) ExecuteContinuedAsNewWorkflow(info *TestContinueAsNewInfo) {
+	e.impl.workflowMock = &e.workflowMock
+	e.impl.activityMock = &e.activityMock
+	e.impl.nexusMock = &e.nexusMock
+	e.impl.executeContinuedAsNewWorkflow(info)
+}
+
 // Now returns the current workflow time (a.k.a workflow.Now() time) of this TestWorkflowEnvironment.
 func (e *TestWorkflowEnvironment) Now() time.Time {
 	return e.impl.Now()
@@ -893,7 +1035,8 @@ func (e *TestWorkflowEnvironment) SetWorkerOptions(options WorkerOptions) *TestW
 	return e
 }
 
-// SetStartWorkflowOptions sets StartWorkflowOptions used to specify workflow execution timeout and task queue.
+// SetStartWorkflowOptions sets StartWorkflowOptions used to specify workflow execution timeout, task queue and
+// headers.
 // Note that StartWorkflowOptions is defined in an internal package, use client.StartWorkflowOptions instead.
 func (e *TestWorkflowEnvironment) SetStartWorkflowOptions(options StartWorkflowOptions) *TestWorkflowEnvironment {
 	e.impl.setStartWorkflowOptions(options)
@@ -1149,6 +1292,30 @@ func (e *TestWorkflowEnvironment) GetWorkflowError() error {
 	return e.impl.testError
 }
 
+// GetContinueAsNewInfo returns the next run requested by the test workflow if it completed by continuing as new,
+// or nil otherwise.
+func (e *TestWorkflowEnvironment) GetContinueAsNewInfo() *TestContinueAsNewInfo {
+	var continueAsNewErr *ContinueAsNewError
+	if !e.impl.isWorkflowCompleted || !errors.As(e.impl.testError, &continueAsNewErr) {
+		return nil
+	}
+	return e.impl.newContinueAsNewInfo(continueAsNewErr)
+}
+
+// GetEmittedSignals returns the signals sent to other workflows by the test workflow and its child workflows, in the
+// order they were sent, including signals to mocked external workflows and those that failed to be delivered.
+func (e *TestWorkflowEnvironment) GetEmittedSignals() []TestEmittedSignal {
+	return slices.Clone(e.impl.emittedSignals)
+}
+
+// GetQueryTypes returns the sorted query types of the query handlers currently set by the test workflow, excluding
+// built-in queries like QueryTypeStackTrace. Use QueryWorkflow to get their results. It can be called while the
+// workflow is running, e.g. from a callback registered with RegisterDelayedCallback, to check the handlers set at that
+// point, or after it completes.
+func (e *TestWorkflowEnvironment) GetQueryTypes() ([]string, error) {
+	return e.impl.getQueryTypes()
+}
+
 // GetWorkflowErrorByID return the error from test workflow
 func (e *TestWorkflowEnvironment) GetWorkflowErrorByID(workflowID string) error {
 	if workflowHandle, ok := e.impl.runningWorkflows[workflowID]; ok {
@@ -1233,6 +1400,8 @@ func (e *TestWorkflowEnvironment) QueryWorkflowByID(workflowID, queryType string
 // Use 0 delayDuration to send a signal to simulate SignalWithStart. Note that a 0 duration delay will *not* work with
 // Queries, as the workflow will not have had a chance to register any query handlers.
 func (e *TestWorkflowEnvironment) RegisterDelayedCallback(callback func(), delayDuration time.Duration) {
+	// Kept to register them again for the first execution of the workflow if non-determinism detection is enabled
+	e.delayedCallbacks = append(e.delayedCallbacks, testDelayedCallback{callback: callback, delay: delayDuration})
 	e.impl.registerDelayedCallback(callback, delayDuration)
 }
 
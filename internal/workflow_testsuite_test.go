@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -1316,3 +1318,1029 @@ func TestExecuteActivitiesInWorkflow(t *testing.T) {
 	_, err = env.ExecuteActivity(checkActivityInfo, true)
 	require.NoError(t, err)
 }
+
+func TestNewIdempotencyKey(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) ([]string, error) {
+		return []string{
+			NewIdempotencyKey(ctx, "charge"),
+			NewIdempotencyKey(ctx, "charge"),
+			NewIdempotencyKey(ctx, "email"),
+		}, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var keys []string
+	require.NoError(t, env.GetWorkflowResult(&keys))
+	runID := env.impl.workflowInfo.WorkflowExecution.RunID
+	require.Equal(t, []string{runID + ":charge:1", runID + ":charge:2", runID + ":email:1"}, keys)
+}
+
+func TestGoTyped(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) (int, error) {
+		futures := []TypedFuture[int]{
+			GoTyped(ctx, func(ctx Context) (int, error) {
+				return 1, Sleep(ctx, time.Minute)
+			}),
+			GoTyped(ctx, func(ctx Context) (int, error) {
+				return 2, nil
+			}),
+		}
+		sum := 0
+		for _, f := range futures {
+			v, err := f.GetTyped(ctx)
+			if err != nil {
+				return 0, err
+			}
+			sum += v
+		}
+		_, err := GoTyped(ctx, func(ctx Context) (int, error) {
+			return 0, errors.New("fail")
+		}).GetTyped(ctx)
+		if err == nil || err.Error() != "fail" {
+			return 0, fmt.Errorf("unexpected error: %v", err)
+		}
+		return sum, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var sum int
+	require.NoError(t, env.GetWorkflowResult(&sum))
+	require.Equal(t, 3, sum)
+}
+
+func TestGoTypedCanceled(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx, cancel := WithCancel(ctx)
+		f := GoTyped(ctx, func(ctx Context) (string, error) {
+			// Ignores cancellation
+			NewChannel(ctx).Receive(ctx, nil)
+			return "unreachable", nil
+		})
+		cancel()
+		_, err := f.GetTyped(ctx)
+		return err
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	var canceledErr *CanceledError
+	require.ErrorAs(t, env.GetWorkflowError(), &canceledErr)
+}
+
+func TestGetTypedSearchAttributesInfo(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	startKey := NewSearchAttributeKeyInt64("CustomIntField")
+	upsertKey := NewSearchAttributeKeyKeyword("CustomKeywordField")
+	require.NoError(t, env.SetTypedSearchAttributesOnStart(NewSearchAttributes(startKey.ValueSet(1))))
+	env.ExecuteWorkflow(func(ctx Context) error {
+		for _, value := range []string{"a", "b"} {
+			if err := UpsertTypedSearchAttributes(ctx, upsertKey.ValueSet(value)); err != nil {
+				return err
+			}
+		}
+		info := GetTypedSearchAttributesInfo(ctx)
+		require.Equal(t, SearchAttributeInfo{Value: int64(1), Source: SearchAttributeSourceStart}, info[startKey])
+		require.Equal(t, SearchAttributeInfo{Value: "b", Source: SearchAttributeSourceUpsert, Version: 2}, info[upsertKey])
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
+
+func TestExecuteActivityCached(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var calls int
+	activity := func(ctx context.Context, arg string) (string, error) {
+		calls++
+		return arg + "!", nil
+	}
+	env.RegisterActivity(activity)
+	env.ExecuteWorkflow(func(ctx Context) ([]string, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: time.Minute})
+		if err := ExecuteActivityCached(ctx, "", activity, "x").Get(ctx, nil); err == nil {
+			return nil, errors.New("expected error for empty cache key")
+		}
+		var results []string
+		for _, key := range []string{"a", "a", "b"} {
+			var result string
+			if err := ExecuteActivityCached(ctx, key, activity, key).Get(ctx, &result); err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var results []string
+	require.NoError(t, env.GetWorkflowResult(&results))
+	require.Equal(t, []string{"a!", "a!", "b!"}, results)
+	require.Equal(t, 2, calls)
+}
+
+func TestRegistrationAliases(t *testing.T) {
+	activity := func(ctx context.Context) (string, error) { return GetActivityInfo(ctx).ActivityType.Name, nil }
+	workflow := func(ctx Context) ([]string, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{StartToCloseTimeout: time.Minute})
+		ctx = WithLocalActivityOptions(ctx, LocalActivityOptions{StartToCloseTimeout: time.Minute})
+		results := []string{GetWorkflowInfo(ctx).WorkflowType.Name}
+		for _, name := range []string{"NewActivity", "OldActivity"} {
+			var result, localResult string
+			if err := ExecuteActivity(ctx, name).Get(ctx, &result); err != nil {
+				return nil, err
+			}
+			if err := ExecuteLocalActivity(ctx, name).Get(ctx, &localResult); err != nil {
+				return nil, err
+			}
+			results = append(results, result, localResult)
+		}
+		return results, nil
+	}
+	for _, name := range []string{"NewWorkflow", "OldWorkflow"} {
+		t.Run(name, func(t *testing.T) {
+			var suite WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(workflow, RegisterWorkflowOptions{Name: "NewWorkflow", Aliases: []string{"OldWorkflow"}})
+			env.RegisterActivityWithOptions(activity, RegisterActivityOptions{Name: "NewActivity", Aliases: []string{"OldActivity"}})
+			env.ExecuteWorkflow(name)
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			var results []string
+			require.NoError(t, env.GetWorkflowResult(&results))
+			// Workflows and activities report their primary name when invoked by an alias
+			require.Equal(t, []string{"NewWorkflow", "NewActivity", "NewActivity", "NewActivity", "NewActivity"}, results)
+		})
+	}
+}
+
+func TestWorkflowInfoExecutionConfig(t *testing.T) {
+	workflowFn := func(ctx Context) (*WorkflowInfo, error) {
+		return GetWorkflowInfo(ctx), nil
+	}
+
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowFn)
+	require.NoError(t, env.GetWorkflowError())
+	var info WorkflowInfo
+	require.NoError(t, env.GetWorkflowResult(&info))
+	require.Nil(t, info.RetryPolicy)
+
+	retryPolicy := &RetryPolicy{InitialInterval: time.Second, BackoffCoefficient: 2, MaximumAttempts: 3}
+	env = suite.NewTestWorkflowEnvironment()
+	env.SetStartWorkflowOptions(StartWorkflowOptions{
+		WorkflowExecutionTimeout: time.Hour,
+		WorkflowRunTimeout:       time.Minute,
+		WorkflowTaskTimeout:      5 * time.Second,
+		RetryPolicy:              retryPolicy,
+	})
+	env.ExecuteWorkflow(workflowFn)
+	require.NoError(t, env.GetWorkflowError())
+	require.NoError(t, env.GetWorkflowResult(&info))
+	require.Equal(t, retryPolicy, info.RetryPolicy)
+	require.Equal(t, time.Hour, info.WorkflowExecutionTimeout)
+	require.Equal(t, time.Minute, info.WorkflowRunTimeout)
+	require.Equal(t, 5*time.Second, info.WorkflowTaskTimeout)
+}
+
+func TestWorkflowCompletionHook(t *testing.T) {
+	type hookCall struct {
+		Completion WorkflowCompletion
+		Result     *string
+	}
+	tests := []struct {
+		name          string
+		workflowErr   func(ctx Context) error
+		failurePolicy WorkflowCompletionHookFailurePolicy
+		hookErr       error
+		expected      WorkflowCompletion
+		expectedErr   string
+	}{
+		{
+			name:     "completed",
+			expected: WorkflowCompletion{Outcome: WorkflowCompletionOutcomeCompleted},
+		},
+		{
+			name:        "failed",
+			workflowErr: func(Context) error { return errors.New("workflow failed") },
+			expected:    WorkflowCompletion{Outcome: WorkflowCompletionOutcomeFailed, Error: "workflow failed"},
+			expectedErr: "workflow failed",
+		},
+		{
+			name: "continued as new",
+			workflowErr: func(ctx Context) error {
+				return NewContinueAsNewError(ctx, "hookedWorkflow")
+			},
+			expected:    WorkflowCompletion{Outcome: WorkflowCompletionOutcomeContinuedAsNew, Error: "continue as new"},
+			expectedErr: "continue as new",
+		},
+		{
+			name:     "hook failure ignored",
+			hookErr:  errors.New("hook failed"),
+			expected: WorkflowCompletion{Outcome: WorkflowCompletionOutcomeCompleted},
+		},
+		{
+			name:          "hook failure fails workflow",
+			hookErr:       errors.New("hook failed"),
+			failurePolicy: WorkflowCompletionHookFailurePolicyFailWorkflow,
+			expected:      WorkflowCompletion{Outcome: WorkflowCompletionOutcomeCompleted},
+			expectedErr:   "hook failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []hookCall
+			hook := func(ctx context.Context, completion WorkflowCompletion, result *string) error {
+				calls = append(calls, hookCall{Completion: completion, Result: result})
+				return tt.hookErr
+			}
+			var suite WorkflowTestSuite
+			env := suite.NewTestWorkflowEnvironment()
+			env.RegisterActivity(hook)
+			env.RegisterWorkflowWithOptions(func(ctx Context) (string, error) {
+				if tt.workflowErr != nil {
+					return "", tt.workflowErr(ctx)
+				}
+				return "done", nil
+			}, RegisterWorkflowOptions{
+				Name: "hookedWorkflow",
+				OnCompletion: &WorkflowCompletionHook{
+					Activity: hook,
+					ActivityOptions: ActivityOptions{
+						StartToCloseTimeout: time.Minute,
+						RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+					},
+					FailurePolicy: tt.failurePolicy,
+				},
+			})
+			env.ExecuteWorkflow("hookedWorkflow")
+			require.True(t, env.IsWorkflowCompleted())
+			if tt.expectedErr == "" {
+				require.NoError(t, env.GetWorkflowError())
+			} else {
+				require.ErrorContains(t, env.GetWorkflowError(), tt.expectedErr)
+			}
+			require.Len(t, calls, 1)
+			require.Equal(t, tt.expected, calls[0].Completion)
+			if tt.expected.Outcome == WorkflowCompletionOutcomeCompleted {
+				require.Equal(t, "done", *calls[0].Result)
+			} else {
+				require.Nil(t, calls[0].Result)
+			}
+		})
+	}
+}
+
+func continueAsNewCounterWorkflow(ctx Context, count int) (int, error) {
+	if count >= 2 {
+		return count, nil
+	}
+	if err := UpsertMemo(ctx, map[string]interface{}{"count": count}); err != nil {
+		return 0, err
+	}
+	if err := UpsertTypedSearchAttributes(ctx, NewSearchAttributeKeyInt64("CustomIntField").ValueSet(int64(count))); err != nil {
+		return 0, err
+	}
+	if err := Sleep(ctx, time.Hour); err != nil {
+		return 0, err
+	}
+	return 0, NewContinueAsNewError(ctx, continueAsNewCounterWorkflow, count+1)
+}
+
+func TestGetContinueAsNewInfo(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(continueAsNewCounterWorkflow)
+	env.ExecuteWorkflow(continueAsNewCounterWorkflow, 1)
+	require.True(t, env.IsWorkflowCompleted())
+	var continueAsNewErr *ContinueAsNewError
+	require.ErrorAs(t, env.GetWorkflowError(), &continueAsNewErr)
+
+	info := env.GetContinueAsNewInfo()
+	require.NotNil(t, info)
+	require.Equal(t, "continueAsNewCounterWorkflow", info.WorkflowType)
+	require.Equal(t, defaultTestTaskQueue, info.TaskQueueName)
+	var count int
+	require.NoError(t, info.Args.Get(&count))
+	require.Equal(t, 2, count)
+	var memoCount int
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(info.Memo.GetFields()["count"], &memoCount))
+	require.Equal(t, 1, memoCount)
+	searchAttribute, ok := info.TypedSearchAttributes.GetInt64(NewSearchAttributeKeyInt64("CustomIntField"))
+	require.True(t, ok)
+	require.Equal(t, int64(1), searchAttribute)
+
+	// Execute the next run with the carried over state
+	nextEnv := suite.NewTestWorkflowEnvironment()
+	var nextInfo *WorkflowInfo
+	var nextSearchAttributes SearchAttributes
+	var nextFirstRunID string
+	nextEnv.RegisterWorkflowWithOptions(func(ctx Context, count int) (int, error) {
+		nextInfo = GetWorkflowInfo(ctx)
+		nextFirstRunID = GetFirstRunID(ctx)
+		nextSearchAttributes = GetTypedSearchAttributes(ctx)
+		return continueAsNewCounterWorkflow(ctx, count)
+	}, RegisterWorkflowOptions{Name: "continueAsNewCounterWorkflow"})
+	nextEnv.ExecuteContinuedAsNewWorkflow(info)
+	require.True(t, nextEnv.IsWorkflowCompleted())
+	require.NoError(t, nextEnv.GetWorkflowResult(&count))
+	require.Equal(t, 2, count)
+	require.Nil(t, nextEnv.GetContinueAsNewInfo())
+	require.Equal(t, env.Now(), nextInfo.WorkflowStartTime)
+	require.Equal(t, defaultTestWorkflowID, nextInfo.WorkflowExecution.ID)
+	require.Equal(t, defaultTestRunID, nextInfo.ContinuedExecutionRunID)
+	require.NotEqual(t, defaultTestRunID, nextInfo.WorkflowExecution.RunID)
+	require.Equal(t, defaultTestRunID, nextFirstRunID)
+	require.Equal(t, info.Memo, nextInfo.Memo)
+	require.Equal(t, info.TypedSearchAttributes, nextSearchAttributes)
+}
+
+func TestDrainSignals(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("add", "a")
+		env.SignalWorkflow("add", "b")
+		env.SignalWorkflow("ignored", "c")
+	}, time.Minute)
+	var items []string
+	var drained map[string]int
+	env.ExecuteWorkflow(func(ctx Context) ([]string, error) {
+		if err := Sleep(ctx, time.Hour); err != nil {
+			return nil, err
+		}
+		drained = DrainSignals(ctx, map[string]func(Context, ReceiveChannel){
+			"add": func(ctx Context, ch ReceiveChannel) {
+				var item string
+				ch.ReceiveAsync(&item)
+				items = append(items, item)
+			},
+			"ignored": func(Context, ReceiveChannel) {},
+			"none":    func(Context, ReceiveChannel) {},
+		})
+		require.Empty(t, GetUnhandledSignalNames(ctx))
+		return items, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{"a", "b"}, items)
+	require.Equal(t, map[string]int{"add": 2, "ignored": 1, "none": 0}, drained)
+}
+
+func TestDrainSignalsHandlerBlocks(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("add", "a")
+		env.SignalWorkflow("add", "b")
+	}, time.Minute)
+	// Arrive while the handler of the first signal blocks
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("add", "c")
+		env.SignalWorkflow("add", "d")
+	}, time.Hour+time.Minute)
+	var items []string
+	var drained map[string]int
+	var left int
+	env.ExecuteWorkflow(func(ctx Context) error {
+		if err := Sleep(ctx, time.Hour); err != nil {
+			return err
+		}
+		drained = DrainSignals(ctx, map[string]func(Context, ReceiveChannel){
+			"add": func(ctx Context, ch ReceiveChannel) {
+				var item string
+				ch.ReceiveAsync(&item)
+				items = append(items, item)
+				if len(items) == 1 {
+					_ = Sleep(ctx, 5*time.Minute)
+				}
+			},
+		})
+		left = GetSignalChannel(ctx, "add").Len()
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{"a", "b"}, items)
+	require.Equal(t, map[string]int{"add": 2}, drained)
+	require.Equal(t, 2, left)
+}
+
+func TestNewRateLimitedSignalChannel(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		for _, item := range []string{"a", "b", "c", "d", "e"} {
+			env.SignalWorkflow("add", item)
+		}
+	}, time.Minute)
+	var items []string
+	var offsets []time.Duration
+	var unhandledWhilePaced []string
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ch := NewRateLimitedSignalChannel(ctx, "add", 2, 10*time.Second)
+		require.PanicsWithValue(t, "a rate limited signal channel cannot be used with a Selector", func() {
+			NewSelector(ctx).AddReceive(ch, func(ReceiveChannel, bool) {})
+		})
+		// Let all signals arrive before consuming them
+		if err := Sleep(ctx, time.Hour); err != nil {
+			return err
+		}
+		start := Now(ctx)
+		for len(items) < 5 {
+			var item string
+			ch.Receive(ctx, &item)
+			items = append(items, item)
+			offsets = append(offsets, Now(ctx).Sub(start))
+			if len(items) == 2 {
+				// The limit is reached, so the rest stays buffered
+				ok := ch.ReceiveAsync(&item)
+				require.False(t, ok)
+				unhandledWhilePaced = GetUnhandledSignalNames(ctx)
+			}
+		}
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, items)
+	require.Equal(t, []time.Duration{0, 0, 10 * time.Second, 10 * time.Second, 20 * time.Second}, offsets)
+	require.Equal(t, []string{"add"}, unhandledWhilePaced)
+}
+
+func TestActivityResultDecodeError(t *testing.T) {
+	for _, nonRetryable := range []bool{false, true} {
+		var suite WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		activityFn := func(context.Context) (string, error) { return "not a number", nil }
+		env.RegisterActivityWithOptions(activityFn, RegisterActivityOptions{Name: "stringActivity"})
+		var decodeErr error
+		env.ExecuteWorkflow(func(ctx Context) error {
+			ctx = WithActivityOptions(ctx, ActivityOptions{
+				StartToCloseTimeout:     time.Minute,
+				NonRetryableDecodeError: nonRetryable,
+			})
+			var result int
+			decodeErr = ExecuteActivity(ctx, "stringActivity").Get(ctx, &result)
+			return nil
+		})
+		require.NoError(t, env.GetWorkflowError())
+
+		var appErr *ApplicationError
+		require.ErrorAs(t, decodeErr, &appErr)
+		require.Equal(t, "DecodeError", appErr.Type())
+		require.Equal(t, nonRetryable, appErr.NonRetryable())
+		var typedErr *DecodeError
+		require.ErrorAs(t, decodeErr, &typedErr)
+		require.Equal(t, "stringActivity", typedErr.TypeName)
+		require.Equal(t, reflect.TypeOf(0), typedErr.TargetType)
+		require.ErrorIs(t, decodeErr, converter.ErrUnableToDecode)
+	}
+}
+
+func TestWithSameTaskQueue(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.SetStartWorkflowOptions(StartWorkflowOptions{TaskQueue: "my-task-queue"})
+	var activityOpts ActivityOptions
+	var childOpts ChildWorkflowOptions
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{TaskQueue: "other", StartToCloseTimeout: time.Minute})
+		ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{TaskQueue: "other"})
+		ctx = WithSameTaskQueue(ctx)
+		activityOpts = GetActivityOptions(ctx)
+		childOpts = GetChildWorkflowOptions(ctx)
+		return nil
+	})
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, "my-task-queue", activityOpts.TaskQueue)
+	require.Equal(t, time.Minute, activityOpts.StartToCloseTimeout)
+	require.Equal(t, "my-task-queue", childOpts.TaskQueue)
+}
+
+func TestCounter(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		for i := 0; i < 3; i++ {
+			env.UpdateWorkflowNoRejection("increment", fmt.Sprint(i), t, int64(i+1))
+		}
+	}, time.Minute)
+	var counter Counter
+	env.ExecuteWorkflow(func(ctx Context) (int64, error) {
+		counter = NewCounter(ctx)
+		err := SetUpdateHandler(ctx, "increment", func(ctx Context, delta int64) (int64, error) {
+			// Yield so that the handlers run concurrently
+			if err := Sleep(ctx, time.Second); err != nil {
+				return 0, err
+			}
+			return counter.Add(delta), nil
+		}, UpdateHandlerOptions{})
+		if err != nil {
+			return 0, err
+		}
+		err = SetQueryHandler(ctx, "count", func() (int64, error) {
+			return counter.Load(), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := Await(ctx, func() bool { return counter.Load() == 6 }); err != nil {
+			return 0, err
+		}
+		return counter.Load(), nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result int64
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.EqualValues(t, 6, result)
+
+	value, err := env.QueryWorkflow("count")
+	require.NoError(t, err)
+	require.NoError(t, value.Get(&result))
+	require.EqualValues(t, 6, result)
+
+	// Using the counter outside of the dispatcher panics
+	require.Panics(t, func() { counter.Add(1) })
+	require.Panics(t, func() { counter.Load() })
+}
+
+func TestHasProcessedUpdate(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow("update", "rejected", &TestUpdateCallback{
+			OnReject:   func(error) {},
+			OnAccept:   func() { require.Fail(t, "update should be rejected") },
+			OnComplete: func(interface{}, error) {},
+		}, true)
+		env.UpdateWorkflowNoRejection("update", "accepted", t, false)
+	}, time.Minute)
+	var processedInHandler, accepted, rejected bool
+	env.ExecuteWorkflow(func(ctx Context) error {
+		err := SetUpdateHandler(ctx, "update", func(ctx Context, reject bool) error {
+			processedInHandler = HasProcessedUpdate(ctx, GetCurrentUpdateInfo(ctx).ID)
+			return nil
+		}, UpdateHandlerOptions{
+			Validator: func(ctx Context, reject bool) error {
+				if reject {
+					return errors.New("rejected")
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := Sleep(ctx, time.Hour); err != nil {
+			return err
+		}
+		accepted = HasProcessedUpdate(ctx, "accepted")
+		rejected = HasProcessedUpdate(ctx, "rejected")
+		return nil
+	})
+	require.NoError(t, env.GetWorkflowError())
+	require.True(t, processedInHandler)
+	require.True(t, accepted)
+	require.False(t, rejected)
+}
+
+func TestGetPendingTimers(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var created, afterCancel, afterFire []PendingTimer
+	var start time.Time
+	env.ExecuteWorkflow(func(ctx Context) error {
+		start = Now(ctx)
+		cancelCtx, cancel := WithCancel(ctx)
+		canceled := NewTimerWithOptions(cancelCtx, time.Hour, TimerOptions{Summary: "canceled"})
+		short := NewTimer(ctx, time.Minute)
+		long := NewTimerWithOptions(ctx, 2*time.Hour, TimerOptions{Summary: "long"})
+		created = GetPendingTimers(ctx)
+
+		cancel()
+		_ = canceled.Get(ctx, nil)
+		afterCancel = GetPendingTimers(ctx)
+
+		if err := short.Get(ctx, nil); err != nil {
+			return err
+		}
+		afterFire = GetPendingTimers(ctx)
+		return long.Get(ctx, nil)
+	})
+	require.NoError(t, env.GetWorkflowError())
+
+	require.Len(t, created, 3)
+	require.Equal(t, "canceled", created[0].Summary)
+	require.Equal(t, time.Hour, created[0].FireTime.Sub(start))
+	require.Equal(t, time.Minute, created[1].FireTime.Sub(start))
+	require.Equal(t, "long", created[2].Summary)
+	require.Equal(t, created[1:], afterCancel)
+	require.Equal(t, created[2:], afterFire)
+	require.Equal(t, 2*time.Hour, afterFire[0].FireTime.Sub(start))
+}
+
+func TestContinueAsNewIfChanged(t *testing.T) {
+	equals := func(a, b []string) bool { return slices.Equal(a, b) }
+	for _, changed := range []bool{false, true} {
+		var suite WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		workflowFn := func(ctx Context, state []string, suffix string) (string, error) {
+			next := slices.Clone(state)
+			if changed {
+				next = append(next, suffix)
+			}
+			if err := ContinueAsNewIfChanged(ctx, state, next, equals, "continueAsNewIfChanged", suffix); err != nil {
+				return "", err
+			}
+			return "kept running", nil
+		}
+		env.RegisterWorkflowWithOptions(workflowFn, RegisterWorkflowOptions{Name: "continueAsNewIfChanged"})
+		env.ExecuteWorkflow("continueAsNewIfChanged", []string{"a"}, "b")
+		require.True(t, env.IsWorkflowCompleted())
+
+		if !changed {
+			require.NoError(t, env.GetWorkflowError())
+			var result string
+			require.NoError(t, env.GetWorkflowResult(&result))
+			require.Equal(t, "kept running", result)
+			continue
+		}
+		info := env.GetContinueAsNewInfo()
+		require.NotNil(t, info)
+		var state []string
+		var suffix string
+		require.NoError(t, info.Args.Get(&state, &suffix))
+		require.Equal(t, []string{"a", "b"}, state)
+		require.Equal(t, "b", suffix)
+	}
+}
+
+func continueAsNewTypedWorkflow(ctx Context, count int) error {
+	return ContinueAsNewTyped(ctx, continueAsNewTypedWorkflow, count+1)
+}
+
+func continueAsNewTypedNoInputWorkflow(ctx Context) error {
+	return ContinueAsNewTypedNoInput(ctx, continueAsNewTypedNoInputWorkflow)
+}
+
+func unregisteredContinueAsNewTypedWorkflow(Context, string) error {
+	return nil
+}
+
+func TestContinueAsNewTyped(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(continueAsNewTypedWorkflow)
+	env.ExecuteWorkflow(continueAsNewTypedWorkflow, 1)
+	require.True(t, env.IsWorkflowCompleted())
+	info := env.GetContinueAsNewInfo()
+	require.NotNil(t, info)
+	require.Equal(t, "continueAsNewTypedWorkflow", info.WorkflowType)
+	var count int
+	require.NoError(t, info.Args.Get(&count))
+	require.Equal(t, 2, count)
+
+	env = suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(continueAsNewTypedNoInputWorkflow)
+	env.ExecuteWorkflow(continueAsNewTypedNoInputWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	info = env.GetContinueAsNewInfo()
+	require.NotNil(t, info)
+	require.Equal(t, "continueAsNewTypedNoInputWorkflow", info.WorkflowType)
+
+	env = suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) error {
+		return ContinueAsNewTyped(ctx, unregisteredContinueAsNewTypedWorkflow, "arg")
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "workflow unregisteredContinueAsNewTypedWorkflow is not registered")
+
+	// The workflows of another task queue are registered with its own workers
+	env = suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithWorkflowTaskQueue(ctx, "other-task-queue")
+		return ContinueAsNewTyped(ctx, unregisteredContinueAsNewTypedWorkflow, "arg")
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	info = env.GetContinueAsNewInfo()
+	require.NotNil(t, info)
+	require.Equal(t, "unregisteredContinueAsNewTypedWorkflow", info.WorkflowType)
+	require.Equal(t, "other-task-queue", info.TaskQueueName)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var calls int
+	activityFn := func(context.Context) error {
+		calls++
+		if calls <= 3 {
+			return errors.New("dependency down")
+		}
+		return nil
+	}
+	env.RegisterActivity(activityFn)
+
+	var states []CircuitBreakerState
+	var errs []error
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+		})
+		breaker := NewCircuitBreaker(ctx, CircuitBreakerSettings{FailureThreshold: 2, Cooldown: time.Minute})
+		execute := func() {
+			errs = append(errs, breaker.Execute(ctx, activityFn).Get(ctx, nil))
+			states = append(states, breaker.State())
+		}
+		// Two failures open the circuit breaker, which then short-circuits
+		execute()
+		execute()
+		execute()
+		// After the cooldown a single probe goes through, the probe fails and reopens it
+		if err := Sleep(ctx, 2*time.Minute); err != nil {
+			return err
+		}
+		probe := breaker.Execute(ctx, activityFn)
+		errs = append(errs, breaker.Execute(ctx, activityFn).Get(ctx, nil))
+		errs = append(errs, probe.Get(ctx, nil))
+		states = append(states, breaker.State())
+		// The next probe succeeds and closes it
+		if err := Sleep(ctx, 2*time.Minute); err != nil {
+			return err
+		}
+		states = append(states, breaker.State())
+		execute()
+		execute()
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, 5, calls)
+	require.Equal(t, []CircuitBreakerState{
+		CircuitBreakerClosed, CircuitBreakerOpen, CircuitBreakerOpen, CircuitBreakerOpen, CircuitBreakerHalfOpen,
+		CircuitBreakerClosed, CircuitBreakerClosed,
+	}, states)
+	require.Len(t, errs, 7)
+	require.ErrorContains(t, errs[0], "dependency down")
+	require.ErrorContains(t, errs[1], "dependency down")
+	require.ErrorIs(t, errs[2], ErrCircuitBreakerOpen)
+	require.ErrorIs(t, errs[3], ErrCircuitBreakerOpen)
+	require.ErrorContains(t, errs[4], "dependency down")
+	require.NoError(t, errs[5])
+	require.NoError(t, errs[6])
+}
+
+func TestGetTypedSignalChannel(t *testing.T) {
+	type approval struct {
+		Approver string
+	}
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	// Both signals are buffered before the workflow reads the channel
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("approval", approval{Approver: "alice"})
+		env.SignalWorkflow("approval", approval{Approver: "bob"})
+	}, time.Second)
+	env.ExecuteWorkflow(func(ctx Context) ([]string, error) {
+		if err := Sleep(ctx, time.Minute); err != nil {
+			return nil, err
+		}
+		ch := GetTypedSignalChannel[approval](ctx, "approval")
+		require.Equal(t, "approval", ch.Name())
+		require.Equal(t, 2, ch.Len())
+		first, more := ch.Receive(ctx)
+		require.True(t, more)
+		second, ok := ch.ReceiveAsync()
+		require.True(t, ok)
+		_, ok = ch.ReceiveAsync()
+		require.False(t, ok)
+		return []string{first.Approver, second.Approver}, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var approvers []string
+	require.NoError(t, env.GetWorkflowResult(&approvers))
+	require.Equal(t, []string{"alice", "bob"}, approvers)
+
+	// A signal that cannot be decoded fails the workflow task instead of being dropped
+	env = suite.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("count", "not a number")
+	}, time.Second)
+	env.ExecuteWorkflow(func(ctx Context) (int, error) {
+		count, _ := GetTypedSignalChannel[int](ctx, "count").Receive(ctx)
+		return count, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	var panicErr *PanicError
+	require.ErrorAs(t, env.GetWorkflowError(), &panicErr)
+	require.Contains(t, panicErr.Error(), "unable to decode signal count into int")
+}
+
+func TestAwaitWithReason(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var results []AwaitResult
+	var errs []error
+	var pendingTimers int
+	env.ExecuteWorkflow(func(ctx Context) error {
+		met := false
+		Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, time.Minute)
+			met = true
+		})
+		result, err := AwaitWithReason(ctx, time.Hour, func() bool { return met })
+		results, errs = append(results, result), append(errs, err)
+		pendingTimers = len(GetPendingTimers(ctx))
+
+		result, err = AwaitWithReason(ctx, time.Hour, func() bool { return false })
+		results, errs = append(results, result), append(errs, err)
+
+		canceledCtx, cancel := WithCancel(ctx)
+		cancel()
+		result, err = AwaitWithReason(canceledCtx, time.Hour, func() bool { return false })
+		results, errs = append(results, result), append(errs, err)
+		return nil
+	})
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []AwaitResult{AwaitResultConditionMet, AwaitResultTimedOut, AwaitResultCanceled}, results)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	var canceledErr *CanceledError
+	require.ErrorAs(t, errs[2], &canceledErr)
+	require.Zero(t, pendingTimers)
+}
+
+func TestActivityOnRetry(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var calls int
+	activityFn := func(context.Context) error {
+		calls++
+		if calls%3 != 0 {
+			return fmt.Errorf("attempt %d failed", calls%3)
+		}
+		return nil
+	}
+	env.RegisterActivity(activityFn)
+
+	type retry struct {
+		attempt int32
+		err     string
+	}
+	var retries, localRetries []retry
+	env.ExecuteWorkflow(func(ctx Context) error {
+		retryPolicy := &RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 5}
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         retryPolicy,
+			OnRetry: func(attempt int32, lastErr error) {
+				retries = append(retries, retry{attempt, lastErr.Error()})
+			},
+		})
+		if err := ExecuteActivity(ctx, activityFn).Get(ctx, nil); err != nil {
+			return err
+		}
+		retries = append(retries, retry{0, "result"})
+		ctx = WithLocalActivityOptions(ctx, LocalActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         retryPolicy,
+			OnRetry: func(attempt int32, lastErr error) {
+				localRetries = append(localRetries, retry{attempt, lastErr.Error()})
+			},
+		})
+		return ExecuteLocalActivity(ctx, activityFn).Get(ctx, nil)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, 6, calls)
+	// Like in production, called once before the result with the final attempt and the failure of the one before
+	require.Equal(t, []retry{{3, "attempt 2 failed"}, {0, "result"}}, retries)
+	require.Len(t, localRetries, 2)
+	require.Equal(t, int32(2), localRetries[0].attempt)
+	require.Contains(t, localRetries[0].err, "attempt 1 failed")
+	require.Equal(t, int32(3), localRetries[1].attempt)
+	require.Contains(t, localRetries[1].err, "attempt 2 failed")
+}
+
+func TestActivityDefaultOptions(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	var infos []ActivityInfo
+	activityFn := func(ctx context.Context) error {
+		info := GetActivityInfo(ctx)
+		infos = append(infos, info)
+		if info.Attempt < 2 {
+			return errors.New("retry me")
+		}
+		return nil
+	}
+	env.RegisterActivityWithOptions(activityFn, RegisterActivityOptions{
+		Name: "defaulted",
+		DefaultOptions: &ActivityDefaultOptions{
+			StartToCloseTimeout: time.Minute,
+			HeartbeatTimeout:    time.Second,
+			RetryPolicy:         &RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 2},
+		},
+	})
+
+	env.ExecuteWorkflow(func(ctx Context) error {
+		// The registered defaults apply without WithActivityOptions
+		if err := ExecuteActivity(ctx, "defaulted").Get(ctx, nil); err != nil {
+			return err
+		}
+		// Options set by the workflow take precedence
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Hour,
+			RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+		})
+		return ExecuteActivity(ctx, "defaulted").Get(ctx, nil)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	var activityErr *ActivityError
+	require.ErrorAs(t, env.GetWorkflowError(), &activityErr)
+	require.Len(t, infos, 3)
+	require.Equal(t, time.Minute, infos[0].StartToCloseTimeout)
+	require.Equal(t, time.Second, infos[0].HeartbeatTimeout)
+	require.Equal(t, int32(2), infos[1].Attempt)
+	require.Equal(t, time.Hour, infos[2].StartToCloseTimeout)
+	require.Equal(t, time.Second, infos[2].HeartbeatTimeout)
+
+	// Options set by WithActivityOptions without timeouts are completed by the defaults
+	infos = nil
+	env = suite.NewTestWorkflowEnvironment()
+	env.RegisterActivityWithOptions(activityFn, RegisterActivityOptions{
+		Name:           "defaulted",
+		DefaultOptions: &ActivityDefaultOptions{StartToCloseTimeout: time.Minute},
+	})
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{RetryPolicy: &RetryPolicy{MaximumAttempts: 2}})
+		return ExecuteActivity(ctx, "defaulted").Get(ctx, nil)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, infos, 2)
+	require.Equal(t, time.Minute, infos[0].StartToCloseTimeout)
+
+	require.Panics(t, func() {
+		env.RegisterActivityWithOptions(activityFn, RegisterActivityOptions{
+			Name:           "invalid",
+			DefaultOptions: &ActivityDefaultOptions{StartToCloseTimeout: -time.Second},
+		})
+	})
+}
+
+type greetingActivities struct {
+	greeting string
+}
+
+func (a *greetingActivities) Greet(_ context.Context, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	return a.greeting + " " + name, nil
+}
+
+func TestExecuteActivityMethod(t *testing.T) {
+	var suite WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(&greetingActivities{greeting: "Hello"})
+
+	var results []string
+	var activityErr *ActivityError
+	env.ExecuteWorkflow(func(ctx Context) error {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         &RetryPolicy{MaximumAttempts: 1},
+		})
+		result, err := ExecuteActivityMethod(ctx, (*greetingActivities).Greet, "Temporal").GetTyped(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+		// The method expression resolves to the same activity type as a method value with a nil receiver
+		var a *greetingActivities
+		if err := ExecuteActivity(ctx, a.Greet, "World").Get(ctx, &result); err != nil {
+			return err
+		}
+		results = append(results, result)
+		_, err = ExecuteActivityMethod(ctx, (*greetingActivities).Greet, "").GetTyped(ctx)
+		errors.As(err, &activityErr)
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{"Hello Temporal", "Hello World"}, results)
+	require.NotNil(t, activityErr)
+	require.Equal(t, "Greet", activityErr.ActivityType().Name)
+	require.ErrorContains(t, activityErr, "name is required")
+}
@@ -23,6 +23,24 @@ type Client struct {
 	mock.Mock
 }
 
+// AddSearchAttributes provides a mock function with given fields: ctx, options
+func (_m *Client) AddSearchAttributes(ctx context.Context, options client.AddSearchAttributesOptions) error {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSearchAttributes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.AddSearchAttributesOptions) error); ok {
+		r0 = rf(ctx, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CancelWorkflow provides a mock function with given fields: ctx, workflowID, runID
 func (_m *Client) CancelWorkflow(ctx context.Context, workflowID string, runID string) error {
 	ret := _m.Called(ctx, workflowID, runID)
@@ -160,6 +178,36 @@ func (_m *Client) CountWorkflow(ctx context.Context, request *workflowservice.Co
 	return r0, r1
 }
 
+// CountWorkflowWithOptions provides a mock function with given fields: ctx, options
+func (_m *Client) CountWorkflowWithOptions(ctx context.Context, options client.CountWorkflowOptions) (*client.CountWorkflowResult, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountWorkflowWithOptions")
+	}
+
+	var r0 *client.CountWorkflowResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.CountWorkflowOptions) (*client.CountWorkflowResult, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.CountWorkflowOptions) *client.CountWorkflowResult); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.CountWorkflowResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.CountWorkflowOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeploymentClient provides a mock function with given fields:
 //
 //lint:ignore SA1019 ignore deprecated versioning APIs
@@ -185,6 +233,36 @@ func (_m *Client) DeploymentClient() client.DeploymentClient {
 	return r0
 }
 
+// DescribeNamespace provides a mock function with given fields: ctx, namespace
+func (_m *Client) DescribeNamespace(ctx context.Context, namespace string) (*client.NamespaceDescription, error) {
+	ret := _m.Called(ctx, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeNamespace")
+	}
+
+	var r0 *client.NamespaceDescription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*client.NamespaceDescription, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *client.NamespaceDescription); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.NamespaceDescription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DescribeTaskQueue provides a mock function with given fields: ctx, taskqueue, taskqueueType
 func (_m *Client) DescribeTaskQueue(ctx context.Context, taskqueue string, taskqueueType enums.TaskQueueType) (*workflowservice.DescribeTaskQueueResponse, error) {
 	ret := _m.Called(ctx, taskqueue, taskqueueType)
@@ -308,6 +386,24 @@ func (_m *Client) ExecuteWorkflow(ctx context.Context, options client.StartWorkf
 	return r0, r1
 }
 
+// ForceNewWorkflowTask provides a mock function with given fields: ctx, workflowID, runID
+func (_m *Client) ForceNewWorkflowTask(ctx context.Context, workflowID string, runID string) error {
+	ret := _m.Called(ctx, workflowID, runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForceNewWorkflowTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, workflowID, runID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetSearchAttributes provides a mock function with given fields: ctx
 func (_m *Client) GetSearchAttributes(ctx context.Context) (*workflowservice.GetSearchAttributesResponse, error) {
 	ret := _m.Called(ctx)
@@ -596,6 +692,66 @@ func (_m *Client) ListOpenWorkflow(ctx context.Context, request *workflowservice
 	return r0, r1
 }
 
+// ListSearchAttributes provides a mock function with given fields: ctx, options
+func (_m *Client) ListSearchAttributes(ctx context.Context, options client.ListSearchAttributesOptions) (*client.SearchAttributesSchema, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSearchAttributes")
+	}
+
+	var r0 *client.SearchAttributesSchema
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListSearchAttributesOptions) (*client.SearchAttributesSchema, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListSearchAttributesOptions) *client.SearchAttributesSchema); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.SearchAttributesSchema)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.ListSearchAttributesOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStuckWorkflows provides a mock function with given fields: ctx, options
+func (_m *Client) ListStuckWorkflows(ctx context.Context, options client.ListStuckWorkflowsOptions) ([]client.StuckWorkflow, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStuckWorkflows")
+	}
+
+	var r0 []client.StuckWorkflow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListStuckWorkflowsOptions) ([]client.StuckWorkflow, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.ListStuckWorkflowsOptions) []client.StuckWorkflow); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.StuckWorkflow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.ListStuckWorkflowsOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListWorkflow provides a mock function with given fields: ctx, request
 func (_m *Client) ListWorkflow(ctx context.Context, request *workflowservice.ListWorkflowExecutionsRequest) (*workflowservice.ListWorkflowExecutionsResponse, error) {
 	ret := _m.Called(ctx, request)
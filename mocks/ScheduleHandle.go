@@ -17,6 +17,36 @@ type ScheduleHandle struct {
 	mock.Mock
 }
 
+// AwaitNextRun provides a mock function with given fields: ctx
+func (_m *ScheduleHandle) AwaitNextRun(ctx context.Context) (client.WorkflowRun, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AwaitNextRun")
+	}
+
+	var r0 client.WorkflowRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (client.WorkflowRun, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) client.WorkflowRun); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.WorkflowRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Backfill provides a mock function with given fields: ctx, options
 func (_m *ScheduleHandle) Backfill(ctx context.Context, options client.ScheduleBackfillOptions) error {
 	ret := _m.Called(ctx, options)
@@ -35,6 +65,36 @@ func (_m *ScheduleHandle) Backfill(ctx context.Context, options client.ScheduleB
 	return r0
 }
 
+// BackfillAndWait provides a mock function with given fields: ctx, options
+func (_m *ScheduleHandle) BackfillAndWait(ctx context.Context, options client.ScheduleBackfillOptions) (*client.ScheduleBackfillResult, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillAndWait")
+	}
+
+	var r0 *client.ScheduleBackfillResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.ScheduleBackfillOptions) (*client.ScheduleBackfillResult, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.ScheduleBackfillOptions) *client.ScheduleBackfillResult); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*client.ScheduleBackfillResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.ScheduleBackfillOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx
 func (_m *ScheduleHandle) Delete(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -101,6 +161,36 @@ func (_m *ScheduleHandle) GetID() string {
 	return r0
 }
 
+// ListRecentActions provides a mock function with given fields: ctx, n
+func (_m *ScheduleHandle) ListRecentActions(ctx context.Context, n int) ([]client.ScheduleActionResult, error) {
+	ret := _m.Called(ctx, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecentActions")
+	}
+
+	var r0 []client.ScheduleActionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]client.ScheduleActionResult, error)); ok {
+		return rf(ctx, n)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []client.ScheduleActionResult); ok {
+		r0 = rf(ctx, n)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]client.ScheduleActionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Pause provides a mock function with given fields: ctx, options
 func (_m *ScheduleHandle) Pause(ctx context.Context, options client.SchedulePauseOptions) error {
 	ret := _m.Called(ctx, options)
@@ -137,6 +227,36 @@ func (_m *ScheduleHandle) Trigger(ctx context.Context, options client.ScheduleTr
 	return r0
 }
 
+// TriggerAndWait provides a mock function with given fields: ctx, options
+func (_m *ScheduleHandle) TriggerAndWait(ctx context.Context, options client.ScheduleTriggerOptions) (client.WorkflowRun, error) {
+	ret := _m.Called(ctx, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TriggerAndWait")
+	}
+
+	var r0 client.WorkflowRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.ScheduleTriggerOptions) (client.WorkflowRun, error)); ok {
+		return rf(ctx, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, client.ScheduleTriggerOptions) client.WorkflowRun); ok {
+		r0 = rf(ctx, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(client.WorkflowRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, client.ScheduleTriggerOptions) error); ok {
+		r1 = rf(ctx, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Unpause provides a mock function with given fields: ctx, options
 func (_m *ScheduleHandle) Unpause(ctx context.Context, options client.ScheduleUnpauseOptions) error {
 	ret := _m.Called(ctx, options)
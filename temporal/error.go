@@ -135,6 +135,11 @@ type (
 	// UnknownExternalWorkflowExecutionError can be returned when external workflow doesn't exist
 	UnknownExternalWorkflowExecutionError = internal.UnknownExternalWorkflowExecutionError
 
+	// DecodeError is the cause of the ApplicationError of type "DecodeError" returned from Future.Get in a workflow
+	// when the result of an activity, local activity or child workflow can't be decoded into the provided value. See
+	// ActivityOptions.NonRetryableDecodeError in the workflow package to make that application error non-retryable.
+	DecodeError = internal.DecodeError
+
 	// QueryRejectedError is a possible error that can be returned by
 	// ClientOutboundInterceptor.QueryWorkflow to indicate that the query was rejected by the server.
 	QueryRejectedError = internal.QueryRejectedError
@@ -147,6 +152,9 @@ var (
 	// ErrScheduleAlreadyRunning can be returned when a schedule ID is reused
 	ErrScheduleAlreadyRunning = internal.ErrScheduleAlreadyRunning
 
+	// ErrSchedulePaused is returned when waiting for the next run of a paused schedule.
+	ErrSchedulePaused = internal.ErrSchedulePaused
+
 	// ErrSkipScheduleUpdate is used by a user if they want to skip updating a schedule.
 	ErrSkipScheduleUpdate = internal.ErrSkipScheduleUpdate
 )
@@ -227,6 +235,25 @@ func IsCanceledError(err error) bool {
 	return errors.As(err, &cancelError)
 }
 
+// GetActivityLastHeartbeatDetails decodes the details of the last heartbeat recorded by a failed activity into
+// valuePtr. This lets a workflow resume or compensate based on how far the activity got. The details are taken from
+// the TimeoutError in the chain of err, e.g. the cause of an ActivityError for an activity that timed out after
+// heartbeating. The server only reports heartbeat details for timeouts, so for other failures, or if the activity never
+// heartbeated, an error wrapping ErrNoData is returned.
+func GetActivityLastHeartbeatDetails(err error, valuePtr interface{}) error {
+	return internal.GetActivityLastHeartbeatDetails(err, valuePtr)
+}
+
+// GetCanceledErrorDetails decodes the details of the CanceledError in the chain of err into valuePtr. For an activity
+// canceled while the workflow waited for the cancellation (see ActivityOptions.WaitForCancellation in the workflow
+// package), these are the details the activity passed to NewCanceledError when accepting the cancellation, which lets
+// compensation logic know how far the activity got. Activities must opt in by returning NewCanceledError with
+// details; for activities returning the context error, or if there is no CanceledError, an error wrapping ErrNoData
+// is returned.
+func GetCanceledErrorDetails(err error, valuePtr interface{}) error {
+	return internal.GetCanceledErrorDetails(err, valuePtr)
+}
+
 // IsTimeoutError return if the err is a TimeoutError
 func IsTimeoutError(err error) bool {
 	var timeoutError *TimeoutError
@@ -80,3 +80,21 @@ func NewSearchAttributeKeyKeywordList(name string) SearchAttributeKeyKeywordList
 func NewSearchAttributes(attributes ...SearchAttributeUpdate) SearchAttributes {
 	return internal.NewSearchAttributes(attributes...)
 }
+
+// SearchAttributesFromUntyped converts search attributes in the untyped form used by UpsertSearchAttributes and
+// StartWorkflowOptions.SearchAttributes to [SearchAttributes], to ease migrating to typed search attributes. Key
+// types are inferred from the values: strings become keywords (text cannot be told apart from keyword), integers
+// int64, floats float64, time.Time time, bool bool, and string slices keyword lists. Payloads, like the values of
+// WorkflowInfo.SearchAttributes, use the type in their metadata.
+//
+// A nil value unsets the attribute. Unsupported values, integers that overflow int64 and payloads without a known
+// type return an error naming the attribute.
+func SearchAttributesFromUntyped(attributes map[string]interface{}) (SearchAttributes, error) {
+	return internal.SearchAttributesFromUntyped(attributes)
+}
+
+// SearchAttributesToUntyped converts [SearchAttributes] to the untyped form used by UpsertSearchAttributes and
+// StartWorkflowOptions.SearchAttributes. Unset attributes are included with a nil value.
+func SearchAttributesToUntyped(attributes SearchAttributes) map[string]interface{} {
+	return internal.SearchAttributesToUntyped(attributes)
+}
@@ -3,7 +3,10 @@
 package testsuite
 
 import (
+	historypb "go.temporal.io/api/history/v1"
+
 	"go.temporal.io/sdk/internal"
+	"go.temporal.io/sdk/log"
 )
 
 type (
@@ -21,7 +24,44 @@ type (
 
 	// TestUpdateCallback is a basic implementation of the UpdateCallbacks interface for testing purposes.
 	TestUpdateCallback = internal.TestUpdateCallback
+
+	// TestEmittedSignal is a signal sent to another workflow by a workflow running in a TestWorkflowEnvironment.
+	TestEmittedSignal = internal.TestEmittedSignal
+
+	// TestContinueAsNewInfo describes the next run of a workflow that continued as new in a TestWorkflowEnvironment.
+	TestContinueAsNewInfo = internal.TestContinueAsNewInfo
 )
 
 // ErrMockStartChildWorkflowFailed is special error used to indicate the mocked child workflow should fail to start.
 var ErrMockStartChildWorkflowFailed = internal.ErrMockStartChildWorkflowFailed
+
+type (
+	// ReplayVariant is a version of the workflow code to replay a history with in BisectReplay.
+	ReplayVariant = internal.ReplayVariant
+
+	// ReplayBisectResult is the result of BisectReplay.
+	ReplayBisectResult = internal.ReplayBisectResult
+)
+
+// BisectReplay finds the first of the variants that fails to replay the history and reports the history event and
+// command where its replay diverges, e.g. to find which code change broke the determinism of a workflow whose history
+// fails to replay in production. Variants must be ordered from the oldest to the newest change, and a variant is
+// assumed to fail if any older one fails, so only about log2(len(variants)) of them are replayed.
+//
+// The logger is an optional parameter. Defaults to the noop logger.
+//
+// Example:
+//
+//	variants := make([]testsuite.ReplayVariant, len(labels))
+//	for i, label := range labels {
+//		replayer := worker.NewWorkflowReplayer()
+//		replayer.RegisterWorkflowWithOptions(workflowsByLabel[label], workflow.RegisterOptions{Name: "MyWorkflow"})
+//		variants[i] = testsuite.ReplayVariant{Label: label, Replayer: replayer}
+//	}
+//	result, err := testsuite.BisectReplay(nil, history, variants)
+//	if err == nil && result.Index >= 0 {
+//		t.Logf("%s broke replay at event %d: %v", result.Label, result.Event.GetEventId(), result.Err)
+//	}
+func BisectReplay(logger log.Logger, history *historypb.History, variants []ReplayVariant) (ReplayBisectResult, error) {
+	return internal.BisectReplay(logger, history, variants)
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/internal"
+)
+
+// RegisterTypedActivity registers an activity function with the given name. Unlike
+// [ActivityRegistry.RegisterActivity], the activity signature is checked at compile time: it must take a
+// [context.Context] and a single input and return a result and an error. This can be used alongside the
+// reflection-based registration methods.
+//
+// This function panics if name is empty or an activity with the same name is already registered.
+func RegisterTypedActivity[In, Out any](r ActivityRegistry, name string, fn func(context.Context, In) (Out, error)) {
+	internal.RegisterTypedActivity(r, name, fn)
+}
+
+// RegisterTypedActivityNoResult is a variant of [RegisterTypedActivity] for activities that only return an error.
+func RegisterTypedActivityNoResult[In any](r ActivityRegistry, name string, fn func(context.Context, In) error) {
+	internal.RegisterTypedActivityNoResult(r, name, fn)
+}
+
+// RegisterTypedActivityNoInput is a variant of [RegisterTypedActivity] for activities that take no input.
+func RegisterTypedActivityNoInput[Out any](r ActivityRegistry, name string, fn func(context.Context) (Out, error)) {
+	internal.RegisterTypedActivityNoInput(r, name, fn)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/nexus-rpc/sdk-go/nexus"
+	commandpb "go.temporal.io/api/command/v1"
 	historypb "go.temporal.io/api/history/v1"
 	"go.temporal.io/api/workflowservice/v1"
 
@@ -45,6 +46,18 @@ type (
 		// via the interrupt channel.
 		Run(interruptCh <-chan interface{}) error
 
+		// RunWithContext runs the worker in a blocking fashion until ctx is done, then stops the worker gracefully,
+		// waiting up to WorkerStopTimeout for in-flight tasks. Use a context with a deadline to bound the lifetime of
+		// ephemeral workers, and signal.NotifyContext to also stop on SIGINT or SIGTERM like Run(InterruptCh()).
+		// Returns nil when stopped because ctx is done, and an error if the worker fails to start or there is a fatal
+		// error during execution.
+		RunWithContext(ctx context.Context) error
+
+		// EffectiveOptions returns a snapshot of the options the worker runs with once defaults are applied, e.g. to
+		// log the resolved slot counts and poller behaviors at startup. It is safe to call at any time, including
+		// after Start.
+		EffectiveOptions() EffectiveOptions
+
 		// Stop the worker.
 		//
 		// This may panic if called a second time.
@@ -180,6 +193,13 @@ type (
 		// History can be loaded from a reader with client.HistoryFromJSON.
 		ReplayWorkflowHistoryWithOptions(logger log.Logger, history *historypb.History, options ReplayWorkflowHistoryOptions) error
 
+		// ReplayAndCaptureCommands replays the given history of an open workflow and returns the commands the current
+		// workflow code produces for its last workflow task, for example to compare them against a golden file. The
+		// history must end with a started workflow task. The commands can be compared with proto.Equal and
+		// serialized with protojson.
+		// The logger is an optional parameter. Defaults to the noop logger.
+		ReplayAndCaptureCommands(logger log.Logger, history *historypb.History, options ReplayWorkflowHistoryOptions) ([]*commandpb.Command, error)
+
 		// ReplayWorkflowHistoryFromJSONFile executes a single workflow task for the json history file downloaded from the cli.
 		// To download the history file: temporal workflow show --workflow-id <workflow_id> --output json > <output_file>
 		// See https://github.com/temporalio/temporal/blob/master/tools/cli/README.md for full documentation
@@ -211,6 +231,13 @@ type (
 	// Options is used to configure a worker instance.
 	Options = internal.WorkerOptions
 
+	// EffectiveOptions is a snapshot of the options a worker runs with once defaults are applied, for diagnostics.
+	// Options holding code, like interceptors and plugins, are represented by their count.
+	EffectiveOptions = internal.WorkerEffectiveOptions
+
+	// EffectivePollerBehavior describes the PollerBehavior of a task type in EffectiveOptions.
+	EffectivePollerBehavior = internal.WorkerEffectivePollerBehavior
+
 	// PollerBehavior is used to configure the behavior of the poller.
 	PollerBehavior = internal.PollerBehavior
 
@@ -232,6 +259,19 @@ type (
 
 	// ReplayWorkflowHistoryOptions are options for replaying a workflow.
 	ReplayWorkflowHistoryOptions = internal.ReplayWorkflowHistoryOptions
+
+	// StickyWorkflowCacheStats is a snapshot of the process-wide sticky workflow cache statistics.
+	// See [StickyCacheStats].
+	StickyWorkflowCacheStats = internal.StickyWorkflowCacheStats
+
+	// SlowReplayInfo describes a workflow task whose replay exceeded [Options.SlowReplayThreshold].
+	SlowReplayInfo = internal.SlowReplayInfo
+
+	// StickyCachePrewarmOptions selects the workflows whose state is loaded into the sticky cache when the worker
+	// starts, see [Options.StickyCachePrewarm].
+	//
+	// NOTE: Experimental
+	StickyCachePrewarmOptions = internal.StickyCachePrewarmOptions
 )
 
 var _ WorkflowRegistry = (WorkflowReplayer)(nil)
@@ -263,6 +303,16 @@ func New(
 	return internal.NewWorker(client, taskQueue, options)
 }
 
+// RegisterActivities registers the activities of several structs with the same options, as if each struct was
+// registered with RegisterActivityWithOptions. Use activity.RegisterOptions.Prefix to give all their activities a
+// common prefix, and ExcludeMethods to leave out methods that should be registered individually with their own options.
+// It panics before registering any struct if two of them define activities with the same name.
+//
+//	worker.RegisterActivities(w, activity.RegisterOptions{Prefix: "billing."}, &InvoiceActivities{}, &PaymentActivities{})
+func RegisterActivities(r ActivityRegistry, options activity.RegisterOptions, activityStructs ...interface{}) {
+	internal.RegisterActivities(r, options, activityStructs...)
+}
+
 // NewWorkflowReplayer creates a WorkflowReplayer instance.
 func NewWorkflowReplayer() WorkflowReplayer {
 	w, err := NewWorkflowReplayerWithOptions(WorkflowReplayerOptions{})
@@ -299,6 +349,46 @@ func PurgeStickyWorkflowCache() {
 	internal.PurgeStickyWorkflowCache()
 }
 
+// StickyCacheStats returns a snapshot of the sticky workflow cache hits, misses, removals, current size and configured
+// capacity. Since the cache is shared between all workers in the process, the statistics are process-wide. Hit, miss
+// and removal counts are cumulative for the life of the process. This is cheap and safe to call concurrently.
+func StickyCacheStats() StickyWorkflowCacheStats {
+	return internal.GetStickyCacheStats()
+}
+
+// ExportStickyCache returns a blob identifying the workflows whose state is in the sticky cache of this process, to
+// restore the cache with [ImportStickyCache] on the workers that replace the current ones, e.g. after an in-place
+// restart, and avoid the latency of a cold cache. Call it before stopping the workers, since stopping them does not
+// keep their state.
+//
+// Workflow state, which includes running coroutines, cannot be serialized. The blob only identifies the workflows,
+// and importing it loads their state the same way as [Options.StickyCachePrewarm], by fetching and replaying their
+// history, only ahead of their next workflow task.
+//
+// The blob contains the namespace, task queue, type, workflow ID and run ID of every cached workflow, which can be
+// sensitive business data. Treat it like workflow data: keep it within the trusted environment the workers run in,
+// e.g. hand it over in memory or through a private file from the process being replaced, and do not import blobs from
+// untrusted sources, which can make the worker fetch and replay any workflow of its task queue.
+//
+// NOTE: Experimental
+func ExportStickyCache() ([]byte, error) {
+	return internal.ExportStickyCache()
+}
+
+// ImportStickyCache loads the state of the workflows of a blob returned by [ExportStickyCache] into the sticky cache,
+// using the started workers of this process with the namespace and task queue of each workflow. It blocks until the
+// workflows are loaded or the cache is full, and returns the number of workflows loaded.
+//
+// Workflows are discarded if no started worker has their namespace and task queue, if their type is no longer
+// registered, if they are no longer running, or if their history does not replay with the current code. Each workflow
+// loaded costs the same as a cache miss, see [StickyCachePrewarmOptions]. See [ExportStickyCache] for the security
+// implications.
+//
+// NOTE: Experimental
+func ImportStickyCache(blob []byte) (int, error) {
+	return internal.ImportStickyCache(blob)
+}
+
 // SetBinaryChecksum sets the identifier of the binary(aka BinaryChecksum).
 // The identifier is mainly used in recording reset points when respondWorkflowTaskCompleted. For each workflow, the very first
 // workflow task completed by a binary will be associated as a auto-reset point for the binary. So that when a customer wants to
@@ -314,6 +404,13 @@ func InterruptCh() <-chan interface{} {
 	return internal.InterruptCh()
 }
 
+// ActiveTaskQueues returns the sorted task queues of the workers in this process that have started and have not been
+// stopped yet, e.g. for a health endpoint to check that the expected workers are running. Workers that failed to start
+// are not included.
+func ActiveTaskQueues() []string {
+	return internal.ActiveTaskQueues()
+}
+
 // NewPollerBehaviorSimpleMaximum creates a PollerBehavior that allows the worker to start up to a maximum number of pollers.
 func NewPollerBehaviorSimpleMaximum(
 	options PollerBehaviorSimpleMaximumOptions,
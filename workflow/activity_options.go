@@ -36,6 +36,16 @@ func WithTaskQueue(ctx Context, name string) Context {
 	return internal.WithTaskQueue(ctx, name)
 }
 
+// WithSameTaskQueue makes a copy of the current context and sets the task
+// queue in both its activity options and its child workflow options to the
+// task queue of the current workflow, as found in GetInfo(ctx).TaskQueueName.
+// Unlike hardcoding the name, this keeps activities and child workflows on the
+// task queue the workflow is actually running on. The name comes from the
+// workflow info, so it is the same on replay.
+func WithSameTaskQueue(ctx Context) Context {
+	return internal.WithSameTaskQueue(ctx)
+}
+
 // WithScheduleToCloseTimeout makes a copy of the current context and update
 // the ScheduleToCloseTimeout field in its activity options. An empty activity
 // options will be created if it does not exist in the original context.
@@ -46,6 +56,15 @@ func WithScheduleToCloseTimeout(ctx Context, d time.Duration) Context {
 	return internal.WithScheduleToCloseTimeout(ctx, d)
 }
 
+// WithActivityBoundedByRun makes a copy of the current context and caps the
+// ScheduleToCloseTimeout field in its activity options to the remaining run
+// time of the workflow, so activities do not outlive the run. A shorter
+// ScheduleToCloseTimeout already set is kept, and the timeout is at least one
+// second. The context is returned unchanged if the workflow has no run timeout.
+func WithActivityBoundedByRun(ctx Context) Context {
+	return internal.WithActivityBoundedByRun(ctx)
+}
+
 // WithScheduleToStartTimeout makes a copy of the current context and update
 // the ScheduleToStartTimeout field in its activity options. An empty activity
 // options will be created if it does not exist in the original context.
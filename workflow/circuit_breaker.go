@@ -0,0 +1,48 @@
+package workflow
+
+import "go.temporal.io/sdk/internal"
+
+type (
+	// CircuitBreakerState is the state of a CircuitBreaker.
+	CircuitBreakerState = internal.CircuitBreakerState
+
+	// CircuitBreakerSettings configures a CircuitBreaker.
+	CircuitBreakerSettings = internal.CircuitBreakerSettings
+
+	// CircuitBreaker executes activities and stops scheduling them while the dependency they call is failing, so
+	// workflows fail fast during outages instead of retrying. After CircuitBreakerSettings.FailureThreshold
+	// consecutive failures it opens and returns ErrCircuitBreakerOpen without scheduling activities. Once the
+	// cooldown has elapsed it is half-open and lets a single probe activity through: it closes if the probe
+	// succeeds, and opens for another cooldown if it fails.
+	//
+	// The failure state is workflow state, kept deterministically with counters and a workflow timer. It is per
+	// workflow run, so it starts closed again after continue-as-new.
+	//
+	// Example:
+	//
+	//	breaker := workflow.NewCircuitBreaker(ctx, workflow.CircuitBreakerSettings{FailureThreshold: 3})
+	//	for _, order := range orders {
+	//		err := breaker.Execute(ctx, ChargeActivity, order).Get(ctx, nil)
+	//		if errors.Is(err, workflow.ErrCircuitBreakerOpen) {
+	//			// The payment provider is down, park the order instead of retrying
+	//		}
+	//	}
+	CircuitBreaker = internal.CircuitBreaker
+)
+
+const (
+	// CircuitBreakerClosed is the state in which activities are executed.
+	CircuitBreakerClosed = internal.CircuitBreakerClosed
+	// CircuitBreakerOpen is the state in which activities are short-circuited until the cooldown elapses.
+	CircuitBreakerOpen = internal.CircuitBreakerOpen
+	// CircuitBreakerHalfOpen is the state after the cooldown in which a single probe activity is executed.
+	CircuitBreakerHalfOpen = internal.CircuitBreakerHalfOpen
+)
+
+// ErrCircuitBreakerOpen is the error of activities short-circuited by an open CircuitBreaker.
+var ErrCircuitBreakerOpen = internal.ErrCircuitBreakerOpen
+
+// NewCircuitBreaker creates a closed CircuitBreaker. See CircuitBreaker for the semantics.
+func NewCircuitBreaker(ctx Context, settings CircuitBreakerSettings) *CircuitBreaker {
+	return internal.NewCircuitBreaker(ctx, settings)
+}
@@ -40,6 +40,10 @@ type (
 	// Use [workflow.NewMutex] method to create a Mutex instance.
 	Mutex = internal.Mutex
 
+	// Counter is an int64 counter shared between coroutines of a workflow.
+	// Use [workflow.NewCounter] method to create a Counter instance.
+	Counter = internal.Counter
+
 	// Semaphore is a counting semaphore.
 	// Use [workflow.NewSemaphore] method to create a Semaphore instance.
 	Semaphore = internal.Semaphore
@@ -49,6 +53,9 @@ type (
 	// NOTE: Experimental
 	TimerOptions = internal.TimerOptions
 
+	// PendingTimer describes a timer returned by [GetPendingTimers].
+	PendingTimer = internal.PendingTimer
+
 	// AwaitOptions are options for [AwaitWithOptions]
 	//
 	// NOTE: Experimental
@@ -92,6 +99,25 @@ func AwaitWithTimeout(ctx Context, timeout time.Duration, condition func() bool)
 	return internal.AwaitWithTimeout(ctx, timeout, condition)
 }
 
+// AwaitWithReason is like AwaitWithTimeout, but returns which of the three
+// outcomes of the wait happened instead of requiring the caller to infer it
+// from ok and err: the condition returned true, the timeout passed, or the ctx
+// was canceled, in which case a CanceledError is returned too. The timeout
+// timer is canceled if the condition is met first.
+//
+//	result, err := workflow.AwaitWithReason(ctx, time.Hour, func() bool {
+//	  return count == 5
+//	})
+//	switch result {
+//	case workflow.AwaitResultConditionMet:
+//	case workflow.AwaitResultTimedOut:
+//	case workflow.AwaitResultCanceled:
+//	  return err
+//	}
+func AwaitWithReason(ctx Context, timeout time.Duration, condition func() bool) (AwaitResult, error) {
+	return internal.AwaitWithReason(ctx, timeout, condition)
+}
+
 // AwaitWithOptions blocks the calling thread until condition() returns true
 // or blocking time exceeds the passed timeout value.
 // Returns ok=false if timed out, and err CanceledError if the ctx is canceled.
@@ -155,6 +181,20 @@ func NewMutex(ctx Context) Mutex {
 	return internal.NewMutex(ctx)
 }
 
+// NewCounter creates a new Counter instance starting at zero. A counter
+// documents that a value is shared between coroutines, e.g. update and signal
+// handlers that run concurrently. Since only one coroutine in a workflow is
+// ever executing at a time, no real atomics are needed and the counter is
+// deterministic as long as the coroutines are.
+//
+// The counter panics if it is used while the workflow is not running, e.g.
+// from a native goroutine that outlived the workflow task that started it.
+// Native goroutines must not be used in workflow code, and a goroutine that
+// uses the counter while the workflow is running can't be detected.
+func NewCounter(ctx Context) Counter {
+	return internal.NewCounter(ctx)
+}
+
 // NewSemaphore creates a new Semaphore instance.
 func NewSemaphore(ctx Context, n int64) Semaphore {
 	return internal.NewSemaphore(ctx, n)
@@ -172,6 +212,33 @@ func GoNamed(ctx Context, name string, f func(ctx Context)) {
 	internal.GoNamed(ctx, name, f)
 }
 
+// TypedFuture is a [Future] whose result has a static type. See [GoTyped].
+type TypedFuture[T any] interface {
+	Future
+	// GetTyped blocks until the future is ready and returns its result or error.
+	GetTyped(ctx Context) (T, error)
+}
+
+// GoTyped creates a new coroutine running fn and returns a future that is resolved with fn's result and error. The
+// coroutine receives ctx, so cancellation of ctx is visible to fn. If ctx is canceled before fn returns, the future
+// is resolved with a CanceledError without waiting for fn.
+//
+//	futures := make([]workflow.TypedFuture[string], len(inputs))
+//	for i, input := range inputs {
+//		futures[i] = workflow.GoTyped(ctx, func(ctx workflow.Context) (string, error) {
+//			var result string
+//			err := workflow.ExecuteActivity(ctx, MyActivity, input).Get(ctx, &result)
+//			return result, err
+//		})
+//	}
+//	for _, f := range futures {
+//		result, err := f.GetTyped(ctx)
+//		...
+//	}
+func GoTyped[T any](ctx Context, fn func(ctx Context) (T, error)) TypedFuture[T] {
+	return internal.GoTyped(ctx, fn)
+}
+
 // NewFuture creates a new future as well as an associated Settable that is used to set its value.
 func NewFuture(ctx Context) (Future, Settable) {
 	return internal.NewFuture(ctx)
@@ -203,6 +270,17 @@ func NewTimerWithOptions(ctx Context, d time.Duration, options TimerOptions) Fut
 	return internal.NewTimerWithOptions(ctx, d, options)
 }
 
+// GetPendingTimers returns the timers created by NewTimer, NewTimerWithOptions
+// or Sleep that have neither fired nor been canceled, in the order they were
+// created. Fire times are in workflow time, see [Now]. The result is built as
+// timers are created and resolved by workflow code, so it is the same on
+// replay and can be used in workflow logic, e.g. to avoid starting duplicate
+// timers. Timeouts enforced by the Server, e.g. activity or workflow
+// timeouts, are not timers and aren't included.
+func GetPendingTimers(ctx Context) []PendingTimer {
+	return internal.GetPendingTimers(ctx)
+}
+
 // Sleep pauses the current workflow for at least the duration d. A negative or zero duration causes Sleep to return
 // immediately. Workflow code must use this Sleep() to sleep, instead of Go's timer.Sleep().
 // You can cancel the pending sleep by canceling the Context (using the context from workflow.WithCancel(ctx)).
@@ -214,3 +292,11 @@ func NewTimerWithOptions(ctx Context, d time.Duration, options TimerOptions) Fut
 func Sleep(ctx Context, d time.Duration) (err error) {
 	return internal.Sleep(ctx, d)
 }
+
+// AwaitMinimumRunDuration pauses the current workflow until at least the duration d has passed since the start of the
+// current run, as given by GetInfo(ctx).WorkflowStartTime, e.g. for workflows that must run for a minimum time however
+// fast their work finishes. It returns nil immediately if d has already passed. Time is measured with [Now], so it is
+// deterministic. Like [Sleep], it returns *CanceledError if the ctx is canceled before d has passed.
+func AwaitMinimumRunDuration(ctx Context, d time.Duration) error {
+	return internal.AwaitMinimumRunDuration(ctx, d)
+}
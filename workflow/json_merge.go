@@ -0,0 +1,46 @@
+package workflow
+
+import "go.temporal.io/sdk/internal"
+
+// JSONPatchOperation is an operation of a JSON patch (RFC 6902). See [ApplyJSONPatch].
+type JSONPatchOperation = internal.JSONPatchOperation
+
+// ApplyJSONMerge applies a JSON merge patch (RFC 7396) to current and returns the result decoded into a new T, so
+// workflows can apply incremental updates to large JSON state. Objects of patch are merged into the objects of current
+// recursively, members set to null in patch are removed, and any other value of patch, including arrays, replaces the
+// value of current.
+//
+// current and patch are encoded with encoding/json, except json.RawMessage and []byte values which are used as JSON
+// as is. A T of json.RawMessage or []byte receives the merged JSON, with object keys sorted so the output is the same
+// on every replay. Neither current nor patch are modified.
+//
+// Merging is a deterministic in-memory operation that records nothing in history.
+//
+// Example:
+//
+//	updated, err := workflow.ApplyJSONMerge(order, map[string]interface{}{
+//		"status":  "shipped",
+//		"coupon":  nil, // removes the coupon
+//		"address": map[string]interface{}{"zip": "94107"},
+//	})
+func ApplyJSONMerge[T any](current T, patch interface{}) (T, error) {
+	return internal.ApplyJSONMerge(current, patch)
+}
+
+// ApplyJSONPatch applies the operations of a JSON patch (RFC 6902) in order to current and returns the result decoded
+// into a new T. The add, remove, replace, move, copy and test operations are supported. If an operation fails, e.g.
+// because its path does not exist or a test does not match, none of the operations are applied and the error names
+// the operation that failed.
+//
+// current is encoded like by [ApplyJSONMerge], and like it this is a deterministic in-memory operation.
+//
+// Example:
+//
+//	updated, err := workflow.ApplyJSONPatch(order, []workflow.JSONPatchOperation{
+//		{Op: "test", Path: "/status", Value: "paid"},
+//		{Op: "replace", Path: "/status", Value: "shipped"},
+//		{Op: "add", Path: "/items/-", Value: item},
+//	})
+func ApplyJSONPatch[T any](current T, patch []JSONPatchOperation) (T, error) {
+	return internal.ApplyJSONPatch(current, patch)
+}
@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"cmp"
+
+	"go.temporal.io/sdk/internal"
+)
+
+// OrderedSet is a set that keeps its elements sorted, for workflow state that
+// needs set semantics. Unlike ranging over a map, ranging over Values is
+// deterministic, so the set is safe to iterate in workflow code. It is not
+// safe for concurrent use outside of a workflow.
+//
+// The zero value is an empty set ordered like [NewOrderedSet], so it is only
+// usable with element types whose underlying type is ordered. The set
+// marshals to JSON as the array of its sorted elements, so it can be part of
+// workflow state that is passed to continue-as-new or returned from a query.
+//
+// Example:
+//
+//	pending := workflow.NewOrderedSet[string]()
+//	pending.Add("b")
+//	pending.Add("a")
+//	for _, id := range pending.Values() { // "a", "b"
+//		...
+//	}
+type OrderedSet[T any] struct {
+	set internal.OrderedSet[T]
+}
+
+// NewOrderedSet creates an empty [OrderedSet] of ordered values.
+func NewOrderedSet[T cmp.Ordered]() *OrderedSet[T] {
+	return &OrderedSet[T]{set: *internal.NewOrderedSet[T]()}
+}
+
+// NewOrderedSetFunc creates an empty [OrderedSet] whose elements are ordered by
+// cmp, for element types that are not ordered. cmp(a, b) should return a
+// negative number when a < b, a positive number when a > b and zero when a and
+// b are the same element.
+func NewOrderedSetFunc[T any](cmp func(a, b T) int) *OrderedSet[T] {
+	return &OrderedSet[T]{set: *internal.NewOrderedSetFunc(cmp)}
+}
+
+// Add adds v to the set and reports whether it was not already present.
+func (s *OrderedSet[T]) Add(v T) bool {
+	return s.set.Add(v)
+}
+
+// Remove removes v from the set and reports whether it was present.
+func (s *OrderedSet[T]) Remove(v T) bool {
+	return s.set.Remove(v)
+}
+
+// Contains reports whether v is in the set.
+func (s *OrderedSet[T]) Contains(v T) bool {
+	return s.set.Contains(v)
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[T]) Len() int {
+	return s.set.Len()
+}
+
+// Values returns the elements of the set in sorted order. The returned slice
+// is a copy, so it can be modified without affecting the set.
+func (s *OrderedSet[T]) Values() []T {
+	return s.set.Values()
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements in sorted order.
+func (s *OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return s.set.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the elements of the set with the ones of a JSON
+// array. To decode a set whose element type is not ordered, create it with
+// [NewOrderedSetFunc] before decoding into it.
+func (s *OrderedSet[T]) UnmarshalJSON(data []byte) error {
+	return s.set.UnmarshalJSON(data)
+}
@@ -0,0 +1,52 @@
+package workflow
+
+import "go.temporal.io/sdk/internal"
+
+// PriorityQueue is a queue that pops its items in priority order, for
+// workflows that schedule work by picking the next item by priority. Items of
+// equal priority are popped in the order they were pushed, so the order items
+// are popped in is the same on replay. The queue is regular workflow state: it
+// is rebuilt by replaying the workflow code that pushes and pops its items. It
+// is not safe for concurrent use outside of a workflow.
+//
+// Example:
+//
+//	jobs := workflow.NewPriorityQueue(func(a, b Job) bool { return a.Priority > b.Priority })
+//	jobs.Push(Job{Name: "backup", Priority: 1})
+//	jobs.Push(Job{Name: "deploy", Priority: 5})
+//	next, _ := jobs.Pop() // "deploy"
+type PriorityQueue[T any] struct {
+	queue *internal.PriorityQueue[T]
+}
+
+// NewPriorityQueue creates an empty [PriorityQueue] that pops the item for
+// which less reports it is less than all others first. less must be
+// deterministic, e.g. it must not depend on map iteration order or wall clock
+// time, and must be a strict weak ordering, i.e. items for which neither
+// less(a, b) nor less(b, a) holds are of equal priority.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{queue: internal.NewPriorityQueue(less)}
+}
+
+// Push adds v to the queue.
+func (q *PriorityQueue[T]) Push(v T) {
+	q.queue.Push(v)
+}
+
+// Pop removes and returns the item with the highest priority, or the earliest
+// pushed among items of equal priority. It returns false if the queue is
+// empty.
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	return q.queue.Pop()
+}
+
+// Peek returns the item Pop would return without removing it. It returns false
+// if the queue is empty.
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	return q.queue.Peek()
+}
+
+// Len returns the number of items in the queue.
+func (q *PriorityQueue[T]) Len() int {
+	return q.queue.Len()
+}
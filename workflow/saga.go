@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"go.temporal.io/sdk/internal"
+)
+
+type (
+	// SagaOptions are options for [NewSaga].
+	SagaOptions = internal.SagaOptions
+
+	// Saga implements the saga pattern: after each forward step of a workflow succeeds, a compensation that undoes it
+	// is added with AddCompensation. If a later step fails, Compensate runs the compensations of the steps that
+	// completed, by default one after another in reverse order.
+	//
+	// Compensations are ordinary workflow code, typically executing activities, and must be deterministic like the
+	// rest of the workflow. If the workflow was canceled, pass a context from [NewDisconnectedContext] to Compensate so
+	// the compensations can still run.
+	//
+	// Compensate returns the errors of all failed compensations joined with errors.Join, so each can be inspected
+	// with errors.Is and errors.As. Unless [SagaOptions.ContinueWithError] is set, sequential compensation stops at
+	// the first failure and the remaining compensations are not run. A compensation that must not fail should handle
+	// retries itself, e.g. with an activity retry policy.
+	//
+	// Example:
+	//
+	//	saga := workflow.NewSaga(workflow.SagaOptions{})
+	//	if err := workflow.ExecuteActivity(ctx, ReserveHotel).Get(ctx, nil); err != nil {
+	//		return err
+	//	}
+	//	saga.AddCompensation(func(ctx workflow.Context) error {
+	//		return workflow.ExecuteActivity(ctx, CancelHotel).Get(ctx, nil)
+	//	})
+	//	if err := workflow.ExecuteActivity(ctx, ReserveFlight).Get(ctx, nil); err != nil {
+	//		return errors.Join(err, saga.Compensate(ctx))
+	//	}
+	Saga = internal.Saga
+)
+
+// NewSaga creates a [Saga] with no compensations.
+func NewSaga(options SagaOptions) *Saga {
+	return internal.NewSaga(options)
+}
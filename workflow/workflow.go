@@ -2,7 +2,9 @@ package workflow
 
 import (
 	"cmp"
+	"context"
 	"errors"
+	"time"
 
 	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/internal"
@@ -67,6 +69,42 @@ const (
 	ContinueAsNewSuggestedReasonTooManyUpdates = internal.ContinueAsNewSuggestedReasonTooManyUpdates
 )
 
+const (
+	// CompletionHookFailurePolicyIgnore logs the failure of the completion hook and closes the workflow with its own
+	// outcome. This is the default.
+	CompletionHookFailurePolicyIgnore = internal.WorkflowCompletionHookFailurePolicyIgnore
+	// CompletionHookFailurePolicyFailWorkflow fails the workflow with the error of the completion hook, even if it
+	// completed successfully or continued as new.
+	CompletionHookFailurePolicyFailWorkflow = internal.WorkflowCompletionHookFailurePolicyFailWorkflow
+)
+
+const (
+	// CompletionOutcomeCompleted means the workflow returned without an error.
+	CompletionOutcomeCompleted = internal.WorkflowCompletionOutcomeCompleted
+	// CompletionOutcomeFailed means the workflow returned an error.
+	CompletionOutcomeFailed = internal.WorkflowCompletionOutcomeFailed
+	// CompletionOutcomeCanceled means the workflow was canceled.
+	CompletionOutcomeCanceled = internal.WorkflowCompletionOutcomeCanceled
+	// CompletionOutcomeContinuedAsNew means the workflow returned a [ContinueAsNewError].
+	CompletionOutcomeContinuedAsNew = internal.WorkflowCompletionOutcomeContinuedAsNew
+)
+
+// SearchAttributeSource describes where the current value of a search attribute was set. See
+// [GetTypedSearchAttributesInfo].
+type SearchAttributeSource = internal.SearchAttributeSource
+
+const (
+	// SearchAttributeSourceStart indicates the attribute was set when the workflow started and has not been upserted
+	// by this run.
+	SearchAttributeSourceStart = internal.SearchAttributeSourceStart
+	// SearchAttributeSourceUpsert indicates the attribute was last set by an upsert from this workflow run.
+	SearchAttributeSourceUpsert = internal.SearchAttributeSourceUpsert
+)
+
+// SearchAttributeInfo is the current value of a search attribute along with where it was set. See
+// [GetTypedSearchAttributesInfo].
+type SearchAttributeInfo = internal.SearchAttributeInfo
+
 // HandlerUnfinishedPolicy defines the actions taken when a workflow exits while update handlers are
 // running. The workflow exit may be due to successful return, failure, cancellation, or
 // continue-as-new.
@@ -82,6 +120,18 @@ const (
 	HandlerUnfinishedPolicyAbandon = internal.HandlerUnfinishedPolicyAbandon
 )
 
+// AwaitResult is the outcome of [AwaitWithReason].
+type AwaitResult = internal.AwaitResult
+
+const (
+	// AwaitResultConditionMet means the condition returned true.
+	AwaitResultConditionMet = internal.AwaitResultConditionMet
+	// AwaitResultTimedOut means the timeout passed before the condition returned true.
+	AwaitResultTimedOut = internal.AwaitResultTimedOut
+	// AwaitResultCanceled means the context was canceled before the condition returned true.
+	AwaitResultCanceled = internal.AwaitResultCanceled
+)
+
 // NexusOperationCancellationType specifies what action should be taken for a Nexus operation when the
 // caller is cancelled.
 type NexusOperationCancellationType = internal.NexusOperationCancellationType
@@ -109,6 +159,10 @@ type (
 	// ChildWorkflowFuture represents the result of a child workflow execution
 	ChildWorkflowFuture = internal.ChildWorkflowFuture
 
+	// ActivityLogs collects the log lines activities attached to their heartbeats with an activity.LogBuffer and
+	// serves them through a query, keyed by activity ID. See NewActivityLogs.
+	ActivityLogs = internal.ActivityLogs
+
 	// Type identifies a workflow type.
 	Type = internal.WorkflowType
 
@@ -124,6 +178,30 @@ type (
 	// RegisterOptions consists of options for registering a workflow
 	RegisterOptions = internal.RegisterWorkflowOptions
 
+	// CompletionHook is an activity run with the outcome of a workflow right before the workflow closes, set with
+	// [RegisterOptions.OnCompletion]. It runs for every outcome, i.e. completion, failure, cancellation and
+	// continue-as-new, so publishing or cleanup logic doesn't have to be repeated at the end of every workflow.
+	//
+	// The activity is called with a [Completion] and the result of the workflow, which is nil unless the workflow
+	// completed successfully, e.g.:
+	//
+	//	func PublishResult(ctx context.Context, completion workflow.Completion, result *OrderResult) error
+	//
+	// The activity is scheduled by the workflow like any other activity, so it is recorded in history and replayed
+	// deterministically. Adding or removing a completion hook therefore changes the commands of the workflow and is
+	// not compatible with open workflows of the type. It runs with a disconnected context, so it also runs if the
+	// workflow was canceled.
+	CompletionHook = internal.WorkflowCompletionHook
+
+	// CompletionHookFailurePolicy decides what happens when the [CompletionHook] activity fails.
+	CompletionHookFailurePolicy = internal.WorkflowCompletionHookFailurePolicy
+
+	// CompletionOutcome is how a workflow is closing.
+	CompletionOutcome = internal.WorkflowCompletionOutcome
+
+	// Completion is the first argument of the [CompletionHook] activity.
+	Completion = internal.WorkflowCompletion
+
 	// LoadDynamicRuntimeOptionsDetails is used as input to the LoadDynamicRuntimeOptions callback for dynamic workflows
 	LoadDynamicRuntimeOptionsDetails = internal.LoadDynamicRuntimeOptionsDetails
 
@@ -190,6 +268,22 @@ type (
 
 	// NexusOperationExecution is the result of [internal.NexusOperationFuture.GetNexusOperationExecution].
 	NexusOperationExecution = internal.NexusOperationExecution
+
+	// Backoff computes exponentially growing delays for retry loops in workflows, to be used with [Sleep]. It only
+	// computes durations, so it records nothing in history, and it is deterministic as long as it is used
+	// deterministically. Use [Backoff.WithJitter] to spread out the retries of many workflows. A Backoff is not
+	// safe for concurrent use by multiple coroutines.
+	Backoff = internal.Backoff
+
+	// Scheduler runs callbacks at given workflow times, for workflows coordinating many delayed tasks. Callbacks are
+	// started in the order of their times, and callbacks scheduled for the same time in the order they were
+	// scheduled. The scheduler waits with a single timer for the earliest pending callback, so the history grows with
+	// the number of distinct callback times, plus a canceled timer whenever a callback earlier than the pending one is
+	// scheduled or the pending one is canceled.
+	Scheduler = internal.Scheduler
+
+	// ScheduledCallback is a callback scheduled with [Scheduler.Schedule], which can be canceled until it starts.
+	ScheduledCallback = internal.ScheduledCallback
 )
 
 // ExecuteActivity requests activity execution in the context of a workflow.
@@ -230,6 +324,8 @@ type (
 //	var a *Activities
 //	workflow.ExecuteActivity(ctx, a.Activity1)
 //
+// or, for an activity with a single input, with [ExecuteActivityMethod].
+//
 // If the activity failed to complete then the future get error would indicate the failure.
 // The error will be of type *ActivityError. It will have important activity information and actual error that caused
 // activity failure. Use errors.Unwrap to get this error or errors.As to check its type which can be one of
@@ -245,6 +341,42 @@ func ExecuteActivity(ctx Context, activity interface{}, args ...interface{}) Fut
 	return internal.ExecuteActivity(ctx, activity, args...)
 }
 
+// ExecuteActivityMethod requests execution of the activity registered for a method of an activity struct and returns
+// a future of its typed result. The method is referenced by its method expression instead of a nil receiver, so the
+// compiler checks the input and result types against the method:
+//
+//	type Activities struct {
+//	  ... // members
+//	}
+//
+//	func (a *Activities) Activity1(ctx context.Context, input Input) (string, error) {
+//	   ...
+//	}
+//
+//	result, err := workflow.ExecuteActivityMethod(ctx, (*Activities).Activity1, input).GetTyped(ctx)
+//
+// The activity type name is the method name, like for [ExecuteActivity] with a method value, so the struct must be
+// registered without a name or prefix, or its activities executed by their name with ExecuteActivity. Methods with
+// other signatures can be executed by passing the method expression to ExecuteActivity.
+func ExecuteActivityMethod[S any, A any, R any](
+	ctx Context,
+	method func(S, context.Context, A) (R, error),
+	input A,
+) TypedFuture[R] {
+	return internal.ExecuteActivityMethod(ctx, method, input)
+}
+
+// ExecuteActivityCached requests activity execution like [ExecuteActivity], but if an activity was already started
+// with the same cacheKey in this workflow run, the Future of that first execution is returned and the activity is not
+// scheduled again. This avoids hand-written memo maps for expensive deterministic activities called repeatedly with the
+// same input. The activity and args of later calls for the same cacheKey are ignored.
+//
+// The cache is kept in workflow state and rebuilt deterministically on replay. It is per run and is cleared on
+// continue-as-new. An empty cacheKey results in a Future that fails with an error.
+func ExecuteActivityCached(ctx Context, cacheKey string, activity interface{}, args ...interface{}) Future {
+	return internal.ExecuteActivityCached(ctx, cacheKey, activity, args...)
+}
+
 // ExecuteLocalActivity requests to run a local activity. A local activity is like a regular activity with some key
 // differences:
 //
@@ -324,6 +456,66 @@ func GetInfo(ctx Context) *Info {
 	return internal.GetWorkflowInfo(ctx)
 }
 
+// GetCurrentBuildID returns the Build ID of the worker that processed the current workflow task, or an empty string if
+// that worker did not use versioning. When replaying, it returns the Build ID recorded in history rather than the one
+// of the replaying worker, so it is safe to use in workflow logic, e.g. to log which version a workflow runs on during
+// a migration.
+func GetCurrentBuildID(ctx Context) string {
+	return internal.GetCurrentBuildID(ctx)
+}
+
+// NewActivityLogs creates an ActivityLogs and registers a query handler of the given type returning the collected
+// lines as a map of activity ID to lines. Pass the errors of activities that heartbeat with an activity.LogBuffer to
+// ActivityLogs.Record to collect their lines.
+//
+// A workflow only sees the heartbeat details of an activity when the activity times out, so this surfaces the
+// last lines of activities that hung or stopped heartbeating. The lines are best-effort and only the most recent
+// lines retained by the buffer are available.
+func NewActivityLogs(ctx Context, queryType string) (*ActivityLogs, error) {
+	return internal.NewActivityLogs(ctx, queryType)
+}
+
+// GetStaticConfig returns a copy of the configuration the workflow type was registered with through
+// RegisterWorkflowOptions.StaticConfig, or nil if there is none. Reading it is deterministic as long as every worker
+// registers the workflow type with the same configuration. It is not recorded in history, unlike values read with
+// MutableSideEffect, so it must not be used for configuration that changes while workflows are running.
+func GetStaticConfig(ctx Context) map[string]string {
+	return internal.GetStaticConfig(ctx)
+}
+
+// GetHeader decodes the value of the client.StartWorkflowOptions.Headers entry key the workflow was started with into
+// valuePtr, using the data converter of the workflow. It returns an error wrapping temporal.ErrNoData if there is no
+// such entry. Headers are also available in child workflows and after continue-as-new, as they are propagated with the
+// start of those.
+//
+// Headers are set when the workflow starts and can't change during a run, so reading them is deterministic.
+func GetHeader(ctx Context, key string, valuePtr interface{}) error {
+	return internal.GetHeader(ctx, key, valuePtr)
+}
+
+// NewIdempotencyKey returns a key for a logical operation with external effects, suitable for passing to an activity
+// that calls an external system supporting idempotency tokens. The key is derived from the workflow run ID, the scope
+// and the number of keys previously generated for that scope in this run, so the n-th call with a given scope always
+// returns the same key. Since workflow code is deterministic, the key is stable across replays and workflow task
+// retries, and no history event is recorded for it. Keys differ between runs, including retries and continue-as-new
+// of the workflow.
+//
+// Keys are only stable as long as the sequence of NewIdempotencyKey calls for a scope does not change, so changes to
+// the calling code must be versioned like any other workflow change (see [GetVersion]).
+func NewIdempotencyKey(ctx Context, scope string) string {
+	return internal.NewIdempotencyKey(ctx, scope)
+}
+
+// GetTypedSearchAttributesInfo returns the current search attributes of the workflow, like [GetTypedSearchAttributes],
+// along with whether each value was set at start or upserted by this run and how many times it was upserted. This can
+// be used to avoid redundant upserts or to audit attribute changes. The information is derived from the start event
+// and the upserts made by the workflow code, so it is deterministic.
+//
+// Search attributes changed outside the workflow during the run, e.g. by a client, may not be reflected.
+func GetTypedSearchAttributesInfo(ctx Context) map[temporal.SearchAttributeKey]SearchAttributeInfo {
+	return internal.GetTypedSearchAttributesInfo(ctx)
+}
+
 // GetTypedSearchAttributes returns a collection of the search attributes currently set for this workflow
 func GetTypedSearchAttributes(ctx Context) temporal.SearchAttributes {
 	return internal.GetTypedSearchAttributes(ctx)
@@ -335,6 +527,21 @@ func GetCurrentUpdateInfo(ctx Context) *UpdateInfo {
 	return internal.GetCurrentUpdateInfo(ctx)
 }
 
+// HasProcessedUpdate returns whether an update with the given ID has been
+// accepted in this workflow run, including the currently running update, so
+// handlers can dedupe updates, e.g. ones retried by a client with the same ID
+// after the Server no longer knows about the original. The set is built as
+// updates are accepted, which on replay follows the update events in history,
+// so it is deterministic. Rejected updates are not included.
+//
+// The IDs of all accepted updates are kept in memory for the rest of the run.
+// Workflows that receive many updates should continue-as-new periodically,
+// passing on the IDs they still need to dedupe against, as this set starts
+// empty in every run.
+func HasProcessedUpdate(ctx Context, updateID string) bool {
+	return internal.HasProcessedUpdate(ctx, updateID)
+}
+
 // GetLogger returns a logger to be used in workflow's context.
 // This logger does not record logs during replay.
 //
@@ -350,11 +557,42 @@ func GetMetricsHandler(ctx Context) metrics.Handler {
 	return internal.GetMetricsHandler(ctx)
 }
 
+// GetFirstRunID returns the run ID of the first run of the chain of runs the current run belongs to, as recorded by
+// the server when the chain started. It stays the same across continue-as-new, retries, cron runs and resets, so it can
+// be used to correlate the runs of a chain, e.g. in logs and metrics. It differs from GetInfo(ctx).WorkflowExecution.RunID
+// in every run but the first. This is the same as GetInfo(ctx).FirstRunID.
+func GetFirstRunID(ctx Context) string {
+	return internal.GetFirstRunID(ctx)
+}
+
 // GetUnhandledSignalNames returns signal names that have unconsumed signals.
 func GetUnhandledSignalNames(ctx Context) []string {
 	return internal.GetUnhandledSignalNames(ctx)
 }
 
+// DrainSignals processes the signals that are currently buffered on the named signal channels, e.g. before the
+// workflow completes or continues as new, so late signals are not lost. For each signal name, the handler is called
+// once per buffered signal and should receive it from the channel, typically with ReceiveAsync. If the handler does
+// not receive it, the signal is discarded. Signals that arrive while draining are not drained. The channel passed to
+// the handler counts the signals it receives and cannot be added to a Selector.
+//
+// Signal names are drained in lexical order and DrainSignals never blocks waiting for new signals, though handlers
+// may block. It returns the number of signals drained per signal name.
+//
+// Example:
+//
+//	workflow.DrainSignals(ctx, map[string]func(workflow.Context, workflow.ReceiveChannel){
+//		"add-item": func(ctx workflow.Context, ch workflow.ReceiveChannel) {
+//			var item string
+//			ch.ReceiveAsync(&item)
+//			items = append(items, item)
+//		},
+//	})
+//	return workflow.NewContinueAsNewError(ctx, MyWorkflow, items)
+func DrainSignals(ctx Context, handlers map[string]func(ctx Context, ch ReceiveChannel)) map[string]int {
+	return internal.DrainSignals(ctx, handlers)
+}
+
 // RequestCancelExternalWorkflow can be used to request cancellation of an external workflow.
 // Input workflowID is the workflow ID of target workflow.
 // Input runID indicates the instance of a workflow. Input runID is optional (default is ""). When runID is not specified,
@@ -388,6 +626,33 @@ func GetSignalChannel(ctx Context, signalName string) ReceiveChannel {
 	return internal.GetSignalChannel(ctx, signalName)
 }
 
+// TypedReceiveChannel is a signal channel whose values are decoded into T. See [GetTypedSignalChannel].
+type TypedReceiveChannel[T any] interface {
+	// Name returns the name of the signal.
+	Name() string
+	// Receive blocks until a signal is received and returns its value. more is false once the channel is closed.
+	Receive(ctx Context) (value T, more bool)
+	// ReceiveAsync returns the value of a buffered signal, or ok false if there is none.
+	ReceiveAsync() (value T, ok bool)
+	// Len returns the number of buffered signals.
+	Len() int
+	// Channel returns the underlying untyped channel, e.g. to add it to a Selector with AddReceive.
+	Channel() ReceiveChannel
+}
+
+// GetTypedSignalChannel returns the channel corresponding to the signal name with its values decoded into T. Signals
+// received before the channel is read are buffered like with [GetSignalChannel] and returned in order.
+//
+// A signal whose value cannot be decoded into T panics, which fails the workflow task according to the panic policy
+// of the worker, instead of being dropped like by the channel returned by [GetSignalChannel]. Since the decoding is
+// replayed from history, the workflow task fails the same way on every attempt.
+//
+//	approvals := workflow.GetTypedSignalChannel[Approval](ctx, "approval")
+//	approval, _ := approvals.Receive(ctx)
+func GetTypedSignalChannel[T any](ctx Context, signalName string) TypedReceiveChannel[T] {
+	return internal.GetTypedSignalChannel[T](ctx, signalName)
+}
+
 // GetSignalChannelWithOptions returns channel corresponding to the signal name.
 // Options will only apply to the first signal channel.
 //
@@ -396,6 +661,21 @@ func GetSignalChannelWithOptions(ctx Context, signalName string, options SignalC
 	return internal.GetSignalChannelWithOptions(ctx, signalName, options)
 }
 
+// NewRateLimitedSignalChannel returns the channel of the signal name that lets at most maxPerInterval signals be
+// received per interval of workflow time, e.g. to not process signals faster than an activity can keep up with them.
+// Receive and ReceiveWithTimeout block on a timer once the limit of the current interval is reached, and ReceiveAsync
+// reports no value until the next interval starts.
+//
+// This only paces the consumption of signals, it does not stop them from arriving. Signals not received yet stay
+// buffered in the signal channel, so they count towards the history size and are reported by
+// [GetUnhandledSignalNames]. Pacing is based on workflow time and timers, so it is replay safe.
+//
+// The returned channel cannot be used with a [Selector], Selector.AddReceive panics if it is passed one. Receive stops
+// pacing once ctx is canceled.
+func NewRateLimitedSignalChannel(ctx Context, signalName string, maxPerInterval int, interval time.Duration) ReceiveChannel {
+	return internal.NewRateLimitedSignalChannel(ctx, signalName, maxPerInterval, interval)
+}
+
 // SideEffect executes the provided function once, records its result into the workflow history. The recorded result on
 // history will be returned without executing the provided function during replay. This guarantees the deterministic
 // requirement for workflow as the exact same result will be returned in replay.
@@ -473,6 +753,29 @@ func MutableSideEffectWithOptions(ctx Context, id string, options MutableSideEff
 	return internal.MutableSideEffectWithOptions(ctx, id, options, f, equals)
 }
 
+// NewConfigValue returns the current value of dynamic config, the canonical
+// use case of MutableSideEffect. On every call outside of replay, fetch is
+// called and a new marker is only recorded in history if its result differs
+// from the last recorded value, compared with ==. On replay, the recorded
+// value is returned instead. fetch must not have side effects other than
+// reading the config. Calls with the same id share the recorded value.
+//
+// For example:
+//
+//	batchSize, err := workflow.NewConfigValue(ctx, "batch-size", func(ctx workflow.Context) int {
+//		return config.BatchSize()
+//	})
+func NewConfigValue[T comparable](ctx Context, id string, fetch func(ctx Context) T) (T, error) {
+	return internal.NewConfigValue(ctx, id, fetch)
+}
+
+// NewConfigValueFunc is like NewConfigValue, but compares values with equals,
+// for values that aren't comparable with ==, e.g. slices, maps or structs
+// containing them.
+func NewConfigValueFunc[T any](ctx Context, id string, fetch func(ctx Context) T, equals func(a, b T) bool) (T, error) {
+	return internal.NewConfigValueFunc(ctx, id, fetch, equals)
+}
+
 // DefaultVersion is a version returned by GetVersion for code that wasn't versioned before
 const DefaultVersion Version = internal.DefaultVersion
 
@@ -722,6 +1025,13 @@ func GetLastError(ctx Context) error {
 	return internal.GetLastError(ctx)
 }
 
+// GetPreviousAttemptError returns the failure of the previous attempt of this workflow when it is retried according to
+// its RetryPolicy, or nil on the first attempt. Workflows can use it to adapt their behavior on retry. Failures of
+// previous cron or schedule runs are not returned, use [GetLastError] for those.
+func GetPreviousAttemptError(ctx Context) error {
+	return internal.GetPreviousAttemptError(ctx)
+}
+
 // UpsertSearchAttributes is used to add or update workflow search attributes.
 // The search attributes can be used in query of List/Scan/Count workflow APIs.
 // The key and value type must be registered on temporal server side;
@@ -812,6 +1122,30 @@ func UpsertMemo(ctx Context, memo map[string]interface{}) error {
 	return internal.UpsertMemo(ctx, memo)
 }
 
+// MetadataUpdate is the memo and search attributes to upsert together with [UpsertWorkflowMetadata].
+type MetadataUpdate = internal.MetadataUpdate
+
+// UpsertWorkflowMetadata upserts the memo and the search attributes of update together, e.g. to keep a status in the
+// memo and a search attribute to list workflows by in sync. A memo or search attributes alone are upserted like by
+// [UpsertMemo] or [UpsertTypedSearchAttributes].
+//
+// Both are validated before anything is upserted, so if an error is returned neither is changed. A search attribute
+// whose key has a different value type than the key of the same name already set on the workflow, or than another key
+// of the same name in update, is an error.
+//
+// The server records a memo change and a search attribute change as separate events, so the commands for both are
+// sent in the same workflow task completion and recorded in history together. For example:
+//
+//	err := workflow.UpsertWorkflowMetadata(ctx, workflow.MetadataUpdate{
+//		Memo:             map[string]interface{}{"Status": "shipped"},
+//		SearchAttributes: []temporal.SearchAttributeUpdate{statusKey.ValueSet("shipped")},
+//	})
+//
+// This is only supported with Temporal Server 1.18+
+func UpsertWorkflowMetadata(ctx Context, update MetadataUpdate) error {
+	return internal.UpsertWorkflowMetadata(ctx, update)
+}
+
 // NewContinueAsNewError creates ContinueAsNewError instance
 // If the workflow main function returns this error then the current execution is ended and
 // the new execution with same workflow ID is started automatically with options
@@ -833,6 +1167,54 @@ func NewContinueAsNewErrorWithOptions(ctx Context, options ContinueAsNewErrorOpt
 	return internal.NewContinueAsNewErrorWithOptions(ctx, options, wfn, args...)
 }
 
+// ContinueAsNewIfChanged continues wfn as new with state as its first
+// argument, followed by args, only if the state carried by the workflow
+// differs from baseline, e.g. the input of the current run. If equals reports
+// that they are equal, nil is returned and the workflow should keep running,
+// which avoids pointless continue-as-new cycles and re-encoding identical
+// inputs in polling-style workflows. Otherwise, the ContinueAsNewError
+// returned by NewContinueAsNewError is returned and must be returned from the
+// workflow function.
+//
+// equals is part of the workflow logic, so it must be deterministic: it must
+// only depend on its arguments, and changing it is a change to the workflow
+// code that requires versioning.
+//
+// For example:
+//
+//	func PollingWorkflow(ctx workflow.Context, state State) error {
+//		baseline := state
+//		for {
+//			// ... update state ...
+//			if err := workflow.ContinueAsNewIfChanged(ctx, baseline, state, State.Equal, PollingWorkflow); err != nil {
+//				return err
+//			}
+//			// ... wait for the next poll ...
+//		}
+//	}
+func ContinueAsNewIfChanged[T any](ctx Context, baseline, state T, equals func(a, b T) bool, wfn interface{}, args ...interface{}) error {
+	return internal.ContinueAsNewIfChanged(ctx, baseline, state, equals, wfn, args...)
+}
+
+// ContinueAsNewTyped returns a ContinueAsNewError for the workflow function wfn taking a single argument, with arg as
+// the argument of the new run. It is NewContinueAsNewError with the type of arg checked against wfn at compile time,
+// which catches the argument mismatches that otherwise only fail when the new run starts. It returns an error if wfn
+// is not registered with the worker, unless the new run is on another task queue set with [WithWorkflowTaskQueue],
+// whose workers register their own workflows. For example:
+//
+//	func CounterWorkflow(ctx workflow.Context, count int) error {
+//		// ...
+//		return workflow.ContinueAsNewTyped(ctx, CounterWorkflow, count+1)
+//	}
+func ContinueAsNewTyped[T any](ctx Context, wfn func(Context, T) error, arg T) error {
+	return internal.ContinueAsNewTyped(ctx, wfn, arg)
+}
+
+// ContinueAsNewTypedNoInput is ContinueAsNewTyped for workflow functions without arguments.
+func ContinueAsNewTypedNoInput(ctx Context, wfn func(Context) error) error {
+	return internal.ContinueAsNewTypedNoInput(ctx, wfn)
+}
+
 // IsContinueAsNewError return if the err is a ContinueAsNewError
 func IsContinueAsNewError(err error) bool {
 	var continueAsNewErr *ContinueAsNewError
@@ -848,6 +1230,45 @@ func DataConverterWithoutDeadlockDetection(c converter.DataConverter) converter.
 	return internal.DataConverterWithoutDeadlockDetection(c)
 }
 
+// NewBackoff creates a [Backoff] whose first delay is initial, multiplied by coefficient after every delay until it
+// reaches max. It panics if initial is not positive, max is less than initial or coefficient is less than 1.
+//
+// Example:
+//
+//	backoff := workflow.NewBackoff(time.Second, time.Minute, 2)
+//	for {
+//		var ready bool
+//		if err := workflow.ExecuteActivity(ctx, CheckReady).Get(ctx, &ready); err != nil {
+//			return err
+//		} else if ready {
+//			break
+//		}
+//		if err := workflow.Sleep(ctx, backoff.Next()); err != nil {
+//			return err
+//		}
+//	}
+func NewBackoff(initial, max time.Duration, coefficient float64) *Backoff {
+	return internal.NewBackoff(initial, max, coefficient)
+}
+
+// NewScheduler creates a [Scheduler] running callbacks in coroutines of ctx. Callbacks stop being started once ctx is
+// canceled.
+//
+// Example:
+//
+//	scheduler := workflow.NewScheduler(ctx)
+//	reminder := scheduler.Schedule(deadline.Add(-time.Hour), func(ctx workflow.Context) {
+//		_ = workflow.ExecuteActivity(ctx, SendReminder).Get(ctx, nil)
+//	})
+//	scheduler.Schedule(deadline, func(ctx workflow.Context) {
+//		_ = workflow.ExecuteActivity(ctx, Expire).Get(ctx, nil)
+//	})
+//	// The reminder is not needed anymore once the task is done
+//	reminder.Cancel()
+func NewScheduler(ctx Context) *Scheduler {
+	return internal.NewScheduler(ctx)
+}
+
 // DeterministicKeys returns the keys of a map in deterministic (sorted) order. To be used in for
 // loops in workflows for deterministic iteration.
 func DeterministicKeys[K cmp.Ordered, V any](m map[K]V) []K {
@@ -59,6 +59,14 @@ func GetChildWorkflowOptions(ctx Context) ChildWorkflowOptions {
 	return internal.GetChildWorkflowOptions(ctx)
 }
 
+// InheritedChildWorkflowOptions returns ChildWorkflowOptions populated from the current (parent) workflow: its
+// task queue, execution, run and workflow task timeouts, memo and current typed search attributes, except reserved
+// ones like TemporalChangeVersion. WorkflowID is left empty, so children get a generated ID unless one is set. Adjust
+// the result as needed and pass it to WithChildOptions.
+func InheritedChildWorkflowOptions(ctx Context) ChildWorkflowOptions {
+	return internal.InheritedChildWorkflowOptions(ctx)
+}
+
 // WithWorkflowVersioningIntent is used to set the VersioningIntent before constructing a
 // ContinueAsNewError with NewContinueAsNewError.
 //